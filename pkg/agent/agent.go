@@ -0,0 +1,294 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package agent implements credential management for long-running berglas
+// agents (such as "berglas server") that want to authenticate as a user
+// instead of relying on gcloud or Application Default Credentials files on
+// disk. A token obtained via Login is cached in the OS keychain (Keychain on
+// macOS, Credential Manager on Windows, Secret Service/D-Bus on Linux) so
+// that it survives reboots and is refreshed automatically as it expires.
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/zalando/go-keyring"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+)
+
+const (
+	// keyringService is the OS keychain "service" (macOS) or equivalent under
+	// which the cached token is stored.
+	keyringService = "berglas"
+
+	// keyringUser is the OS keychain "account" under which the cached token is
+	// stored. Only one agent identity may be cached per machine user.
+	keyringUser = "agent"
+
+	grantTypeDeviceCode = "urn:ietf:params:oauth:grant-type:device_code"
+
+	defaultPollInterval = 5 * time.Second
+)
+
+// deviceCodeURL and tokenURL are declared as vars (rather than consts) so
+// tests can point them at a local httptest server.
+var (
+	deviceCodeURL = "https://oauth2.googleapis.com/device/code"
+	tokenURL      = "https://oauth2.googleapis.com/token"
+)
+
+// DefaultScopes are the OAuth scopes requested by Login when the caller does
+// not specify any.
+var DefaultScopes = []string{"https://www.googleapis.com/auth/cloud-platform"}
+
+// deviceCodeResponse is the response from the device authorization endpoint.
+type deviceCodeResponse struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int64  `json:"expires_in"`
+	Interval        int64  `json:"interval"`
+}
+
+// tokenErrorResponse is the error shape returned by the token endpoint while
+// a device code is pending user approval.
+type tokenErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Login performs the OAuth 2.0 device authorization flow against Google's
+// OAuth endpoints. It writes the verification URL and user code that the
+// operator must visit to w, then blocks polling the token endpoint until the
+// user approves the request, the device code expires, or ctx is canceled.
+//
+// The returned token is not cached; call SaveToken to persist it.
+func Login(ctx context.Context, clientID, clientSecret string, scopes []string, w io.Writer) (*oauth2.Token, error) {
+	if len(scopes) == 0 {
+		scopes = DefaultScopes
+	}
+
+	dc, err := requestDeviceCode(ctx, clientID, scopes)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to request device code")
+	}
+
+	fmt.Fprintf(w, "To authenticate the berglas agent, visit:\n\n    %s\n\n", dc.VerificationURL)
+	fmt.Fprintf(w, "and enter the code:\n\n    %s\n\n", dc.UserCode)
+	fmt.Fprintf(w, "Waiting for approval...\n")
+
+	interval := time.Duration(dc.Interval) * time.Second
+	if interval <= 0 {
+		interval = defaultPollInterval
+	}
+
+	return pollForToken(ctx, clientID, clientSecret, dc.DeviceCode, interval)
+}
+
+// requestDeviceCode requests a device and user code pair from Google's
+// device authorization endpoint.
+func requestDeviceCode(ctx context.Context, clientID string, scopes []string) (*deviceCodeResponse, error) {
+	form := url.Values{
+		"client_id": {clientID},
+		"scope":     {strings.Join(scopes, " ")},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, deviceCodeURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("device code request failed with status %s", resp.Status)
+	}
+
+	var dc deviceCodeResponse
+	if err := json.NewDecoder(resp.Body).Decode(&dc); err != nil {
+		return nil, errors.Wrap(err, "failed to decode device code response")
+	}
+	return &dc, nil
+}
+
+// pollForToken polls the token endpoint on interval until the user approves
+// the device code, the device code expires, or ctx is canceled.
+func pollForToken(ctx context.Context, clientID, clientSecret, deviceCode string, interval time.Duration) (*oauth2.Token, error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+			tok, pending, err := fetchToken(ctx, clientID, clientSecret, deviceCode)
+			if err != nil {
+				return nil, err
+			}
+			if pending {
+				continue
+			}
+			return tok, nil
+		}
+	}
+}
+
+// fetchToken makes a single attempt to exchange the device code for a token.
+// pending is true if the user has not yet approved the request and polling
+// should continue.
+func fetchToken(ctx context.Context, clientID, clientSecret, deviceCode string) (tok *oauth2.Token, pending bool, err error) {
+	form := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"device_code":   {deviceCode},
+		"grant_type":    {grantTypeDeviceCode},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, false, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var tokErr tokenErrorResponse
+		if err := json.Unmarshal(body, &tokErr); err == nil {
+			switch tokErr.Error {
+			case "authorization_pending", "slow_down":
+				return nil, true, nil
+			}
+			return nil, false, errors.Errorf("token request failed: %s", tokErr.Error)
+		}
+		return nil, false, errors.Errorf("token request failed with status %s", resp.Status)
+	}
+
+	var raw struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		TokenType    string `json:"token_type"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, false, errors.Wrap(err, "failed to decode token response")
+	}
+
+	return &oauth2.Token{
+		AccessToken:  raw.AccessToken,
+		RefreshToken: raw.RefreshToken,
+		TokenType:    raw.TokenType,
+		Expiry:       time.Now().Add(time.Duration(raw.ExpiresIn) * time.Second),
+	}, false, nil
+}
+
+// SaveToken persists tok in the OS keychain, overwriting any previously
+// cached token.
+func SaveToken(tok *oauth2.Token) error {
+	b, err := json.Marshal(tok)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal token")
+	}
+	if err := keyring.Set(keyringService, keyringUser, string(b)); err != nil {
+		return errors.Wrap(err, "failed to save token to OS keychain")
+	}
+	return nil
+}
+
+// LoadToken reads the cached token from the OS keychain. It returns
+// keyring.ErrNotFound if no token has been cached.
+func LoadToken() (*oauth2.Token, error) {
+	s, err := keyring.Get(keyringService, keyringUser)
+	if err != nil {
+		return nil, err
+	}
+
+	var tok oauth2.Token
+	if err := json.Unmarshal([]byte(s), &tok); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal cached token")
+	}
+	return &tok, nil
+}
+
+// DeleteToken removes any cached token from the OS keychain.
+func DeleteToken() error {
+	if err := keyring.Delete(keyringService, keyringUser); err != nil && err != keyring.ErrNotFound {
+		return errors.Wrap(err, "failed to delete cached token from OS keychain")
+	}
+	return nil
+}
+
+// TokenSource returns an oauth2.TokenSource backed by the cached token. The
+// token is refreshed automatically as needed, and any refreshed token is
+// written back to the OS keychain so future processes pick it up.
+func TokenSource(ctx context.Context, clientID, clientSecret string) (oauth2.TokenSource, error) {
+	tok, err := LoadToken()
+	if err != nil {
+		return nil, errors.Wrap(err, "no cached agent credentials found; run \"berglas agent login\"")
+	}
+
+	cfg := &oauth2.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		Endpoint:     google.Endpoint,
+	}
+
+	return &persistingTokenSource{
+		src: oauth2.ReuseTokenSource(tok, cfg.TokenSource(ctx, tok)),
+	}, nil
+}
+
+// persistingTokenSource wraps another TokenSource and saves every token it
+// returns to the OS keychain, so refreshed tokens survive process restarts.
+type persistingTokenSource struct {
+	src oauth2.TokenSource
+}
+
+// Token implements oauth2.TokenSource.
+func (p *persistingTokenSource) Token() (*oauth2.Token, error) {
+	tok, err := p.src.Token()
+	if err != nil {
+		return nil, err
+	}
+	if err := SaveToken(tok); err != nil {
+		return nil, err
+	}
+	return tok, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package agent
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/zalando/go-keyring"
+	"golang.org/x/oauth2"
+)
+
+func TestLogin(t *testing.T) {
+	t.Parallel()
+
+	var attempts int
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/device/code":
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"device_code":      "test-device-code",
+				"user_code":        "TEST-CODE",
+				"verification_url": "https://example.com/device",
+				"expires_in":       1800,
+				"interval":         0,
+			})
+		case "/token":
+			attempts++
+			if attempts < 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				json.NewEncoder(w).Encode(map[string]string{"error": "authorization_pending"})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"access_token":  "test-access-token",
+				"refresh_token": "test-refresh-token",
+				"token_type":    "Bearer",
+				"expires_in":    3600,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer ts.Close()
+
+	oldDeviceCodeURL, oldTokenURL := deviceCodeURL, tokenURL
+	deviceCodeURL, tokenURL = ts.URL+"/device/code", ts.URL+"/token"
+	defer func() { deviceCodeURL, tokenURL = oldDeviceCodeURL, oldTokenURL }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tok, err := pollForToken(ctx, "client-id", "client-secret", "test-device-code", 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tok.AccessToken != "test-access-token" {
+		t.Errorf("expected access token %q to be %q", tok.AccessToken, "test-access-token")
+	}
+	if attempts < 2 {
+		t.Errorf("expected at least 2 poll attempts, got %d", attempts)
+	}
+}
+
+func TestSaveLoadDeleteToken(t *testing.T) {
+	keyring.MockInit()
+
+	tok := &oauth2.Token{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		TokenType:    "Bearer",
+		Expiry:       time.Now().Add(time.Hour),
+	}
+
+	if err := SaveToken(tok); err != nil {
+		t.Fatalf("SaveToken: %v", err)
+	}
+
+	got, err := LoadToken()
+	if err != nil {
+		t.Fatalf("LoadToken: %v", err)
+	}
+	if got.AccessToken != tok.AccessToken || got.RefreshToken != tok.RefreshToken {
+		t.Errorf("expected loaded token %+v to match saved token %+v", got, tok)
+	}
+
+	if err := DeleteToken(); err != nil {
+		t.Fatalf("DeleteToken: %v", err)
+	}
+
+	if _, err := LoadToken(); err == nil {
+		t.Error("expected error loading deleted token")
+	}
+}
@@ -43,12 +43,17 @@ var (
 func init() {
 	ctx := context.Background()
 
-	client, err := berglas.New(ctx)
+	client, err := berglas.New(ctx, berglas.WithLogger(berglas.NewLogrusLogger(&logrus.Logger{
+		Out:          os.Stderr,
+		Formatter:    new(logrus.JSONFormatter),
+		Hooks:        make(logrus.LevelHooks),
+		Level:        logLevel,
+		ReportCaller: true,
+	})))
 	if err != nil {
 		handleError(errors.Wrap(err, "failed to initialize berglas client"))
 		return
 	}
-	client.SetLogLevel(logLevel)
 
 	runtimeEnv, err := client.DetectRuntimeEnvironment()
 	if err != nil {
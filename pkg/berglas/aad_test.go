@@ -0,0 +1,135 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"testing"
+)
+
+func TestEnvelopeEncryptDecrypt_aad(t *testing.T) {
+	t.Parallel()
+
+	plaintext := []byte("my secret value")
+	aad := []byte("my-bucket/my-object")
+
+	dek, ciphertext, err := envelopeEncrypt(plaintext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := envelopeDecrypt(dek, ciphertext, aad)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("envelopeDecrypt(envelopeEncrypt(...)) = %v, want %v", got, plaintext)
+	}
+
+	if _, err := envelopeDecrypt(dek, ciphertext, []byte("other-bucket/my-object")); err == nil {
+		t.Error("expected decryption with mismatched aad to fail")
+	}
+	if _, err := envelopeDecrypt(dek, ciphertext, nil); err == nil {
+		t.Error("expected decryption with missing aad to fail")
+	}
+}
+
+// Test_Client_Read_ciphertextSwapAcrossBuckets exercises the actual
+// vulnerability this AAD binding closes: a privileged attacker with direct
+// storage access swaps one secret's ciphertext blob onto another object
+// with the same name in a different bucket. Before bucket/object was bound
+// into the envelope, wrapDEK's aad was the object name alone, so this swap
+// went undetected as long as the two objects shared a name.
+func Test_Client_Read_ciphertextSwapAcrossBuckets(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newMemSecretStorage()
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "bucket-a",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("secret A"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "bucket-b",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("secret B"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	storage.mu.Lock()
+	a, b := storage.objects[storage.key("bucket-a", "my-object")], storage.objects[storage.key("bucket-b", "my-object")]
+	a.data, b.data = b.data, a.data
+	storage.mu.Unlock()
+
+	if _, err := client.Read(ctx, &ReadRequest{Bucket: "bucket-a", Object: "my-object"}); err == nil {
+		t.Error("expected read of swapped ciphertext to fail")
+	}
+	if _, err := client.Read(ctx, &ReadRequest{Bucket: "bucket-b", Object: "my-object"}); err == nil {
+		t.Error("expected read of swapped ciphertext to fail")
+	}
+}
+
+// Test_Client_Read_legacyUnboundCiphertext verifies secrets written before
+// MetadataAADKey existed - with no AAD binding the content ciphertext at
+// all - still read correctly.
+func Test_Client_Read_legacyUnboundCiphertext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newMemSecretStorage()
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dek, ciphertext, err := envelopeEncrypt([]byte("legacy secret"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	blob := fmt.Sprintf("%s:%s",
+		base64.StdEncoding.EncodeToString(dek), // memKeyManager wraps a DEK as a no-op
+		base64.StdEncoding.EncodeToString(ciphertext))
+
+	storage.mu.Lock()
+	storage.objects[storage.key("my-bucket", "my-object")] = &memObject{
+		data:           []byte(blob),
+		metadata:       map[string]string{MetadataIDKey: "1", MetadataKMSKey: "my-key"},
+		generation:     1,
+		metageneration: 1,
+	}
+	storage.mu.Unlock()
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(secret.Plaintext), "legacy secret"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
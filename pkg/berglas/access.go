@@ -16,6 +16,7 @@ package berglas
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -36,16 +37,57 @@ type AccessRequest struct {
 	// Bucket is the name of the bucket where the secret lives.
 	Bucket string
 
-	// Object is the name of the object in Cloud Storage.
+	// Object is the name of the object in Cloud Storage. If it begins with
+	// AliasPrefix, it is instead looked up as an alias name in Bucket's alias
+	// table (see SetAlias) and the secret its target points at is returned.
 	Object string
 
 	// Generation of the object to fetch
 	Generation int64
+
+	// IfNoneMatchGeneration, if given, is compared against the secret's current
+	// generation before downloading or decrypting anything. If they match,
+	// Access returns errSecretNotModified (see IsSecretNotModifiedErr) instead
+	// of the plaintext, so callers that poll frequently (watch loops, agents)
+	// don't pay for a download and KMS decrypt when nothing has changed.
+	IfNoneMatchGeneration int64
+
+	// ExpectKey, if given, is compared against the KMS key (or local-key://
+	// path) the secret is actually encrypted with. If they do not match,
+	// Access returns errUnexpectedKey (see IsUnexpectedKeyErr) instead of the
+	// plaintext, protecting callers from consuming a secret that was
+	// re-encrypted with an unauthorized or downgraded key.
+	ExpectKey string
+
+	// Offset is the byte offset into the decrypted plaintext to start
+	// returning data from. It is only honored when Length is also set.
+	Offset int64
+
+	// Length, if greater than zero, limits the returned plaintext to at most
+	// this many bytes starting at Offset, instead of returning the entire
+	// secret - useful for sniffing a large secret's format or reading a
+	// fixed-size header.
+	//
+	// berglas's envelope format wraps the whole secret in a single AES-GCM
+	// seal, which cannot be decrypted partially, so this does not save a
+	// download or a KMS decrypt: the full secret is still fetched and
+	// decrypted, and only the requested range of the resulting plaintext is
+	// returned.
+	Length int64
 }
 
 // Access reads the contents of the secret from the bucket, decrypting the
-// ciphertext using Cloud KMS.
-func (c *Client) Access(ctx context.Context, i *AccessRequest) ([]byte, error) {
+// ciphertext using Cloud KMS. See AccessRequest.Length to read only part of
+// the resulting plaintext.
+func (c *Client) Access(ctx context.Context, i *AccessRequest) (_ []byte, err error) {
+	start := time.Now()
+	defer func() {
+		if err != nil && !IsSecretNotModifiedErr(err) {
+			recordError("access")
+		}
+		recordAccessLatency(time.Since(start))
+	}()
+
 	if i == nil {
 		return nil, errors.New("missing request")
 	}
@@ -60,6 +102,11 @@ func (c *Client) Access(ctx context.Context, i *AccessRequest) ([]byte, error) {
 		return nil, errors.New("missing object name")
 	}
 
+	bucket, object, err = c.resolveAliasRef(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+
 	generation := i.Generation
 	if generation == 0 {
 		generation = -1
@@ -74,13 +121,125 @@ func (c *Client) Access(ctx context.Context, i *AccessRequest) ([]byte, error) {
 	logger.Debug("access.start")
 	defer logger.Debug("access.finish")
 
-	secret, err := c.Read(ctx, &ReadRequest{
-		Bucket:     bucket,
-		Object:     object,
-		Generation: generation,
-	})
+	recordAccess()
+
+	if i.IfNoneMatchGeneration != 0 {
+		var obj *StorageObject
+		err := c.withRetry(ctx, func(ctx context.Context) error {
+			var err error
+			obj, err = c.secretStorage.StatObject(ctx, bucket, object, generation)
+			return err
+		})
+		if err != nil {
+			return nil, wrapAPIErr(err, "failed to check secret generation")
+		}
+
+		if obj.Generation == i.IfNoneMatchGeneration {
+			logger.Debug("secret generation unchanged, skipping download")
+			recordCacheHit()
+			return nil, errSecretNotModified
+		}
+	}
+
+	c.cacheLock.RLock()
+	cache := c.cache
+	c.cacheLock.RUnlock()
+
+	c.diskCacheLock.RLock()
+	diskCache := c.diskCache
+	c.diskCacheLock.RUnlock()
+
+	var secret *Secret
+	if cache != nil && i.IfNoneMatchGeneration == 0 {
+		secret, err = c.cachedRead(ctx, cache, bucket, object, generation)
+	} else {
+		secret, err = c.Read(ctx, &ReadRequest{
+			Bucket:     bucket,
+			Object:     object,
+			Generation: generation,
+		})
+	}
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to access secret")
+		if diskCache == nil {
+			return nil, errors.Wrap(err, "failed to access secret")
+		}
+
+		cached, cacheErr := diskCache.get(bucket, object, generation)
+		if cacheErr != nil {
+			return nil, errors.Wrap(err, "failed to access secret")
+		}
+
+		logger.WithError(err).Warn("failed to resolve secret live, serving stale value from disk cache")
+		secret = cached
+	} else if diskCache != nil {
+		if err := diskCache.put(bucket, object, generation, secret); err != nil {
+			logger.WithError(err).Debug("failed to write disk cache entry")
+		}
 	}
+
+	if i.ExpectKey != "" && secret.KMSKey != i.ExpectKey {
+		return nil, errors.Wrapf(errUnexpectedKey, "secret is encrypted with %q, expected %q", secret.KMSKey, i.ExpectKey)
+	}
+
+	if i.Length > 0 {
+		return slicePlaintext(secret.Plaintext, i.Offset, i.Length)
+	}
+
 	return secret.Plaintext, nil
 }
+
+// cachedRead is Read, but consulting and populating cache first. A cache
+// miss is resolved with singleflight, so concurrent Access calls for the
+// same not-yet-cached key share a single Read.
+func (c *Client) cachedRead(ctx context.Context, cache *secretCache, bucket, object string, generation int64) (*Secret, error) {
+	key := cacheKey(bucket, object, generation)
+
+	if entry, ok := cache.get(key); ok {
+		recordCacheHit()
+		return &Secret{Name: object, KMSKey: entry.kmsKey, Plaintext: entry.plaintext}, nil
+	}
+
+	v, err, _ := cache.group.Do(key, func() (interface{}, error) {
+		secret, err := c.Read(ctx, &ReadRequest{
+			Bucket:     bucket,
+			Object:     object,
+			Generation: generation,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		cache.set(key, &cacheEntry{
+			plaintext: secret.Plaintext,
+			kmsKey:    secret.KMSKey,
+			expiresAt: time.Now().Add(cache.ttl),
+		})
+
+		return secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return v.(*Secret), nil
+}
+
+// slicePlaintext returns the [offset, offset+length) range of plaintext,
+// clamped to the end of the data so that asking for more than is left
+// returns what remains instead of erroring, matching the common io.Reader
+// convention.
+func slicePlaintext(plaintext []byte, offset, length int64) ([]byte, error) {
+	if offset < 0 || length < 0 {
+		return nil, errors.New("offset and length must be non-negative")
+	}
+	if offset > int64(len(plaintext)) {
+		return nil, errors.Errorf("offset %d is past the end of the secret (%d bytes)", offset, len(plaintext))
+	}
+
+	end := offset + length
+	if end > int64(len(plaintext)) {
+		end = int64(len(plaintext))
+	}
+
+	return plaintext[offset:end], nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"testing"
+)
+
+func TestSlicePlaintext(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name   string
+		data   string
+		offset int64
+		length int64
+		exp    string
+		err    bool
+	}{
+		{"from start", "hello world", 0, 5, "hello", false},
+		{"with offset", "hello world", 6, 5, "world", false},
+		{"clamps to end", "hello world", 6, 100, "world", false},
+		{"offset at end returns empty", "hello world", 11, 5, "", false},
+		{"negative offset", "hello world", -1, 5, "", true},
+		{"negative length", "hello world", 0, -1, "", true},
+		{"offset past end", "hello world", 12, 5, "", true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := slicePlaintext([]byte(tc.data), tc.offset, tc.length)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if tc.err {
+				return
+			}
+
+			if string(act) != tc.exp {
+				t.Errorf("expected %q to be %q", act, tc.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// aliasObjectName is the reserved object name, within a bucket, of the
+	// secret that stores that bucket's alias table. It is a regular secret -
+	// a Bundle mapping alias name to "bucket/object" target - so it is
+	// encrypted, versioned, and IAM-controlled exactly like any other secret.
+	aliasObjectName = ".berglas-aliases"
+
+	// AliasPrefix marks an object name, in any bucket/object ref accepted by
+	// the CLI or Resolve, as an alias name to look up in that bucket's alias
+	// table rather than a literal object name.
+	AliasPrefix = "@"
+)
+
+var (
+	// errAliasDoesNotExist is returned when an alias name has no entry in its
+	// bucket's alias table.
+	errAliasDoesNotExist = Error("alias does not exist")
+)
+
+// IsAliasDoesNotExistErr returns true if the given error means that the
+// referenced alias does not exist.
+func IsAliasDoesNotExistErr(err error) bool {
+	return errors.Cause(err) == errAliasDoesNotExist
+}
+
+// AliasSetRequest is used as input to SetAlias.
+type AliasSetRequest struct {
+	// Bucket is the name of the bucket whose alias table is being updated.
+	Bucket string
+
+	// Key is the fully qualified KMS key id for the bucket's alias table
+	// secret. Only used the first time an alias is set in Bucket.
+	Key string
+
+	// Name is the alias's logical name, without the leading AliasPrefix.
+	Name string
+
+	// Target is the "bucket/object" ref this alias resolves to.
+	Target string
+}
+
+// SetAlias creates or updates an alias in Bucket's alias table so it points
+// at Target, creating the alias table itself the first time an alias is set
+// in that bucket.
+func (c *Client) SetAlias(ctx context.Context, i *AliasSetRequest) (*Secret, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	if i.Name == "" {
+		return nil, errors.New("missing alias name")
+	}
+	if i.Target == "" {
+		return nil, errors.New("missing alias target")
+	}
+	if _, _, err := parseBucketObj(i.Target); err != nil {
+		return nil, errors.Wrap(err, "invalid alias target")
+	}
+
+	return c.BundleSet(ctx, &BundleSetRequest{
+		Bucket: i.Bucket,
+		Object: aliasObjectName,
+		Key:    i.Key,
+		Values: Bundle{i.Name: i.Target},
+	})
+}
+
+// AliasDeleteRequest is used as input to DeleteAlias.
+type AliasDeleteRequest struct {
+	// Bucket is the name of the bucket whose alias table is being updated.
+	Bucket string
+
+	// Name is the alias's logical name, without the leading AliasPrefix.
+	Name string
+}
+
+// DeleteAlias removes an alias from Bucket's alias table. It is a no-op if
+// the alias table or the alias itself does not exist.
+func (c *Client) DeleteAlias(ctx context.Context, i *AliasDeleteRequest) (*Secret, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	secret, err := c.BundleUnset(ctx, &BundleUnsetRequest{
+		Bucket: i.Bucket,
+		Object: aliasObjectName,
+		Keys:   []string{i.Name},
+	})
+	if IsSecretDoesNotExistErr(err) {
+		return nil, nil
+	}
+	return secret, err
+}
+
+// AliasResolveRequest is used as input to ResolveAlias.
+type AliasResolveRequest struct {
+	// Bucket is the name of the bucket whose alias table is being queried.
+	Bucket string
+
+	// Name is the alias's logical name, without the leading AliasPrefix.
+	Name string
+}
+
+// ResolveAlias returns the "bucket/object" target the given alias points at.
+func (c *Client) ResolveAlias(ctx context.Context, i *AliasResolveRequest) (string, error) {
+	if i == nil {
+		return "", errors.New("missing request")
+	}
+
+	aliases, err := c.ListAliases(ctx, &AliasListRequest{Bucket: i.Bucket})
+	if err != nil {
+		return "", err
+	}
+
+	target, ok := aliases.Aliases[i.Name]
+	if !ok {
+		return "", errAliasDoesNotExist
+	}
+	return target, nil
+}
+
+// AliasListRequest is used as input to ListAliases.
+type AliasListRequest struct {
+	// Bucket is the name of the bucket whose alias table is being listed.
+	Bucket string
+}
+
+// AliasListResponse is the response from ListAliases.
+type AliasListResponse struct {
+	// Aliases maps each alias name to its "bucket/object" target.
+	Aliases Bundle
+}
+
+// ListAliases returns every alias defined in Bucket's alias table, or an
+// empty result if Bucket has no alias table yet.
+func (c *Client) ListAliases(ctx context.Context, i *AliasListRequest) (*AliasListResponse, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	plaintext, err := c.Access(ctx, &AccessRequest{
+		Bucket: i.Bucket,
+		Object: aliasObjectName,
+	})
+	if err != nil {
+		if IsSecretDoesNotExistErr(err) {
+			return &AliasListResponse{Aliases: Bundle{}}, nil
+		}
+		return nil, err
+	}
+
+	b, err := ParseBundle(plaintext)
+	if err != nil {
+		return nil, err
+	}
+	return &AliasListResponse{Aliases: b}, nil
+}
+
+// resolveAliasRef rewrites bucket/object to the target of the alias object
+// names, if object is an alias reference (see AliasPrefix). Non-alias
+// object names are returned unchanged.
+func (c *Client) resolveAliasRef(ctx context.Context, bucket, object string) (string, string, error) {
+	if !strings.HasPrefix(object, AliasPrefix) {
+		return bucket, object, nil
+	}
+
+	name := strings.TrimPrefix(object, AliasPrefix)
+
+	target, err := c.ResolveAlias(ctx, &AliasResolveRequest{Bucket: bucket, Name: name})
+	if err != nil {
+		return "", "", errors.Wrapf(err, "failed to resolve alias %s%s", AliasPrefix, name)
+	}
+
+	return parseBucketObj(target)
+}
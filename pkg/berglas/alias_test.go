@@ -0,0 +1,124 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+)
+
+func TestClient_SetAlias_ResolveAlias(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	target := h.Bucket + "/postgres/primary/password"
+	if _, err := h.Client.SetAlias(ctx, &berglas.AliasSetRequest{
+		Bucket: h.Bucket,
+		Key:    h.Key,
+		Name:   "prod-db-pass",
+		Target: target,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := h.Client.ResolveAlias(ctx, &berglas.AliasResolveRequest{
+		Bucket: h.Bucket,
+		Name:   "prod-db-pass",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != target {
+		t.Errorf("expected alias target to be %q, got %q", target, got)
+	}
+}
+
+func TestClient_ResolveAlias_notFound(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.ResolveAlias(ctx, &berglas.AliasResolveRequest{
+		Bucket: h.Bucket,
+		Name:   "missing",
+	}); !berglas.IsAliasDoesNotExistErr(err) {
+		t.Errorf("expected an alias-does-not-exist error, got %v", err)
+	}
+}
+
+func TestClient_Access_alias(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "postgres/primary/password",
+		Key:       h.Key,
+		Plaintext: []byte("hunter2"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.SetAlias(ctx, &berglas.AliasSetRequest{
+		Bucket: h.Bucket,
+		Key:    h.Key,
+		Name:   "prod-db-pass",
+		Target: h.Bucket + "/postgres/primary/password",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "@prod-db-pass",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "hunter2"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
+
+func TestClient_ListAliases_empty(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	resp, err := h.Client.ListAliases(ctx, &berglas.AliasListRequest{Bucket: h.Bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Aliases) != 0 {
+		t.Errorf("expected no aliases, got %#v", resp.Aliases)
+	}
+}
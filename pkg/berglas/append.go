@@ -0,0 +1,125 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/retry"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// appendMaxAttempts is the number of times Append will retry after a
+// generation precondition conflict before giving up.
+const appendMaxAttempts = 5
+
+// Append is a top-level package function for appending to a secret. For
+// large volumes of secrets, please create a client instead.
+func Append(ctx context.Context, i *AppendRequest) (*Secret, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Append(ctx, i)
+}
+
+// AppendRequest is used as input to append to a secret.
+type AppendRequest struct {
+	// Bucket is the name of the bucket where the secret lives.
+	Bucket string
+
+	// Object is the name of the object in Cloud Storage.
+	Object string
+
+	// Data is the plaintext data to append to the existing secret.
+	Data []byte
+}
+
+// Append atomically appends data to the end of an existing secret's
+// plaintext. The secret is re-read and the append is retried if a concurrent
+// writer changes the secret's generation between the read and the write,
+// avoiding the read-modify-write race that a plain Read+Update would have.
+func (c *Client) Append(ctx context.Context, i *AppendRequest) (_ *Secret, err error) {
+	defer func() {
+		if err != nil {
+			recordError("append")
+		}
+	}()
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	object := i.Object
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+
+	data := i.Data
+	if data == nil {
+		return nil, errors.New("missing data")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"bucket": bucket,
+		"object": object,
+	})
+
+	logger.Debug("append.start")
+	defer logger.Debug("append.finish")
+
+	var secret *Secret
+	if err := retry.RetryFib(ctx, 100*time.Millisecond, appendMaxAttempts, func() error {
+		existing, err := c.Read(ctx, &ReadRequest{
+			Bucket: bucket,
+			Object: object,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to read existing secret")
+		}
+
+		if err := checkRetention(existing.RetainUntil); err != nil {
+			return err
+		}
+
+		plaintext := make([]byte, 0, len(existing.Plaintext)+len(data))
+		plaintext = append(plaintext, existing.Plaintext...)
+		plaintext = append(plaintext, data...)
+
+		s, err := c.encryptAndWrite(ctx, bucket, object, existing.KMSKey, existing.AdditionalKMSKeys, plaintext, existing.Format, existing.Asymmetric,
+			existing.Generation, existing.Metageneration, existing.RetainUntil)
+		if err != nil {
+			if IsSecretModifiedErr(err) {
+				logger.Debug("secret modified between read and write, retrying")
+				return retry.RetryableError(err)
+			}
+			return err
+		}
+
+		secret = s
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to append to secret")
+	}
+
+	return secret, nil
+}
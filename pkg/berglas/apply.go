@@ -0,0 +1,362 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Apply is a top-level package function for applying a set of changes. For
+// large volumes of secrets, please create a client instead.
+func Apply(ctx context.Context, i *ApplyRequest) (*ApplyResponse, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Apply(ctx, i)
+}
+
+// ChangeSet describes the new contents of a single secret as part of an
+// Apply transaction.
+type ChangeSet struct {
+	// Bucket is the name of the bucket where the secret lives.
+	Bucket string
+
+	// Object is the name of the object in Cloud Storage.
+	Object string
+
+	// Key is the fully qualified KMS key id. Required when the secret does
+	// not already exist; if it does, and Key is empty, the secret's existing
+	// KMS key is reused.
+	Key string
+
+	// Plaintext is the new plaintext value of the secret.
+	Plaintext []byte
+
+	// Members, if non-nil, is the desired IAM membership on the secret: any
+	// member not already granted access is granted, and any member
+	// currently granted access that is not listed here is revoked, so the
+	// secret's bindings end up matching this list exactly. A nil Members
+	// leaves the secret's existing IAM bindings untouched; an explicit empty
+	// slice revokes every current member.
+	Members []string
+}
+
+// ApplyRequest is used as input to Apply.
+type ApplyRequest struct {
+	// Changes are the secrets to reconcile together.
+	Changes []*ChangeSet
+
+	// DryRun, if true, computes what Apply would do without creating,
+	// updating, granting, or revoking anything. ApplyResponse.Secrets is
+	// unset in this case, since nothing was written; ApplyResponse.Actions
+	// still describes the plan.
+	DryRun bool
+}
+
+// ChangeAction records what Apply did (or, under ApplyRequest.DryRun, would
+// do) to reconcile one ChangeSet.
+type ChangeAction struct {
+	// Bucket and Object identify the secret this action applies to.
+	Bucket string
+	Object string
+
+	// SecretAction is "create" if the secret did not previously exist,
+	// "update" if it existed with a different plaintext or KMS key, or
+	// "unchanged" if it already matched the change and nothing was written.
+	SecretAction string
+
+	// Granted and Revoked are the IAM members added and removed,
+	// respectively, to reconcile the secret's membership with
+	// ChangeSet.Members. Both are empty if Members is nil.
+	Granted []string
+	Revoked []string
+}
+
+// ApplyResponse is the response from Apply.
+type ApplyResponse struct {
+	// Secrets are the resulting secrets, in the same order as
+	// ApplyRequest.Changes - the newly written value for a "create" or
+	// "update" action, or the existing value for "unchanged". Unset if
+	// ApplyRequest.DryRun was set.
+	Secrets []*Secret
+
+	// Actions describes, in the same order as ApplyRequest.Changes, the
+	// reconciliation Apply performed (or, under DryRun, would perform) for
+	// each change.
+	Actions []*ChangeAction
+}
+
+// applied records one committed change, so Apply can roll it back if a
+// later change fails.
+type applied struct {
+	change *ChangeSet
+
+	// wroteContent is true if this change created or updated the secret's
+	// content; previous is then the secret's value beforehand (nil if the
+	// secret did not exist before this change).
+	wroteContent bool
+	previous     *Secret
+
+	// granted and revoked are the IAM members actually granted and revoked
+	// by this change - not ChangeAction.Granted/Revoked, which may list
+	// members Apply attempted but failed to grant or revoke.
+	granted []string
+	revoked []string
+}
+
+// Apply commits several secret changes together: if any change fails, every
+// change already committed in this call is rolled back - its content
+// restored to its previous value (or deleted if Apply created it) and any
+// IAM members it granted or revoked reversed - before the error is
+// returned, so a partial failure never leaves related secrets (e.g. a cert,
+// its key, and its chain) in a mismatched state.
+//
+// Cloud Storage has no multi-object transaction primitive, so this is not a
+// true atomic commit: each individual change is still protected by its own
+// generation precondition, but a crash between two changes, or a failure
+// during rollback itself, can still leave the set only partially applied.
+// Apply logs any rollback failure so it can be investigated and reconciled
+// by hand.
+func (c *Client) Apply(ctx context.Context, i *ApplyRequest) (_ *ApplyResponse, err error) {
+	defer func() {
+		if err != nil {
+			recordError("apply")
+		}
+	}()
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	if len(i.Changes) == 0 {
+		return nil, errors.New("missing changes")
+	}
+
+	logger := c.Logger().WithField("changes", len(i.Changes))
+	logger.Debug("apply.start")
+	defer logger.Debug("apply.finish")
+
+	var committed []applied
+
+	rollback := func() {
+		for j := len(committed) - 1; j >= 0; j-- {
+			a := committed[j]
+			clogger := logger.WithFields(logrus.Fields{
+				"bucket": a.change.Bucket,
+				"object": a.change.Object,
+			})
+
+			if len(a.revoked) > 0 {
+				if gerr := c.Grant(ctx, &GrantRequest{
+					Bucket:  a.change.Bucket,
+					Object:  a.change.Object,
+					Members: a.revoked,
+				}); gerr != nil {
+					clogger.WithError(gerr).Error("failed to re-grant IAM members revoked by a failed apply")
+				}
+			}
+			if len(a.granted) > 0 {
+				if rerr := c.Revoke(ctx, &RevokeRequest{
+					Bucket:  a.change.Bucket,
+					Object:  a.change.Object,
+					Members: a.granted,
+				}); rerr != nil {
+					clogger.WithError(rerr).Error("failed to revoke IAM members granted by a failed apply")
+				}
+			}
+
+			if !a.wroteContent {
+				continue
+			}
+
+			if a.previous == nil {
+				if derr := c.Delete(ctx, &DeleteRequest{
+					Bucket: a.change.Bucket,
+					Object: a.change.Object,
+				}); derr != nil {
+					clogger.WithError(derr).Error("failed to roll back secret created by a failed apply")
+				}
+				continue
+			}
+
+			if _, uerr := c.Update(ctx, &UpdateRequest{
+				Bucket:      a.change.Bucket,
+				Object:      a.change.Object,
+				Key:         a.previous.KMSKey,
+				Plaintext:   a.previous.Plaintext,
+				RetainUntil: a.previous.RetainUntil,
+			}); uerr != nil {
+				clogger.WithError(uerr).Error("failed to roll back secret to its pre-apply value")
+			}
+		}
+	}
+
+	results := make([]*Secret, len(i.Changes))
+	actions := make([]*ChangeAction, len(i.Changes))
+	for idx, change := range i.Changes {
+		if change.Bucket == "" || change.Object == "" {
+			err = errors.New("missing bucket or object name in change set")
+			rollback()
+			return nil, err
+		}
+
+		action := &ChangeAction{Bucket: change.Bucket, Object: change.Object}
+		actions[idx] = action
+
+		previous, rerr := c.Read(ctx, &ReadRequest{
+			Bucket: change.Bucket,
+			Object: change.Object,
+		})
+		if rerr != nil && !IsSecretDoesNotExistErr(rerr) {
+			err = errors.Wrapf(rerr, "failed to read existing value of %s/%s", change.Bucket, change.Object)
+			rollback()
+			return nil, err
+		}
+		if IsSecretDoesNotExistErr(rerr) {
+			previous = nil
+		}
+
+		key := change.Key
+		if key == "" && previous != nil {
+			key = previous.KMSKey
+		}
+		if key == "" {
+			err = errors.Errorf("missing key for new secret %s/%s", change.Bucket, change.Object)
+			rollback()
+			return nil, err
+		}
+
+		unchanged := previous != nil && previous.KMSKey == key && bytes.Equal(previous.Plaintext, change.Plaintext)
+
+		switch {
+		case previous == nil:
+			action.SecretAction = "create"
+		case unchanged:
+			action.SecretAction = "unchanged"
+		default:
+			action.SecretAction = "update"
+		}
+
+		if change.Members != nil {
+			var current []string
+			if previous != nil {
+				current, err = c.IAMMembers(ctx, change.Bucket, change.Object)
+				if err != nil {
+					err = errors.Wrapf(err, "failed to read IAM members of %s/%s", change.Bucket, change.Object)
+					rollback()
+					return nil, err
+				}
+			}
+			action.Granted, action.Revoked = diffMembers(current, change.Members)
+		}
+
+		if i.DryRun {
+			continue
+		}
+
+		entry := applied{change: change}
+
+		var result *Secret
+		if unchanged {
+			result = previous
+		} else {
+			var werr error
+			if previous == nil {
+				result, werr = c.Create(ctx, &CreateRequest{
+					Bucket:    change.Bucket,
+					Object:    change.Object,
+					Key:       key,
+					Plaintext: change.Plaintext,
+				})
+			} else {
+				result, werr = c.Update(ctx, &UpdateRequest{
+					Bucket:      change.Bucket,
+					Object:      change.Object,
+					Key:         key,
+					Plaintext:   change.Plaintext,
+					RetainUntil: previous.RetainUntil,
+				})
+			}
+			if werr != nil {
+				err = errors.Wrapf(werr, "failed to apply change to %s/%s", change.Bucket, change.Object)
+				rollback()
+				return nil, err
+			}
+
+			entry.wroteContent = true
+			entry.previous = previous
+		}
+		results[idx] = result
+
+		if len(action.Granted) > 0 {
+			if gerr := c.Grant(ctx, &GrantRequest{Bucket: change.Bucket, Object: change.Object, Members: action.Granted}); gerr != nil {
+				logger.WithError(gerr).Warnf("failed to grant IAM members on %s/%s", change.Bucket, change.Object)
+			} else {
+				entry.granted = action.Granted
+			}
+		}
+		if len(action.Revoked) > 0 {
+			if rerr := c.Revoke(ctx, &RevokeRequest{Bucket: change.Bucket, Object: change.Object, Members: action.Revoked}); rerr != nil {
+				logger.WithError(rerr).Warnf("failed to revoke IAM members on %s/%s", change.Bucket, change.Object)
+			} else {
+				entry.revoked = action.Revoked
+			}
+		}
+
+		if entry.wroteContent || len(entry.granted) > 0 || len(entry.revoked) > 0 {
+			committed = append(committed, entry)
+		}
+	}
+
+	if i.DryRun {
+		results = nil
+	}
+
+	return &ApplyResponse{Secrets: results, Actions: actions}, nil
+}
+
+// diffMembers compares a secret's current IAM members against its desired
+// membership, returning the members to grant (present in desired but not
+// current) and to revoke (present in current but not desired).
+func diffMembers(current, desired []string) (grant, revoke []string) {
+	inCurrent := make(map[string]bool, len(current))
+	for _, m := range current {
+		inCurrent[m] = true
+	}
+	inDesired := make(map[string]bool, len(desired))
+	for _, m := range desired {
+		inDesired[m] = true
+	}
+
+	for _, m := range desired {
+		if !inCurrent[m] {
+			grant = append(grant, m)
+		}
+	}
+	for _, m := range current {
+		if !inDesired[m] {
+			revoke = append(revoke, m)
+		}
+	}
+	sort.Strings(grant)
+	sort.Strings(revoke)
+	return grant, revoke
+}
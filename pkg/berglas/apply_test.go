@@ -0,0 +1,281 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas_test
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+)
+
+func TestClient_Apply(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	resp, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1")},
+			{Bucket: h.Bucket, Object: "tls-key", Key: h.Key, Plaintext: []byte("key-v1")},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Secrets) != 2 {
+		t.Fatalf("expected 2 committed secrets, got %d", len(resp.Secrets))
+	}
+
+	cert, err := h.Client.Access(ctx, &berglas.AccessRequest{Bucket: h.Bucket, Object: "tls-cert"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert, []byte("cert-v1")) {
+		t.Errorf("expected %q to be %q", cert, "cert-v1")
+	}
+}
+
+func TestClient_Apply_rollsBackOnPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "tls-cert",
+		Key:       h.Key,
+		Plaintext: []byte("cert-v1"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second change has no Key and no pre-existing secret, so Apply fails
+	// after already committing the first change - which should be rolled
+	// back to its pre-apply value.
+	_, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v2")},
+			{Bucket: h.Bucket, Object: "tls-key-missing-key", Plaintext: []byte("key-v1")},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	cert, err := h.Client.Access(ctx, &berglas.AccessRequest{Bucket: h.Bucket, Object: "tls-cert"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(cert, []byte("cert-v1")) {
+		t.Errorf("expected rolled-back secret to be %q, got %q", "cert-v1", cert)
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{Bucket: h.Bucket, Object: "tls-key-missing-key"}); !berglas.IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected secret to not exist, got %v", err)
+	}
+}
+
+func TestClient_Apply_rollsBackCreatedSecret(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// The first change creates a brand new secret; the second fails, so the
+	// first should be deleted as part of the rollback, not just reverted.
+	_, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-chain", Key: h.Key, Plaintext: []byte("chain-v1")},
+			{Bucket: h.Bucket, Object: "tls-key-missing-key", Plaintext: []byte("key-v1")},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{Bucket: h.Bucket, Object: "tls-chain"}); !berglas.IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected newly-created secret to be rolled back, got %v", err)
+	}
+}
+
+func TestClient_Apply_rollsBackIAMOnPartialFailure(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	const keep = "user:keep@example.com"
+	const drop = "user:drop@example.com"
+	const add = "user:add@example.com"
+
+	if _, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1"), Members: []string{keep, drop}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first change grants "add" and revokes "drop"; the second fails, so
+	// both IAM changes to tls-cert should be reversed along with its content.
+	_, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v2"), Members: []string{keep, add}},
+			{Bucket: h.Bucket, Object: "tls-key-missing-key", Plaintext: []byte("key-v1")},
+		},
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+
+	members, err := h.Client.IAMMembers(ctx, h.Bucket, "tls-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{drop, keep}; !equalStringSlices(members, want) {
+		t.Errorf("expected IAM members to be rolled back to %v, got %v", want, members)
+	}
+}
+
+func TestClient_Apply_unchanged(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	change := &berglas.ChangeSet{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1")}
+
+	resp, err := h.Client.Apply(ctx, &berglas.ApplyRequest{Changes: []*berglas.ChangeSet{change}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Actions[0].SecretAction; got != "create" {
+		t.Fatalf("expected first apply to create, got %q", got)
+	}
+	generation := resp.Secrets[0].Generation
+
+	resp, err = h.Client.Apply(ctx, &berglas.ApplyRequest{Changes: []*berglas.ChangeSet{change}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Actions[0].SecretAction; got != "unchanged" {
+		t.Fatalf("expected re-applying an identical change to be unchanged, got %q", got)
+	}
+	if got := resp.Secrets[0].Generation; got != generation {
+		t.Errorf("expected generation to stay %d for an unchanged apply, got %d", generation, got)
+	}
+}
+
+func TestClient_Apply_dryRun(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	resp, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1")},
+		},
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := resp.Actions[0].SecretAction; got != "create" {
+		t.Fatalf("expected dry-run plan to report create, got %q", got)
+	}
+	if resp.Secrets != nil {
+		t.Errorf("expected dry-run to leave Secrets unset, got %v", resp.Secrets)
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{Bucket: h.Bucket, Object: "tls-cert"}); !berglas.IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected dry-run to not create the secret, got %v", err)
+	}
+}
+
+func TestClient_Apply_members(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	const keep = "user:keep@example.com"
+	const drop = "user:drop@example.com"
+	const add = "user:add@example.com"
+
+	if _, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1"), Members: []string{keep, drop}},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := h.Client.Apply(ctx, &berglas.ApplyRequest{
+		Changes: []*berglas.ChangeSet{
+			{Bucket: h.Bucket, Object: "tls-cert", Key: h.Key, Plaintext: []byte("cert-v1"), Members: []string{keep, add}},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	action := resp.Actions[0]
+	if want := []string{add}; !equalStringSlices(action.Granted, want) {
+		t.Errorf("expected Granted %v, got %v", want, action.Granted)
+	}
+	if want := []string{drop}; !equalStringSlices(action.Revoked, want) {
+		t.Errorf("expected Revoked %v, got %v", want, action.Revoked)
+	}
+
+	members, err := h.Client.IAMMembers(ctx, h.Bucket, "tls-cert")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{add, keep}; !equalStringSlices(members, want) {
+		t.Errorf("expected final members %v, got %v", want, members)
+	}
+}
+
+func equalStringSlices(act, exp []string) bool {
+	if len(act) != len(exp) {
+		return false
+	}
+	for i := range act {
+		if act[i] != exp[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,147 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"testing"
+)
+
+// Test_Client_Create_asymmetric verifies that a secret created with
+// Asymmetric wraps its DEK through AsymmetricKeyManager instead of
+// KeyManager, and reads back correctly through a KeyManager with decrypt
+// permission.
+func Test_Client_Create_asymmetric(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	akm := newMemAsymmetricKeyManager(t)
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), akm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.Create(ctx, &CreateRequest{
+		Bucket:     "my-bucket",
+		Object:     "my-object",
+		Key:        "my-key",
+		Plaintext:  []byte("my secret value"),
+		Asymmetric: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !created.Asymmetric {
+		t.Error("created.Asymmetric = false, want true")
+	}
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(secret.Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+	if !secret.Asymmetric {
+		t.Error("secret.Asymmetric = false, want true")
+	}
+}
+
+// Test_Client_Create_asymmetric_unsupportedKeyManager verifies that
+// requesting Asymmetric against a KeyManager that does not implement
+// AsymmetricKeyManager fails clearly instead of silently falling back to a
+// symmetric wrap.
+func Test_Client_Create_asymmetric_unsupportedKeyManager(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:     "my-bucket",
+		Object:     "my-object",
+		Key:        "my-key",
+		Plaintext:  []byte("my secret value"),
+		Asymmetric: true,
+	}); !IsBackendUnsupportedErr(err) {
+		t.Errorf("expected IsBackendUnsupportedErr, got %v", err)
+	}
+}
+
+// Test_Client_Create_asymmetric_localKey verifies that Asymmetric is
+// rejected outright with a local-key:// key, which has no asymmetric
+// counterpart.
+func Test_Client_Create_asymmetric_localKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:     "my-bucket",
+		Object:     "my-object",
+		Key:        LocalKeyPrefix + "/dev/null",
+		Plaintext:  []byte("my secret value"),
+		Asymmetric: true,
+	}); err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
+
+// Test_Client_CreateFromReader_asymmetric verifies that the streaming create
+// path also wraps its DEK asymmetrically and reads back correctly through
+// AccessReader.
+func Test_Client_CreateFromReader_asymmetric(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	akm := newMemAsymmetricKeyManager(t)
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), akm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("my streamed secret value")
+	if _, err := client.CreateFromReader(ctx, &CreateFromReaderRequest{
+		Bucket:     "my-bucket",
+		Object:     "my-object",
+		Key:        "my-key",
+		Asymmetric: true,
+	}, bytes.NewReader(plaintext)); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := client.AccessReader(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(plaintext) {
+		t.Errorf("plaintext = %q, want %q", got, plaintext)
+	}
+}
@@ -0,0 +1,339 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/x509"
+	"encoding/pem"
+	"hash"
+	"io/ioutil"
+	"time"
+
+	kms "cloud.google.com/go/kms/apiv1"
+	"cloud.google.com/go/storage"
+	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+// StorageObject is a backend-neutral view of a stored secret's ciphertext
+// metadata, analogous to storage.ObjectAttrs but without any GCS-specific
+// fields a SecretStorage implementation would not have.
+type StorageObject struct {
+	Generation     int64
+	Metageneration int64
+	Metadata       map[string]string
+	Created        time.Time
+	Updated        time.Time
+	Size           int64
+	MD5            []byte
+}
+
+// StorageConditions constrains a SecretStorage.WriteObject call, mirroring
+// the GCS preconditions berglas relies on for safe concurrent writes, in a
+// form any backend can implement with a conditional put.
+type StorageConditions struct {
+	// DoesNotExist, if true, requires that no object currently exists at this
+	// name. Used when creating a brand new secret.
+	DoesNotExist bool
+
+	// GenerationMatch and MetagenerationMatch, meaningful only when
+	// DoesNotExist is false, require the existing object to be at exactly
+	// this generation and metageneration. Used by Update's compare-and-swap
+	// retry loop to detect a concurrent writer.
+	GenerationMatch     int64
+	MetagenerationMatch int64
+}
+
+// SecretStorage is the storage layer a Client uses to read and write a
+// secret's (already encrypted) contents and metadata. berglas's default
+// Client, constructed with New or NewClient, implements this with Cloud
+// Storage; implement it yourself and pass it to NewWithBackends to store
+// berglas secrets somewhere else (e.g. S3) without forking the client.
+//
+// A SecretStorage only needs to support the lowest-common-denominator of
+// object storage: conditional writes keyed on a generation and
+// metageneration counter, and metadata alongside an opaque blob. It has no
+// knowledge of envelope encryption - see KeyManager for that layer.
+type SecretStorage interface {
+	// StatObject returns the metadata for the object at the given generation
+	// (or the latest generation if generation is 0), without downloading its
+	// contents. It returns an error satisfying IsSecretDoesNotExistErr if the
+	// object does not exist.
+	StatObject(ctx context.Context, bucket, object string, generation int64) (*StorageObject, error)
+
+	// ReadObject returns the raw (still-encrypted) contents and metadata of
+	// the object at the given generation (or the latest generation if
+	// generation is 0). It returns an error satisfying
+	// IsSecretDoesNotExistErr if the object does not exist.
+	ReadObject(ctx context.Context, bucket, object string, generation int64) ([]byte, *StorageObject, error)
+
+	// WriteObject writes data as the contents of bucket/object with the given
+	// metadata, subject to conds. It returns an error satisfying
+	// IsSecretAlreadyExistsErr or IsSecretModifiedErr if conds was not met.
+	WriteObject(ctx context.Context, bucket, object string, data []byte, metadata map[string]string, conds StorageConditions) (*StorageObject, error)
+
+	// UpdateMetadata updates only the metadata of the object at the given
+	// generation, subject to a metageneration match, without touching its
+	// contents. It is used for best-effort bookkeeping (e.g. last-accessed
+	// time); callers do not treat its failure as fatal.
+	UpdateMetadata(ctx context.Context, bucket, object string, generation, metageneration int64, metadata map[string]string) error
+}
+
+// KeyManager is the envelope-encryption key-wrapping layer a Client uses to
+// protect each secret's randomly generated data encryption key (DEK).
+// berglas's default Client, constructed with New or NewClient, implements
+// this with Cloud KMS; implement it yourself and pass it to
+// NewWithBackends to wrap DEKs with a different key management service.
+//
+// KeyManager never sees a secret's plaintext value, only its DEK - the
+// plaintext itself is always encrypted locally by the Client before any
+// KeyManager or SecretStorage call is made.
+type KeyManager interface {
+	// Encrypt wraps plaintext (a DEK) under the given key, binding aad to the
+	// resulting ciphertext so it cannot be unwrapped under a different aad.
+	Encrypt(ctx context.Context, key string, plaintext, aad []byte) ([]byte, error)
+
+	// Decrypt unwraps ciphertext produced by Encrypt, verifying it was bound
+	// to the given aad.
+	Decrypt(ctx context.Context, key string, ciphertext, aad []byte) ([]byte, error)
+}
+
+// AsymmetricKeyManager is implemented by a KeyManager that can also wrap a
+// DEK under an asymmetric Cloud KMS key's public half and unwrap it with the
+// matching private half. berglas's default KeyManager implements it; a
+// KeyManager passed to NewWithBackends that does not causes
+// CreateRequest.Asymmetric to fail with an error satisfying
+// IsBackendUnsupportedErr.
+//
+// There is no aad parameter here, unlike KeyManager: Cloud KMS's asymmetric
+// encrypt/decrypt API has no AAD concept, so an asymmetrically wrapped DEK
+// relies solely on the secret's content-level AAD (see envelopeAAD) for the
+// binding KeyManager.Encrypt gets for free.
+type AsymmetricKeyManager interface {
+	KeyManager
+
+	// EncryptAsymmetric wraps plaintext (a DEK) with the public half of key,
+	// fetched via GetPublicKey. Unlike Encrypt, this performs no Cloud KMS
+	// call that a writer could lack permission for - RSA-OAEP encryption
+	// needs only the public key - so a caller with no decrypt permission on
+	// key can still create secrets with it.
+	EncryptAsymmetric(ctx context.Context, key string, plaintext []byte) ([]byte, error)
+
+	// DecryptAsymmetric unwraps ciphertext produced by EncryptAsymmetric
+	// using key's private half, which Cloud KMS never exposes outside the
+	// AsymmetricDecrypt RPC.
+	DecryptAsymmetric(ctx context.Context, key string, ciphertext []byte) ([]byte, error)
+}
+
+// gcsSecretStorage is the default SecretStorage, backed by Cloud Storage.
+type gcsSecretStorage struct {
+	client *storage.Client
+}
+
+func (s *gcsSecretStorage) StatObject(ctx context.Context, bucket, object string, generation int64) (*StorageObject, error) {
+	if generation == 0 {
+		generation = -1
+	}
+
+	attrs, err := s.client.Bucket(bucket).Object(object).Generation(generation).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, errSecretDoesNotExist
+	}
+	if err != nil {
+		return nil, err
+	}
+	return storageObjectFromAttrs(attrs), nil
+}
+
+func (s *gcsSecretStorage) ReadObject(ctx context.Context, bucket, object string, generation int64) ([]byte, *StorageObject, error) {
+	if generation == 0 {
+		generation = -1
+	}
+
+	attrs, err := s.client.Bucket(bucket).Object(object).Generation(generation).Attrs(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, nil, errSecretDoesNotExist
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ior, err := s.client.Bucket(bucket).Object(object).Generation(generation).NewReader(ctx)
+	if err == storage.ErrObjectNotExist {
+		return nil, nil, errSecretDoesNotExist
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	defer ior.Close()
+
+	data, err := ioutil.ReadAll(ior)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, storageObjectFromAttrs(attrs), nil
+}
+
+func (s *gcsSecretStorage) WriteObject(ctx context.Context, bucket, object string, data []byte, metadata map[string]string, conds StorageConditions) (*StorageObject, error) {
+	var gcsConds storage.Conditions
+	if conds.DoesNotExist {
+		gcsConds = storage.Conditions{DoesNotExist: true}
+	} else {
+		gcsConds = storage.Conditions{
+			GenerationMatch:     conds.GenerationMatch,
+			MetagenerationMatch: conds.MetagenerationMatch,
+		}
+	}
+
+	iow := s.client.Bucket(bucket).Object(object).If(gcsConds).NewWriter(ctx)
+	iow.ObjectAttrs.CacheControl = CacheControl
+	iow.ChunkSize = ChunkSize
+	iow.Metadata = metadata
+
+	if _, err := iow.Write(data); err != nil {
+		return nil, err
+	}
+
+	if err := iow.Close(); err != nil {
+		if terr, ok := err.(*googleapi.Error); ok && terr.Code == 412 {
+			if conds.DoesNotExist {
+				return nil, errSecretAlreadyExists
+			}
+			return nil, errSecretModified
+		}
+		return nil, err
+	}
+
+	return storageObjectFromAttrs(iow.Attrs()), nil
+}
+
+func (s *gcsSecretStorage) UpdateMetadata(ctx context.Context, bucket, object string, generation, metageneration int64, metadata map[string]string) error {
+	_, err := s.client.
+		Bucket(bucket).
+		Object(object).
+		Generation(generation).
+		If(storage.Conditions{MetagenerationMatch: metageneration}).
+		Update(ctx, storage.ObjectAttrsToUpdate{Metadata: metadata})
+	return err
+}
+
+// storageObjectFromAttrs converts GCS-specific attrs into the backend-
+// neutral StorageObject representation.
+func storageObjectFromAttrs(attrs *storage.ObjectAttrs) *StorageObject {
+	return &StorageObject{
+		Generation:     attrs.Generation,
+		Metageneration: attrs.Metageneration,
+		Metadata:       attrs.Metadata,
+		Created:        attrs.Created,
+		Updated:        attrs.Updated,
+		Size:           attrs.Size,
+		MD5:            attrs.MD5,
+	}
+}
+
+// kmsKeyManager is the default KeyManager, backed by Cloud KMS.
+type kmsKeyManager struct {
+	client *kms.KeyManagementClient
+}
+
+func (k *kmsKeyManager) Encrypt(ctx context.Context, key string, plaintext, aad []byte) ([]byte, error) {
+	recordKMSCall()
+	resp, err := k.client.Encrypt(ctx, &kmspb.EncryptRequest{
+		Name:                        key,
+		Plaintext:                   plaintext,
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Ciphertext, nil
+}
+
+func (k *kmsKeyManager) Decrypt(ctx context.Context, key string, ciphertext, aad []byte) ([]byte, error) {
+	recordKMSCall()
+	resp, err := k.client.Decrypt(ctx, &kmspb.DecryptRequest{
+		Name:                        key,
+		Ciphertext:                  ciphertext,
+		AdditionalAuthenticatedData: aad,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+func (k *kmsKeyManager) EncryptAsymmetric(ctx context.Context, key string, plaintext []byte) ([]byte, error) {
+	recordKMSCall()
+	resp, err := k.client.GetPublicKey(ctx, &kmspb.GetPublicKeyRequest{Name: key})
+	if err != nil {
+		return nil, err
+	}
+
+	pub, h, err := parseAsymmetricPublicKey(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return rsa.EncryptOAEP(h, rand.Reader, pub, plaintext, nil)
+}
+
+func (k *kmsKeyManager) DecryptAsymmetric(ctx context.Context, key string, ciphertext []byte) ([]byte, error) {
+	recordKMSCall()
+	resp, err := k.client.AsymmetricDecrypt(ctx, &kmspb.AsymmetricDecryptRequest{
+		Name:       key,
+		Ciphertext: ciphertext,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp.Plaintext, nil
+}
+
+// parseAsymmetricPublicKey decodes a GetPublicKey response's PEM-encoded
+// public key and returns it alongside the OAEP hash its algorithm calls for,
+// so EncryptAsymmetric's local RSA-OAEP encryption matches what the Cloud
+// KMS key version's AsymmetricDecrypt expects on the other end.
+func parseAsymmetricPublicKey(pk *kmspb.PublicKey) (*rsa.PublicKey, hash.Hash, error) {
+	block, _ := pem.Decode([]byte(pk.Pem))
+	if block == nil {
+		return nil, nil, errors.New("failed to decode public key PEM block")
+	}
+
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "failed to parse public key")
+	}
+
+	pub, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, nil, errors.Errorf("asymmetric key is not RSA: %T", parsed)
+	}
+
+	switch pk.Algorithm {
+	case kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_2048_SHA256, kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_3072_SHA256, kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA256:
+		return pub, sha256.New(), nil
+	case kmspb.CryptoKeyVersion_RSA_DECRYPT_OAEP_4096_SHA512:
+		return pub, sha512.New(), nil
+	default:
+		return nil, nil, errors.Errorf("unsupported asymmetric key algorithm: %s", pk.Algorithm)
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"errors"
+	"sync"
+	"testing"
+)
+
+// memSecretStorage is a trivial in-memory SecretStorage used to exercise
+// NewWithBackends without any real GCS dependency.
+type memSecretStorage struct {
+	mu      sync.Mutex
+	objects map[string]*memObject
+}
+
+type memObject struct {
+	data           []byte
+	metadata       map[string]string
+	generation     int64
+	metageneration int64
+}
+
+func newMemSecretStorage() *memSecretStorage {
+	return &memSecretStorage{objects: make(map[string]*memObject)}
+}
+
+func (m *memSecretStorage) key(bucket, object string) string {
+	return bucket + "/" + object
+}
+
+func (m *memSecretStorage) StatObject(ctx context.Context, bucket, object string, generation int64) (*StorageObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[m.key(bucket, object)]
+	if !ok {
+		return nil, errSecretDoesNotExist
+	}
+	return &StorageObject{Generation: obj.generation, Metageneration: obj.metageneration, Metadata: obj.metadata}, nil
+}
+
+func (m *memSecretStorage) ReadObject(ctx context.Context, bucket, object string, generation int64) ([]byte, *StorageObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[m.key(bucket, object)]
+	if !ok {
+		return nil, nil, errSecretDoesNotExist
+	}
+	return obj.data, &StorageObject{Generation: obj.generation, Metageneration: obj.metageneration, Metadata: obj.metadata}, nil
+}
+
+func (m *memSecretStorage) WriteObject(ctx context.Context, bucket, object string, data []byte, metadata map[string]string, conds StorageConditions) (*StorageObject, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	k := m.key(bucket, object)
+	existing, ok := m.objects[k]
+
+	if conds.DoesNotExist {
+		if ok {
+			return nil, errSecretAlreadyExists
+		}
+	} else if !ok || existing.generation != conds.GenerationMatch || existing.metageneration != conds.MetagenerationMatch {
+		return nil, errSecretModified
+	}
+
+	generation := int64(1)
+	if ok {
+		generation = existing.generation + 1
+	}
+
+	obj := &memObject{data: data, metadata: metadata, generation: generation, metageneration: 1}
+	m.objects[k] = obj
+
+	return &StorageObject{Generation: obj.generation, Metageneration: obj.metageneration, Metadata: obj.metadata}, nil
+}
+
+func (m *memSecretStorage) UpdateMetadata(ctx context.Context, bucket, object string, generation, metageneration int64, metadata map[string]string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	obj, ok := m.objects[m.key(bucket, object)]
+	if !ok || obj.metageneration != metageneration {
+		return errSecretModified
+	}
+	obj.metadata = metadata
+	obj.metageneration++
+	return nil
+}
+
+// memKeyManager is a trivial, insecure KeyManager (it "wraps" a DEK by
+// returning it unchanged) used to exercise NewWithBackends without any real
+// KMS dependency.
+type memKeyManager struct{}
+
+func (m *memKeyManager) Encrypt(ctx context.Context, key string, plaintext, aad []byte) ([]byte, error) {
+	return plaintext, nil
+}
+
+func (m *memKeyManager) Decrypt(ctx context.Context, key string, ciphertext, aad []byte) ([]byte, error) {
+	return ciphertext, nil
+}
+
+// memAsymmetricKeyManager is a memKeyManager that also implements
+// AsymmetricKeyManager with a real (but tiny, test-only) RSA key pair, used
+// to exercise the asymmetric wrap/unwrap path without any real Cloud KMS
+// dependency.
+type memAsymmetricKeyManager struct {
+	memKeyManager
+	key *rsa.PrivateKey
+}
+
+func newMemAsymmetricKeyManager(t *testing.T) *memAsymmetricKeyManager {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &memAsymmetricKeyManager{key: key}
+}
+
+func (m *memAsymmetricKeyManager) EncryptAsymmetric(ctx context.Context, key string, plaintext []byte) ([]byte, error) {
+	return rsa.EncryptOAEP(sha256.New(), rand.Reader, &m.key.PublicKey, plaintext, nil)
+}
+
+func (m *memAsymmetricKeyManager) DecryptAsymmetric(ctx context.Context, key string, ciphertext []byte) ([]byte, error) {
+	return rsa.DecryptOAEP(sha256.New(), rand.Reader, m.key, ciphertext, nil)
+}
+
+// memKeyManagerWithOutage is a memKeyManager whose Decrypt fails for any key
+// listed in downKeys, to simulate a KMS key (or region) outage for
+// Test_Client_Read_additionalKeys_outageFallback.
+type memKeyManagerWithOutage struct {
+	memKeyManager
+	downKeys map[string]bool
+}
+
+func (m *memKeyManagerWithOutage) Decrypt(ctx context.Context, key string, ciphertext, aad []byte) ([]byte, error) {
+	if m.downKeys[key] {
+		return nil, errors.New("simulated kms outage")
+	}
+	return m.memKeyManager.Decrypt(ctx, key, ciphertext, aad)
+}
+
+func TestNewWithBackends(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("my secret value")
+	createdSecret, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: plaintext,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	readSecret, err := client.Read(ctx, &ReadRequest{
+		Bucket: "my-bucket",
+		Object: "my-object",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := readSecret.Plaintext, plaintext; !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+	if act, exp := readSecret.Generation, createdSecret.Generation; act != exp {
+		t.Errorf("expected generation %d to be %d", act, exp)
+	}
+
+	updatedSecret, err := client.Update(ctx, &UpdateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Plaintext: []byte("my new secret value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := updatedSecret.Plaintext, []byte("my new secret value"); !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	if err := client.Grant(ctx, &GrantRequest{Bucket: "my-bucket", Object: "my-object", Members: []string{"user:a@example.com"}}); !IsBackendUnsupportedErr(err) {
+		t.Errorf("expected backend-unsupported error, got %v", err)
+	}
+	if _, err := client.List(ctx, &ListRequest{Bucket: "my-bucket"}); !IsBackendUnsupportedErr(err) {
+		t.Errorf("expected backend-unsupported error, got %v", err)
+	}
+}
+
+func TestNewWithBackends_missingBackend(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	if _, err := NewWithBackends(ctx, nil, &memKeyManager{}); err == nil {
+		t.Fatal("expected an error for a missing storage backend")
+	}
+
+	if _, err := NewWithBackends(ctx, newMemSecretStorage(), nil); err == nil {
+		t.Fatal("expected an error for a missing key manager backend")
+	}
+}
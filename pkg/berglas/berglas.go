@@ -20,7 +20,12 @@ import (
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
 	"io"
+	"net/http"
 	"os"
 	"strings"
 	"sync"
@@ -30,7 +35,9 @@ import (
 	"cloud.google.com/go/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
+	"golang.org/x/oauth2/google"
 	"google.golang.org/api/option"
+	secretmanagerv1 "google.golang.org/api/secretmanager/v1"
 	storagev1 "google.golang.org/api/storage/v1"
 )
 
@@ -60,51 +67,373 @@ const (
 	// MetadataKMSKey is the key in the metadata where the name of the KMS key is
 	// stored.
 	MetadataKMSKey = "berglas-kms-key"
+
+	// MetadataKMSKeyVersionKey is the key in the object metadata recording
+	// which CryptoKeyVersion of MetadataKMSKey encrypted a secret, if --key
+	// was pinned to one (e.g.
+	// ".../cryptoKeys/my-key/cryptoKeyVersions/3"). Its absence means the
+	// secret was encrypted against whichever version was primary for
+	// MetadataKMSKey at the time - the common case, and the only one
+	// possible for local development and passphrase keys. See
+	// Secret.KMSKeyVersion and the "key-report" command.
+	MetadataKMSKeyVersionKey = "berglas-kms-key-version"
+
+	// MetadataRetainUntilKey is the key in the object metadata where a
+	// secret's retain-until timestamp, if any, is stored as RFC 3339 text.
+	// See CreateRequest.RetainUntil.
+	MetadataRetainUntilKey = "berglas-retain-until"
+
+	// MetadataLastAccessedKey is the key in the object metadata where a
+	// secret's last-accessed timestamp, if tracked, is stored as RFC 3339
+	// text. See Secret.LastAccessed.
+	MetadataLastAccessedKey = "berglas-last-accessed"
+
+	// MetadataEnvelopeFormatKey is the key in the object metadata recording
+	// which EnvelopeFormat a secret's ciphertext is encoded in. Its absence
+	// means EnvelopeFormatDefault: only non-default formats are recorded, so
+	// secrets written before a given format existed keep reading correctly
+	// without a metadata migration. See Secret.Format.
+	MetadataEnvelopeFormatKey = "berglas-envelope-format"
+
+	// MetadataAADKey is the key in the object metadata recording which
+	// additional authenticated data (AAD) scheme binds a secret's wrapped DEK
+	// and content ciphertext. Its absence means the legacy scheme that
+	// predates this key, where the wrapped DEK is bound to the object name
+	// alone and the content ciphertext is not AAD-bound at all - so secrets
+	// written before this existed keep reading correctly. See envelopeAAD.
+	MetadataAADKey = "berglas-envelope-aad"
+
+	// envelopeAADVersion1 is the current AAD scheme: both the wrapped DEK and
+	// the content ciphertext are bound to the secret's "bucket/object", so a
+	// privileged attacker with direct storage access can no longer
+	// substitute one secret's ciphertext (or its wrapped DEK) for another's
+	// without decryption failing. Recorded in MetadataAADKey.
+	envelopeAADVersion1 = "1"
+
+	// MetadataIntegrityKey is the key in the object metadata where a
+	// secret's plaintext SHA-256 digest, hex-encoded, is stored. Verified on
+	// every read; a mismatch surfaces ErrIntegrityCheckFailed. Its absence
+	// means the secret predates this key, in which case no check is
+	// performed. This guards against a customer-managed storage backend
+	// returning data it was not asked to, not against the same storage
+	// backend that issued the wrapped DEK substituting ciphertext, which AAD
+	// binding (see MetadataAADKey) already rules out.
+	MetadataIntegrityKey = "berglas-integrity-sha256"
+
+	// MetadataAsymmetricKey is the key in the object metadata recording that
+	// a secret's DEK was wrapped with an asymmetric Cloud KMS key's public
+	// half (see CreateRequest.Asymmetric) rather than encrypted with
+	// KeyManager.Encrypt. Its absence means the ordinary symmetric wrap.
+	// Present secrets are unwrapped with AsymmetricKeyManager.DecryptAsymmetric
+	// instead of Decrypt.
+	MetadataAsymmetricKey = "berglas-asymmetric"
+
+	// MetadataAdditionalKeysKey is the key in the object metadata where a
+	// secret's DEK, independently wrapped under each of CreateRequest's
+	// AdditionalKeys, is stored as a JSON array (see additionalDEK). Its
+	// absence means the secret was wrapped under a single key, as recorded
+	// in MetadataKMSKey alone. Read and Access try each of these, in order,
+	// if unwrapping the primary key's DEK fails, so a single KMS key (or
+	// region) outage does not make the secret unreadable.
+	MetadataAdditionalKeysKey = "berglas-additional-keys"
+
+	// lastAccessedSampleInterval is the minimum time between last-accessed
+	// metadata writes for the same secret, so a secret read many times a
+	// second doesn't generate a metadata write on every read.
+	lastAccessedSampleInterval = 1 * time.Hour
 )
 
 // Client is a berglas client
 type Client struct {
-	kmsClient        *kms.KeyManagementClient
-	storageClient    *storage.Client
-	storageIAMClient *storagev1.Service
+	kmsClient           *kms.KeyManagementClient
+	storageClient       *storage.Client
+	storageIAMClient    *storagev1.Service
+	secretManagerClient *secretmanagerv1.Service
+
+	// secretStorage and keyManager are the backends a Client's core secret
+	// CRUD path (Read, Create, Update) is actually written against. New and
+	// NewClient populate these with the Cloud Storage- and Cloud KMS-backed
+	// implementations above; NewWithBackends lets a caller supply their own.
+	secretStorage SecretStorage
+	keyManager    KeyManager
 
 	loggerLock sync.RWMutex
-	logger     *logrus.Logger
+	logger     Logger
+
+	// retryPolicy governs how transient Cloud Storage and Cloud KMS failures
+	// are retried. See RetryPolicy and WithRetryPolicy.
+	retryPolicyLock sync.RWMutex
+	retryPolicy     RetryPolicy
+
+	// timeout bounds how long a single operation (including every attempt
+	// permitted by retryPolicy) may take before returning
+	// context.DeadlineExceeded, instead of blocking on a hung Cloud Storage or
+	// Cloud KMS connection indefinitely. Zero (the default) means no timeout
+	// beyond the caller's own context. See WithTimeout.
+	timeoutLock sync.RWMutex
+	timeout     time.Duration
+
+	// cache, if non-nil, is an opt-in cache of resolved secret plaintexts
+	// consulted by Access. See WithCache.
+	cacheLock sync.RWMutex
+	cache     *secretCache
+
+	// diskCache, if non-nil, is an opt-in encrypted on-disk fallback consulted
+	// by Access when a live resolution fails. See WithDiskCache.
+	diskCacheLock sync.RWMutex
+	diskCache     *diskCache
+
+	// userAgent, httpClient, credentials, scopes, and clientOpts are consumed
+	// once, by New, to build the option.ClientOption values passed to the
+	// underlying Cloud Storage and Cloud KMS clients. They are not read again
+	// afterward, so unlike the fields above they need no lock; applying their
+	// Options (e.g. WithHTTPClient) via ApplyOptions after New has already
+	// returned has no effect.
+	userAgent   string
+	httpClient  *http.Client
+	credentials *google.Credentials
+	scopes      []string
+	clientOpts  []option.ClientOption
+}
+
+// Option configures a Client constructed with New or NewWithBackends.
+type Option func(*Client)
+
+// ApplyOptions applies each of the given Options to c. Options that only
+// take effect during construction (WithHTTPClient, WithCredentials,
+// WithScopes, WithClientOptions, WithUserAgent) have no effect when applied
+// this way, since New has already built the underlying Cloud Storage and
+// Cloud KMS clients by the time ApplyOptions can be called.
+func (c *Client) ApplyOptions(opts ...Option) {
+	for _, opt := range opts {
+		opt(c)
+	}
+}
+
+// WithHTTPClient overrides the HTTP client used to talk to Cloud Storage and
+// Cloud KMS, for example to inject a custom RoundTripper for a proxy or for
+// test instrumentation. Only takes effect when passed to New.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) { c.httpClient = hc }
+}
+
+// WithUserAgent overrides the User-Agent string sent with every Cloud
+// Storage and Cloud KMS request, in place of the default UserAgent. Only
+// takes effect when passed to New.
+func WithUserAgent(ua string) Option {
+	return func(c *Client) { c.userAgent = ua }
+}
+
+// WithCredentials authenticates Cloud Storage and Cloud KMS with creds
+// instead of the ambient application default credentials. Only takes effect
+// when passed to New.
+func WithCredentials(creds *google.Credentials) Option {
+	return func(c *Client) { c.credentials = creds }
+}
+
+// WithScopes overrides the OAuth scopes requested for the ambient
+// application default credentials. Only takes effect when passed to New.
+func WithScopes(scopes ...string) Option {
+	return func(c *Client) { c.scopes = scopes }
+}
+
+// WithClientOptions passes arbitrary option.ClientOption values through to
+// the underlying Cloud Storage and Cloud KMS clients New constructs,
+// bridging option.ClientOption-based configuration - such as
+// WithStorageEndpoint, WithKMSEndpoint, WithQuotaProject, WithImpersonation,
+// or the option package's own helpers - into New's Option-based signature.
+// Only takes effect when passed to New.
+func WithClientOptions(opts ...option.ClientOption) Option {
+	return func(c *Client) { c.clientOpts = append(c.clientOpts, opts...) }
+}
+
+// WithLogger sets the logger a Client uses, equivalent to calling SetLogger
+// immediately after construction. See Logger.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.loggerLock.Lock()
+		c.logger = &redactingLogger{Logger: l}
+		c.loggerLock.Unlock()
+	}
+}
+
+// defaultLogger returns the logger a Client uses unless overridden with
+// WithLogger or SetLogger: JSON to stderr, logging only fatal errors.
+func defaultLogger() Logger {
+	return &redactingLogger{Logger: NewLogrusLogger(&logrus.Logger{
+		Out:          os.Stderr,
+		Formatter:    new(logrus.JSONFormatter),
+		Hooks:        make(logrus.LevelHooks),
+		Level:        logrus.FatalLevel,
+		ReportCaller: true,
+	})}
+}
+
+// backend identifies which underlying API client a backend-scoped
+// option.ClientOption (see WithStorageEndpoint and WithKMSEndpoint) applies
+// to, since New passes the same opts to four different clients and an
+// endpoint override for one must not leak into the others.
+type backend int
+
+const (
+	backendStorage backend = iota
+	backendKMS
+)
+
+// backendOption is an option.ClientOption that only applies to one of New's
+// underlying API clients. It satisfies option.ClientOption itself by
+// embedding one, promoting its Apply method, since option.ClientOption's
+// Apply method takes an argument from an internal package that only
+// google.golang.org/api itself can reference.
+type backendOption struct {
+	option.ClientOption
+	backend backend
+}
+
+// WithStorageEndpoint overrides the Cloud Storage API endpoint the Client
+// dials, for use against fake-gcs-server or another Cloud Storage-compatible
+// emulator in tests and CI instead of the real Cloud Storage service. Unlike
+// the STORAGE_EMULATOR_HOST environment variable, which
+// cloud.google.com/go/storage already honors natively by also switching to
+// unauthenticated HTTP, this option only overrides the endpoint URL - pair
+// it with option.WithoutAuthentication() if the emulator does not check
+// credentials either. It only affects the Client's object read/write path
+// (Read, Access, Create, Update, Delete); Grant, Revoke, and IAMMembers
+// still talk to the real Cloud Storage IAM API, since emulators generally
+// do not implement it.
+func WithStorageEndpoint(endpoint string) option.ClientOption {
+	return &backendOption{ClientOption: option.WithEndpoint(endpoint), backend: backendStorage}
+}
+
+// WithKMSEndpoint overrides the Cloud KMS API endpoint the Client dials, for
+// use against a local KMS emulator in tests and CI instead of the real Cloud
+// KMS service. Cloud KMS's client library has no built-in emulator
+// convention like Cloud Storage's STORAGE_EMULATOR_HOST, so a KMS emulator
+// typically also needs option.WithGRPCDialOption(grpc.WithInsecure()) and
+// option.WithoutAuthentication() to skip TLS and credential lookup.
+func WithKMSEndpoint(endpoint string) option.ClientOption {
+	return &backendOption{ClientOption: option.WithEndpoint(endpoint), backend: backendKMS}
+}
+
+// WithQuotaProject returns an option.ClientOption that sets the
+// X-Goog-User-Project header (the billing/quota project) on Cloud Storage
+// and Cloud KMS calls, letting a caller with only user credentials in a VPC
+// Service Controls or requester-pays project access secrets without a
+// service account key file. It is a thin wrapper around
+// option.WithQuotaProject, applied to every underlying client.
+func WithQuotaProject(project string) option.ClientOption {
+	return option.WithQuotaProject(project)
+}
+
+// splitBackendOpts separates opts into the options every client should
+// receive and the options scoped to a single backend via WithStorageEndpoint
+// or WithKMSEndpoint.
+func splitBackendOpts(opts []option.ClientOption) (generic, storageOpts, kmsOpts []option.ClientOption) {
+	for _, opt := range opts {
+		bo, ok := opt.(*backendOption)
+		if !ok {
+			generic = append(generic, opt)
+			continue
+		}
+		switch bo.backend {
+		case backendStorage:
+			storageOpts = append(storageOpts, bo.ClientOption)
+		case backendKMS:
+			kmsOpts = append(kmsOpts, bo.ClientOption)
+		}
+	}
+	return generic, storageOpts, kmsOpts
 }
 
-// New creates a new berglas client.
-func New(ctx context.Context, opts ...option.ClientOption) (*Client, error) {
-	opts = append(opts, option.WithUserAgent(UserAgent))
+// New creates a new berglas client. Its behavior is configured with opts -
+// see WithHTTPClient, WithUserAgent, WithCredentials, WithScopes,
+// WithLogger, WithRetryPolicy, WithTimeout, and WithClientOptions (for
+// anything else the option package itself exposes, plus
+// WithStorageEndpoint, WithKMSEndpoint, WithQuotaProject, and
+// WithImpersonation).
+func New(ctx context.Context, opts ...Option) (*Client, error) {
+	c := &Client{
+		userAgent: UserAgent,
+		logger:    defaultLogger(),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	clientOpts := append([]option.ClientOption{option.WithUserAgent(c.userAgent)}, c.clientOpts...)
+	if c.httpClient != nil {
+		clientOpts = append(clientOpts, option.WithHTTPClient(c.httpClient))
+	}
+	if c.credentials != nil {
+		clientOpts = append(clientOpts, option.WithCredentials(c.credentials))
+	}
+	if len(c.scopes) > 0 {
+		clientOpts = append(clientOpts, option.WithScopes(c.scopes...))
+	}
 
-	var c Client
+	generic, storageOpts, kmsOpts := splitBackendOpts(clientOpts)
 
-	kmsClient, err := kms.NewKeyManagementClient(ctx, opts...)
+	kmsClient, err := kms.NewKeyManagementClient(ctx, append(append([]option.ClientOption{}, generic...), kmsOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create kms client")
 	}
 	c.kmsClient = kmsClient
 
-	storageClient, err := storage.NewClient(ctx, opts...)
+	storageClient, err := storage.NewClient(ctx, append(append([]option.ClientOption{}, generic...), storageOpts...)...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create storage client")
 	}
 	c.storageClient = storageClient
 
-	storageIAMClient, err := storagev1.NewService(ctx, opts...)
+	storageIAMClient, err := storagev1.NewService(ctx, generic...)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create storagev1 client")
 	}
 	c.storageIAMClient = storageIAMClient
 
-	c.logger = &logrus.Logger{
-		Out:          os.Stderr,
-		Formatter:    new(logrus.JSONFormatter),
-		Hooks:        make(logrus.LevelHooks),
-		Level:        logrus.FatalLevel,
-		ReportCaller: true,
+	secretManagerClient, err := secretmanagerv1.NewService(ctx, generic...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create secretmanager client")
+	}
+	c.secretManagerClient = secretManagerClient
+
+	c.secretStorage = &gcsSecretStorage{client: storageClient}
+	c.keyManager = &kmsKeyManager{client: kmsClient}
+
+	return c, nil
+}
+
+// NewWithBackends creates a berglas client backed by the given SecretStorage
+// and KeyManager implementations instead of Cloud Storage and Cloud KMS,
+// for embedders that want to store berglas secrets somewhere else (e.g. S3)
+// without forking the whole Client.
+//
+// The resulting Client only supports the core secret CRUD path: Read,
+// Access, Create, and Update (including the MergeFunc retry loop). Methods
+// that are inherently specific to Cloud Storage and Cloud KMS - Grant,
+// Revoke, IAMMembers, List, Delete, Move, Bootstrap, Discover, ListBuckets,
+// and the Secret Manager (SM-prefixed) methods - have no backend-neutral
+// equivalent yet and return an error satisfying IsMisuseErr.
+func NewWithBackends(ctx context.Context, storage SecretStorage, kms KeyManager, opts ...Option) (*Client, error) {
+	if storage == nil {
+		return nil, errors.New("missing secret storage backend")
+	}
+	if kms == nil {
+		return nil, errors.New("missing key manager backend")
+	}
+
+	c := &Client{
+		userAgent: UserAgent,
+		logger:    defaultLogger(),
+	}
+	c.secretStorage = storage
+	c.keyManager = kms
+
+	for _, opt := range opts {
+		opt(c)
 	}
 
-	return &c, nil
+	return c, nil
 }
 
 // Secret represents a specific secret stored in Google Cloud Storage
@@ -120,29 +449,177 @@ type Secret struct {
 	// KMSKey is the key used to encrypt the secret key
 	KMSKey string
 
+	// KMSKeyVersion is the CryptoKeyVersion of KMSKey that encrypted this
+	// secret, if --key was pinned to one (see MetadataKMSKeyVersionKey). It
+	// is "" if the secret was encrypted against whichever version was
+	// primary for KMSKey at the time.
+	KMSKeyVersion string
+
 	// Metageneration indicates a secret's metageneration
 	Metageneration int64
 
 	// Plaintext value of the secret (may not be filled in)
 	Plaintext []byte
 
+	// RetainUntil, if non-zero, is the time before which this secret must not
+	// be overwritten or deleted. See CreateRequest.RetainUntil.
+	RetainUntil time.Time
+
+	// LastAccessed is the last time this secret was read, if tracked. It is
+	// updated with coarse sampling (see lastAccessedSampleInterval), so it may
+	// lag the true last access time by up to that interval. It is the zero
+	// time if the secret has never been read since last-accessed tracking
+	// began, which includes every secret that predates this field.
+	LastAccessed time.Time
+
 	// UpdatedAt indicates when a secret was last updated
 	UpdatedAt time.Time
+
+	// CreatedAt indicates when this generation of the secret was created. For
+	// a secret's current generation this is usually the same as UpdatedAt,
+	// since "update" creates a new generation rather than mutating in place;
+	// they can differ after operations (like a metadata-only change) that
+	// bump UpdatedAt without creating a new generation.
+	CreatedAt time.Time
+
+	// Size is the size, in bytes, of the encrypted object stored in Cloud
+	// Storage. It is always somewhat larger than the plaintext, since it
+	// includes the envelope-encrypted DEK and ciphertext framing.
+	Size int64
+
+	// MD5 is the MD5 checksum of the encrypted object, as reported by Cloud
+	// Storage. Because each secret is envelope-encrypted with its own
+	// randomly generated DEK (see envelopeEncrypt), two secrets sharing a
+	// plaintext value will NOT share this checksum; it is only useful for
+	// detecting byte-for-byte duplicate ciphertext, e.g. a secret copied
+	// with "berglas cp" rather than re-created.
+	MD5 []byte
+
+	// Format is the envelope format the secret's ciphertext is encoded in.
+	// See MetadataEnvelopeFormatKey.
+	Format EnvelopeFormat
+
+	// Asymmetric is true if the secret's DEK was wrapped with an asymmetric
+	// Cloud KMS key's public half instead of being encrypted with
+	// KeyManager.Encrypt. See CreateRequest.Asymmetric.
+	Asymmetric bool
+
+	// AdditionalKMSKeys lists any extra keys the secret's DEK is
+	// independently wrapped under, beyond the primary KMSKey. See
+	// CreateRequest.AdditionalKeys.
+	AdditionalKMSKeys []string
 }
 
 // secretFromAttrs constructs a secret from the given object attributes and
 // plaintext.
 func secretFromAttrs(attrs *storage.ObjectAttrs, plaintext []byte) *Secret {
 	return &Secret{
-		Name:           attrs.Name,
-		Generation:     attrs.Generation,
-		Metageneration: attrs.Metageneration,
-		UpdatedAt:      attrs.Updated,
-		KMSKey:         attrs.Metadata[MetadataKMSKey],
-		Plaintext:      plaintext,
+		Name:              attrs.Name,
+		Generation:        attrs.Generation,
+		Metageneration:    attrs.Metageneration,
+		UpdatedAt:         attrs.Updated,
+		CreatedAt:         attrs.Created,
+		Size:              attrs.Size,
+		MD5:               attrs.MD5,
+		KMSKey:            attrs.Metadata[MetadataKMSKey],
+		KMSKeyVersion:     attrs.Metadata[MetadataKMSKeyVersionKey],
+		RetainUntil:       retainUntilFromMetadata(attrs.Metadata),
+		LastAccessed:      lastAccessedFromMetadata(attrs.Metadata),
+		Format:            EnvelopeFormat(attrs.Metadata[MetadataEnvelopeFormatKey]),
+		Asymmetric:        attrs.Metadata[MetadataAsymmetricKey] == "1",
+		AdditionalKMSKeys: additionalKMSKeysFromMetadata(attrs.Metadata),
+		Plaintext:         plaintext,
 	}
 }
 
+// secretFromStorageObject constructs a secret from the given backend-neutral
+// StorageObject and plaintext. It is the SecretStorage-backed counterpart to
+// secretFromAttrs, used by the core Read/encryptAndWrite path that goes
+// through a Client's secretStorage rather than its storageClient directly.
+func secretFromStorageObject(object string, obj *StorageObject, plaintext []byte) *Secret {
+	return &Secret{
+		Name:              object,
+		Generation:        obj.Generation,
+		Metageneration:    obj.Metageneration,
+		UpdatedAt:         obj.Updated,
+		CreatedAt:         obj.Created,
+		Size:              obj.Size,
+		MD5:               obj.MD5,
+		KMSKey:            obj.Metadata[MetadataKMSKey],
+		KMSKeyVersion:     obj.Metadata[MetadataKMSKeyVersionKey],
+		RetainUntil:       retainUntilFromMetadata(obj.Metadata),
+		LastAccessed:      lastAccessedFromMetadata(obj.Metadata),
+		Format:            EnvelopeFormat(obj.Metadata[MetadataEnvelopeFormatKey]),
+		Asymmetric:        obj.Metadata[MetadataAsymmetricKey] == "1",
+		AdditionalKMSKeys: additionalKMSKeysFromMetadata(obj.Metadata),
+		Plaintext:         plaintext,
+	}
+}
+
+// additionalKMSKeysFromMetadata parses the KMS key names out of
+// MetadataAdditionalKeysKey's JSON array, for Secret.AdditionalKMSKeys. It
+// returns nil if the metadata key is absent or malformed, rather than
+// failing the read: this is surfaced for visibility, not relied on to
+// unwrap anything (see unwrapAnyDEK).
+func additionalKMSKeysFromMetadata(meta map[string]string) []string {
+	raw := meta[MetadataAdditionalKeysKey]
+	if raw == "" {
+		return nil
+	}
+
+	var entries []additionalDEK
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil
+	}
+
+	keys := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		keys = append(keys, entry.Key)
+	}
+	return keys
+}
+
+// retainUntilFromMetadata parses the MetadataRetainUntilKey value out of a
+// storage object's metadata, returning the zero time if it is absent or
+// malformed.
+func retainUntilFromMetadata(meta map[string]string) time.Time {
+	s := meta[MetadataRetainUntilKey]
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// lastAccessedFromMetadata parses the MetadataLastAccessedKey value out of a
+// storage object's metadata, returning the zero time if it is absent or
+// malformed.
+func lastAccessedFromMetadata(meta map[string]string) time.Time {
+	s := meta[MetadataLastAccessedKey]
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// checkRetention returns errSecretRetained if retainUntil is set and has not
+// yet passed, meaning the secret must not be deleted or overwritten.
+func checkRetention(retainUntil time.Time) error {
+	if retainUntil.IsZero() || !retainUntil.After(time.Now()) {
+		return nil
+	}
+	return errors.Wrapf(errSecretRetained, "secret is retained until %s", retainUntil.Format(time.RFC3339))
+}
+
 // kmsKeyIncludesVersion returns true if the given KMS key reference includes
 // a version.
 func kmsKeyIncludesVersion(s string) bool {
@@ -159,9 +636,261 @@ func kmsKeyTrimVersion(s string) string {
 	return strings.Join(parts[0:8], "/")
 }
 
+// kmsKeyVersion returns the version segment of a KMS key reference pinned
+// to a specific CryptoKeyVersion (e.g. "3" for
+// ".../cryptoKeys/my-key/cryptoKeyVersions/3"), or "" if s does not pin a
+// version. For MetadataKMSKeyVersionKey.
+func kmsKeyVersion(s string) string {
+	if !kmsKeyIncludesVersion(s) {
+		return ""
+	}
+
+	parts := strings.SplitN(s, "/", 10)
+	return parts[9]
+}
+
+// envelopeAAD returns the additional authenticated data new secrets are
+// encrypted with: their bucket and object name, so neither the wrapped DEK
+// nor the content ciphertext can be substituted from a different secret
+// without decryption failing. See MetadataAADKey for how existing secrets,
+// written before this existed, are still read correctly.
+func envelopeAAD(bucket, object string) string {
+	return bucket + "/" + object
+}
+
+// readEnvelopeAAD determines the additional authenticated data to unwrap an
+// existing secret's DEK and content ciphertext with, based on meta (an
+// object's metadata). See MetadataAADKey.
+func readEnvelopeAAD(bucket, object string, meta map[string]string) (dekAAD string, contentAAD []byte) {
+	if meta[MetadataAADKey] == envelopeAADVersion1 {
+		aad := envelopeAAD(bucket, object)
+		return aad, []byte(aad)
+	}
+	return object, nil
+}
+
+// integrityDigest returns the hex-encoded SHA-256 digest of plaintext, for
+// recording in MetadataIntegrityKey at write time.
+func integrityDigest(plaintext []byte) string {
+	sum := sha256.Sum256(plaintext)
+	return hex.EncodeToString(sum[:])
+}
+
+// verifyIntegrity checks plaintext against the digest recorded in meta's
+// MetadataIntegrityKey, if any. A secret with no recorded digest - one
+// written before this key existed - is not checked.
+func verifyIntegrity(plaintext []byte, meta map[string]string) error {
+	want := meta[MetadataIntegrityKey]
+	if want == "" {
+		return nil
+	}
+	if got := integrityDigest(plaintext); got != want {
+		return errIntegrityCheckFailed
+	}
+	return nil
+}
+
+// wrapDEK encrypts dek so it can be stored alongside a secret's ciphertext,
+// using the given Cloud KMS key, a local development key when key is a
+// local-key:// or keyring-key:// path, or a passphrase-derived key when key
+// is a passphrase:// value (see PassphraseKeyPrefix) - for field deployments
+// with no Cloud KMS connectivity at all. An age:// key (see AgeKeyPrefix)
+// fails with errAgeKeyUnsupported, since berglas vendors no age library.
+// aad is bound to the resulting ciphertext (see KeyManager.Encrypt) and must
+// be identical when it is later unwrapped with unwrapDEK - berglas passes
+// envelopeAAD(bucket, object) for new secrets, or readEnvelopeAAD's dekAAD
+// when reading an existing one. If asymmetric is true, key is wrapped with
+// AsymmetricKeyManager.EncryptAsymmetric instead, which ignores aad entirely
+// (see AsymmetricKeyManager); it is an error to pass asymmetric with a
+// local-key:// or passphrase:// key, since neither has an asymmetric
+// counterpart.
+func (c *Client) wrapDEK(ctx context.Context, key, aad string, dek []byte, asymmetric bool) ([]byte, error) {
+	if IsLocalKey(key) {
+		if asymmetric {
+			return nil, errors.New("asymmetric encryption is not supported with local development keys")
+		}
+		localKey, err := resolveLocalKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return localKeyEncrypt(localKey, dek)
+	}
+
+	if IsPassphraseKey(key) {
+		if asymmetric {
+			return nil, errors.New("asymmetric encryption is not supported with passphrase keys")
+		}
+		passphrase, err := resolvePassphrase()
+		if err != nil {
+			return nil, err
+		}
+		return passphraseKeyEncrypt(passphrase, dek)
+	}
+
+	if IsAgeKey(key) {
+		return nil, errAgeKeyUnsupported
+	}
+
+	if asymmetric {
+		akm, ok := c.keyManager.(AsymmetricKeyManager)
+		if !ok {
+			return nil, errBackendUnsupported
+		}
+
+		var encDEK []byte
+		err := c.withRetry(ctx, func(ctx context.Context) error {
+			var err error
+			encDEK, err = akm.EncryptAsymmetric(ctx, key, dek)
+			return err
+		})
+		if err != nil {
+			return nil, wrapAPIErr(err, "failed to encrypt secret")
+		}
+		return encDEK, nil
+	}
+
+	var encDEK []byte
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		encDEK, err = c.keyManager.Encrypt(ctx, key, dek, []byte(aad))
+		return err
+	})
+	if err != nil {
+		return nil, wrapAPIErr(err, "failed to encrypt secret")
+	}
+	return encDEK, nil
+}
+
+// unwrapDEK is the inverse of wrapDEK.
+func (c *Client) unwrapDEK(ctx context.Context, key, aad string, encDEK []byte, asymmetric bool) ([]byte, error) {
+	if IsLocalKey(key) {
+		localKey, err := resolveLocalKey(key)
+		if err != nil {
+			return nil, err
+		}
+		return envelopeDecrypt(localKey, encDEK, nil)
+	}
+
+	if IsPassphraseKey(key) {
+		passphrase, err := resolvePassphrase()
+		if err != nil {
+			return nil, err
+		}
+		return passphraseKeyDecrypt(passphrase, encDEK)
+	}
+
+	if IsAgeKey(key) {
+		return nil, errAgeKeyUnsupported
+	}
+
+	if asymmetric {
+		akm, ok := c.keyManager.(AsymmetricKeyManager)
+		if !ok {
+			return nil, errBackendUnsupported
+		}
+
+		var dek []byte
+		err := c.withRetry(ctx, func(ctx context.Context) error {
+			var err error
+			dek, err = akm.DecryptAsymmetric(ctx, key, encDEK)
+			return err
+		})
+		if err != nil {
+			return nil, wrapAPIErr(err, "failed to decrypt dek")
+		}
+		return dek, nil
+	}
+
+	var dek []byte
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		dek, err = c.keyManager.Decrypt(ctx, key, encDEK, []byte(aad))
+		return err
+	})
+	if err != nil {
+		return nil, wrapAPIErr(err, "failed to decrypt dek")
+	}
+	return dek, nil
+}
+
+// additionalDEK is one entry of the JSON array stored in
+// MetadataAdditionalKeysKey: dek wrapped under key, independently of the
+// primary wrap recorded in MetadataKMSKey and the blob's encDEK.
+type additionalDEK struct {
+	Key    string `json:"key"`
+	EncDEK string `json:"enc_dek"`
+}
+
+// wrapAdditionalDEKs wraps dek under each of keys, for recording in
+// MetadataAdditionalKeysKey, and returns the resulting metadata value - or
+// "" if keys is empty, so callers can skip setting the metadata key
+// entirely and keep single-key secrets byte-for-byte as before this existed.
+func (c *Client) wrapAdditionalDEKs(ctx context.Context, keys []string, aad string, dek []byte, asymmetric bool) (string, error) {
+	if len(keys) == 0 {
+		return "", nil
+	}
+
+	entries := make([]additionalDEK, 0, len(keys))
+	for _, key := range keys {
+		encDEK, err := c.wrapDEK(ctx, key, aad, dek, asymmetric)
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to encrypt dek with additional key %q", key)
+		}
+		entries = append(entries, additionalDEK{
+			Key:    kmsKeyTrimVersion(key),
+			EncDEK: base64.StdEncoding.EncodeToString(encDEK),
+		})
+	}
+
+	b, err := json.Marshal(entries)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to marshal additional keys metadata")
+	}
+	return string(b), nil
+}
+
+// unwrapAnyDEK unwraps a secret's DEK, trying the primary key first and
+// falling back to each of meta's MetadataAdditionalKeysKey entries in order
+// until one succeeds. This is what lets Read and Access tolerate a single
+// KMS key (or region) outage on a secret created with
+// CreateRequest.AdditionalKeys.
+func (c *Client) unwrapAnyDEK(ctx context.Context, key, aad string, encDEK []byte, asymmetric bool, meta map[string]string) ([]byte, error) {
+	dek, primaryErr := c.unwrapDEK(ctx, key, aad, encDEK, asymmetric)
+	if primaryErr == nil {
+		return dek, nil
+	}
+
+	raw := meta[MetadataAdditionalKeysKey]
+	if raw == "" {
+		return nil, primaryErr
+	}
+
+	var entries []additionalDEK
+	if err := json.Unmarshal([]byte(raw), &entries); err != nil {
+		return nil, primaryErr
+	}
+
+	lastErr := primaryErr
+	for _, entry := range entries {
+		encDEK, err := base64.StdEncoding.DecodeString(entry.EncDEK)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		dek, err := c.unwrapDEK(ctx, entry.Key, aad, encDEK, asymmetric)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return dek, nil
+	}
+	return nil, lastErr
+}
+
 // envelopeDecrypt decrypts the data with the dek, returning the plaintext and
-// any errors that occur.
-func envelopeDecrypt(dek, data []byte) ([]byte, error) {
+// any errors that occur. aad must match what was passed to envelopeEncrypt,
+// or nil if the ciphertext predates MetadataAADKey (see readEnvelopeAAD).
+func envelopeDecrypt(dek, data, aad []byte) ([]byte, error) {
 	block, err := aes.NewCipher(dek)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create cipher from dek")
@@ -178,7 +907,7 @@ func envelopeDecrypt(dek, data []byte) ([]byte, error) {
 	}
 	nonce, ciphertext := data[:size], data[size:]
 
-	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := aesgcm.Open(nil, nonce, ciphertext, aad)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decrypt ciphertext with dek")
 	}
@@ -186,8 +915,9 @@ func envelopeDecrypt(dek, data []byte) ([]byte, error) {
 }
 
 // envelopeEncrypt generates a unique DEK and encrypts the plaintext with the
-// given key. The encryption key and resulting ciphertext are returned.
-func envelopeEncrypt(plaintext []byte) ([]byte, []byte, error) {
+// given key, binding it to aad (see envelopeAAD). The encryption key and
+// resulting ciphertext are returned.
+func envelopeEncrypt(plaintext, aad []byte) ([]byte, []byte, error) {
 	key := make([]byte, 32)
 	if _, err := io.ReadFull(rand.Reader, key); err != nil {
 		return nil, nil, errors.Wrap(err, "failed to generate random key bytes")
@@ -210,7 +940,7 @@ func envelopeEncrypt(plaintext []byte) ([]byte, []byte, error) {
 	}
 
 	// Encrypt the ciphertext with the DEK
-	ciphertext := aesgcm.Seal(nonce, nonce, plaintext, nil)
+	ciphertext := aesgcm.Seal(nonce, nonce, plaintext, aad)
 
 	return key, ciphertext, nil
 }
@@ -19,6 +19,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
@@ -63,6 +64,73 @@ func TestBerglasIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("access if-none-match", func(t *testing.T) {
+		t.Parallel()
+
+		client, ctx := testClient(t)
+		bucket, object, key := testBucket(t), testObject(t), testKey(t)
+
+		createdSecret, err := client.Create(ctx, &CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: []byte("my secret value"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer testCleanup(t, bucket, object)
+
+		if _, err := client.Access(ctx, &AccessRequest{
+			Bucket:                bucket,
+			Object:                object,
+			IfNoneMatchGeneration: createdSecret.Generation,
+		}); !IsSecretNotModifiedErr(err) {
+			t.Errorf("expected not-modified error, got %v", err)
+		}
+
+		if _, err := client.Access(ctx, &AccessRequest{
+			Bucket:                bucket,
+			Object:                object,
+			IfNoneMatchGeneration: createdSecret.Generation + 1,
+		}); err != nil {
+			t.Errorf("expected no error for a non-matching generation, got %v", err)
+		}
+	})
+
+	t.Run("access expect-key", func(t *testing.T) {
+		t.Parallel()
+
+		client, ctx := testClient(t)
+		bucket, object, key := testBucket(t), testObject(t), testKey(t)
+
+		if _, err := client.Create(ctx, &CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: []byte("my secret value"),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		defer testCleanup(t, bucket, object)
+
+		if _, err := client.Access(ctx, &AccessRequest{
+			Bucket:    bucket,
+			Object:    object,
+			ExpectKey: key,
+		}); err != nil {
+			t.Errorf("expected no error for the matching key, got %v", err)
+		}
+
+		if _, err := client.Access(ctx, &AccessRequest{
+			Bucket:    bucket,
+			Object:    object,
+			ExpectKey: key + "-bogus",
+		}); !IsUnexpectedKeyErr(err) {
+			t.Errorf("expected unexpected-key error, got %v", err)
+		}
+	})
+
 	t.Run("create", func(t *testing.T) {
 		t.Parallel()
 
@@ -382,6 +450,157 @@ func TestBerglasIntegration(t *testing.T) {
 			t.Errorf("expected %q to be %q", act, exp)
 		}
 	})
+
+	t.Run("update merge func retries on conflict", func(t *testing.T) {
+		t.Parallel()
+
+		client, ctx := testClient(t)
+		bucket, object, key := testBucket(t), testObject(t), testKey(t)
+
+		if _, err := client.Create(ctx, &CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: []byte("0"),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		defer testCleanup(t, bucket, object)
+
+		// Simulate a concurrent writer that changes the generation out from under
+		// the first attempt of the merge func.
+		var attempts int
+		updatedSecret, err := client.Update(ctx, &UpdateRequest{
+			Bucket: bucket,
+			Object: object,
+			MergeFunc: func(current []byte) ([]byte, error) {
+				attempts++
+				if attempts == 1 {
+					if _, err := client.Update(ctx, &UpdateRequest{
+						Bucket:    bucket,
+						Object:    object,
+						Plaintext: []byte("1"),
+					}); err != nil {
+						t.Fatal(err)
+					}
+				}
+				return append(current, []byte("x")...), nil
+			},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := attempts, 2; act != exp {
+			t.Errorf("expected %d attempts to be %d", act, exp)
+		}
+
+		if act, exp := updatedSecret.Plaintext, []byte("1x"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("append", func(t *testing.T) {
+		t.Parallel()
+
+		client, ctx := testClient(t)
+		bucket, object, key := testBucket(t), testObject(t), testKey(t)
+
+		if _, err := client.Create(ctx, &CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: []byte("line one\n"),
+		}); err != nil {
+			t.Fatal(err)
+		}
+		defer testCleanup(t, bucket, object)
+
+		appendedSecret, err := client.Append(ctx, &AppendRequest{
+			Bucket: bucket,
+			Object: object,
+			Data:   []byte("line two\n"),
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := appendedSecret.Plaintext, []byte("line one\nline two\n"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+
+		accessPlaintext, err := client.Access(ctx, &AccessRequest{
+			Bucket: bucket,
+			Object: object,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := accessPlaintext, appendedSecret.Plaintext; !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("bundle", func(t *testing.T) {
+		t.Parallel()
+
+		client, ctx := testClient(t)
+		bucket, object, key := testBucket(t), testObject(t), testKey(t)
+		defer testCleanup(t, bucket, object)
+
+		createdSecret, err := client.BundleSet(ctx, &BundleSetRequest{
+			Bucket: bucket,
+			Object: object,
+			Key:    key,
+			Values: Bundle{"a": "1", "b": "2"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := ParseBundle(createdSecret.Plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := b, (Bundle{"a": "1", "b": "2"}); !reflect.DeepEqual(act, exp) {
+			t.Errorf("expected %#v to be %#v", act, exp)
+		}
+
+		updatedSecret, err := client.BundleSet(ctx, &BundleSetRequest{
+			Bucket: bucket,
+			Object: object,
+			Values: Bundle{"b": "3", "c": "4"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err = ParseBundle(updatedSecret.Plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := b, (Bundle{"a": "1", "b": "3", "c": "4"}); !reflect.DeepEqual(act, exp) {
+			t.Errorf("expected %#v to be %#v", act, exp)
+		}
+
+		unsetSecret, err := client.BundleUnset(ctx, &BundleUnsetRequest{
+			Bucket: bucket,
+			Object: object,
+			Keys:   []string{"a"},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		b, err = ParseBundle(unsetSecret.Plaintext)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := b, (Bundle{"b": "3", "c": "4"}); !reflect.DeepEqual(act, exp) {
+			t.Errorf("expected %#v to be %#v", act, exp)
+		}
+	})
 }
 
 func TestKMSKeyTrimVersion(t *testing.T) {
@@ -422,6 +641,177 @@ func TestKMSKeyTrimVersion(t *testing.T) {
 	}
 }
 
+func TestKMSKeyVersion(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		i    string
+		o    string
+	}{
+		{
+			"malformed",
+			"foo",
+			"",
+		},
+		{
+			"no_version",
+			"projects/p/locations/l/keyRings/kr/cryptoKeys/ck",
+			"",
+		},
+		{
+			"version",
+			"projects/p/locations/l/keyRings/kr/cryptoKeys/ck/cryptoKeyVersions/1",
+			"1",
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if act, exp := kmsKeyVersion(tc.i), tc.o; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+}
+
+// Test_Client_Create_keyVersionPinned verifies that pinning --key to a
+// specific CryptoKeyVersion records that version on the resulting Secret,
+// separately from the trimmed KMSKey, and that it reads back the same way.
+func Test_Client_Create_keyVersionPinned(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const (
+		unpinnedKey = "projects/p/locations/l/keyRings/kr/cryptoKeys/ck"
+		pinnedKey   = unpinnedKey + "/cryptoKeyVersions/3"
+	)
+
+	created, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       pinnedKey,
+		Plaintext: []byte("my secret value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := created.KMSKey, unpinnedKey; act != exp {
+		t.Errorf("KMSKey = %q, want %q", act, exp)
+	}
+	if act, exp := created.KMSKeyVersion, "3"; act != exp {
+		t.Errorf("KMSKeyVersion = %q, want %q", act, exp)
+	}
+
+	read, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := read.KMSKeyVersion, "3"; act != exp {
+		t.Errorf("KMSKeyVersion = %q, want %q", act, exp)
+	}
+}
+
+func TestRetainUntilFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("absent", func(t *testing.T) {
+		t.Parallel()
+
+		if act := retainUntilFromMetadata(nil); !act.IsZero() {
+			t.Errorf("expected zero time, got %v", act)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Parallel()
+
+		meta := map[string]string{MetadataRetainUntilKey: "not-a-time"}
+		if act := retainUntilFromMetadata(meta); !act.IsZero() {
+			t.Errorf("expected zero time, got %v", act)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		exp := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+		meta := map[string]string{MetadataRetainUntilKey: exp.Format(time.RFC3339)}
+		if act := retainUntilFromMetadata(meta); !act.Equal(exp) {
+			t.Errorf("expected %v to be %v", act, exp)
+		}
+	})
+}
+
+func TestCheckRetention(t *testing.T) {
+	t.Parallel()
+
+	t.Run("zero", func(t *testing.T) {
+		t.Parallel()
+
+		if err := checkRetention(time.Time{}); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("past", func(t *testing.T) {
+		t.Parallel()
+
+		if err := checkRetention(time.Now().Add(-time.Hour)); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("future", func(t *testing.T) {
+		t.Parallel()
+
+		err := checkRetention(time.Now().Add(time.Hour))
+		if !IsSecretRetainedErr(err) {
+			t.Fatalf("expected retained error, got %v", err)
+		}
+	})
+}
+
+func TestLastAccessedFromMetadata(t *testing.T) {
+	t.Parallel()
+
+	t.Run("absent", func(t *testing.T) {
+		t.Parallel()
+
+		if act := lastAccessedFromMetadata(nil); !act.IsZero() {
+			t.Errorf("expected zero time, got %v", act)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		t.Parallel()
+
+		meta := map[string]string{MetadataLastAccessedKey: "not-a-time"}
+		if act := lastAccessedFromMetadata(meta); !act.IsZero() {
+			t.Errorf("expected zero time, got %v", act)
+		}
+	})
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		exp := time.Date(2030, 1, 2, 0, 0, 0, 0, time.UTC)
+		meta := map[string]string{MetadataLastAccessedKey: exp.Format(time.RFC3339)}
+		if act := lastAccessedFromMetadata(meta); !act.Equal(exp) {
+			t.Errorf("expected %v to be %v", act, exp)
+		}
+	})
+}
+
 func testClient(tb testing.TB) (*Client, context.Context) {
 	tb.Helper()
 
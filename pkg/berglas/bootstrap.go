@@ -65,6 +65,10 @@ type BootstrapRequest struct {
 // Bootstrap adds IAM permission to the given entity to the storage object and the
 // underlying KMS key.
 func (c *Client) Bootstrap(ctx context.Context, i *BootstrapRequest) error {
+	if c.storageClient == nil || c.kmsClient == nil {
+		return errBackendUnsupported
+	}
+
 	if i == nil {
 		return errors.New("missing request")
 	}
@@ -0,0 +1,91 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"sort"
+
+	"github.com/pkg/errors"
+	"google.golang.org/api/iterator"
+)
+
+// bucketPurposeLabel is the bucket label Bootstrap sets, used here to
+// recognize buckets that were provisioned for berglas.
+const bucketPurposeLabel = "purpose"
+
+// ListBuckets is a top-level package function for listing berglas-managed
+// buckets in a project.
+func ListBuckets(ctx context.Context, i *ListBucketsRequest) ([]string, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.ListBuckets(ctx, i)
+}
+
+// ListBucketsRequest is used as input to list the berglas-managed buckets in
+// a project.
+type ListBucketsRequest struct {
+	// Project is the GCP project ID to search for buckets in.
+	Project string
+}
+
+// ListBuckets returns the names, sorted lexically, of all buckets in the
+// given project that Bootstrap provisioned for berglas (identified by the
+// "purpose=berglas" bucket label). This is intended for org-wide inventory
+// tooling that wants to discover buckets without being told about each one
+// individually.
+func (c *Client) ListBuckets(ctx context.Context, i *ListBucketsRequest) ([]string, error) {
+	if c.storageClient == nil {
+		return nil, errBackendUnsupported
+	}
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	logger := c.Logger().WithField("project", project)
+	logger.Debug("listbuckets.start")
+	defer logger.Debug("listbuckets.finish")
+
+	var names []string
+
+	it := c.storageClient.Buckets(ctx, project)
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to list buckets")
+		}
+
+		if attrs.Labels[bucketPurposeLabel] != "berglas" {
+			continue
+		}
+
+		logger.WithField("bucket", attrs.Name).Debug("found berglas-labeled bucket")
+		names = append(names, attrs.Name)
+	}
+
+	sort.Strings(names)
+	return names, nil
+}
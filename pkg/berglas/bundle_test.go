@@ -0,0 +1,73 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBundle(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		data []byte
+		exp  Bundle
+	}{
+		{"empty", nil, Bundle{}},
+		{"values", []byte(`{"a":"1","b":"2"}`), Bundle{"a": "1", "b": "2"}},
+		{"null", []byte(`null`), Bundle{}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := ParseBundle(tc.data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(b, tc.exp) {
+				t.Errorf("expected %#v to be %#v", b, tc.exp)
+			}
+		})
+	}
+
+	if _, err := ParseBundle([]byte("not json")); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
+
+func TestBundle_Marshal(t *testing.T) {
+	t.Parallel()
+
+	b := Bundle{"a": "1"}
+
+	data, err := b.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseBundle(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, b) {
+		t.Errorf("expected %#v to be %#v", got, b)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// WithCache enables an opt-in in-process cache of resolved secret
+// plaintexts, keyed by bucket, object, and generation, so repeated Access
+// calls (directly, or indirectly through Resolve) for the same secret don't
+// hit Cloud Storage and Cloud KMS every time - for example, when an
+// autoscaling burst of pods all cold-start against the same handful of
+// secrets. Concurrent Access calls for a key that isn't cached yet are
+// collapsed into a single Read via singleflight, so a burst still only pays
+// for one download and decrypt.
+//
+// ttl bounds how long an entry is served before it is fetched again; a
+// cached "latest" (unpinned) generation can therefore serve a stale value
+// for up to ttl. maxEntries bounds the number of distinct bucket/object/
+// generation keys held at once, evicting the oldest entry once exceeded;
+// zero means unlimited.
+//
+// Access calls made with IfNoneMatchGeneration set bypass the cache, since
+// that path already has its own generation-freshness check.
+//
+// Pass it to New, or apply it to an already-constructed Client with
+// Client.ApplyOptions, e.g.
+//
+//	client.ApplyOptions(berglas.WithCache(30*time.Second, 1000))
+func WithCache(ttl time.Duration, maxEntries int) Option {
+	return func(c *Client) {
+		c.cacheLock.Lock()
+		c.cache = newSecretCache(ttl, maxEntries)
+		c.cacheLock.Unlock()
+	}
+}
+
+// cacheEntry is a single cached secret plaintext, along with the KMS key it
+// was encrypted with (needed to honor AccessRequest.ExpectKey on a cache
+// hit).
+type cacheEntry struct {
+	plaintext []byte
+	kmsKey    string
+	expiresAt time.Time
+}
+
+// secretCache is the in-process cache enabled by WithCache.
+type secretCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	group singleflight.Group
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	order   []string // insertion order, oldest first, for FIFO eviction
+}
+
+func newSecretCache(ttl time.Duration, maxEntries int) *secretCache {
+	return &secretCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    make(map[string]*cacheEntry),
+	}
+}
+
+// cacheKey identifies a secret's cache entry. Unlike lease and metrics keys
+// elsewhere in this package, this always includes the generation - even
+// when it's the sentinel -1 for "latest" - so that pinning a generation
+// explicitly never returns an unpinned entry or vice versa.
+func cacheKey(bucket, object string, generation int64) string {
+	return bucket + "/" + object + "@" + strconv.FormatInt(generation, 10)
+}
+
+func (c *secretCache) get(key string) (*cacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	e, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(e.expiresAt) {
+		delete(c.entries, key)
+		c.removeOrder(key)
+		return nil, false
+	}
+	return e, true
+}
+
+// removeOrder removes key from c.order, if present. Without this, an
+// expired entry deleted by get would leave a stale key in c.order that the
+// next set re-appends a second copy of, growing c.order without bound
+// across repeated expiry/re-fetch cycles of the same key and corrupting
+// FIFO eviction order once duplicates exist.
+func (c *secretCache) removeOrder(key string) {
+	for i, k := range c.order {
+		if k == key {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *secretCache) set(key string, e *cacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, exists := c.entries[key]; !exists {
+		c.order = append(c.order, key)
+	}
+	c.entries[key] = e
+
+	for c.maxEntries > 0 && len(c.entries) > c.maxEntries {
+		oldest := c.order[0]
+		c.order = c.order[1:]
+		delete(c.entries, oldest)
+	}
+}
@@ -0,0 +1,180 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// countingSecretStorage wraps a SecretStorage and counts ReadObject calls,
+// so tests can assert whether WithCache actually avoided a backend read.
+type countingSecretStorage struct {
+	SecretStorage
+	reads int64
+}
+
+func (s *countingSecretStorage) ReadObject(ctx context.Context, bucket, object string, generation int64) ([]byte, *StorageObject, error) {
+	atomic.AddInt64(&s.reads, 1)
+	return s.SecretStorage.ReadObject(ctx, bucket, object, generation)
+}
+
+func Test_Client_Access_withCache(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := &countingSecretStorage{SecretStorage: newMemSecretStorage()}
+
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{}, WithCache(time.Minute, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 5; i++ {
+		plaintext, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := string(plaintext), "my secret value"; act != exp {
+			t.Errorf("access %d: got %q, want %q", i, act, exp)
+		}
+	}
+
+	if reads := atomic.LoadInt64(&storage.reads); reads != 1 {
+		t.Errorf("ReadObject called %d times, want 1", reads)
+	}
+}
+
+func Test_Client_Access_withCache_expires(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := &countingSecretStorage{SecretStorage: newMemSecretStorage()}
+
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{}, WithCache(time.Nanosecond, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"}); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if reads := atomic.LoadInt64(&storage.reads); reads != 2 {
+		t.Errorf("ReadObject called %d times, want 2", reads)
+	}
+}
+
+func Test_Client_Access_withCache_singleflight(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := &countingSecretStorage{SecretStorage: newMemSecretStorage()}
+
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{}, WithCache(time.Minute, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reads := atomic.LoadInt64(&storage.reads); reads != 1 {
+		t.Errorf("ReadObject called %d times, want 1", reads)
+	}
+}
+
+// Test_secretCache_expiredEntryDoesNotLeakOrder verifies that get removes an
+// expired entry's key from c.order as well as c.entries, so repeated
+// expire/re-set cycles on the same key don't grow c.order without bound.
+func Test_secretCache_expiredEntryDoesNotLeakOrder(t *testing.T) {
+	t.Parallel()
+
+	c := newSecretCache(time.Nanosecond, 0)
+
+	for i := 0; i < 1000; i++ {
+		c.set("a", &cacheEntry{plaintext: []byte("a"), expiresAt: time.Now().Add(time.Nanosecond)})
+		time.Sleep(time.Microsecond)
+		if _, ok := c.get("a"); ok {
+			t.Fatalf("iteration %d: expected entry to have expired", i)
+		}
+	}
+
+	if act, exp := len(c.order), 0; act != exp {
+		t.Errorf("len(order) = %d, want %d", act, exp)
+	}
+}
+
+func Test_secretCache_evictsOldest(t *testing.T) {
+	t.Parallel()
+
+	c := newSecretCache(time.Minute, 2)
+	c.set("a", &cacheEntry{plaintext: []byte("a"), expiresAt: time.Now().Add(time.Minute)})
+	c.set("b", &cacheEntry{plaintext: []byte("b"), expiresAt: time.Now().Add(time.Minute)})
+	c.set("c", &cacheEntry{plaintext: []byte("c"), expiresAt: time.Now().Add(time.Minute)})
+
+	if _, ok := c.get("a"); ok {
+		t.Error("expected the oldest entry to have been evicted")
+	}
+	if _, ok := c.get("b"); !ok {
+		t.Error("expected b to still be cached")
+	}
+	if _, ok := c.get("c"); !ok {
+		t.Error("expected c to still be cached")
+	}
+}
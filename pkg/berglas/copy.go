@@ -0,0 +1,104 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// CopyRequest is used as input to Copy.
+type CopyRequest struct {
+	// SourceBucket and SourceObject identify the secret to copy.
+	SourceBucket string
+	SourceObject string
+
+	// DestinationBucket and DestinationObject identify where the copy is
+	// written. DestinationObject defaults to SourceObject if empty.
+	DestinationBucket string
+	DestinationObject string
+
+	// Key is the fully qualified KMS key id to encrypt the copy with. If
+	// empty, the source secret's own key is reused - this only works when
+	// DestinationBucket's Cloud KMS key ring is the same as (or has access to
+	// decrypt secrets encrypted with) SourceBucket's, since Copy always
+	// decrypts the source and re-encrypts the plaintext rather than copying
+	// ciphertext directly.
+	Key string
+
+	// Overwrite, if true, allows Copy to replace an existing secret at the
+	// destination instead of failing.
+	Overwrite bool
+}
+
+// Copy decrypts the secret at SourceBucket/SourceObject and re-creates it at
+// DestinationBucket/DestinationObject, re-encrypting it with Key (or the
+// source secret's own key, if Key is empty). This promotes a secret between
+// buckets - e.g. dev to staging to prod - without a separate access+create
+// pipeline.
+//
+// Copy always starts a fresh generation at the destination; it does not
+// preserve the source secret's generation, metageneration, or IAM bindings.
+func (c *Client) Copy(ctx context.Context, i *CopyRequest) (*Secret, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	if i.SourceBucket == "" {
+		return nil, errors.New("missing source bucket name")
+	}
+	if i.SourceObject == "" {
+		return nil, errors.New("missing source object name")
+	}
+	if i.DestinationBucket == "" {
+		return nil, errors.New("missing destination bucket name")
+	}
+
+	destinationObject := i.DestinationObject
+	if destinationObject == "" {
+		destinationObject = i.SourceObject
+	}
+
+	secret, err := c.Read(ctx, &ReadRequest{
+		Bucket: i.SourceBucket,
+		Object: i.SourceObject,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read source secret")
+	}
+
+	key := i.Key
+	if key == "" {
+		key = secret.KMSKey
+	}
+
+	if i.Overwrite {
+		return c.Update(ctx, &UpdateRequest{
+			Bucket:          i.DestinationBucket,
+			Object:          destinationObject,
+			Key:             key,
+			Plaintext:       secret.Plaintext,
+			CreateIfMissing: true,
+		})
+	}
+
+	return c.Create(ctx, &CreateRequest{
+		Bucket:    i.DestinationBucket,
+		Object:    destinationObject,
+		Key:       key,
+		Plaintext: secret.Plaintext,
+	})
+}
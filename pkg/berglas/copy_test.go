@@ -0,0 +1,102 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+)
+
+func TestClient_Copy(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "api-key",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := h.Client.Copy(ctx, &berglas.CopyRequest{
+		SourceBucket:      h.Bucket,
+		SourceObject:      "api-key",
+		DestinationBucket: h.Bucket,
+		DestinationObject: "api-key-copy",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if secret.Generation == 0 {
+		t.Error("expected a non-zero generation")
+	}
+
+	copied, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "api-key-copy",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(copied), "s3cr3t"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	// Copying again without --overwrite must fail since the destination
+	// already exists.
+	if _, err := h.Client.Copy(ctx, &berglas.CopyRequest{
+		SourceBucket:      h.Bucket,
+		SourceObject:      "api-key",
+		DestinationBucket: h.Bucket,
+		DestinationObject: "api-key-copy",
+	}); err == nil {
+		t.Error("expected an error copying onto an existing secret without Overwrite")
+	}
+}
+
+func TestClient_Copy_defaultObjectName(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "api-key",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.Copy(ctx, &berglas.CopyRequest{
+		SourceBucket:      h.Bucket,
+		SourceObject:      "api-key",
+		DestinationBucket: h.Bucket,
+	}); err == nil {
+		t.Error("expected an error copying onto itself with no destination object override")
+	}
+}
@@ -16,6 +16,7 @@ package berglas
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -42,13 +43,47 @@ type CreateRequest struct {
 	// Key is the fully qualified KMS key id.
 	Key string
 
+	// AdditionalKeys, if given, are extra fully qualified KMS key ids to
+	// independently wrap the same DEK under, alongside Key. Access and Read
+	// try each wrapped DEK in turn - Key first, then AdditionalKeys in
+	// order - until one succeeds, so an outage affecting one key (e.g. a
+	// single Cloud KMS region) does not make the secret unreadable as long
+	// as another still works. See MetadataAdditionalKeysKey.
+	AdditionalKeys []string
+
 	// Plaintext is the plaintext secret to encrypt and store.
 	Plaintext []byte
+
+	// Format selects the envelope format used to wrap the DEK. Defaults to
+	// EnvelopeFormatDefault.
+	Format EnvelopeFormat
+
+	// Asymmetric, if true, wraps the DEK with Key's public half instead of
+	// encrypting it with Cloud KMS's symmetric Encrypt, so creating the
+	// secret needs no decrypt permission on Key at all - only Key itself
+	// must be an asymmetric (RSA_DECRYPT_OAEP_*) Cloud KMS key. This is
+	// intended for write-only producers, such as a CI pipeline, that should
+	// create secrets they can never read back; reading one requires separate
+	// decrypt permission via Access or Read. Not supported with a
+	// local-key:// Key, or on a client constructed with NewWithBackends
+	// whose KeyManager does not implement AsymmetricKeyManager.
+	Asymmetric bool
+
+	// RetainUntil, if non-zero, prevents the secret from being deleted or
+	// overwritten until this time has passed. Attempts to do so return an
+	// error satisfying IsSecretRetainedErr.
+	RetainUntil time.Time
 }
 
 // Create creates a new encrypted secret on GCS. If the secret already exists,
 // an error is returned. Use Update to update an existing secret.
-func (c *Client) Create(ctx context.Context, i *CreateRequest) (*Secret, error) {
+func (c *Client) Create(ctx context.Context, i *CreateRequest) (_ *Secret, err error) {
+	defer func() {
+		if err != nil {
+			recordError("create")
+		}
+	}()
+
 	if i == nil {
 		return nil, errors.New("missing request")
 	}
@@ -73,16 +108,29 @@ func (c *Client) Create(ctx context.Context, i *CreateRequest) (*Secret, error)
 		return nil, errors.New("missing plaintext")
 	}
 
+	if err := i.Format.validate(); err != nil {
+		return nil, errors.Wrap(err, "unsupported envelope format")
+	}
+
+	if i.Asymmetric && IsLocalKey(key) {
+		return nil, errors.New("asymmetric encryption is not supported with local development keys")
+	}
+
+	if i.Asymmetric && IsPassphraseKey(key) {
+		return nil, errors.New("asymmetric encryption is not supported with passphrase keys")
+	}
+
 	logger := c.Logger().WithFields(logrus.Fields{
-		"bucket": bucket,
-		"object": object,
-		"key":    key,
+		"bucket":     bucket,
+		"object":     object,
+		"key":        key,
+		"asymmetric": i.Asymmetric,
 	})
 
 	logger.Debug("create.start")
 	defer logger.Debug("create.finish")
 
-	secret, err := c.encryptAndWrite(ctx, bucket, object, key, plaintext, 0, 0)
+	secret, err := c.encryptAndWrite(ctx, bucket, object, key, i.AdditionalKeys, plaintext, i.Format, i.Asymmetric, 0, 0, i.RetainUntil)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create secret")
 	}
@@ -42,11 +42,32 @@ type DeleteRequest struct {
 
 	// Object is the name of the secret in Cloud Storage.
 	Object string
+
+	// IfGeneration, if non-zero, aborts the delete with errSecretModified
+	// (see IsSecretModifiedErr) unless it matches the secret's current live
+	// generation, giving callers compare-and-swap semantics so a concurrent
+	// update or delete can't be silently clobbered.
+	IfGeneration int64
+
+	// IfMetageneration, if non-zero, aborts the delete with errSecretModified
+	// (see IsSecretModifiedErr) unless it matches the secret's current live
+	// metageneration.
+	IfMetageneration int64
 }
 
 // Delete reads the contents of the secret from the bucket, decrypting the
 // ciphertext using Cloud KMS.
-func (c *Client) Delete(ctx context.Context, i *DeleteRequest) error {
+func (c *Client) Delete(ctx context.Context, i *DeleteRequest) (err error) {
+	defer func() {
+		if err != nil {
+			recordError("delete")
+		}
+	}()
+
+	if c.storageClient == nil {
+		return errBackendUnsupported
+	}
+
 	if i == nil {
 		return errors.New("missing request")
 	}
@@ -69,6 +90,24 @@ func (c *Client) Delete(ctx context.Context, i *DeleteRequest) error {
 	logger.Debug("delete.start")
 	defer logger.Debug("delete.finish")
 
+	attrs, err := c.storageClient.Bucket(bucket).Object(object).Attrs(ctx)
+	switch err {
+	case nil:
+		if rerr := checkRetention(retainUntilFromMetadata(attrs.Metadata)); rerr != nil {
+			return errors.Wrap(rerr, "failed to delete secret")
+		}
+		if i.IfGeneration != 0 && attrs.Generation != i.IfGeneration {
+			return errors.Wrap(errSecretModified, "failed to delete secret")
+		}
+		if i.IfMetageneration != 0 && attrs.Metageneration != i.IfMetageneration {
+			return errors.Wrap(errSecretModified, "failed to delete secret")
+		}
+	case storage.ErrObjectNotExist:
+		// Nothing to check; the delete loop below is a no-op in this case.
+	default:
+		return wrapAPIErr(err, "failed to fetch existing secret")
+	}
+
 	it := c.storageClient.
 		Bucket(bucket).
 		Objects(ctx, &storage.Query{
@@ -148,7 +187,7 @@ L:
 
 	select {
 	case err := <-errCh:
-		return errors.Wrap(err, "failed to delete secret")
+		return wrapAPIErr(err, "failed to delete secret")
 	default:
 		return nil
 	}
@@ -0,0 +1,107 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+)
+
+func TestClient_Delete_ifGeneration(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "if-generation",
+		Key:       h.Key,
+		Plaintext: []byte("my secret value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket:       h.Bucket,
+		Object:       "if-generation",
+		IfGeneration: created.Generation + 1,
+	}); !berglas.IsSecretModifiedErr(err) {
+		t.Fatalf("expected IsSecretModifiedErr for a mismatched generation, got %v", err)
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "if-generation",
+	}); err != nil {
+		t.Fatalf("expected secret to survive a rejected delete, got %v", err)
+	}
+
+	if err := h.Client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket:       h.Bucket,
+		Object:       "if-generation",
+		IfGeneration: created.Generation,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "if-generation",
+	}); err == nil {
+		t.Error("expected secret to be deleted")
+	}
+}
+
+func TestClient_Delete_ifMetageneration(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	created, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "if-metageneration",
+		Key:       h.Key,
+		Plaintext: []byte("my secret value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket:           h.Bucket,
+		Object:           "if-metageneration",
+		IfMetageneration: created.Metageneration + 1,
+	}); !berglas.IsSecretModifiedErr(err) {
+		t.Fatalf("expected IsSecretModifiedErr for a mismatched metageneration, got %v", err)
+	}
+
+	if err := h.Client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket:           h.Bucket,
+		Object:           "if-metageneration",
+		IfMetageneration: created.Metageneration,
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"cloud.google.com/go/storage"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/pkg/errors"
+	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
+)
+
+// bootstrapRotationPeriod and bootstrapProtectionLevel mirror the defaults
+// Bootstrap configures, so Discover has something to diff discovered KMS
+// keys against. See Bootstrap in bootstrap.go.
+const bootstrapRotationPeriod = 30 * 24 * time.Hour
+
+// Discover is a top-level package function for discovering berglas
+// environments in a project.
+func Discover(ctx context.Context, i *DiscoverRequest) (*DiscoverResponse, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Discover(ctx, i)
+}
+
+// DiscoverRequest is used as input to discover berglas environments across a
+// project.
+type DiscoverRequest struct {
+	// Project is the GCP project ID to search.
+	Project string
+}
+
+// DiscoveredBucket describes a berglas-managed bucket found by Discover, and
+// how its configuration has drifted from what Bootstrap would create.
+type DiscoveredBucket struct {
+	// Name is the bucket name.
+	Name string
+
+	// Location is the bucket's storage location.
+	Location string
+
+	// VersioningEnabled reports whether object versioning is on.
+	VersioningEnabled bool
+
+	// Drift lists human-readable ways this bucket's configuration differs
+	// from Bootstrap's defaults. An empty Drift means no drift was detected.
+	Drift []string
+}
+
+// DiscoveredKMSKey describes a KMS key used to encrypt at least one secret
+// found by Discover, and how its configuration has drifted from what
+// Bootstrap would create.
+type DiscoveredKMSKey struct {
+	// Name is the fully-qualified KMS crypto key resource name.
+	Name string
+
+	// RotationPeriod is the key's configured rotation period, or zero if
+	// automatic rotation is disabled.
+	RotationPeriod time.Duration
+
+	// ProtectionLevel is the key's protection level (e.g. "SOFTWARE", "HSM").
+	ProtectionLevel string
+
+	// Drift lists human-readable ways this key's configuration differs from
+	// Bootstrap's defaults. An empty Drift means no drift was detected.
+	Drift []string
+}
+
+// DiscoverResponse is the response from a Discover call.
+type DiscoverResponse struct {
+	// Buckets are the berglas-managed buckets found in the project.
+	Buckets []*DiscoveredBucket
+
+	// KMSKeys are the KMS keys protecting at least one secret in one of
+	// Buckets.
+	KMSKeys []*DiscoveredKMSKey
+}
+
+// Discover finds buckets and KMS keys that Bootstrap created or labeled
+// across a project, and reports how their configuration has drifted from
+// Bootstrap's defaults. It is intended for platform teams doing org-wide
+// inventory of berglas environments they may not know about individually.
+func (c *Client) Discover(ctx context.Context, i *DiscoverRequest) (*DiscoverResponse, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	logger := c.Logger().WithField("project", project)
+	logger.Debug("discover.start")
+	defer logger.Debug("discover.finish")
+
+	bucketNames, err := c.ListBuckets(ctx, &ListBucketsRequest{Project: project})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list buckets")
+	}
+
+	resp := &DiscoverResponse{}
+	kmsKeysSeen := make(map[string]bool)
+
+	for _, name := range bucketNames {
+		bucket, err := c.discoverBucket(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		resp.Buckets = append(resp.Buckets, bucket)
+
+		list, err := c.List(ctx, &ListRequest{Bucket: name})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list secrets in bucket %s", name)
+		}
+
+		for _, secret := range list.Secrets {
+			if secret.KMSKey == "" || kmsKeysSeen[secret.KMSKey] {
+				continue
+			}
+			kmsKeysSeen[secret.KMSKey] = true
+
+			key, err := c.discoverKMSKey(ctx, secret.KMSKey)
+			if err != nil {
+				logger.WithError(err).WithField("kms_key", secret.KMSKey).
+					Debug("failed to inspect kms key, skipping")
+				continue
+			}
+			resp.KMSKeys = append(resp.KMSKeys, key)
+		}
+	}
+
+	sort.Slice(resp.Buckets, func(i, j int) bool { return resp.Buckets[i].Name < resp.Buckets[j].Name })
+	sort.Slice(resp.KMSKeys, func(i, j int) bool { return resp.KMSKeys[i].Name < resp.KMSKeys[j].Name })
+
+	return resp, nil
+}
+
+// discoverBucket fetches a bucket's attributes and diffs them against what
+// Bootstrap would have configured.
+func (c *Client) discoverBucket(ctx context.Context, name string) (*DiscoveredBucket, error) {
+	attrs, err := c.storageClient.Bucket(name).Attrs(ctx)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get attributes for bucket %s", name)
+	}
+
+	b := &DiscoveredBucket{
+		Name:              name,
+		Location:          attrs.Location,
+		VersioningEnabled: attrs.VersioningEnabled,
+	}
+
+	if !attrs.VersioningEnabled {
+		b.Drift = append(b.Drift, "object versioning is disabled, but Bootstrap enables it")
+	}
+
+	if !hasBootstrapLifecycleRule(attrs.Lifecycle) {
+		b.Drift = append(b.Drift, "missing Bootstrap's lifecycle rule to delete objects with 10+ newer versions")
+	}
+
+	return b, nil
+}
+
+// hasBootstrapLifecycleRule reports whether lc contains the delete-after-10
+// -newer-versions rule Bootstrap configures.
+func hasBootstrapLifecycleRule(lc storage.Lifecycle) bool {
+	for _, rule := range lc.Rules {
+		if rule.Action.Type == "Delete" && rule.Condition.NumNewerVersions == 10 {
+			return true
+		}
+	}
+	return false
+}
+
+// discoverKMSKey fetches a KMS crypto key's configuration and diffs it
+// against what Bootstrap would have configured.
+func (c *Client) discoverKMSKey(ctx context.Context, name string) (*DiscoveredKMSKey, error) {
+	key, err := c.kmsClient.GetCryptoKey(ctx, &kmspb.GetCryptoKeyRequest{Name: name})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get kms key %s", name)
+	}
+
+	k := &DiscoveredKMSKey{
+		Name:            name,
+		ProtectionLevel: key.GetVersionTemplate().GetProtectionLevel().String(),
+	}
+
+	if rp := key.GetRotationPeriod(); rp != nil {
+		d, err := ptypes.Duration(rp)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse rotation period for kms key %s", name)
+		}
+		k.RotationPeriod = d
+	}
+
+	if k.RotationPeriod != bootstrapRotationPeriod {
+		k.Drift = append(k.Drift, "rotation period does not match Bootstrap's 30-day default")
+	}
+	if key.GetVersionTemplate().GetProtectionLevel() != kmspb.ProtectionLevel_SOFTWARE {
+		k.Drift = append(k.Drift, "protection level is not SOFTWARE, Bootstrap's default")
+	}
+
+	return k, nil
+}
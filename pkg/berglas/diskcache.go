@@ -0,0 +1,170 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// WithDiskCache enables an optional encrypted on-disk fallback cache rooted
+// at dir. Every Access call that succeeds is written there, keyed by
+// bucket/object/generation; if a later Access call for the same secret
+// fails - for example because Cloud Storage or Cloud KMS is unreachable -
+// the last value written is served instead, provided it is no older than
+// maxStale (zero means no age limit). This exists so a long-running process
+// like "exec --cache-dir" can survive a regional Cloud Storage/KMS outage
+// across a restart, at the cost of possibly serving a stale secret.
+//
+// The cache is encrypted at rest with a random key generated the first time
+// dir is used and stored alongside the cached entries with the same
+// permissions as a local development key (see GenerateLocalKey); this
+// protects against casual disk access (a stolen backup, another user on a
+// shared host), not a compromised machine, since the key lives right next
+// to the ciphertext it protects.
+//
+// Pass it to New, or apply it to an already-constructed Client with
+// Client.ApplyOptions, e.g.
+//
+//	client.ApplyOptions(berglas.WithDiskCache("/var/cache/berglas", time.Hour))
+func WithDiskCache(dir string, maxStale time.Duration) Option {
+	return func(c *Client) {
+		c.diskCacheLock.Lock()
+		c.diskCache = &diskCache{dir: dir, maxStale: maxStale}
+		c.diskCacheLock.Unlock()
+	}
+}
+
+// diskCache is the on-disk cache enabled by WithDiskCache.
+type diskCache struct {
+	dir      string
+	maxStale time.Duration
+
+	keyOnce sync.Once
+	key     []byte
+	keyErr  error
+}
+
+// diskCacheRecord is the JSON payload encrypted and written to a single
+// cache entry file.
+type diskCacheRecord struct {
+	KMSKey    string    `json:"kms_key"`
+	Plaintext []byte    `json:"plaintext"`
+	StoredAt  time.Time `json:"stored_at"`
+}
+
+// loadKey returns the key protecting d's cache entries, generating and
+// persisting one under d.dir the first time it's needed.
+func (d *diskCache) loadKey() ([]byte, error) {
+	d.keyOnce.Do(func() {
+		path := filepath.Join(d.dir, "cache.key")
+
+		if key, err := ReadLocalKeyFile(path); err == nil {
+			d.key = key
+			return
+		}
+
+		key, err := GenerateLocalKey()
+		if err != nil {
+			d.keyErr = errors.Wrap(err, "failed to generate disk cache key")
+			return
+		}
+		if err := WriteLocalKeyFile(path, key); err != nil {
+			d.keyErr = errors.Wrap(err, "failed to persist disk cache key")
+			return
+		}
+		d.key = key
+	})
+	return d.key, d.keyErr
+}
+
+// entryPath returns the file a cache key's entry is stored under. The
+// bucket/object/generation key is hashed rather than used as a path
+// directly, since object names may themselves contain slashes.
+func (d *diskCache) entryPath(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(d.dir, "entries", hex.EncodeToString(sum[:])+".enc")
+}
+
+// put persists secret as the cached value for bucket/object/generation.
+// Failures are the caller's to decide how to handle; a disk cache write
+// failure should never fail an otherwise-successful Access call.
+func (d *diskCache) put(bucket, object string, generation int64, secret *Secret) error {
+	key, err := d.loadKey()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&diskCacheRecord{
+		KMSKey:    secret.KMSKey,
+		Plaintext: secret.Plaintext,
+		StoredAt:  time.Now(),
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal disk cache record")
+	}
+
+	enc, err := localKeyEncrypt(key, data)
+	if err != nil {
+		return errors.Wrap(err, "failed to encrypt disk cache record")
+	}
+
+	path := d.entryPath(cacheKey(bucket, object, generation))
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "failed to create disk cache directory")
+	}
+	if err := ioutil.WriteFile(path, enc, 0600); err != nil {
+		return errors.Wrap(err, "failed to write disk cache entry")
+	}
+	return nil
+}
+
+// get returns the cached value for bucket/object/generation, if one exists
+// and is no older than d.maxStale.
+func (d *diskCache) get(bucket, object string, generation int64) (*Secret, error) {
+	key, err := d.loadKey()
+	if err != nil {
+		return nil, err
+	}
+
+	enc, err := ioutil.ReadFile(d.entryPath(cacheKey(bucket, object, generation)))
+	if err != nil {
+		return nil, errors.Wrap(err, "no disk cache entry")
+	}
+
+	data, err := envelopeDecrypt(key, enc, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt disk cache entry")
+	}
+
+	var rec diskCacheRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal disk cache entry")
+	}
+
+	if d.maxStale > 0 && time.Since(rec.StoredAt) > d.maxStale {
+		return nil, errors.Errorf("cached secret is older than max-stale (%s)", d.maxStale)
+	}
+
+	return &Secret{Name: object, KMSKey: rec.KMSKey, Plaintext: rec.Plaintext}, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// erroringSecretStorage's ReadObject always fails, to exercise a Client's
+// disk cache fallback when live resolution is unavailable.
+type erroringSecretStorage struct {
+	SecretStorage
+}
+
+func (s *erroringSecretStorage) ReadObject(ctx context.Context, bucket, object string, generation int64) ([]byte, *StorageObject, error) {
+	return nil, nil, errors.New("simulated outage")
+}
+
+func Test_diskCache_putAndGet(t *testing.T) {
+	t.Parallel()
+
+	d := &diskCache{dir: t.TempDir(), maxStale: time.Hour}
+
+	if err := d.put("my-bucket", "my-object", -1, &Secret{KMSKey: "my-key", Plaintext: []byte("my secret value")}); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := d.get("my-bucket", "my-object", -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(got.Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+	if act, exp := got.KMSKey, "my-key"; act != exp {
+		t.Errorf("kms key = %q, want %q", act, exp)
+	}
+}
+
+func Test_diskCache_get_missing(t *testing.T) {
+	t.Parallel()
+
+	d := &diskCache{dir: t.TempDir(), maxStale: time.Hour}
+
+	if _, err := d.get("my-bucket", "my-object", -1); err == nil {
+		t.Error("expected an error for a missing cache entry")
+	}
+}
+
+func Test_diskCache_get_expired(t *testing.T) {
+	t.Parallel()
+
+	d := &diskCache{dir: t.TempDir(), maxStale: time.Nanosecond}
+
+	if err := d.put("my-bucket", "my-object", -1, &Secret{Plaintext: []byte("my secret value")}); err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if _, err := d.get("my-bucket", "my-object", -1); err == nil {
+		t.Error("expected an error for an entry older than max-stale")
+	}
+}
+
+func Test_Client_Access_diskCacheFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newMemSecretStorage()
+
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{}, WithDiskCache(t.TempDir(), time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The first Access succeeds live and populates the disk cache.
+	if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Swap in a backend that always fails, simulating an outage, and confirm
+	// the disk cache serves the previously-cached value instead of the error.
+	client.secretStorage = &erroringSecretStorage{SecretStorage: storage}
+
+	plaintext, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatalf("expected disk cache fallback to succeed, got %v", err)
+	}
+	if act, exp := string(plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+func Test_Client_Access_diskCacheFallback_missReturnsOriginalError(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+
+	client, err := NewWithBackends(ctx, &erroringSecretStorage{SecretStorage: newMemSecretStorage()}, &memKeyManager{}, WithDiskCache(t.TempDir(), time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"}); err == nil {
+		t.Error("expected an error when the disk cache has no entry to fall back to")
+	}
+}
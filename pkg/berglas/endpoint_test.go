@@ -0,0 +1,49 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"testing"
+
+	"google.golang.org/api/option"
+)
+
+func Test_splitBackendOpts(t *testing.T) {
+	t.Parallel()
+
+	generic := option.WithUserAgent("test")
+	storageOpt := WithStorageEndpoint("http://localhost:9000")
+	kmsOpt := WithKMSEndpoint("localhost:9001")
+
+	gotGeneric, gotStorage, gotKMS := splitBackendOpts([]option.ClientOption{generic, storageOpt, kmsOpt})
+
+	if len(gotGeneric) != 1 || gotGeneric[0] != generic {
+		t.Errorf("generic = %v, want [%v]", gotGeneric, generic)
+	}
+	if len(gotStorage) != 1 {
+		t.Errorf("storageOpts = %v, want 1 entry", gotStorage)
+	}
+	if len(gotKMS) != 1 {
+		t.Errorf("kmsOpts = %v, want 1 entry", gotKMS)
+	}
+}
+
+func Test_WithQuotaProject(t *testing.T) {
+	t.Parallel()
+
+	if opt := WithQuotaProject("my-project"); opt == nil {
+		t.Error("expected a non-nil option.ClientOption")
+	}
+}
@@ -15,7 +15,12 @@
 package berglas
 
 import (
+	"net/http"
+
 	"github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 const (
@@ -27,6 +32,71 @@ const (
 
 	// errSecretModified is the error returned when preconditions fail.
 	errSecretModified = Error("secret modified between read and write")
+
+	// errSecretNotModified is the error returned by Access when the caller's
+	// known generation (IfNoneMatchGeneration) still matches the current
+	// generation of the secret.
+	errSecretNotModified = Error("secret not modified")
+
+	// errSecretRetained is the error returned when an operation would delete
+	// or overwrite a secret before its configured RetainUntil timestamp has
+	// passed. See CreateRequest.RetainUntil.
+	errSecretRetained = Error("secret is retained and cannot be deleted or overwritten yet")
+
+	// errBackendUnsupported is the error returned by methods that are
+	// inherently specific to Cloud Storage and Cloud KMS (IAM, bucket
+	// management, discovery) when called on a Client created with
+	// NewWithBackends instead of New or NewClient.
+	errBackendUnsupported = Error("this operation is not supported on a client created with NewWithBackends")
+
+	// errTinkFormatUnsupported is the error returned when EnvelopeFormatTink is
+	// requested in a build that does not vendor github.com/google/tink/go.
+	errTinkFormatUnsupported = Error("tink envelope format is not supported in this build")
+
+	// errAgeKeyUnsupported is the error returned when an age:// key (see
+	// AgeKeyPrefix) is used in a build that does not vendor an age library.
+	errAgeKeyUnsupported = Error("age keys are not supported in this build")
+
+	// errUnexpectedKey is the error returned by Access when AccessRequest.
+	// ExpectKey is set and does not match the KMS key the secret is actually
+	// encrypted with.
+	errUnexpectedKey = Error("secret is not encrypted with the expected key")
+
+	// errPermissionDenied is the error returned when the caller's credentials
+	// are rejected by Cloud Storage or Cloud KMS.
+	errPermissionDenied = Error("permission denied")
+
+	// errIntegrityCheckFailed is the error returned by Read (and therefore
+	// Access) when a secret's plaintext does not hash to the digest recorded
+	// in MetadataIntegrityKey at write time.
+	errIntegrityCheckFailed = Error("secret integrity check failed")
+)
+
+// ErrSecretNotFound, ErrSecretAlreadyExists, ErrPermissionDenied, and
+// ErrPreconditionFailed are sentinel errors returned by Client methods.
+// Callers should prefer errors.Is over string-matching or type-asserting on
+// the underlying Cloud Storage/KMS client errors, since those can change
+// across client library upgrades.
+var (
+	// ErrSecretNotFound means that an operation was given a secret that does
+	// not exist.
+	ErrSecretNotFound error = errSecretDoesNotExist
+
+	// ErrSecretAlreadyExists means that Create was given a secret that
+	// already exists.
+	ErrSecretAlreadyExists error = errSecretAlreadyExists
+
+	// ErrPermissionDenied means that the caller's credentials were rejected
+	// by Cloud Storage or Cloud KMS.
+	ErrPermissionDenied error = errPermissionDenied
+
+	// ErrPreconditionFailed means that an operation's generation or
+	// metageneration precondition did not match the secret's current state.
+	ErrPreconditionFailed error = errSecretModified
+
+	// ErrIntegrityCheckFailed means that a secret's decrypted plaintext did
+	// not match the digest recorded for it in MetadataIntegrityKey.
+	ErrIntegrityCheckFailed error = errIntegrityCheckFailed
 )
 
 // Error is an error from Berglas.
@@ -54,3 +124,78 @@ func IsSecretDoesNotExistErr(err error) bool {
 func IsSecretModifiedErr(err error) bool {
 	return errors.Cause(err) == errSecretModified
 }
+
+// IsSecretNotModifiedErr returns true if the given error means that the
+// secret's generation still matches the caller-supplied IfNoneMatchGeneration.
+func IsSecretNotModifiedErr(err error) bool {
+	return errors.Cause(err) == errSecretNotModified
+}
+
+// IsSecretRetainedErr returns true if the given error means that an
+// operation was blocked by a secret's retention timestamp.
+func IsSecretRetainedErr(err error) bool {
+	return errors.Cause(err) == errSecretRetained
+}
+
+// IsTinkFormatUnsupportedErr returns true if the given error means that
+// EnvelopeFormatTink was requested in a build that does not support it.
+func IsTinkFormatUnsupportedErr(err error) bool {
+	return errors.Cause(err) == errTinkFormatUnsupported
+}
+
+// IsAgeKeyUnsupportedErr returns true if the given error means that an
+// age:// key was used in a build that does not support it.
+func IsAgeKeyUnsupportedErr(err error) bool {
+	return errors.Cause(err) == errAgeKeyUnsupported
+}
+
+// IsUnexpectedKeyErr returns true if the given error means that a secret was
+// not encrypted with the key the caller expected (see AccessRequest.ExpectKey).
+func IsUnexpectedKeyErr(err error) bool {
+	return errors.Cause(err) == errUnexpectedKey
+}
+
+// IsBackendUnsupportedErr returns true if the given error means that the
+// operation is not supported on a Client created with NewWithBackends.
+func IsBackendUnsupportedErr(err error) bool {
+	return errors.Cause(err) == errBackendUnsupported
+}
+
+// IsPermissionDeniedErr returns true if the given error means that the
+// caller's credentials were rejected by Cloud Storage or Cloud KMS.
+func IsPermissionDeniedErr(err error) bool {
+	return errors.Cause(err) == errPermissionDenied
+}
+
+// IsIntegrityCheckFailedErr returns true if the given error means that a
+// secret's decrypted plaintext did not match its recorded integrity digest.
+func IsIntegrityCheckFailedErr(err error) bool {
+	return errors.Cause(err) == errIntegrityCheckFailed
+}
+
+// isTransientAPIErr returns true if err is a Cloud Storage 429/5xx response
+// or a Cloud KMS RESOURCE_EXHAUSTED status - the failure classes a Client's
+// RetryPolicy (see WithRetryPolicy) backs off and retries.
+func isTransientAPIErr(err error) bool {
+	if terr, ok := err.(*googleapi.Error); ok {
+		return terr.Code == http.StatusTooManyRequests || (terr.Code >= 500 && terr.Code <= 599)
+	}
+	if s, ok := status.FromError(err); ok {
+		return s.Code() == codes.ResourceExhausted
+	}
+	return false
+}
+
+// wrapAPIErr wraps err with message, translating well-known Cloud Storage and
+// Cloud KMS permission failures into errPermissionDenied so callers can use
+// errors.Is (or IsPermissionDeniedErr) instead of matching on the underlying
+// client library's error types, which can change across library upgrades.
+func wrapAPIErr(err error, message string) error {
+	if terr, ok := err.(*googleapi.Error); ok && terr.Code == http.StatusForbidden {
+		return errors.Wrap(errPermissionDenied, message)
+	}
+	if s, ok := status.FromError(err); ok && s.Code() == codes.PermissionDenied {
+		return errors.Wrap(errPermissionDenied, message)
+	}
+	return errors.Wrap(err, message)
+}
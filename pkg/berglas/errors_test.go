@@ -0,0 +1,85 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_sentinelErrors_errorsIs(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		exp  error
+	}{
+		{"not found", pkgerrors.Wrap(errSecretDoesNotExist, "failed to read secret"), ErrSecretNotFound},
+		{"already exists", pkgerrors.Wrap(errSecretAlreadyExists, "failed to create secret"), ErrSecretAlreadyExists},
+		{"precondition failed", pkgerrors.Wrap(errSecretModified, "failed to delete secret"), ErrPreconditionFailed},
+		{"permission denied", pkgerrors.Wrap(errPermissionDenied, "failed to encrypt secret"), ErrPermissionDenied},
+		{"doubly wrapped", pkgerrors.Wrap(pkgerrors.Wrap(errSecretModified, "failed to update secret"), "update failed"), ErrPreconditionFailed},
+		{"integrity check failed", pkgerrors.Wrap(errIntegrityCheckFailed, "failed to read secret"), ErrIntegrityCheckFailed},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if !errors.Is(tc.err, tc.exp) {
+				t.Errorf("expected errors.Is(%v, %v) to be true", tc.err, tc.exp)
+			}
+		})
+	}
+}
+
+func Test_isTransientAPIErr(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		exp  bool
+	}{
+		{"nil", nil, false},
+		{"gcs 429", &googleapi.Error{Code: http.StatusTooManyRequests}, true},
+		{"gcs 500", &googleapi.Error{Code: http.StatusInternalServerError}, true},
+		{"gcs 503", &googleapi.Error{Code: http.StatusServiceUnavailable}, true},
+		{"gcs 403", &googleapi.Error{Code: http.StatusForbidden}, false},
+		{"gcs 404", &googleapi.Error{Code: http.StatusNotFound}, false},
+		{"kms resource exhausted", status.Error(codes.ResourceExhausted, "quota exceeded"), true},
+		{"kms permission denied", status.Error(codes.PermissionDenied, "denied"), false},
+		{"plain error", errors.New("boom"), false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := isTransientAPIErr(tc.err); got != tc.exp {
+				t.Errorf("isTransientAPIErr(%v) = %v, want %v", tc.err, got, tc.exp)
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// FileAccessRequest is used as input to a local filesystem access request.
+type FileAccessRequest struct {
+	// Path is the filesystem path to the secret.
+	Path string
+
+	// Key, if given, is a local-key:// or keyring-key:// key (see
+	// LocalKeyPrefix and KeyringKeyPrefix) used to decrypt the file's
+	// contents, for secrets created with a Key in FileCreateRequest. If
+	// empty, the file's contents are returned as-is.
+	Key string
+}
+
+// FileAccess reads a secret from the local filesystem, for development
+// workflows that run without any GCP credentials. Unlike Access, there is
+// no bucket or generation: the file at Path is the one and only version of
+// the secret.
+func (c *Client) FileAccess(ctx context.Context, i *FileAccessRequest) ([]byte, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	path := i.Path
+	if path == "" {
+		return nil, errors.New("missing path")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"path": path,
+		"key":  i.Key,
+	})
+
+	logger.Debug("fileaccess.start")
+	defer logger.Debug("fileaccess.finish")
+
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, errSecretDoesNotExist
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read secret file %s", path)
+	}
+
+	if i.Key == "" {
+		return data, nil
+	}
+
+	return fileDecrypt(i.Key, data)
+}
+
+// FileCreateRequest is used as input to a local filesystem create request.
+type FileCreateRequest struct {
+	// Path is the filesystem path at which to create the secret. Parent
+	// directories are created automatically.
+	Path string
+
+	// Plaintext is the plaintext secret data to write.
+	Plaintext []byte
+
+	// Key, if given, is a local-key:// or keyring-key:// key (see
+	// LocalKeyPrefix and KeyringKeyPrefix) used to encrypt Plaintext before
+	// writing it, so the file on disk is not itself a plaintext secret. If
+	// empty, Plaintext is written to the file as-is.
+	Key string
+}
+
+// FileCreate writes a new secret to the local filesystem. It returns
+// errSecretAlreadyExists (see IsSecretAlreadyExistsErr) if a file already
+// exists at Path.
+func (c *Client) FileCreate(ctx context.Context, i *FileCreateRequest) error {
+	if i == nil {
+		return errors.New("missing request")
+	}
+
+	path := i.Path
+	if path == "" {
+		return errors.New("missing path")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"path": path,
+		"key":  i.Key,
+	})
+
+	logger.Debug("filecreate.start")
+	defer logger.Debug("filecreate.finish")
+
+	data, err := fileEncodePlaintext(i.Key, i.Plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrapf(err, "failed to create parent directory for %s", path)
+	}
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		if os.IsExist(err) {
+			return errSecretAlreadyExists
+		}
+		return errors.Wrapf(err, "failed to create secret file %s", path)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to write secret file %s", path)
+	}
+
+	return nil
+}
+
+// FileUpdateRequest is used as input to a local filesystem update request.
+type FileUpdateRequest struct {
+	// Path is the filesystem path to the secret.
+	Path string
+
+	// Plaintext is the new plaintext secret data to write.
+	Plaintext []byte
+
+	// Key, if given, is a local-key:// or keyring-key:// key used to encrypt
+	// Plaintext before writing it. See FileCreateRequest.Key.
+	Key string
+}
+
+// FileUpdate overwrites an existing secret on the local filesystem. It
+// returns errSecretDoesNotExist (see IsSecretDoesNotExistErr) if no file
+// exists at Path.
+func (c *Client) FileUpdate(ctx context.Context, i *FileUpdateRequest) error {
+	if i == nil {
+		return errors.New("missing request")
+	}
+
+	path := i.Path
+	if path == "" {
+		return errors.New("missing path")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"path": path,
+		"key":  i.Key,
+	})
+
+	logger.Debug("fileupdate.start")
+	defer logger.Debug("fileupdate.finish")
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return errSecretDoesNotExist
+	} else if err != nil {
+		return errors.Wrapf(err, "failed to stat secret file %s", path)
+	}
+
+	data, err := fileEncodePlaintext(i.Key, i.Plaintext)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write secret file %s", path)
+	}
+
+	return nil
+}
+
+// FileDeleteRequest is used as input to a local filesystem delete request.
+type FileDeleteRequest struct {
+	// Path is the filesystem path to the secret.
+	Path string
+}
+
+// FileDelete removes a secret from the local filesystem.
+func (c *Client) FileDelete(ctx context.Context, i *FileDeleteRequest) error {
+	if i == nil {
+		return errors.New("missing request")
+	}
+
+	path := i.Path
+	if path == "" {
+		return errors.New("missing path")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{"path": path})
+	logger.Debug("filedelete.start")
+	defer logger.Debug("filedelete.finish")
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return errSecretDoesNotExist
+		}
+		return errors.Wrapf(err, "failed to delete secret file %s", path)
+	}
+
+	return nil
+}
+
+// fileEncodePlaintext returns the bytes that should be written to disk for
+// plaintext: the plaintext itself if key is empty, or the same
+// base64(encDEK):base64(ciphertext) envelope format used for local-key
+// secrets in Cloud Storage (see envelopeEncrypt) if key is given.
+func fileEncodePlaintext(key string, plaintext []byte) ([]byte, error) {
+	if key == "" {
+		return plaintext, nil
+	}
+
+	dek, ciphertext, err := envelopeEncrypt(plaintext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to perform envelope encryption")
+	}
+
+	localKey, err := resolveLocalKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	encDEK, err := localKeyEncrypt(localKey, dek)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt dek with local key")
+	}
+
+	return []byte(fmt.Sprintf("%s:%s",
+		base64.StdEncoding.EncodeToString(encDEK),
+		base64.StdEncoding.EncodeToString(ciphertext))), nil
+}
+
+// fileDecrypt decrypts data written by fileEncodePlaintext with a non-empty
+// key.
+func fileDecrypt(key string, data []byte) ([]byte, error) {
+	parts := strings.SplitN(string(data), ":", 2)
+	if len(parts) < 2 {
+		return nil, errors.New("invalid ciphertext: not enough parts")
+	}
+
+	encDEK, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid ciphertext: failed to parse dek")
+	}
+
+	ciphertext, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("invalid ciphertext: failed to parse ciphertext")
+	}
+
+	localKey, err := resolveLocalKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	dek, err := envelopeDecrypt(localKey, encDEK, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt dek with local key")
+	}
+
+	plaintext, err := envelopeDecrypt(dek, ciphertext, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt envelope")
+	}
+
+	return plaintext, nil
+}
@@ -0,0 +1,167 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSecret_plaintext(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), "nested", "secret")
+
+	if err := client.FileCreate(ctx, &FileCreateRequest{
+		Path:      path,
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := client.FileCreate(ctx, &FileCreateRequest{
+		Path:      path,
+		Plaintext: []byte("my secret value"),
+	}); !IsSecretAlreadyExistsErr(err) {
+		t.Errorf("expected secret-already-exists error, got %v", err)
+	}
+
+	plaintext, err := client.FileAccess(ctx, &FileAccessRequest{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := plaintext, []byte("my secret value"); !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	if err := client.FileUpdate(ctx, &FileUpdateRequest{
+		Path:      path,
+		Plaintext: []byte("my new secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err = client.FileAccess(ctx, &FileAccessRequest{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := plaintext, []byte("my new secret value"); !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	if err := client.FileDelete(ctx, &FileDeleteRequest{Path: path}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.FileAccess(ctx, &FileAccessRequest{Path: path}); !IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected secret-does-not-exist error, got %v", err)
+	}
+
+	if err := client.FileUpdate(ctx, &FileUpdateRequest{Path: path, Plaintext: []byte("x")}); !IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected secret-does-not-exist error, got %v", err)
+	}
+
+	if err := client.FileDelete(ctx, &FileDeleteRequest{Path: path}); !IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected secret-does-not-exist error, got %v", err)
+	}
+}
+
+func TestFileSecret_localKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, "key")
+
+	key, err := GenerateLocalKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteLocalKeyFile(keyPath, key); err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(dir, "secret")
+	localKeyRef := LocalKeyPrefix + keyPath
+
+	if err := client.FileCreate(ctx, &FileCreateRequest{
+		Path:      path,
+		Plaintext: []byte("my secret value"),
+		Key:       localKeyRef,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The file on disk should not contain the plaintext.
+	raw, err := client.FileAccess(ctx, &FileAccessRequest{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(raw, []byte("my secret value")) {
+		t.Error("expected file contents to be encrypted, got plaintext")
+	}
+
+	plaintext, err := client.FileAccess(ctx, &FileAccessRequest{Path: path, Key: localKeyRef})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := plaintext, []byte("my secret value"); !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
+
+func TestIsFileReference(t *testing.T) {
+	t.Parallel()
+
+	if !IsFileReference("file:///path/to/secret") {
+		t.Error("expected file:///path/to/secret to be a file reference")
+	}
+	if IsFileReference("berglas://my-bucket/my-secret") {
+		t.Error("expected berglas:// reference to not be a file reference")
+	}
+}
+
+func TestParseFileReference(t *testing.T) {
+	t.Parallel()
+
+	ref, err := ParseFileReference("file:///path/to/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := ref.Path(), "/path/to/secret"; act != exp {
+		t.Errorf("expected path %q to be %q", act, exp)
+	}
+
+	if _, err := ParseFileReference("berglas://my-bucket/my-secret"); err == nil {
+		t.Error("expected an error for a non-file reference")
+	}
+
+	if _, err := ParseFileReference("file://"); err == nil {
+		t.Error("expected an error for an empty path")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"github.com/pkg/errors"
+)
+
+// EnvelopeFormat selects the on-disk format used to wrap a secret's DEK.
+type EnvelopeFormat string
+
+const (
+	// EnvelopeFormatDefault is berglas' original envelope format: a DEK
+	// encrypted with Cloud KMS (or a local-key://, see IsLocalKey) and the
+	// plaintext encrypted locally with that DEK using AES-GCM. This is the
+	// format used when Format is left unset.
+	EnvelopeFormatDefault EnvelopeFormat = ""
+
+	// EnvelopeFormatTink selects a Tink keyset envelope: the DEK is stored as
+	// a Tink keyset whose keyset-encryption key is wrapped by Cloud KMS,
+	// giving forward compatibility with key rotation inside the envelope and
+	// interop with other Tink-based tooling.
+	//
+	// This format is not yet implemented in this build: it requires
+	// vendoring github.com/google/tink/go, which is not currently a
+	// dependency of this module. Requesting it returns errTinkFormatUnsupported
+	// rather than silently falling back to EnvelopeFormatDefault.
+	EnvelopeFormatTink EnvelopeFormat = "tink"
+
+	// EnvelopeFormatChunked splits the plaintext into a sequence of
+	// independently AEAD-sealed streamChunkSize chunks instead of one
+	// single-shot AES-GCM seal, so secrets larger than what's practical to
+	// hold as one ciphertext/base64 blob round-trip correctly. See
+	// CreateFromReader and AccessReader for a streaming API that reads and
+	// writes this format without holding the whole plaintext in memory at
+	// once; Create/Read/Update/Access also support it, but always encrypt
+	// or decrypt the whole plaintext in memory in one call.
+	//
+	// Which format a given secret actually uses is recorded in its
+	// MetadataEnvelopeFormatKey metadata (see Secret.Format) rather than
+	// guessed from its ciphertext, so secrets written before this format
+	// existed keep reading correctly.
+	EnvelopeFormatChunked EnvelopeFormat = "chunked-v2"
+)
+
+// validate returns an error if f is not a format this build knows how to
+// write.
+func (f EnvelopeFormat) validate() error {
+	switch f {
+	case EnvelopeFormatDefault, EnvelopeFormatChunked:
+		return nil
+	case EnvelopeFormatTink:
+		return errTinkFormatUnsupported
+	default:
+		return errors.Errorf("unknown envelope format %q", string(f))
+	}
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"testing"
+)
+
+func TestEnvelopeFormat_validate(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		format  EnvelopeFormat
+		wantErr bool
+	}{
+		{"default", EnvelopeFormatDefault, false},
+		{"chunked", EnvelopeFormatChunked, false},
+		{"tink", EnvelopeFormatTink, true},
+		{"unknown", EnvelopeFormat("bogus"), true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.format.validate()
+			if (err != nil) != tc.wantErr {
+				t.Errorf("validate() error = %v, wantErr %t", err, tc.wantErr)
+			}
+		})
+	}
+
+	if err := EnvelopeFormatTink.validate(); !IsTinkFormatUnsupportedErr(err) {
+		t.Errorf("expected tink format to report IsTinkFormatUnsupportedErr, got %v", err)
+	}
+}
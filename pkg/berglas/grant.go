@@ -16,6 +16,7 @@ package berglas
 
 import (
 	"context"
+	"fmt"
 	"sort"
 
 	"cloud.google.com/go/iam"
@@ -50,6 +51,10 @@ type GrantRequest struct {
 // Grant adds IAM permission to the given entity to the storage object and the
 // underlying KMS key.
 func (c *Client) Grant(ctx context.Context, i *GrantRequest) error {
+	if c.storageIAMClient == nil {
+		return errBackendUnsupported
+	}
+
 	if i == nil {
 		return errors.New("missing request")
 	}
@@ -88,7 +93,7 @@ func (c *Client) Grant(ctx context.Context, i *GrantRequest) error {
 		return errors.New("secret object not found")
 	}
 	if err != nil {
-		return errors.Wrap(err, "failed to read secret metadata")
+		return wrapAPIErr(err, "failed to read secret metadata")
 	}
 	if attrs.Metadata == nil || attrs.Metadata[MetadataKMSKey] == "" {
 		return errors.New("missing kms key in secret metadata")
@@ -108,7 +113,14 @@ func (c *Client) Grant(ctx context.Context, i *GrantRequest) error {
 		}
 		return p
 	}); err != nil {
-		return errors.Wrapf(err, "failed to update Storage IAM policy for %s", object)
+		return wrapAPIErr(err, fmt.Sprintf("failed to update Storage IAM policy for %s", object))
+	}
+
+	// Secrets encrypted with a local development key (see IsLocalKey) or a
+	// passphrase key (see IsPassphraseKey) have no corresponding Cloud KMS
+	// key, so there is no KMS IAM policy to grant.
+	if IsLocalKey(key) || IsPassphraseKey(key) {
+		return nil
 	}
 
 	// Grant access to KMS
@@ -121,7 +133,7 @@ func (c *Client) Grant(ctx context.Context, i *GrantRequest) error {
 		}
 		return p
 	}); err != nil {
-		return errors.Wrapf(err, "failed to update KMS IAM policy for %s", key)
+		return wrapAPIErr(err, fmt.Sprintf("failed to update KMS IAM policy for %s", key))
 	}
 
 	return nil
@@ -17,6 +17,7 @@ package berglas
 import (
 	"context"
 	"net/http"
+	"sort"
 	"strings"
 	"time"
 
@@ -41,6 +42,32 @@ func (c *Client) storageIAM(bucket, object string) *iam.Handle {
 	}, bucket+"/"+object)
 }
 
+// IAMMembers returns the members currently granted read access to the
+// secret, sorted for stable output. This is the read-only counterpart to
+// Grant and Revoke, useful for inventory and audit tooling that needs to
+// snapshot who can access a secret without granting or revoking anything.
+func (c *Client) IAMMembers(ctx context.Context, bucket, object string) ([]string, error) {
+	if c.storageIAMClient == nil {
+		return nil, errBackendUnsupported
+	}
+
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+
+	p, err := getIAMPolicy(ctx, c.storageIAM(bucket, object))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get IAM policy")
+	}
+
+	members := p.Members(iamObjectReader)
+	sort.Strings(members)
+	return members, nil
+}
+
 // iamClient implements the iam.client interface.
 type iamClient struct {
 	raw *storagev1.Service
@@ -62,6 +89,13 @@ func (c *iamClient) Get(ctx context.Context, resource string) (*iampb.Policy, er
 	return iamFromStoragePolicy(rp), nil
 }
 
+// GetWithVersion is like Get, ignoring requestedPolicyVersion: Cloud Storage
+// object ACLs do not support IAM conditions, so there is only one policy
+// version to return.
+func (c *iamClient) GetWithVersion(ctx context.Context, resource string, requestedPolicyVersion int32) (*iampb.Policy, error) {
+	return c.Get(ctx, resource)
+}
+
 func (c *iamClient) Set(ctx context.Context, resource string, p *iampb.Policy) error {
 	bucket, object, err := parseBucketObj(resource)
 	if err != nil {
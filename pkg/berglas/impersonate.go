@@ -0,0 +1,136 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/google"
+	iamcredentials "google.golang.org/api/iamcredentials/v1"
+	"google.golang.org/api/option"
+)
+
+// ImpersonateScopes are the OAuth scopes requested for impersonated tokens.
+var ImpersonateScopes = []string{"https://www.googleapis.com/auth/cloud-platform"}
+
+// ParseImpersonateChain parses a comma-separated impersonation delegate chain
+// of the form "delegate1,delegate2,target" (as accepted by the CLI's
+// --impersonate flag) into the final target service account and the
+// delegates to hop through to reach it, in the order the IAM Credentials API
+// expects them.
+func ParseImpersonateChain(s string) (target string, delegates []string) {
+	parts := strings.Split(s, ",")
+	for i, p := range parts {
+		parts[i] = strings.TrimSpace(p)
+	}
+	return parts[len(parts)-1], parts[:len(parts)-1]
+}
+
+// WithImpersonation returns an option.ClientOption that authenticates as
+// impersonate instead of the ambient credentials, so operators can access
+// secrets using short-lived impersonated credentials instead of
+// distributing service account key files. impersonate is a target service
+// account email, optionally preceded by a comma-separated delegate chain
+// ending in the target, in the format "delegate1,delegate2,target" - see
+// ParseImpersonateChain. Passing the result to New or NewClient applies it
+// to both the Cloud Storage and Cloud KMS clients.
+func WithImpersonation(ctx context.Context, impersonate string) (option.ClientOption, error) {
+	target, delegates := ParseImpersonateChain(impersonate)
+	ts, err := ImpersonateTokenSource(ctx, target, delegates)
+	if err != nil {
+		return nil, err
+	}
+	return option.WithTokenSource(ts), nil
+}
+
+// ImpersonateTokenSource returns an oauth2.TokenSource that exchanges the
+// caller's ambient credentials for short-lived credentials of
+// targetServiceAccount via the IAM Credentials API's generateAccessToken,
+// hopping through delegates (if any) as intermediate links in the
+// delegation chain.
+//
+// The caller (or the last delegate in the chain) must be granted
+// roles/iam.serviceAccountTokenCreator on targetServiceAccount, and each
+// preceding delegate must be granted that role on the next account in the
+// chain.
+func ImpersonateTokenSource(ctx context.Context, targetServiceAccount string, delegates []string) (oauth2.TokenSource, error) {
+	if targetServiceAccount == "" {
+		return nil, errors.New("missing target service account")
+	}
+
+	src, err := google.DefaultTokenSource(ctx, ImpersonateScopes...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load default credentials")
+	}
+
+	svc, err := iamcredentials.NewService(ctx, option.WithTokenSource(src))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create iamcredentials client")
+	}
+
+	delegateNames := make([]string, len(delegates))
+	for i, d := range delegates {
+		delegateNames[i] = serviceAccountResourceName(d)
+	}
+
+	its := &impersonateTokenSource{
+		svc:       svc,
+		name:      serviceAccountResourceName(targetServiceAccount),
+		delegates: delegateNames,
+	}
+	return oauth2.ReuseTokenSource(nil, its), nil
+}
+
+// serviceAccountResourceName returns email in the
+// "projects/-/serviceAccounts/{email}" form required by the IAM Credentials
+// API.
+func serviceAccountResourceName(email string) string {
+	return "projects/-/serviceAccounts/" + email
+}
+
+// impersonateTokenSource is an oauth2.TokenSource that calls generateAccessToken
+// on every call to Token. Wrap it in oauth2.ReuseTokenSource so the token is
+// only refreshed once it is close to expiring.
+type impersonateTokenSource struct {
+	svc       *iamcredentials.Service
+	name      string
+	delegates []string
+}
+
+// Token implements oauth2.TokenSource.
+func (i *impersonateTokenSource) Token() (*oauth2.Token, error) {
+	resp, err := i.svc.Projects.ServiceAccounts.GenerateAccessToken(i.name, &iamcredentials.GenerateAccessTokenRequest{
+		Delegates: i.delegates,
+		Scope:     ImpersonateScopes,
+	}).Do()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate impersonated access token")
+	}
+
+	expiry, err := time.Parse(time.RFC3339, resp.ExpireTime)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse impersonated token expiry")
+	}
+
+	return &oauth2.Token{
+		AccessToken: resp.AccessToken,
+		TokenType:   "Bearer",
+		Expiry:      expiry,
+	}, nil
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseImpersonateChain(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name         string
+		s            string
+		expTarget    string
+		expDelegates []string
+	}{
+		{"single", "target@project.iam.gserviceaccount.com", "target@project.iam.gserviceaccount.com", []string{}},
+		{"chain", "sa1@project.iam.gserviceaccount.com,sa2@project.iam.gserviceaccount.com,target@project.iam.gserviceaccount.com",
+			"target@project.iam.gserviceaccount.com",
+			[]string{"sa1@project.iam.gserviceaccount.com", "sa2@project.iam.gserviceaccount.com"}},
+		{"spaces", "sa1@project.iam.gserviceaccount.com, target@project.iam.gserviceaccount.com",
+			"target@project.iam.gserviceaccount.com",
+			[]string{"sa1@project.iam.gserviceaccount.com"}},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			target, delegates := ParseImpersonateChain(tc.s)
+			if target != tc.expTarget {
+				t.Errorf("expected target %q to be %q", target, tc.expTarget)
+			}
+			if !reflect.DeepEqual(delegates, tc.expDelegates) {
+				t.Errorf("expected delegates %#v to be %#v", delegates, tc.expDelegates)
+			}
+		})
+	}
+}
+
+func TestWithImpersonation(t *testing.T) {
+	t.Parallel()
+
+	if _, err := WithImpersonation(context.Background(), ""); err == nil {
+		t.Error("expected error for missing target service account")
+	}
+}
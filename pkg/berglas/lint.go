@@ -0,0 +1,272 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// minSecretLength is the length, in bytes, below which a decrypted
+	// secret's value is flagged as weak by Lint.
+	minSecretLength = 12
+
+	// pwnedPasswordsRangeURL is the k-anonymity range endpoint Lint queries
+	// when LintRequest.CheckBreached is set. Only the first five hex
+	// characters of a secret's SHA-1 hash are ever sent; the full hash, and
+	// the secret itself, never leave the machine. See
+	// https://haveibeenpwned.com/API/v3#PwnedPasswords.
+	pwnedPasswordsRangeURL = "https://api.pwnedpasswords.com/range/"
+)
+
+// LintRequest is used as input to lint the secrets in a bucket.
+type LintRequest struct {
+	// Bucket is the name of the bucket to lint.
+	Bucket string
+
+	// Prefix matches secret names to filter, like ListRequest.Prefix.
+	Prefix string
+
+	// Decrypt, if true, decrypts each secret's current value to check for
+	// weak and reused values. Checks that only need object metadata (like
+	// missing rotation policies) run regardless of this setting.
+	Decrypt bool
+
+	// CheckBreached, if true, checks each decrypted value against the
+	// haveibeenpwned.com Pwned Passwords k-anonymity API. Requires Decrypt.
+	CheckBreached bool
+}
+
+// LintIssue is a single finding reported by Lint.
+type LintIssue struct {
+	// Secret is the name of the object the issue was found on.
+	Secret string
+
+	// Generation is the generation of the object the issue was found on, or
+	// zero if the issue applies to the secret as a whole rather than one
+	// generation of it (for example ReusedValue, which compares live values).
+	Generation int64
+
+	// Severity is one of "critical", "warning", or "info", in descending
+	// order of how urgently the finding deserves attention.
+	Severity string
+
+	// Check is a short machine-readable name for the kind of finding, e.g.
+	// "weak-value" or "no-rotation-policy".
+	Check string
+
+	// Message is a human-readable description of the finding.
+	Message string
+}
+
+// lintIssueList sorts LintIssues by severity (most urgent first), then by
+// secret name, so the report is usable without the caller re-sorting it.
+type lintIssueList []*LintIssue
+
+var lintSeverityRank = map[string]int{"critical": 0, "warning": 1, "info": 2}
+
+func (l lintIssueList) Len() int {
+	return len(l)
+}
+
+func (l lintIssueList) Less(i, j int) bool {
+	if ri, rj := lintSeverityRank[l[i].Severity], lintSeverityRank[l[j].Severity]; ri != rj {
+		return ri < rj
+	}
+	return l[i].Secret < l[j].Secret
+}
+
+func (l lintIssueList) Swap(i, j int) {
+	l[i], l[j] = l[j], l[i]
+}
+
+// LintResponse is the response from a lint call.
+type LintResponse struct {
+	// Issues are the findings, most urgent first.
+	Issues []*LintIssue
+}
+
+// Lint is a top-level package function for linting the secrets in a bucket.
+// For large volumes of secrets, please create a client instead.
+func Lint(ctx context.Context, i *LintRequest) (*LintResponse, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Lint(ctx, i)
+}
+
+// Lint inspects the secrets in a bucket for hygiene issues: secrets with no
+// retain-until (rotation) policy, and - only if i.Decrypt is set - weak
+// values, values reused across more than one secret, and (if
+// i.CheckBreached is also set) values that appear in a known breach corpus.
+// It does not fail on a per-secret basis; problems decrypting or checking an
+// individual secret are reported as issues rather than returned as errors.
+func (c *Client) Lint(ctx context.Context, i *LintRequest) (*LintResponse, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	if i.CheckBreached && !i.Decrypt {
+		return nil, errors.New("CheckBreached requires Decrypt")
+	}
+
+	listResp, err := c.List(ctx, &ListRequest{
+		Bucket: bucket,
+		Prefix: i.Prefix,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list secrets")
+	}
+
+	var issues lintIssueList
+	valuesBySum := make(map[[sha256.Size]byte][]string)
+
+	for _, s := range listResp.Secrets {
+		if s.RetainUntil.IsZero() {
+			issues = append(issues, &LintIssue{
+				Secret:     s.Name,
+				Generation: s.Generation,
+				Severity:   "info",
+				Check:      "no-rotation-policy",
+				Message:    "secret has no retain-until policy set; consider rotating it on a schedule",
+			})
+		}
+
+		if !i.Decrypt {
+			continue
+		}
+
+		plaintext, err := c.Access(ctx, &AccessRequest{
+			Bucket:     bucket,
+			Object:     s.Name,
+			Generation: s.Generation,
+		})
+		if err != nil {
+			issues = append(issues, &LintIssue{
+				Secret:     s.Name,
+				Generation: s.Generation,
+				Severity:   "warning",
+				Check:      "decrypt-failed",
+				Message:    errors.Wrap(err, "failed to decrypt for linting").Error(),
+			})
+			continue
+		}
+
+		if len(plaintext) < minSecretLength {
+			issues = append(issues, &LintIssue{
+				Secret:     s.Name,
+				Generation: s.Generation,
+				Severity:   "warning",
+				Check:      "weak-value",
+				Message:    fmt.Sprintf("value is only %d bytes; consider a longer secret", len(plaintext)),
+			})
+		}
+
+		sum := sha256.Sum256(plaintext)
+		valuesBySum[sum] = append(valuesBySum[sum], s.Name)
+
+		if i.CheckBreached {
+			breached, err := checkBreached(ctx, plaintext)
+			if err != nil {
+				issues = append(issues, &LintIssue{
+					Secret:     s.Name,
+					Generation: s.Generation,
+					Severity:   "warning",
+					Check:      "breach-check-failed",
+					Message:    errors.Wrap(err, "failed to check value against breach corpus").Error(),
+				})
+			} else if breached {
+				issues = append(issues, &LintIssue{
+					Secret:     s.Name,
+					Generation: s.Generation,
+					Severity:   "critical",
+					Check:      "breached-value",
+					Message:    "value appears in the haveibeenpwned.com Pwned Passwords corpus",
+				})
+			}
+		}
+	}
+
+	for _, names := range valuesBySum {
+		if len(names) < 2 {
+			continue
+		}
+
+		for i, name := range names {
+			others := append(append([]string{}, names[:i]...), names[i+1:]...)
+			issues = append(issues, &LintIssue{
+				Secret:   name,
+				Severity: "warning",
+				Check:    "reused-value",
+				Message:  fmt.Sprintf("value is identical to %s", strings.Join(others, ", ")),
+			})
+		}
+	}
+
+	sort.Sort(issues)
+
+	return &LintResponse{
+		Issues: issues,
+	}, nil
+}
+
+// checkBreached reports whether plaintext appears in the haveibeenpwned.com
+// Pwned Passwords corpus, using the k-anonymity range API: only the first
+// five hex characters of the value's SHA-1 hash are sent, and the service
+// returns every hash suffix sharing that prefix for a local comparison.
+func checkBreached(ctx context.Context, plaintext []byte) (bool, error) {
+	sum := sha1.Sum(plaintext)
+	full := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := full[:5], full[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pwnedPasswordsRangeURL+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return false, errors.Errorf("breach corpus returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		if strings.HasPrefix(scanner.Text(), suffix+":") {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
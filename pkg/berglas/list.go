@@ -78,6 +78,10 @@ type ListRequest struct {
 // of secrets.
 func (c *Client) List(
 	ctx context.Context, i *ListRequest) (*ListResponse, error) {
+	if c.storageClient == nil {
+		return nil, errBackendUnsupported
+	}
+
 	if i == nil {
 		return nil, errors.New("missing request")
 	}
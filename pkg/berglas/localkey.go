@@ -0,0 +1,184 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/zalando/go-keyring"
+)
+
+// LocalKeyPrefix identifies a --key value as a path to a local development
+// key (generated by "berglas keygen") instead of a Cloud KMS key resource
+// name. Secrets created or updated with a local key are NOT protected by
+// Cloud KMS: anyone with the key file can decrypt them. This mode exists so
+// developers can run the full berglas workflow without Cloud KMS access, and
+// must never be used in production.
+const LocalKeyPrefix = "local-key://"
+
+// KeyringKeyPrefix identifies a --key value as the name of a local
+// development key (generated by "berglas keygen --keyring") stored in the
+// OS keychain rather than a plaintext file under LocalKeyPrefix. This closes
+// the one remaining way a local development key sat on disk in the clear:
+// the key material itself now never touches the filesystem, at the cost of
+// only being usable on the machine that generated it.
+const KeyringKeyPrefix = "keyring-key://"
+
+// AgeKeyPrefix identifies a --key value as an age
+// (https://github.com/FiloSottile/age) identity instead of a Cloud KMS key.
+// NOT YET SUPPORTED: berglas vendors no age library, so wrapDEK and
+// unwrapDEK reject an age:// key with an error satisfying
+// IsAgeKeyUnsupportedErr rather than silently falling back to something
+// else. Use PassphraseKeyPrefix or LocalKeyPrefix in the meantime.
+const AgeKeyPrefix = "age://"
+
+// IsAgeKey returns true if key identifies an age identity rather than a
+// Cloud KMS key.
+func IsAgeKey(key string) bool {
+	return strings.HasPrefix(key, AgeKeyPrefix)
+}
+
+// keyringKeyService is the OS keychain "service" (macOS) or equivalent under
+// which local development keys are stored, distinct from keyringService in
+// package agent since the two caches are unrelated.
+const keyringKeyService = "berglas-local-key"
+
+// IsLocalKey returns true if key identifies a local development key, stored
+// either in a plaintext file (LocalKeyPrefix) or the OS keychain
+// (KeyringKeyPrefix), rather than a Cloud KMS key.
+func IsLocalKey(key string) bool {
+	return strings.HasPrefix(key, LocalKeyPrefix) || strings.HasPrefix(key, KeyringKeyPrefix)
+}
+
+// IsKeyringKey returns true if key identifies a local development key
+// stored in the OS keychain rather than a plaintext file.
+func IsKeyringKey(key string) bool {
+	return strings.HasPrefix(key, KeyringKeyPrefix)
+}
+
+// localKeyPath returns the filesystem path encoded in a local-key:// key.
+func localKeyPath(key string) string {
+	return strings.TrimPrefix(key, LocalKeyPrefix)
+}
+
+// GenerateLocalKey generates a new random AES-256 key suitable for use as a
+// local development key.
+func GenerateLocalKey() ([]byte, error) {
+	key := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, key); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random key bytes")
+	}
+	return key, nil
+}
+
+// WriteLocalKeyFile writes key to path, base64-encoded, creating any missing
+// parent directories. The file is created with permissions readable only by
+// the current user, since it is the sole thing protecting secrets encrypted
+// with it.
+func WriteLocalKeyFile(path string, key []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Wrap(err, "failed to create parent directory for local key")
+	}
+
+	enc := base64.StdEncoding.EncodeToString(key)
+	if err := ioutil.WriteFile(path, []byte(enc), 0600); err != nil {
+		return errors.Wrap(err, "failed to write local key file")
+	}
+	return nil
+}
+
+// ReadLocalKeyFile reads and decodes the local development key at path.
+func ReadLocalKeyFile(path string) ([]byte, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read local key file")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(b)))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode local key file")
+	}
+	return key, nil
+}
+
+// keyringKeyName returns the keychain entry name encoded in a
+// keyring-key:// key.
+func keyringKeyName(key string) string {
+	return strings.TrimPrefix(key, KeyringKeyPrefix)
+}
+
+// WriteKeyringKey stores key, base64-encoded, in the OS keychain under name.
+func WriteKeyringKey(name string, key []byte) error {
+	enc := base64.StdEncoding.EncodeToString(key)
+	if err := keyring.Set(keyringKeyService, name, enc); err != nil {
+		return errors.Wrap(err, "failed to save local key to OS keychain")
+	}
+	return nil
+}
+
+// ReadKeyringKey reads and decodes the local development key stored in the
+// OS keychain under name.
+func ReadKeyringKey(name string) ([]byte, error) {
+	s, err := keyring.Get(keyringKeyService, name)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read local key from OS keychain")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decode local key from OS keychain")
+	}
+	return key, nil
+}
+
+// resolveLocalKey reads the raw key bytes identified by key, which must
+// satisfy IsLocalKey, from whichever of the file or keychain backing stores
+// it names.
+func resolveLocalKey(key string) ([]byte, error) {
+	if IsKeyringKey(key) {
+		return ReadKeyringKey(keyringKeyName(key))
+	}
+	return ReadLocalKeyFile(localKeyPath(key))
+}
+
+// localKeyEncrypt encrypts plaintext with key using AES-GCM, in the same
+// format envelopeDecrypt expects.
+func localKeyEncrypt(key, plaintext []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher from local key")
+	}
+
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gcm from local key")
+	}
+
+	nonce := make([]byte, aesgcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random nonce bytes")
+	}
+
+	return aesgcm.Seal(nonce, nonce, plaintext, nil), nil
+}
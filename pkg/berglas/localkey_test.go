@@ -0,0 +1,163 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/zalando/go-keyring"
+)
+
+func TestIsLocalKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		key  string
+		exp  bool
+	}{
+		{"local", "local-key:///tmp/foo.key", true},
+		{"keyring", "keyring-key://dev", true},
+		{"kms", "projects/p/locations/l/keyRings/r/cryptoKeys/k", false},
+		{"empty", "", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsLocalKey(tc.key); got != tc.exp {
+				t.Errorf("IsLocalKey(%q) = %t, want %t", tc.key, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestIsKeyringKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		key  string
+		exp  bool
+	}{
+		{"keyring", "keyring-key://dev", true},
+		{"local", "local-key:///tmp/foo.key", false},
+		{"kms", "projects/p/locations/l/keyRings/r/cryptoKeys/k", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsKeyringKey(tc.key); got != tc.exp {
+				t.Errorf("IsKeyringKey(%q) = %t, want %t", tc.key, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestGenerateWriteReadLocalKeyFile(t *testing.T) {
+	t.Parallel()
+
+	dir, err := ioutil.TempDir("", "berglas-localkey-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := filepath.Join(dir, "nested", "dev.key")
+
+	key, err := GenerateLocalKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(key) != 32 {
+		t.Fatalf("expected 32-byte key, got %d bytes", len(key))
+	}
+
+	if err := WriteLocalKeyFile(path, key); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadLocalKeyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("ReadLocalKeyFile returned %v, want %v", got, key)
+	}
+}
+
+func TestWriteReadKeyringKey(t *testing.T) {
+	keyring.MockInit()
+
+	key, err := GenerateLocalKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteKeyringKey("dev", key); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadKeyringKey("dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("ReadKeyringKey returned %v, want %v", got, key)
+	}
+
+	got, err = resolveLocalKey(KeyringKeyPrefix + "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, key) {
+		t.Errorf("resolveLocalKey returned %v, want %v", got, key)
+	}
+}
+
+func TestLocalKeyEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	key, err := GenerateLocalKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := []byte("my-dek-material")
+
+	ciphertext, err := localKeyEncrypt(key, plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := envelopeDecrypt(key, ciphertext, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Errorf("envelopeDecrypt(localKeyEncrypt(...)) = %v, want %v", got, plaintext)
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.21
+
+package berglas
+
+import (
+	"fmt"
+	"log/slog"
+)
+
+// slogLogger adapts a *slog.Logger to Logger. slog has no dedicated warn
+// method with the same name as logrus's, and no printf-style methods at
+// all, so Warn/Warnf/Debugf are implemented in terms of Log and Sprintf.
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger adapts l to Logger, so it can be passed to SetLogger or
+// WithLogger. It requires Go 1.21 or newer, since that's when log/slog was
+// introduced.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+// WithField implements Logger.
+func (l *slogLogger) WithField(key string, value interface{}) Logger {
+	return &slogLogger{l: l.l.With(key, value)}
+}
+
+// WithFields implements Logger.
+func (l *slogLogger) WithFields(fields map[string]interface{}) Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	return &slogLogger{l: l.l.With(args...)}
+}
+
+// WithError implements Logger.
+func (l *slogLogger) WithError(err error) Logger {
+	return &slogLogger{l: l.l.With("error", err)}
+}
+
+// Debug implements Logger.
+func (l *slogLogger) Debug(args ...interface{}) { l.l.Debug(fmt.Sprint(args...)) }
+
+// Debugf implements Logger.
+func (l *slogLogger) Debugf(format string, args ...interface{}) {
+	l.l.Debug(fmt.Sprintf(format, args...))
+}
+
+// Warn implements Logger.
+func (l *slogLogger) Warn(args ...interface{}) { l.l.Warn(fmt.Sprint(args...)) }
+
+// Warnf implements Logger.
+func (l *slogLogger) Warnf(format string, args ...interface{}) {
+	l.l.Warn(fmt.Sprintf(format, args...))
+}
+
+// Error implements Logger.
+func (l *slogLogger) Error(args ...interface{}) { l.l.Error(fmt.Sprint(args...)) }
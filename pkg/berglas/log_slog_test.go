@@ -0,0 +1,44 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build go1.21
+
+package berglas
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func Test_SlogLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	l := NewSlogLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	l.WithField("bucket", "my-bucket").
+		WithFields(map[string]interface{}{"object": "my-object"}).
+		WithError(errors.New("test error")).
+		Warnf("failed to %s", "frob")
+
+	out := buf.String()
+	for _, want := range []string{"bucket=my-bucket", "object=my-object", "error=", "failed to frob"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected log output %q to contain %q", out, want)
+		}
+	}
+}
@@ -3,7 +3,6 @@ package berglas
 import (
 	"bytes"
 	"encoding/json"
-	"io"
 	"strconv"
 	"time"
 
@@ -11,42 +10,89 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Logger is the logging interface a Client uses to record what it's doing,
+// independent of any particular logging library. It is satisfied by
+// NewLogrusLogger (the default), the log/slog adapter NewSlogLogger (on
+// Go 1.21+), and the go.uber.org/zap adapter in package
+// github.com/GoogleCloudPlatform/berglas/pkg/zaplogger, so embedders that
+// standardize on one of those don't need to run a second logging stack just
+// for berglas. Set it with SetLogger or WithLogger.
+type Logger interface {
+	// WithField and WithFields return a Logger that annotates every
+	// subsequent entry with the given key/value pairs, without mutating the
+	// receiver.
+	WithField(key string, value interface{}) Logger
+	WithFields(fields map[string]interface{}) Logger
+
+	// WithError returns a Logger that annotates every subsequent entry with
+	// err, without mutating the receiver.
+	WithError(err error) Logger
+
+	Debug(args ...interface{})
+	Debugf(format string, args ...interface{})
+	Warn(args ...interface{})
+	Warnf(format string, args ...interface{})
+	Error(args ...interface{})
+}
+
 // SetLogger is a lower-level library that allows injecting a custom logger.
-func (c *Client) SetLogger(l *logrus.Logger) {
+// See Logger.
+func (c *Client) SetLogger(l Logger) {
 	c.loggerLock.Lock()
-	c.logger = l
+	c.logger = &redactingLogger{Logger: l}
 	c.loggerLock.Unlock()
 }
 
 // Logger returns the logger instance attached to this client.
-func (c *Client) Logger() *logrus.Logger {
+func (c *Client) Logger() Logger {
 	c.loggerLock.RLock()
 	l := c.logger
 	c.loggerLock.RUnlock()
 	return l
 }
 
-// SetLogLevel is a high-level function for setting the log level.
-func (c *Client) SetLogLevel(level logrus.Level) {
-	c.loggerLock.Lock()
-	c.logger.SetLevel(level)
-	c.loggerLock.Unlock()
+// logrusLogger adapts a logrus.FieldLogger - satisfied by both *logrus.Logger
+// and *logrus.Entry - to Logger.
+type logrusLogger struct {
+	entry logrus.FieldLogger
 }
 
-// SetLogOutput is a high-level function for setting log output destination.
-func (c *Client) SetLogOutput(out io.Writer) {
-	c.loggerLock.Lock()
-	c.logger.SetOutput(out)
-	c.loggerLock.Unlock()
+// NewLogrusLogger adapts l to Logger, so it can be passed to SetLogger or
+// WithLogger.
+func NewLogrusLogger(l *logrus.Logger) Logger {
+	return &logrusLogger{entry: l}
 }
 
-// SetLogFormatter sets the format of the logger. Use
-func (c *Client) SetLogFormatter(formatter logrus.Formatter) {
-	c.loggerLock.Lock()
-	c.logger.SetFormatter(formatter)
-	c.loggerLock.Unlock()
+// WithField implements Logger.
+func (l *logrusLogger) WithField(key string, value interface{}) Logger {
+	return &logrusLogger{entry: l.entry.WithField(key, value)}
 }
 
+// WithFields implements Logger.
+func (l *logrusLogger) WithFields(fields map[string]interface{}) Logger {
+	return &logrusLogger{entry: l.entry.WithFields(logrus.Fields(fields))}
+}
+
+// WithError implements Logger.
+func (l *logrusLogger) WithError(err error) Logger {
+	return &logrusLogger{entry: l.entry.WithError(err)}
+}
+
+// Debug implements Logger.
+func (l *logrusLogger) Debug(args ...interface{}) { l.entry.Debug(args...) }
+
+// Debugf implements Logger.
+func (l *logrusLogger) Debugf(format string, args ...interface{}) { l.entry.Debugf(format, args...) }
+
+// Warn implements Logger.
+func (l *logrusLogger) Warn(args ...interface{}) { l.entry.Warn(args...) }
+
+// Warnf implements Logger.
+func (l *logrusLogger) Warnf(format string, args ...interface{}) { l.entry.Warnf(format, args...) }
+
+// Error implements Logger.
+func (l *logrusLogger) Error(args ...interface{}) { l.entry.Error(args...) }
+
 // LogFormatterStackdriver is a logrus-compatible formatter that formats entries
 // in a Stackdriver-compatible way. It specifically produces JSON structured logs.
 type LogFormatterStackdriver struct{}
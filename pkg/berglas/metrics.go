@@ -0,0 +1,116 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"expvar"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Stats is a snapshot of the counters tracked across every berglas Client in
+// this process.
+type Stats struct {
+	// Accesses is the number of completed Access calls.
+	Accesses int64
+
+	// CacheHits is the number of Access calls that were satisfied by an
+	// IfNoneMatchGeneration check instead of a full download and decrypt.
+	CacheHits int64
+
+	// KMSCalls is the number of Encrypt and Decrypt calls made to Cloud KMS.
+	KMSCalls int64
+
+	// Errors is the number of failed calls, keyed by operation (e.g.
+	// "access", "create").
+	Errors map[string]int64
+
+	// AccessLatencySeconds is the cumulative wall-clock time spent in Access
+	// calls, in seconds. Divide by Accesses for the mean resolution latency.
+	AccessLatencySeconds float64
+}
+
+var (
+	statAccesses  int64
+	statCacheHits int64
+	statKMSCalls  int64
+
+	// statAccessLatencyNanos is the running sum of Access call durations, in
+	// nanoseconds. A sum this way (rather than a fixed set of histogram
+	// buckets) keeps the same homegrown, dependency-free style as the
+	// counters above; WritePrometheus exposes it as a Prometheus summary's
+	// _sum alongside Accesses as its _count.
+	statAccessLatencyNanos int64
+
+	statErrorsMu sync.Mutex
+	statErrors   = make(map[string]int64)
+)
+
+func init() {
+	expvar.Publish("berglas", expvar.Func(func() interface{} {
+		return GlobalStats()
+	}))
+}
+
+// GlobalStats returns a snapshot of the counters tracked across every
+// berglas Client in this process. The same snapshot is published under the
+// "berglas" key in expvar, so embedders that don't use Prometheus can still
+// surface berglas health in their own monitoring.
+func GlobalStats() Stats {
+	statErrorsMu.Lock()
+	errs := make(map[string]int64, len(statErrors))
+	for k, v := range statErrors {
+		errs[k] = v
+	}
+	statErrorsMu.Unlock()
+
+	return Stats{
+		Accesses:             atomic.LoadInt64(&statAccesses),
+		CacheHits:            atomic.LoadInt64(&statCacheHits),
+		KMSCalls:             atomic.LoadInt64(&statKMSCalls),
+		Errors:               errs,
+		AccessLatencySeconds: time.Duration(atomic.LoadInt64(&statAccessLatencyNanos)).Seconds(),
+	}
+}
+
+// Stats returns a snapshot of the counters tracked across every berglas
+// Client in this process. Counters are process-wide, not per-Client, because
+// they are also published through the process-wide expvar registry.
+func (c *Client) Stats() Stats {
+	return GlobalStats()
+}
+
+func recordAccess() {
+	atomic.AddInt64(&statAccesses, 1)
+}
+
+func recordCacheHit() {
+	atomic.AddInt64(&statCacheHits, 1)
+}
+
+func recordKMSCall() {
+	atomic.AddInt64(&statKMSCalls, 1)
+}
+
+func recordAccessLatency(d time.Duration) {
+	atomic.AddInt64(&statAccessLatencyNanos, int64(d))
+}
+
+func recordError(op string) {
+	statErrorsMu.Lock()
+	statErrors[op]++
+	statErrorsMu.Unlock()
+}
@@ -0,0 +1,41 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import "testing"
+
+func TestStats(t *testing.T) {
+	before := GlobalStats()
+
+	recordAccess()
+	recordCacheHit()
+	recordKMSCall()
+	recordError("test")
+
+	after := (&Client{}).Stats()
+
+	if got, exp := after.Accesses, before.Accesses+1; got != exp {
+		t.Errorf("expected Accesses %d to be %d", got, exp)
+	}
+	if got, exp := after.CacheHits, before.CacheHits+1; got != exp {
+		t.Errorf("expected CacheHits %d to be %d", got, exp)
+	}
+	if got, exp := after.KMSCalls, before.KMSCalls+1; got != exp {
+		t.Errorf("expected KMSCalls %d to be %d", got, exp)
+	}
+	if got, exp := after.Errors["test"], before.Errors["test"]+1; got != exp {
+		t.Errorf("expected Errors[test] %d to be %d", got, exp)
+	}
+}
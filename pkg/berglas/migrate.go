@@ -0,0 +1,261 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// MigrateResult is the outcome of a MigrateToSM or MigrateFromSM call.
+type MigrateResult struct {
+	// Migrated is the destination names of secrets that were successfully
+	// copied, in the order they were processed.
+	Migrated []string
+
+	// Skipped is the destination names of secrets that already existed at
+	// the destination before this run and so were left untouched.
+	Skipped []string
+
+	// Conflicts maps a destination name to the two or more source names
+	// that both mapped to it. None of the secrets named in a conflict are
+	// migrated; resolve the naming collision (typically by renaming one of
+	// the source secrets) and run again.
+	Conflicts map[string][]string
+
+	// Failed maps a destination name to the error that occurred while
+	// migrating it. Migration keeps going past individual failures so a
+	// handful of bad secrets don't block the rest of the batch.
+	Failed map[string]error
+}
+
+// smSecretID maps a berglas object name to the Secret Manager secret ID it
+// migrates to. Secret Manager IDs may only contain letters, digits,
+// underscores, and hyphens - unlike a Cloud Storage object name, they have
+// no notion of a "/"-delimited hierarchy - so each "/" is escaped as a
+// literal doubled underscore. smObjectName reverses the same mapping.
+//
+// Because the mapping is lossy (an object already containing "__" collides
+// with one that only collides after escaping), MigrateToSM and
+// MigrateFromSM both detect and report any two source secrets that map to
+// the same destination name rather than silently overwriting one with the
+// other.
+func smSecretID(object string) string {
+	return strings.ReplaceAll(object, "/", "__")
+}
+
+// smObjectName reverses smSecretID.
+func smObjectName(secretID string) string {
+	return strings.ReplaceAll(secretID, "__", "/")
+}
+
+// MigrateToSMRequest is used as input to MigrateToSM.
+type MigrateToSMRequest struct {
+	// Bucket is the name of the berglas bucket to migrate secrets out of.
+	Bucket string
+
+	// Project is the GCP project ID or number to create Secret Manager
+	// secrets in.
+	Project string
+}
+
+// MigrateToSM copies every secret in i.Bucket to Secret Manager in
+// i.Project, mapping each object name to a secret ID with smSecretID.
+// Copying a secret is idempotent: it is implemented as SMCreate, which adds
+// a new version to an already-existing destination secret rather than
+// failing, so an interrupted or repeated migration can simply be run again.
+// A destination secret that existed before this run is still reported, in
+// Migrated, since a new version was added to it either way.
+func (c *Client) MigrateToSM(ctx context.Context, i *MigrateToSMRequest) (*MigrateResult, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"bucket":  bucket,
+		"project": project,
+	})
+
+	logger.Debug("migratetosm.start")
+	defer logger.Debug("migratetosm.finish")
+
+	resp, err := c.List(ctx, &ListRequest{Bucket: bucket})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list berglas secrets")
+	}
+
+	names := make([]string, 0, len(resp.Secrets))
+	for _, s := range resp.Secrets {
+		names = append(names, s.Name)
+	}
+	sort.Strings(names)
+
+	result := &MigrateResult{
+		Conflicts: conflictsByMappedName(names, smSecretID),
+		Failed:    make(map[string]error),
+	}
+
+	for _, name := range names {
+		secretID := smSecretID(name)
+		if _, conflict := result.Conflicts[secretID]; conflict {
+			continue
+		}
+
+		plaintext, err := c.Access(ctx, &AccessRequest{Bucket: bucket, Object: name})
+		if err != nil {
+			result.Failed[secretID] = err
+			continue
+		}
+
+		if err := c.SMCreate(ctx, &SMCreateRequest{
+			Project:   project,
+			Secret:    secretID,
+			Plaintext: plaintext,
+		}); err != nil {
+			result.Failed[secretID] = err
+			continue
+		}
+
+		result.Migrated = append(result.Migrated, secretID)
+	}
+
+	return result, nil
+}
+
+// MigrateFromSMRequest is used as input to MigrateFromSM.
+type MigrateFromSMRequest struct {
+	// Project is the GCP project ID or number to migrate secrets out of.
+	Project string
+
+	// Bucket is the name of the destination berglas bucket.
+	Bucket string
+
+	// Key is the fully qualified KMS key id (or a local-key:// reference,
+	// see IsLocalKey) used to encrypt the migrated secrets.
+	Key string
+}
+
+// MigrateFromSM copies the latest version of every secret in i.Project to
+// i.Bucket, mapping each secret ID to an object name with smObjectName. A
+// destination object that already exists is left untouched and reported in
+// Skipped, so an interrupted or repeated migration does not overwrite
+// secrets it already copied.
+func (c *Client) MigrateFromSM(ctx context.Context, i *MigrateFromSMRequest) (*MigrateResult, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	key := i.Key
+	if key == "" {
+		return nil, errors.New("missing key name")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"project": project,
+		"bucket":  bucket,
+	})
+
+	logger.Debug("migratefromsm.start")
+	defer logger.Debug("migratefromsm.finish")
+
+	ids, err := c.SMList(ctx, &SMListRequest{Project: project})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list secret manager secrets")
+	}
+	sort.Strings(ids)
+
+	result := &MigrateResult{
+		Conflicts: conflictsByMappedName(ids, smObjectName),
+		Failed:    make(map[string]error),
+	}
+
+	for _, id := range ids {
+		object := smObjectName(id)
+		if _, conflict := result.Conflicts[object]; conflict {
+			continue
+		}
+
+		if _, rerr := c.Read(ctx, &ReadRequest{Bucket: bucket, Object: object}); rerr == nil {
+			result.Skipped = append(result.Skipped, object)
+			continue
+		} else if !IsSecretDoesNotExistErr(rerr) {
+			result.Failed[object] = rerr
+			continue
+		}
+
+		plaintext, err := c.SMAccess(ctx, &SMAccessRequest{Project: project, Secret: id})
+		if err != nil {
+			result.Failed[object] = err
+			continue
+		}
+
+		if _, err := c.Create(ctx, &CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: plaintext,
+		}); err != nil {
+			result.Failed[object] = err
+			continue
+		}
+
+		result.Migrated = append(result.Migrated, object)
+	}
+
+	return result, nil
+}
+
+// conflictsByMappedName groups names by mapped(name), returning only the
+// groups with more than one member, sorted for stable output.
+func conflictsByMappedName(names []string, mapped func(string) string) map[string][]string {
+	byMapped := make(map[string][]string)
+	for _, name := range names {
+		to := mapped(name)
+		byMapped[to] = append(byMapped[to], name)
+	}
+
+	conflicts := make(map[string][]string)
+	for to, group := range byMapped {
+		if len(group) > 1 {
+			sort.Strings(group)
+			conflicts[to] = group
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,60 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import "testing"
+
+func TestSMSecretIDRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	cases := []string{"api-key", "db/password", "payments/stripe/secret-key"}
+	for _, object := range cases {
+		id := smSecretID(object)
+		if got := smObjectName(id); got != object {
+			t.Errorf("smObjectName(smSecretID(%q)) = %q, want %q", object, got, object)
+		}
+	}
+}
+
+func TestConflictsByMappedName(t *testing.T) {
+	t.Parallel()
+
+	names := []string{"a/b", "a__b", "c/d", "e"}
+	conflicts := conflictsByMappedName(names, smSecretID)
+
+	if len(conflicts) != 1 {
+		t.Fatalf("expected exactly one conflict, got %v", conflicts)
+	}
+
+	group, ok := conflicts["a__b"]
+	if !ok {
+		t.Fatalf("expected a conflict on %q, got %v", "a__b", conflicts)
+	}
+	if want := []string{"a/b", "a__b"}; !equalStringSlices(group, want) {
+		t.Errorf("expected conflict group %v, got %v", want, group)
+	}
+}
+
+func equalStringSlices(act, exp []string) bool {
+	if len(act) != len(exp) {
+		return false
+	}
+	for i := range act {
+		if act[i] != exp[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,179 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+
+	"cloud.google.com/go/iam"
+	"github.com/pkg/errors"
+)
+
+// MoveRequest is used as input to Move.
+type MoveRequest struct {
+	// SourceBucket and SourceObject identify the secret to rename.
+	SourceBucket string
+	SourceObject string
+
+	// DestinationBucket and DestinationObject identify the new name.
+	// DestinationObject defaults to SourceObject if empty.
+	DestinationBucket string
+	DestinationObject string
+
+	// Key is passed through to Copy; see CopyRequest.Key.
+	Key string
+
+	// KeepIAM, if true, copies the source object's Cloud Storage IAM bindings,
+	// and its KMS key's decrypter bindings, onto the destination before the
+	// source is deleted. Without it, the destination starts with whatever
+	// default access its bucket and key already grant, and any per-secret
+	// grants made with "berglas grant" against the old name are lost.
+	KeepIAM bool
+}
+
+// Move renames a secret by copying it to DestinationBucket/DestinationObject
+// (see Copy), verifying the copy reads back identically to the source, and
+// only then deleting SourceBucket/SourceObject. This closes the window a
+// manual access-create-delete rename leaves open, where both the old and new
+// names exist and nothing has checked they actually agree - Move refuses to
+// touch the source at all unless the destination is confirmed intact first.
+//
+// Like Copy, Move always starts a fresh generation at the destination and
+// does not preserve the source secret's generation or metageneration.
+func (c *Client) Move(ctx context.Context, i *MoveRequest) (*Secret, error) {
+	if c.storageClient == nil {
+		return nil, errBackendUnsupported
+	}
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	if i.SourceBucket == "" {
+		return nil, errors.New("missing source bucket name")
+	}
+	if i.SourceObject == "" {
+		return nil, errors.New("missing source object name")
+	}
+	if i.DestinationBucket == "" {
+		return nil, errors.New("missing destination bucket name")
+	}
+
+	destinationObject := i.DestinationObject
+	if destinationObject == "" {
+		destinationObject = i.SourceObject
+	}
+
+	if i.SourceBucket == i.DestinationBucket && i.SourceObject == destinationObject {
+		return nil, errors.New("source and destination must differ")
+	}
+
+	secret, err := c.Copy(ctx, &CopyRequest{
+		SourceBucket:      i.SourceBucket,
+		SourceObject:      i.SourceObject,
+		DestinationBucket: i.DestinationBucket,
+		DestinationObject: destinationObject,
+		Key:               i.Key,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to copy secret to destination")
+	}
+
+	// Verify by independently re-reading both the destination and the source
+	// from storage, rather than trusting the plaintext Copy already decrypted
+	// once, before deleting anything.
+	destPlaintext, err := c.Access(ctx, &AccessRequest{
+		Bucket: i.DestinationBucket,
+		Object: destinationObject,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to verify copied secret before deleting source")
+	}
+
+	sourcePlaintext, err := c.Access(ctx, &AccessRequest{
+		Bucket: i.SourceBucket,
+		Object: i.SourceObject,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to re-read source secret for verification")
+	}
+
+	if !bytes.Equal(destPlaintext, sourcePlaintext) {
+		return nil, errors.New("copied secret does not match source; refusing to delete source")
+	}
+
+	if i.KeepIAM {
+		if err := c.copyIAM(ctx, i.SourceBucket, i.SourceObject, i.DestinationBucket, destinationObject, secret.KMSKey); err != nil {
+			return nil, errors.Wrap(err, "copied secret but failed to carry over IAM bindings; source was not deleted")
+		}
+	}
+
+	if err := c.Delete(ctx, &DeleteRequest{
+		Bucket: i.SourceBucket,
+		Object: i.SourceObject,
+	}); err != nil {
+		return nil, errors.Wrap(err, "copied secret but failed to delete source; both now exist")
+	}
+
+	return secret, nil
+}
+
+// copyIAM copies the source object's Cloud Storage IAM bindings, and its KMS
+// key's decrypter bindings, onto the destination - see MoveRequest.KeepIAM.
+func (c *Client) copyIAM(ctx context.Context, sourceBucket, sourceObject, destinationBucket, destinationObject, destinationKey string) error {
+	sourcePolicy, err := getIAMPolicy(ctx, c.storageIAM(sourceBucket, sourceObject))
+	if err != nil {
+		return errors.Wrap(err, "failed to read source object IAM policy")
+	}
+
+	if err := updateIAMPolicy(ctx, c.storageIAM(destinationBucket, destinationObject), func(p *iam.Policy) *iam.Policy {
+		for _, role := range sourcePolicy.Roles() {
+			for _, member := range sourcePolicy.Members(role) {
+				p.Add(member, role)
+			}
+		}
+		return p
+	}); err != nil {
+		return errors.Wrap(err, "failed to update destination object IAM policy")
+	}
+
+	attrs, err := c.storageClient.Bucket(sourceBucket).Object(sourceObject).Attrs(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to read source secret metadata")
+	}
+	sourceKey := attrs.Metadata[MetadataKMSKey]
+	if sourceKey == "" || sourceKey == destinationKey {
+		return nil
+	}
+
+	sourceKMSPolicy, err := getIAMPolicy(ctx, c.kmsClient.ResourceIAM(sourceKey))
+	if err != nil {
+		return errors.Wrap(err, "failed to read source KMS key IAM policy")
+	}
+
+	if err := updateIAMPolicy(ctx, c.kmsClient.ResourceIAM(destinationKey), func(p *iam.Policy) *iam.Policy {
+		for _, role := range sourceKMSPolicy.Roles() {
+			for _, member := range sourceKMSPolicy.Members(role) {
+				p.Add(member, role)
+			}
+		}
+		return p
+	}); err != nil {
+		return errors.Wrap(err, "failed to update destination KMS key IAM policy")
+	}
+
+	return nil
+}
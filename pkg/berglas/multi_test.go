@@ -0,0 +1,141 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"testing"
+)
+
+func Test_Client_AccessMulti(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, name := range []string{"one", "two"} {
+		if _, err := client.Create(ctx, &CreateRequest{
+			Bucket:    "my-bucket",
+			Object:    name,
+			Key:       "my-key",
+			Plaintext: []byte(name),
+		}); err != nil {
+			t.Fatalf("create %d: %v", i, err)
+		}
+	}
+
+	reqs := []*AccessRequest{
+		{Bucket: "my-bucket", Object: "one"},
+		{Bucket: "my-bucket", Object: "does-not-exist"},
+		{Bucket: "my-bucket", Object: "two"},
+	}
+
+	results, err := client.AccessMulti(ctx, reqs)
+	if err == nil {
+		t.Fatal("expected an error for the missing object")
+	}
+	if _, ok := err.(*MultiError); !ok {
+		t.Fatalf("expected a *MultiError, got %T", err)
+	}
+
+	if len(results) != len(reqs) {
+		t.Fatalf("got %d results, want %d", len(results), len(reqs))
+	}
+	if act, exp := string(results[0].Plaintext), "one"; act != exp {
+		t.Errorf("results[0] = %q, want %q", act, exp)
+	}
+	if results[1].Err == nil {
+		t.Error("expected results[1] to have an error")
+	}
+	if act, exp := string(results[2].Plaintext), "two"; act != exp {
+		t.Errorf("results[2] = %q, want %q", act, exp)
+	}
+}
+
+func Test_Client_AccessMulti_allSucceed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := client.AccessMulti(ctx, []*AccessRequest{
+		{Bucket: "my-bucket", Object: "my-object"},
+	})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if act, exp := string(results[0].Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+func Test_Client_ResolveAll(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	refs := map[string]string{
+		"FOUND":     "berglas://my-bucket/my-object",
+		"NOT_FOUND": "berglas://my-bucket/does-not-exist",
+	}
+
+	out, err := client.ResolveAll(ctx, refs)
+	if err == nil {
+		t.Fatal("expected an error for the missing reference")
+	}
+
+	merr, ok := err.(*MultiError)
+	if !ok {
+		t.Fatalf("expected a *MultiError, got %T", err)
+	}
+	if _, ok := merr.Errors["NOT_FOUND"]; !ok {
+		t.Errorf("expected MultiError to include NOT_FOUND, got %v", merr.Errors)
+	}
+
+	if act, exp := string(out["FOUND"]), "my secret value"; act != exp {
+		t.Errorf("FOUND = %q, want %q", act, exp)
+	}
+	if _, ok := out["NOT_FOUND"]; ok {
+		t.Error("expected NOT_FOUND to be omitted from the results map")
+	}
+}
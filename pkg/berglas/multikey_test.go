@@ -0,0 +1,118 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"testing"
+)
+
+// Test_Client_Create_additionalKeys verifies that a secret created with
+// AdditionalKeys records them on the resulting Secret and reads back
+// correctly.
+func Test_Client_Create_additionalKeys(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.Create(ctx, &CreateRequest{
+		Bucket:         "my-bucket",
+		Object:         "my-object",
+		Key:            "key-a",
+		AdditionalKeys: []string{"key-b", "key-c"},
+		Plaintext:      []byte("my secret value"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := created.AdditionalKMSKeys, []string{"key-b", "key-c"}; len(act) != len(exp) || act[0] != exp[0] || act[1] != exp[1] {
+		t.Errorf("created.AdditionalKMSKeys = %v, want %v", act, exp)
+	}
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(secret.Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+// Test_Client_Read_additionalKeys_outageFallback verifies the core
+// disaster-recovery scenario: when the primary key fails to decrypt (e.g. a
+// single-region KMS outage), Read falls back to an additional key and still
+// succeeds.
+func Test_Client_Read_additionalKeys_outageFallback(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	akm := &memKeyManagerWithOutage{downKeys: map[string]bool{"key-a": true}}
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), akm)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:         "my-bucket",
+		Object:         "my-object",
+		Key:            "key-a",
+		AdditionalKeys: []string{"key-b"},
+		Plaintext:      []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(secret.Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+// Test_Client_Read_additionalKeys_allDown verifies that Read surfaces the
+// primary key's error when every key - primary and additional - fails to
+// decrypt, instead of a generic "all keys failed" message.
+func Test_Client_Read_additionalKeys_allDown(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	live := &memKeyManagerWithOutage{}
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), live)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:         "my-bucket",
+		Object:         "my-object",
+		Key:            "key-a",
+		AdditionalKeys: []string{"key-b"},
+		Plaintext:      []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	live.downKeys = map[string]bool{"key-a": true, "key-b": true}
+
+	if _, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"}); err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
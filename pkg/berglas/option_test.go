@@ -0,0 +1,93 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/option"
+)
+
+func Test_ClientOptions(t *testing.T) {
+	t.Parallel()
+
+	t.Run("WithHTTPClient", func(t *testing.T) {
+		t.Parallel()
+
+		hc := &http.Client{}
+		c := &Client{}
+		WithHTTPClient(hc)(c)
+		if c.httpClient != hc {
+			t.Errorf("httpClient = %v, want %v", c.httpClient, hc)
+		}
+	})
+
+	t.Run("WithUserAgent", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		WithUserAgent("custom-agent")(c)
+		if c.userAgent != "custom-agent" {
+			t.Errorf("userAgent = %q, want %q", c.userAgent, "custom-agent")
+		}
+	})
+
+	t.Run("WithScopes", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		WithScopes("scope-a", "scope-b")(c)
+		if len(c.scopes) != 2 || c.scopes[0] != "scope-a" || c.scopes[1] != "scope-b" {
+			t.Errorf("scopes = %v, want [scope-a scope-b]", c.scopes)
+		}
+	})
+
+	t.Run("WithClientOptions", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		WithClientOptions(option.WithUserAgent("a"), option.WithUserAgent("b"))(c)
+		if len(c.clientOpts) != 2 {
+			t.Errorf("clientOpts = %v, want 2 entries", c.clientOpts)
+		}
+	})
+
+	t.Run("WithLogger", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		l := NewLogrusLogger(logrus.New())
+		WithLogger(l)(c)
+		if rl, ok := c.Logger().(*redactingLogger); !ok || rl.Logger != l {
+			t.Error("expected Logger() to wrap the logger passed to WithLogger")
+		}
+	})
+}
+
+func Test_NewWithBackends_appliesOptions(t *testing.T) {
+	t.Parallel()
+
+	l := NewLogrusLogger(logrus.New())
+	c, err := NewWithBackends(context.Background(), newMemSecretStorage(), &memKeyManager{}, WithLogger(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rl, ok := c.Logger().(*redactingLogger); !ok || rl.Logger != l {
+		t.Error("expected NewWithBackends to apply WithLogger")
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"crypto/rand"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PassphraseKeyPrefix identifies a --key value as a passphrase-derived key
+// instead of a Cloud KMS key or local development key. Like local-key:// and
+// keyring-key://, a passphrase key is NOT protected by Cloud KMS; it exists
+// for field deployments (e.g. air-gapped environments) that need the same
+// CLI and envelope format as a KMS-backed secret but have no KMS
+// connectivity at all.
+const PassphraseKeyPrefix = "passphrase://"
+
+// PassphraseEnvVar is the environment variable wrapDEK and unwrapDEK read
+// the passphrase from for a passphrase:// key. The passphrase never appears
+// in the --key flag value itself, so it is not exposed in shell history or
+// "ps" output the way a literal flag argument would be.
+const PassphraseEnvVar = "BERGLAS_PASSPHRASE"
+
+// passphraseSaltSize is the scrypt salt length prepended to a passphrase
+// key's wrapped DEK, mirroring how localKeyEncrypt prepends its AES-GCM
+// nonce - so unwrapping needs nothing beyond the encrypted blob itself and
+// PassphraseEnvVar, with no extra metadata key to carry the salt.
+const passphraseSaltSize = 16
+
+// scryptN, scryptR, and scryptP are the scrypt cost parameters used to
+// derive a passphrase key's AES-256 key. scrypt, unlike PBKDF2, is
+// memory-hard: deriving a key also requires scryptN*scryptR*128 bytes of
+// RAM (128 MiB here), which is what makes it resistant to the parallel
+// GPU/ASIC attacks a human-chosen passphrase would otherwise be cheap to
+// brute-force. These match the widely used "interactive" scrypt defaults
+// (e.g. age's).
+const (
+	scryptN = 1 << 15
+	scryptR = 8
+	scryptP = 1
+)
+
+// IsPassphraseKey returns true if key identifies a passphrase-derived key
+// rather than a Cloud KMS key or local development key.
+func IsPassphraseKey(key string) bool {
+	return strings.HasPrefix(key, PassphraseKeyPrefix)
+}
+
+// resolvePassphrase reads the passphrase for a passphrase:// key from
+// PassphraseEnvVar. It is an error for the variable to be unset or empty,
+// since berglas has no other way to obtain it non-interactively.
+func resolvePassphrase() (string, error) {
+	passphrase := os.Getenv(PassphraseEnvVar)
+	if passphrase == "" {
+		return "", errors.Errorf("missing passphrase: set %s", PassphraseEnvVar)
+	}
+	return passphrase, nil
+}
+
+// passphraseKeyEncrypt derives a key from passphrase with a fresh random
+// salt and uses it to encrypt dek with localKeyEncrypt, prefixing the salt
+// so passphraseKeyDecrypt can re-derive the same key later.
+func passphraseKeyEncrypt(passphrase string, dek []byte) ([]byte, error) {
+	salt := make([]byte, passphraseSaltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random salt bytes")
+	}
+
+	derived, err := derivePassphraseKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed, err := localKeyEncrypt(derived, dek)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(salt, sealed...), nil
+}
+
+// passphraseKeyDecrypt is the inverse of passphraseKeyEncrypt.
+func passphraseKeyDecrypt(passphrase string, encDEK []byte) ([]byte, error) {
+	if len(encDEK) < passphraseSaltSize {
+		return nil, errors.New("invalid passphrase-wrapped dek: too short")
+	}
+	salt, sealed := encDEK[:passphraseSaltSize], encDEK[passphraseSaltSize:]
+
+	derived, err := derivePassphraseKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	return envelopeDecrypt(derived, sealed, nil)
+}
+
+// derivePassphraseKey derives a 32-byte AES-256 key from passphrase and
+// salt using scrypt, so that brute-forcing a human-chosen passphrase
+// requires not just many guesses but scryptN*scryptR*128 bytes of memory
+// per guess - unlike an iterated-hash KDF (e.g. PBKDF2), which parallelizes
+// cheaply on GPUs/ASICs.
+func derivePassphraseKey(passphrase string, salt []byte) ([]byte, error) {
+	key, err := scrypt.Key([]byte(passphrase), salt, scryptN, scryptR, scryptP, 32)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to derive key from passphrase")
+	}
+	return key, nil
+}
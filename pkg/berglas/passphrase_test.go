@@ -0,0 +1,187 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+)
+
+func TestIsPassphraseKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		key  string
+		exp  bool
+	}{
+		{"passphrase", "passphrase://", true},
+		{"local", "local-key:///tmp/foo.key", false},
+		{"kms", "projects/p/locations/l/keyRings/r/cryptoKeys/k", false},
+		{"empty", "", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsPassphraseKey(tc.key); got != tc.exp {
+				t.Errorf("IsPassphraseKey(%q) = %t, want %t", tc.key, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestIsAgeKey(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		key  string
+		exp  bool
+	}{
+		{"age", "age://AGE-SECRET-KEY-1...", true},
+		{"passphrase", "passphrase://", false},
+		{"kms", "projects/p/locations/l/keyRings/r/cryptoKeys/k", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if got := IsAgeKey(tc.key); got != tc.exp {
+				t.Errorf("IsAgeKey(%q) = %t, want %t", tc.key, got, tc.exp)
+			}
+		})
+	}
+}
+
+func TestPassphraseKeyEncryptDecrypt(t *testing.T) {
+	t.Parallel()
+
+	dek := []byte("my-dek-material-0123456789012345")
+
+	encDEK, err := passphraseKeyEncrypt("correct horse battery staple", dek)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := passphraseKeyDecrypt("correct horse battery staple", encDEK)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, dek) {
+		t.Errorf("passphraseKeyDecrypt(...) = %v, want %v", got, dek)
+	}
+
+	if _, err := passphraseKeyDecrypt("wrong passphrase", encDEK); err == nil {
+		t.Error("expected an error decrypting with the wrong passphrase, got nil")
+	}
+}
+
+// Test_Client_Create_passphraseKey verifies the full create/read round trip
+// through a passphrase:// key, with the passphrase supplied via
+// PassphraseEnvVar rather than the client.
+func Test_Client_Create_passphraseKey(t *testing.T) {
+	oldVal, hadOldVal := os.LookupEnv(PassphraseEnvVar)
+	if err := os.Setenv(PassphraseEnvVar, "correct horse battery staple"); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if hadOldVal {
+			os.Setenv(PassphraseEnvVar, oldVal)
+		} else {
+			os.Unsetenv(PassphraseEnvVar)
+		}
+	}()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       PassphraseKeyPrefix,
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(secret.Plaintext), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+// Test_Client_Create_passphraseKey_missingEnvVar verifies that creating a
+// secret with a passphrase:// key fails clearly when PassphraseEnvVar is
+// unset, instead of silently using an empty passphrase.
+func Test_Client_Create_passphraseKey_missingEnvVar(t *testing.T) {
+	oldVal, hadOldVal := os.LookupEnv(PassphraseEnvVar)
+	os.Unsetenv(PassphraseEnvVar)
+	defer func() {
+		if hadOldVal {
+			os.Setenv(PassphraseEnvVar, oldVal)
+		}
+	}()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       PassphraseKeyPrefix,
+		Plaintext: []byte("my secret value"),
+	}); err == nil {
+		t.Error("expected an error, got nil")
+	}
+}
+
+// Test_Client_Create_ageKey verifies that an age:// key fails clearly with
+// IsAgeKeyUnsupportedErr instead of silently falling back to something else.
+func Test_Client_Create_ageKey(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       AgeKeyPrefix + "AGE-SECRET-KEY-1...",
+		Plaintext: []byte("my secret value"),
+	}); !IsAgeKeyUnsupportedErr(err) {
+		t.Errorf("expected IsAgeKeyUnsupportedErr, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// WritePrometheus writes a snapshot of GlobalStats to w in the Prometheus
+// text exposition format. It has no dependency on the Prometheus client
+// libraries, matching the homegrown style of the rest of this file - callers
+// that already depend on client_golang can instead read GlobalStats directly
+// and populate their own collectors.
+func WritePrometheus(w io.Writer) error {
+	stats := GlobalStats()
+
+	if _, err := fmt.Fprintf(w, "# HELP berglas_accesses_total Total number of completed Access calls.\n"+
+		"# TYPE berglas_accesses_total counter\n"+
+		"berglas_accesses_total %d\n", stats.Accesses); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP berglas_cache_hits_total Total number of Access calls satisfied without a download and decrypt.\n"+
+		"# TYPE berglas_cache_hits_total counter\n"+
+		"berglas_cache_hits_total %d\n", stats.CacheHits); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP berglas_kms_calls_total Total number of Encrypt and Decrypt calls made to Cloud KMS.\n"+
+		"# TYPE berglas_kms_calls_total counter\n"+
+		"berglas_kms_calls_total %d\n", stats.KMSCalls); err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintf(w, "# HELP berglas_access_latency_seconds Cumulative wall-clock time spent in Access calls.\n"+
+		"# TYPE berglas_access_latency_seconds summary\n"+
+		"berglas_access_latency_seconds_sum %g\n"+
+		"berglas_access_latency_seconds_count %d\n", stats.AccessLatencySeconds, stats.Accesses); err != nil {
+		return err
+	}
+
+	ops := make([]string, 0, len(stats.Errors))
+	for op := range stats.Errors {
+		ops = append(ops, op)
+	}
+	sort.Strings(ops)
+
+	if _, err := fmt.Fprintf(w, "# HELP berglas_errors_total Total number of failed calls, by operation.\n"+
+		"# TYPE berglas_errors_total counter\n"); err != nil {
+		return err
+	}
+	for _, op := range ops {
+		if _, err := fmt.Fprintf(w, "berglas_errors_total{op=%q} %d\n", op, stats.Errors[op]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PrometheusHandler returns an http.Handler that serves GlobalStats in the
+// Prometheus text exposition format, suitable for mounting at /metrics.
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WritePrometheus(w); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
@@ -0,0 +1,64 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func Test_WritePrometheus(t *testing.T) {
+	t.Parallel()
+
+	recordAccess()
+	recordCacheHit()
+	recordKMSCall()
+	recordError("access")
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{
+		"berglas_accesses_total",
+		"berglas_cache_hits_total",
+		"berglas_kms_calls_total",
+		"berglas_access_latency_seconds_sum",
+		"berglas_access_latency_seconds_count",
+		`berglas_errors_total{op="access"}`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+}
+
+func Test_PrometheusHandler(t *testing.T) {
+	t.Parallel()
+
+	w := httptest.NewRecorder()
+	PrometheusHandler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+
+	if w.Code != 200 {
+		t.Errorf("status = %d, want 200", w.Code)
+	}
+	if !strings.Contains(w.Body.String(), "berglas_accesses_total") {
+		t.Errorf("expected body to contain berglas_accesses_total, got:\n%s", w.Body.String())
+	}
+}
@@ -17,13 +17,11 @@ package berglas
 import (
 	"context"
 	"encoding/base64"
-	"io/ioutil"
 	"strings"
+	"time"
 
-	"cloud.google.com/go/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 )
 
 // Read is a top-level package function for reading an entire secret object. It
@@ -78,54 +76,28 @@ func (c *Client) Read(ctx context.Context, i *ReadRequest) (*Secret, error) {
 	logger.Debug("read.start")
 	defer logger.Debug("read.finish")
 
-	// Get attributes to find the KMS key
-	logger.Debug("reading attributes from storage")
+	// Download the (still-encrypted) contents and metadata from the storage
+	// backend in one call.
+	logger.Debug("reading object from storage")
 
-	attrs, err := c.storageClient.
-		Bucket(bucket).
-		Object(object).
-		Generation(generation).
-		Attrs(ctx)
-	if err == storage.ErrObjectNotExist {
-		return nil, errSecretDoesNotExist
-	}
+	var data []byte
+	var obj *StorageObject
+	err := c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		data, obj, err = c.secretStorage.ReadObject(ctx, bucket, object, generation)
+		return err
+	})
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to read secret metadata")
+		return nil, wrapAPIErr(err, "failed to read secret")
 	}
-	if attrs.Metadata == nil || attrs.Metadata[MetadataKMSKey] == "" {
+	if obj.Metadata == nil || obj.Metadata[MetadataKMSKey] == "" {
 		return nil, errors.New("missing kms key in secret metadata")
 	}
-	key := attrs.Metadata[MetadataKMSKey]
+	key := obj.Metadata[MetadataKMSKey]
 
 	logger = logger.WithField("key", key)
 	logger.Debug("found kms key")
 
-	// Download the file from GCS
-	logger.Debug("downloading file from storage")
-
-	ior, err := c.storageClient.
-		Bucket(bucket).
-		Object(object).
-		Generation(generation).
-		NewReader(ctx)
-	if err == storage.ErrObjectNotExist {
-		return nil, errors.New("secret object not found")
-	}
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read secret")
-	}
-
-	// Read the entire response into memory
-	logger.Debug("reading object into memory")
-
-	data, err := ioutil.ReadAll(ior)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to read secret into string")
-	}
-	if err := ior.Close(); err != nil {
-		return nil, errors.Wrap(err, "failed to close reader")
-	}
-
 	// Split into parts
 	logger.Debug("deconstructing and decoding ciphertext into parts")
 
@@ -144,25 +116,64 @@ func (c *Client) Read(ctx context.Context, i *ReadRequest) (*Secret, error) {
 		return nil, errors.New("invalid ciphertext: failed to parse ciphertext")
 	}
 
-	// Decrypt the DEK using a KMS key
-	logger.Debug("decrypting dek using kms")
+	dekAAD, contentAAD := readEnvelopeAAD(bucket, object, obj.Metadata)
 
-	kmsResp, err := c.kmsClient.Decrypt(ctx, &kmspb.DecryptRequest{
-		Name:                        key,
-		Ciphertext:                  encDEK,
-		AdditionalAuthenticatedData: []byte(object),
-	})
+	// Decrypt the DEK using a local development key, a passphrase key, or a
+	// Cloud KMS key.
+	switch {
+	case IsLocalKey(key):
+		logger.Warn("using a local development key; this secret is NOT protected by Cloud KMS and must not be used in production")
+	case IsPassphraseKey(key):
+		logger.Warn("using a passphrase key; this secret is NOT protected by Cloud KMS and is only as strong as the passphrase")
+	default:
+		logger.Debug("decrypting dek using kms")
+	}
+	dek, err := c.unwrapAnyDEK(ctx, key, dekAAD, encDEK, obj.Metadata[MetadataAsymmetricKey] == "1", obj.Metadata)
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decrypt dek")
 	}
-	dek := kmsResp.Plaintext
 
 	// Decrypt with the local key
 	logger.Debug("decrypting data with deck locally")
 
-	plaintext, err := envelopeDecrypt(dek, ciphertext)
+	var plaintext []byte
+	if EnvelopeFormat(obj.Metadata[MetadataEnvelopeFormatKey]) == EnvelopeFormatChunked {
+		plaintext, err = chunkedEnvelopeDecrypt(dek, ciphertext, contentAAD)
+	} else {
+		plaintext, err = envelopeDecrypt(dek, ciphertext, contentAAD)
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to decrypt envelope")
 	}
-	return secretFromAttrs(attrs, plaintext), nil
+
+	if err := verifyIntegrity(plaintext, obj.Metadata); err != nil {
+		return nil, err
+	}
+
+	c.touchLastAccessed(ctx, bucket, object, obj, logger)
+
+	return secretFromStorageObject(object, obj, plaintext), nil
+}
+
+// touchLastAccessed best-effort records that object was just read by
+// updating MetadataLastAccessedKey, sampled to at most once per
+// lastAccessedSampleInterval so a secret read many times a second doesn't
+// generate a metadata write on every read. Failures are logged at debug
+// level and never surfaced, since last-accessed tracking must never break a
+// read.
+func (c *Client) touchLastAccessed(ctx context.Context, bucket, object string, obj *StorageObject, logger Logger) {
+	now := time.Now()
+	if last := lastAccessedFromMetadata(obj.Metadata); now.Sub(last) < lastAccessedSampleInterval {
+		return
+	}
+
+	meta := make(map[string]string, len(obj.Metadata)+1)
+	for k, v := range obj.Metadata {
+		meta[k] = v
+	}
+	meta[MetadataLastAccessedKey] = now.UTC().Format(time.RFC3339)
+
+	if err := c.secretStorage.UpdateMetadata(ctx, bucket, object, obj.Generation, obj.Metageneration, meta); err != nil {
+		logger.WithError(err).Debug("failed to record last-accessed time")
+	}
 }
@@ -0,0 +1,88 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// sensitiveLogFields are the WithField/WithFields keys the Client's internal
+// logging always redacts before handing them to a Logger, regardless of
+// which Logger implementation is in use. This is a defense-in-depth
+// backstop so a future log statement can't accidentally leak plaintext or a
+// DEK; today no call site tags either under these keys.
+var sensitiveLogFields = map[string]bool{
+	"plaintext": true,
+	"dek":       true,
+	"value":     true,
+}
+
+// RedactString returns a fingerprint of s suitable for a log line that needs
+// to show a secret value changed or matched another without revealing what
+// the value was. The same input always redacts to the same output, so
+// redacted values can still be compared for equality across log lines.
+func RedactString(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return "REDACTED:" + hex.EncodeToString(sum[:])[:8]
+}
+
+// redactingLogger wraps a Logger, redacting the value of any
+// sensitiveLogFields key passed to WithField or WithFields with
+// RedactString before forwarding it to the wrapped Logger. Every Client
+// logger is wrapped in one of these, so redaction happens no matter which
+// Logger implementation SetLogger or WithLogger is given.
+type redactingLogger struct {
+	Logger
+}
+
+// WithField implements Logger.
+func (l *redactingLogger) WithField(key string, value interface{}) Logger {
+	return &redactingLogger{Logger: l.Logger.WithField(key, redactValue(key, value))}
+}
+
+// WithFields implements Logger.
+func (l *redactingLogger) WithFields(fields map[string]interface{}) Logger {
+	redacted := make(map[string]interface{}, len(fields))
+	for k, v := range fields {
+		redacted[k] = redactValue(k, v)
+	}
+	return &redactingLogger{Logger: l.Logger.WithFields(redacted)}
+}
+
+// WithError implements Logger.
+func (l *redactingLogger) WithError(err error) Logger {
+	return &redactingLogger{Logger: l.Logger.WithError(err)}
+}
+
+// redactValue returns value unchanged unless key is a sensitiveLogFields
+// key, in which case it returns a RedactString fingerprint of value.
+func redactValue(key string, value interface{}) interface{} {
+	if !sensitiveLogFields[key] {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return RedactString(v)
+	case []byte:
+		return RedactString(string(v))
+	case fmt.Stringer:
+		return RedactString(v.String())
+	default:
+		return "REDACTED"
+	}
+}
@@ -0,0 +1,82 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_RedactString(t *testing.T) {
+	t.Parallel()
+
+	if strings.Contains(RedactString("s3cr3t"), "s3cr3t") {
+		t.Error("expected RedactString to not contain the original value")
+	}
+
+	if RedactString("s3cr3t") != RedactString("s3cr3t") {
+		t.Error("expected RedactString to be deterministic")
+	}
+
+	if RedactString("s3cr3t") == RedactString("other") {
+		t.Error("expected different values to redact differently")
+	}
+}
+
+func Test_RedactingLogger(t *testing.T) {
+	t.Parallel()
+
+	seen := make(map[string]interface{})
+	l := &redactingLogger{Logger: &recordingLogger{seen: seen}}
+
+	l.WithField("plaintext", "s3cr3t").Debug("noop")
+	if got := seen["plaintext"]; got == "s3cr3t" || got != RedactString("s3cr3t") {
+		t.Errorf("expected plaintext field to be redacted, got %v", got)
+	}
+
+	l.WithFields(map[string]interface{}{"dek": []byte("s3cr3t"), "bucket": "my-bucket"}).Debug("noop")
+	if got := seen["dek"]; got != RedactString("s3cr3t") {
+		t.Errorf("expected dek field to be redacted, got %v", got)
+	}
+	if got := seen["bucket"]; got != "my-bucket" {
+		t.Errorf("expected unrelated field to pass through unchanged, got %v", got)
+	}
+}
+
+// recordingLogger is a minimal Logger that records every field it is given
+// into seen, for asserting what redactingLogger forwards downstream.
+type recordingLogger struct {
+	seen map[string]interface{}
+}
+
+func (l *recordingLogger) WithField(key string, value interface{}) Logger {
+	l.seen[key] = value
+	return l
+}
+
+func (l *recordingLogger) WithFields(fields map[string]interface{}) Logger {
+	for k, v := range fields {
+		l.seen[k] = v
+	}
+	return l
+}
+
+func (l *recordingLogger) WithError(err error) Logger { return l }
+
+func (l *recordingLogger) Debug(args ...interface{})                 {}
+func (l *recordingLogger) Debugf(format string, args ...interface{}) {}
+func (l *recordingLogger) Warn(args ...interface{})                  {}
+func (l *recordingLogger) Warnf(format string, args ...interface{})  {}
+func (l *recordingLogger) Error(args ...interface{})                 {}
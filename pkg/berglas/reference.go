@@ -15,10 +15,13 @@
 package berglas
 
 import (
+	"context"
 	"io/ioutil"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
+	"sync"
 
 	"github.com/pkg/errors"
 )
@@ -26,14 +29,66 @@ import (
 const (
 	// ReferencePrefix is the beginning identifier for a berglas secret reference.
 	ReferencePrefix string = "berglas://"
+
+	// SMReferencePrefix is the beginning identifier for a Google Secret
+	// Manager reference, e.g. "sm://project/secret#version".
+	SMReferencePrefix string = "sm://"
+
+	// FileReferencePrefix is the beginning identifier for a local filesystem
+	// reference, e.g. "file:///path/to/secret". File references let a
+	// developer run berglas against a directory of plaintext secrets
+	// without any GCP credentials.
+	FileReferencePrefix string = "file://"
+)
+
+// ResolverFunc resolves a custom secret reference (the full string, including
+// its scheme) to the plaintext secret contents, or a path to the decrypted
+// contents on disk. It has the same contract as Client.Resolve.
+type ResolverFunc func(ctx context.Context, ref string) ([]byte, error)
+
+var (
+	customResolversMu sync.RWMutex
+	customResolvers   = make(map[string]ResolverFunc)
 )
 
+// RegisterResolver registers fn to resolve references with the given scheme
+// (e.g. "corpvault", matched against references beginning with
+// "corpvault://"), so that IsReference and Client.Resolve honor the scheme
+// alongside berglas's own "berglas://" references. Registering the same
+// scheme twice replaces the previously registered ResolverFunc.
+//
+// RegisterResolver is intended to be called from an init function by
+// applications and plugins that want to extend berglas with custom reference
+// schemes, without forking the reference-parsing code.
+func RegisterResolver(scheme string, fn ResolverFunc) {
+	customResolversMu.Lock()
+	defer customResolversMu.Unlock()
+	customResolvers[scheme+"://"] = fn
+}
+
+// customResolver returns the ResolverFunc registered for s's scheme, if any.
+func customResolver(s string) (ResolverFunc, bool) {
+	customResolversMu.RLock()
+	defer customResolversMu.RUnlock()
+
+	for prefix, fn := range customResolvers {
+		if strings.HasPrefix(s, prefix) {
+			return fn, true
+		}
+	}
+	return nil, false
+}
+
 // Reference is a parsed berglas reference.
 type Reference struct {
 	bucket     string
 	object     string
 	generation int64
+	field      string
 	filepath   string
+	filemode   os.FileMode
+	fallback   string
+	optional   bool
 }
 
 // Bucket is the storage bucket where the secret lives.
@@ -51,19 +106,169 @@ func (r *Reference) Filepath() string {
 	return r.filepath
 }
 
+// Filemode is the permission mode to write Filepath with, defaulting to
+// 0600 if the reference did not specify a "?mode=" query parameter.
+func (r *Reference) Filemode() os.FileMode {
+	if r.filemode == 0 {
+		return 0600
+	}
+	return r.filemode
+}
+
 // Generation is the secret generation, if any.
 func (r *Reference) Generation() int64 {
 	return r.generation
 }
 
-// IsReference returns true if the given string looks like a berglas reference,
-// false otherwise.
+// Field is the single bundle key to extract from the secret, if any (see
+// ParseReference's "#field" fragment syntax). The secret's plaintext must be
+// a JSON object, as written by BundleSet or "berglas bundle"/"berglas kv".
+func (r *Reference) Field() string {
+	return r.field
+}
+
+// Fallback is the value to use in place of the secret if Optional is true and
+// the secret does not exist. It is the empty string if the reference did not
+// specify one.
+func (r *Reference) Fallback() string {
+	return r.fallback
+}
+
+// Optional reports whether this reference should resolve to Fallback instead
+// of failing when the secret does not exist, so a non-critical secret can be
+// absent without failing whatever is resolving the reference.
+func (r *Reference) Optional() bool {
+	return r.optional
+}
+
+// IsReference returns true if the given string looks like a berglas
+// reference, a Secret Manager reference (see IsSMReference), a local
+// filesystem reference (see IsFileReference), or a reference registered via
+// RegisterResolver, false otherwise.
 func IsReference(s string) bool {
-	return strings.HasPrefix(s, ReferencePrefix)
+	if strings.HasPrefix(s, ReferencePrefix) {
+		return true
+	}
+	if IsSMReference(s) {
+		return true
+	}
+	if IsFileReference(s) {
+		return true
+	}
+	_, ok := customResolver(s)
+	return ok
+}
+
+// IsSMReference returns true if the given string looks like a Google Secret
+// Manager reference, e.g. "sm://project/secret" or
+// "sm://project/secret#version".
+func IsSMReference(s string) bool {
+	return strings.HasPrefix(s, SMReferencePrefix)
+}
+
+// SMReference is a parsed Google Secret Manager reference.
+type SMReference struct {
+	project string
+	secret  string
+	version string
+}
+
+// Project is the GCP project ID or number the secret lives in.
+func (r *SMReference) Project() string {
+	return r.project
+}
+
+// Secret is the name of the secret in Secret Manager.
+func (r *SMReference) Secret() string {
+	return r.secret
+}
+
+// Version is the version of the secret to fetch, e.g. "latest" or "3".
+func (r *SMReference) Version() string {
+	return r.version
+}
+
+// ParseSMReference parses a secret ref of the format `sm://project/secret`
+// or `sm://project/secret#version` and returns a structure representing
+// that information. The version defaults to "latest" if not given.
+func ParseSMReference(s string) (*SMReference, error) {
+	if !IsSMReference(s) {
+		return nil, errors.New("not a secret manager reference")
+	}
+
+	s = strings.TrimPrefix(s, SMReferencePrefix)
+	s = strings.TrimPrefix(s, "/")
+
+	version := "latest"
+	if i := strings.Index(s, "#"); i >= 0 {
+		if v := s[i+1:]; v != "" {
+			version = v
+		}
+		s = s[:i]
+	}
+
+	ss := strings.SplitN(s, "/", 2)
+	if len(ss) < 2 || ss[0] == "" || ss[1] == "" {
+		return nil, errors.Errorf("invalid secret manager reference format %q", s)
+	}
+
+	return &SMReference{
+		project: ss[0],
+		secret:  ss[1],
+		version: version,
+	}, nil
+}
+
+// IsFileReference returns true if the given string looks like a local
+// filesystem reference, e.g. "file:///path/to/secret".
+func IsFileReference(s string) bool {
+	return strings.HasPrefix(s, FileReferencePrefix)
+}
+
+// FileReference is a parsed local filesystem reference.
+type FileReference struct {
+	path string
+}
+
+// Path is the filesystem path to the secret.
+func (r *FileReference) Path() string {
+	return r.path
+}
+
+// ParseFileReference parses a secret ref of the format
+// `file:///path/to/secret` and returns a structure representing that
+// information.
+func ParseFileReference(s string) (*FileReference, error) {
+	if !IsFileReference(s) {
+		return nil, errors.New("not a file reference")
+	}
+
+	path := strings.TrimPrefix(s, FileReferencePrefix)
+	if path == "" {
+		return nil, errors.Errorf("invalid file reference format %q", s)
+	}
+
+	return &FileReference{path: path}, nil
 }
 
 // ParseReference parses a secret ref of the format `berglas://bucket/secret`
-// and returns a structure representing that information.
+// and returns a structure representing that information. It accepts a
+// "?fallback=" query parameter giving a value to fall back to, and a
+// "?optional=true" query parameter marking the secret as non-critical - see
+// Fallback and Optional. It also accepts a "?destination=" query parameter
+// (see Filepath) and, alongside it, a "?mode=" query parameter giving the
+// octal permission mode to write that file with (see Filemode) - for
+// example "berglas://bucket/db-pass?destination=/run/secrets/db-pass&mode=0440"
+// to hand a secret to a process running as a different user without making
+// it world-readable.
+//
+// A "#fragment" is a generation number when it parses as one (e.g.
+// "berglas://bucket/secret#3"); otherwise it names a single field to
+// extract from the secret's plaintext, which must then be a JSON object
+// (see Field, and BundleSet) - e.g. "berglas://bucket/db#password" resolves
+// to just the "password" key of the "db" bundle, so a multi-field secret
+// doesn't have to be split apart to hand one field to an environment
+// variable.
 func ParseReference(s string) (*Reference, error) {
 	// Make sure it's a reference
 	if !IsReference(s) {
@@ -96,9 +301,21 @@ func ParseReference(s string) (*Reference, error) {
 	if u.Fragment != "" {
 		if generation, err := strconv.ParseInt(u.Fragment, 0, 64); err == nil {
 			r.generation = generation
+		} else {
+			r.field = u.Fragment
 		}
 	}
 
+	r.fallback = u.Query().Get("fallback")
+
+	if v := u.Query().Get("optional"); v != "" {
+		optional, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid optional value %q", v)
+		}
+		r.optional = optional
+	}
+
 	// Parse out destination
 	switch d := u.Query().Get("destination"); d {
 	case "":
@@ -118,5 +335,13 @@ func ParseReference(s string) (*Reference, error) {
 		r.filepath = d
 	}
 
+	if m := u.Query().Get("mode"); m != "" {
+		mode, err := strconv.ParseUint(m, 8, 32)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid mode value %q", m)
+		}
+		r.filemode = os.FileMode(mode)
+	}
+
 	return &r, nil
 }
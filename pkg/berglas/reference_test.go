@@ -15,8 +15,12 @@
 package berglas
 
 import (
+	"context"
+	"os"
 	"reflect"
 	"testing"
+
+	"github.com/sirupsen/logrus"
 )
 
 func TestParseReference(t *testing.T) {
@@ -97,6 +101,70 @@ func TestParseReference(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"fallback",
+			"berglas://foo/bar?fallback=default-value",
+			&Reference{
+				bucket:   "foo",
+				object:   "bar",
+				fallback: "default-value",
+			},
+			false,
+		},
+		{
+			"optional",
+			"berglas://foo/bar?optional=true",
+			&Reference{
+				bucket:   "foo",
+				object:   "bar",
+				optional: true,
+			},
+			false,
+		},
+		{
+			"optional_invalid",
+			"berglas://foo/bar?optional=sure",
+			nil,
+			true,
+		},
+		{
+			"destination_mode",
+			"berglas://foo/bar?destination=/var/foo&mode=0440",
+			&Reference{
+				bucket:   "foo",
+				object:   "bar",
+				filepath: "/var/foo",
+				filemode: 0440,
+			},
+			false,
+		},
+		{
+			"mode_invalid",
+			"berglas://foo/bar?destination=/var/foo&mode=not-octal",
+			nil,
+			true,
+		},
+		{
+			"optional_with_fallback",
+			"berglas://foo/bar?optional=true&fallback=default-value",
+			&Reference{
+				bucket:   "foo",
+				object:   "bar",
+				fallback: "default-value",
+				optional: true,
+			},
+			false,
+		},
+		{
+			"field",
+			"berglas://foo/bar#password",
+			&Reference{
+				bucket: "foo",
+				object: "bar",
+				field:  "password",
+			},
+			false,
+		},
 	}
 
 	for _, tc := range cases {
@@ -116,3 +184,49 @@ func TestParseReference(t *testing.T) {
 		})
 	}
 }
+
+func TestReference_Filemode(t *testing.T) {
+	t.Parallel()
+
+	ref, err := ParseReference("berglas://foo/bar?destination=/var/foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := ref.Filemode(), os.FileMode(0600); act != exp {
+		t.Errorf("expected default mode %o, got %o", exp, act)
+	}
+
+	ref, err = ParseReference("berglas://foo/bar?destination=/var/foo&mode=0440")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := ref.Filemode(), os.FileMode(0440); act != exp {
+		t.Errorf("expected mode %o, got %o", exp, act)
+	}
+}
+
+func TestRegisterResolver(t *testing.T) {
+	RegisterResolver("corpvault", func(ctx context.Context, ref string) ([]byte, error) {
+		return []byte("plaintext for " + ref), nil
+	})
+	defer delete(customResolvers, "corpvault://")
+
+	if !IsReference("corpvault://team/secret") {
+		t.Error("expected corpvault:// reference to be recognized")
+	}
+
+	if IsReference("unknown://team/secret") {
+		t.Error("expected unregistered scheme to not be recognized")
+	}
+
+	c := &Client{}
+	c.SetLogger(NewLogrusLogger(logrus.New()))
+	plaintext, err := c.Resolve(context.Background(), "corpvault://team/secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if act, exp := string(plaintext), "plaintext for corpvault://team/secret"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
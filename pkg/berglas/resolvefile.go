@@ -0,0 +1,128 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// ResolveFileRequest is used as input to ResolveFile.
+type ResolveFileRequest struct {
+	// Data is the JSON or YAML document to walk.
+	Data []byte
+
+	// Format selects how Data is parsed and re-marshaled: "json" or "yaml".
+	Format string
+}
+
+// ResolveFile is a top-level package function for resolving every berglas
+// reference embedded in a JSON or YAML document. See Client.ResolveFile for
+// more details.
+func ResolveFile(ctx context.Context, i *ResolveFileRequest) ([]byte, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.ResolveFile(ctx, i)
+}
+
+// ResolveFile walks a JSON or YAML document and replaces every string value
+// that is a berglas reference (see IsReference) with its resolved
+// plaintext, leaving every other value untouched. This lets a checked-in
+// Helm values file or app config keep encrypted references in place of
+// plaintext secrets, resolved just before it's consumed.
+func (c *Client) ResolveFile(ctx context.Context, i *ResolveFileRequest) ([]byte, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	switch i.Format {
+	case "json":
+		var doc interface{}
+		if err := json.Unmarshal(i.Data, &doc); err != nil {
+			return nil, errors.Wrap(err, "failed to parse json")
+		}
+
+		resolved, err := c.resolveFileNode(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := json.MarshalIndent(resolved, "", "  ")
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal json")
+		}
+		return append(out, '\n'), nil
+	case "yaml":
+		var doc interface{}
+		if err := yaml.Unmarshal(i.Data, &doc); err != nil {
+			return nil, errors.Wrap(err, "failed to parse yaml")
+		}
+
+		resolved, err := c.resolveFileNode(ctx, doc)
+		if err != nil {
+			return nil, err
+		}
+
+		out, err := yaml.Marshal(resolved)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to marshal yaml")
+		}
+		return out, nil
+	default:
+		return nil, errors.Errorf("unknown format %q: expected json or yaml", i.Format)
+	}
+}
+
+// resolveFileNode recursively walks a JSON/YAML-decoded value, replacing
+// every string leaf that IsReference identifies as a berglas reference with
+// its resolved plaintext.
+func (c *Client) resolveFileNode(ctx context.Context, node interface{}) (interface{}, error) {
+	switch v := node.(type) {
+	case string:
+		if !IsReference(v) {
+			return v, nil
+		}
+		plaintext, err := c.Resolve(ctx, v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to resolve reference %s", v)
+		}
+		return string(plaintext), nil
+	case map[string]interface{}:
+		for k, child := range v {
+			resolved, err := c.resolveFileNode(ctx, child)
+			if err != nil {
+				return nil, err
+			}
+			v[k] = resolved
+		}
+		return v, nil
+	case []interface{}:
+		for idx, child := range v {
+			resolved, err := c.resolveFileNode(ctx, child)
+			if err != nil {
+				return nil, err
+			}
+			v[idx] = resolved
+		}
+		return v, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestClient_ResolveFile(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "db-password",
+		Key:       "my-key",
+		Plaintext: []byte("hunter2"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("json", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"database":{"password":"berglas://my-bucket/db-password","host":"localhost"}}`)
+		out, err := client.ResolveFile(ctx, &ResolveFileRequest{Data: data, Format: "json"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), `"password": "hunter2"`) {
+			t.Errorf("expected resolved password in %s", out)
+		}
+		if !strings.Contains(string(out), `"host": "localhost"`) {
+			t.Errorf("expected untouched host in %s", out)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte("database:\n  password: berglas://my-bucket/db-password\n  host: localhost\n")
+		out, err := client.ResolveFile(ctx, &ResolveFileRequest{Data: data, Format: "yaml"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(out), "password: hunter2\n") {
+			t.Errorf("expected resolved password in %s", out)
+		}
+		if !strings.Contains(string(out), "host: localhost\n") {
+			t.Errorf("expected untouched host in %s", out)
+		}
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := client.ResolveFile(ctx, &ResolveFileRequest{Data: []byte("{}"), Format: "toml"}); err == nil {
+			t.Error("expected error")
+		}
+	})
+
+	t.Run("missing secret", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`{"password":"berglas://my-bucket/does-not-exist"}`)
+		if _, err := client.ResolveFile(ctx, &ResolveFileRequest{Data: data, Format: "json"}); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
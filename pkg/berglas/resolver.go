@@ -17,6 +17,7 @@ package berglas
 import (
 	"context"
 	"os"
+	"strconv"
 
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
@@ -35,6 +36,23 @@ func Resolve(ctx context.Context, s string) ([]byte, error) {
 // Resolve parses and extracts a berglas reference. The result is the plaintext
 // secrets contents, or a path to the decrypted contents on disk.
 func (c *Client) Resolve(ctx context.Context, s string) ([]byte, error) {
+	return c.resolve(ctx, s, 0)
+}
+
+// ResolvePinned is like Resolve, except generation overrides whatever
+// generation, if any, the "berglas://" reference s itself specifies. This
+// lets callers (e.g. "exec --pins") pin a deployment to exact secret
+// generations captured ahead of time by "berglas pin", without rewriting
+// every reference string to embed a "#generation" fragment. generation has
+// no effect on references handled by a custom resolver (see
+// RegisterResolver), since those are not resolved through Access.
+func (c *Client) ResolvePinned(ctx context.Context, s string, generation int64) ([]byte, error) {
+	return c.resolve(ctx, s, generation)
+}
+
+// resolve implements Resolve and ResolvePinned. pinnedGeneration, if
+// non-zero, overrides the generation embedded in (or defaulted by) s.
+func (c *Client) resolve(ctx context.Context, s string, pinnedGeneration int64) ([]byte, error) {
 	logger := c.Logger().WithFields(logrus.Fields{
 		"reference": s,
 	})
@@ -42,29 +60,93 @@ func (c *Client) Resolve(ctx context.Context, s string) ([]byte, error) {
 	logger.Debug("resolve.start")
 	defer logger.Debug("resolve.finish")
 
+	if fn, ok := customResolver(s); ok {
+		return fn(ctx, s)
+	}
+
+	if IsSMReference(s) {
+		smRef, err := ParseSMReference(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse reference %s", s)
+		}
+
+		version := smRef.Version()
+		if pinnedGeneration != 0 {
+			version = strconv.FormatInt(pinnedGeneration, 10)
+		}
+
+		plaintext, err := c.SMAccess(ctx, &SMAccessRequest{
+			Project: smRef.Project(),
+			Secret:  smRef.Secret(),
+			Version: version,
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to access secret %s/%s", smRef.Project(), smRef.Secret())
+		}
+		return plaintext, nil
+	}
+
+	if IsFileReference(s) {
+		fileRef, err := ParseFileReference(s)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse reference %s", s)
+		}
+
+		plaintext, err := c.FileAccess(ctx, &FileAccessRequest{
+			Path: fileRef.Path(),
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to access secret file %s", fileRef.Path())
+		}
+		return plaintext, nil
+	}
+
 	ref, err := ParseReference(s)
 	if err != nil {
 		return nil, errors.Wrapf(err, "failed to parse reference %s", s)
 	}
 
+	generation := ref.Generation()
+	if pinnedGeneration != 0 {
+		generation = pinnedGeneration
+	}
+
 	plaintext, err := c.Access(ctx, &AccessRequest{
 		Bucket:     ref.Bucket(),
 		Object:     ref.Object(),
-		Generation: ref.Generation(),
+		Generation: generation,
 	})
 	if err != nil {
+		if ref.Optional() && IsSecretDoesNotExistErr(err) {
+			logger.WithField("fallback", ref.Fallback() != "").
+				Debug("optional secret does not exist, using fallback")
+			return []byte(ref.Fallback()), nil
+		}
 		return nil, errors.Wrapf(err, "failed to access secret %s/%s", ref.Bucket(), ref.Object())
 	}
 
+	if field := ref.Field(); field != "" {
+		b, err := ParseBundle(plaintext)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse %s/%s as a bundle for field %q", ref.Bucket(), ref.Object(), field)
+		}
+		v, ok := b[field]
+		if !ok {
+			return nil, errors.Errorf("secret %s/%s has no field %q", ref.Bucket(), ref.Object(), field)
+		}
+		plaintext = []byte(v)
+	}
+
 	if pth := ref.Filepath(); pth != "" {
-		logger.WithField("filepath", pth).Debug("writing to filepath")
+		mode := ref.Filemode()
+		logger.WithFields(logrus.Fields{"filepath": pth, "mode": mode}).Debug("writing to filepath")
 
-		f, err := os.OpenFile(ref.Filepath(), os.O_RDWR|os.O_CREATE, 0600)
+		f, err := os.OpenFile(ref.Filepath(), os.O_RDWR|os.O_CREATE, mode)
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to open filepath %s", pth)
 		}
 
-		if err := f.Chmod(0600); err != nil {
+		if err := f.Chmod(mode); err != nil {
 			return nil, errors.Wrapf(err, "failed to chmod filepath %s", pth)
 		}
 
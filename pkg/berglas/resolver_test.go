@@ -0,0 +1,209 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas_test
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+)
+
+func TestClient_Resolve_optional(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	plaintext, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/missing?optional=true&fallback=default-value")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "default-value"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	if _, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/missing"); err == nil {
+		t.Error("expected an error for a missing, non-optional secret")
+	}
+}
+
+func TestClient_ResolvePinned(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	secret, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "api-key",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ref := "berglas://" + h.Bucket + "/api-key"
+
+	plaintext, err := h.Client.ResolvePinned(ctx, ref, secret.Generation)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "s3cr3t"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	// A zero generation behaves like Resolve, which defaults to the latest
+	// version.
+	plaintext, err = h.Client.ResolvePinned(ctx, ref, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "s3cr3t"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
+
+func TestClient_Resolve_destination(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("file permission bits are not meaningful on windows")
+	}
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "db-pass",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dest := filepath.Join(t.TempDir(), "db-pass")
+
+	result, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/db-pass?destination="+dest+"&mode=0440")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(result), dest; act != exp {
+		t.Errorf("expected resolved value to be the destination path %q, got %q", exp, act)
+	}
+
+	contents, err := ioutil.ReadFile(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(contents), "s3cr3t"; act != exp {
+		t.Errorf("expected file contents %q, got %q", exp, act)
+	}
+
+	info, err := os.Stat(dest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := info.Mode().Perm(), os.FileMode(0440); act != exp {
+		t.Errorf("expected file mode %o, got %o", exp, act)
+	}
+}
+
+func TestClient_Resolve_field(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.BundleSet(ctx, &berglas.BundleSetRequest{
+		Bucket: h.Bucket,
+		Object: "db",
+		Key:    h.Key,
+		Values: berglas.Bundle{"user": "admin", "password": "s3cr3t"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/db#password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "s3cr3t"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+
+	if _, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/db#missing"); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "not-a-bundle",
+		Key:       h.Key,
+		Plaintext: []byte("just a string"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.Resolve(ctx, "berglas://"+h.Bucket+"/not-a-bundle#password"); err == nil {
+		t.Error("expected an error for a field reference to a non-bundle secret")
+	}
+}
+
+func TestClient_Access_partial(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "large-blob",
+		Key:       h.Key,
+		Plaintext: []byte("hello world"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "large-blob",
+		Offset: 6,
+		Length: 5,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext), "world"; act != exp {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+)
+
+// RestoreRequest is used as input to Restore.
+type RestoreRequest struct {
+	// Bucket is the name of the bucket where the secret lives.
+	Bucket string
+
+	// Object is the name of the object in Cloud Storage.
+	Object string
+
+	// Generation is the generation of the secret to restore. It is required.
+	Generation int64
+}
+
+// Restore is a top-level package function for restoring a secret to a prior
+// generation. For large volumes of secrets, please create a client instead.
+func Restore(ctx context.Context, i *RestoreRequest) (*Secret, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.Restore(ctx, i)
+}
+
+// Restore reads the plaintext of a prior generation of a secret and writes
+// it back as a new, live generation, leaving the secret's current KMS key
+// and IAM bindings in place. This is the safe way to undo a bad Update: an
+// Access followed by an Update round-trips the plaintext through a shell
+// pipeline, which can mangle binary values or trailing newlines, where
+// Restore never leaves the process.
+//
+// The restored generation is new - Restore does not resurrect Generation
+// itself, so the secret's generation number keeps increasing and the
+// restored-from generation remains in its history alongside it.
+func (c *Client) Restore(ctx context.Context, i *RestoreRequest) (*Secret, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	object := i.Object
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+
+	if i.Generation == 0 {
+		return nil, errors.New("missing generation to restore")
+	}
+
+	old, err := c.Access(ctx, &AccessRequest{
+		Bucket:     bucket,
+		Object:     object,
+		Generation: i.Generation,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to access generation to restore")
+	}
+
+	return c.Update(ctx, &UpdateRequest{
+		Bucket: bucket,
+		Object: object,
+		MergeFunc: func(current []byte) ([]byte, error) {
+			return old, nil
+		},
+	})
+}
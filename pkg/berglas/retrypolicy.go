@@ -0,0 +1,106 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/retry"
+)
+
+// RetryPolicy governs how a Client retries transient Cloud Storage
+// (429 and 5xx responses) and Cloud KMS (RESOURCE_EXHAUSTED) failures with
+// exponential backoff and jitter, instead of returning them to the caller
+// immediately. The zero value disables retries, matching the Client's
+// behavior before RetryPolicy existed.
+type RetryPolicy struct {
+	// MaxRetries is the maximum number of retry attempts after the initial
+	// try. Zero (the default) disables retries.
+	MaxRetries int
+
+	// MaxElapsedTime bounds the total time spent waiting between retries,
+	// across all attempts. Zero means no limit beyond MaxRetries.
+	MaxElapsedTime time.Duration
+}
+
+// retryBaseDelay is the wait before the first retry; it doubles (with full
+// jitter) on each subsequent attempt. See retry.ExponentialBackoffWithJitter.
+const retryBaseDelay = 200 * time.Millisecond
+
+// WithRetryPolicy overrides how a Client retries transient Cloud Storage and
+// Cloud KMS failures. See RetryPolicy. Pass it to New, or apply it to an
+// already-constructed Client with Client.ApplyOptions, e.g.
+//
+//	client.ApplyOptions(berglas.WithRetryPolicy(berglas.RetryPolicy{MaxRetries: 5}))
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicyLock.Lock()
+		c.retryPolicy = policy
+		c.retryPolicyLock.Unlock()
+	}
+}
+
+// WithTimeout bounds how long a single Client operation may take, including
+// every attempt permitted by RetryPolicy, before returning
+// context.DeadlineExceeded instead of blocking on a hung Cloud Storage or
+// Cloud KMS connection indefinitely. Zero disables the timeout, deferring
+// entirely to the caller's own context. Pass it to New, or apply it to an
+// already-constructed Client with Client.ApplyOptions, e.g.
+//
+//	client.ApplyOptions(berglas.WithTimeout(30 * time.Second))
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.timeoutLock.Lock()
+		c.timeout = timeout
+		c.timeoutLock.Unlock()
+	}
+}
+
+// withRetry executes f, retrying according to c's RetryPolicy (see
+// WithRetryPolicy) whenever f's error is a transient Cloud Storage (429/5xx)
+// or Cloud KMS (RESOURCE_EXHAUSTED) failure. If no RetryPolicy has been
+// configured, f is executed exactly once. If a timeout has been configured
+// (see WithTimeout), f is passed a context bounded by it, covering every
+// attempt.
+func (c *Client) withRetry(ctx context.Context, f func(ctx context.Context) error) error {
+	c.timeoutLock.RLock()
+	timeout := c.timeout
+	c.timeoutLock.RUnlock()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	c.retryPolicyLock.RLock()
+	policy := c.retryPolicy
+	c.retryPolicyLock.RUnlock()
+
+	if policy.MaxRetries <= 0 {
+		return f(ctx)
+	}
+
+	return retry.RetryExpJitter(ctx, retryBaseDelay, policy.MaxRetries, policy.MaxElapsedTime, func() error {
+		if err := f(ctx); err != nil {
+			if isTransientAPIErr(err) {
+				return retry.RetryableError(err)
+			}
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_Client_withRetry(t *testing.T) {
+	t.Parallel()
+
+	t.Run("no policy runs once", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		attempts := 0
+		err := c.withRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return status.Error(codes.ResourceExhausted, "quota exceeded")
+		})
+		if err == nil {
+			t.Fatal("expected error")
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("retries transient errors until success", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		c.ApplyOptions(WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+
+		attempts := 0
+		err := c.withRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			if attempts < 3 {
+				return status.Error(codes.ResourceExhausted, "quota exceeded")
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("expected success, got %v", err)
+		}
+		if attempts != 3 {
+			t.Errorf("attempts = %d, want 3", attempts)
+		}
+	})
+
+	t.Run("does not retry non-transient errors", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		c.ApplyOptions(WithRetryPolicy(RetryPolicy{MaxRetries: 3}))
+
+		attempts := 0
+		wantErr := errors.New("permanent failure")
+		err := c.withRetry(context.Background(), func(ctx context.Context) error {
+			attempts++
+			return wantErr
+		})
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("err = %v, want %v", err, wantErr)
+		}
+		if attempts != 1 {
+			t.Errorf("attempts = %d, want 1", attempts)
+		}
+	})
+
+	t.Run("timeout bounds the context passed to f", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+		c.ApplyOptions(WithTimeout(10 * time.Millisecond))
+
+		var hadDeadline bool
+		err := c.withRetry(context.Background(), func(ctx context.Context) error {
+			_, hadDeadline = ctx.Deadline()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !hadDeadline {
+			t.Error("expected the context passed to f to carry a deadline")
+		}
+	})
+
+	t.Run("no timeout leaves the context unbounded", func(t *testing.T) {
+		t.Parallel()
+
+		c := &Client{}
+
+		var hadDeadline bool
+		err := c.withRetry(context.Background(), func(ctx context.Context) error {
+			_, hadDeadline = ctx.Deadline()
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if hadDeadline {
+			t.Error("expected the context passed to f to be unbounded")
+		}
+	})
+}
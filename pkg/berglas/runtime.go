@@ -85,7 +85,7 @@ type RuntimeEnvironment interface {
 
 // cloudFunctionEnv is a Google Cloud Functions environment.
 type cloudFunctionEnv struct {
-	logger *logrus.Logger
+	logger Logger
 }
 
 // EnvVars returns the list of envvars set on the function.
@@ -124,7 +124,7 @@ func (e *cloudFunctionEnv) EnvVars(ctx context.Context) (map[string]string, erro
 
 // cloudRunEnv is a Google Cloud Run environment.
 type cloudRunEnv struct {
-	logger *logrus.Logger
+	logger Logger
 }
 
 // EnvVars returns the list of envvars set on the virtual machine.
@@ -262,7 +262,7 @@ type cloudRunContainer struct {
 
 // gaeEnv is a Google App Engine environment.
 type gaeEnv struct {
-	logger *logrus.Logger
+	logger Logger
 }
 
 type appengineVersion struct {
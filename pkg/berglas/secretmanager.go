@@ -0,0 +1,269 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"encoding/base64"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	secretmanagerv1 "google.golang.org/api/secretmanager/v1"
+)
+
+// SMAccessRequest is used as input to a Secret Manager access request.
+type SMAccessRequest struct {
+	// Project is the GCP project ID or number the secret lives in.
+	Project string
+
+	// Secret is the name of the secret in Secret Manager.
+	Secret string
+
+	// Version is the version of the secret to fetch. Defaults to "latest" if
+	// not given.
+	Version string
+}
+
+// SMAccess reads and returns the plaintext of a Secret Manager secret
+// version. Unlike Access, there is no envelope encryption to undo: Secret
+// Manager encrypts secret material at rest itself, so this is a direct
+// AccessSecretVersion call.
+func (c *Client) SMAccess(ctx context.Context, i *SMAccessRequest) ([]byte, error) {
+	if c.secretManagerClient == nil {
+		return nil, errBackendUnsupported
+	}
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	secret := i.Secret
+	if secret == "" {
+		return nil, errors.New("missing secret name")
+	}
+
+	version := i.Version
+	if version == "" {
+		version = "latest"
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"project": project,
+		"secret":  secret,
+		"version": version,
+	})
+
+	logger.Debug("smaccess.start")
+	defer logger.Debug("smaccess.finish")
+
+	name := "projects/" + project + "/secrets/" + secret + "/versions/" + version
+
+	resp, err := c.secretManagerClient.Projects.Secrets.Versions.Access(name).Context(ctx).Do()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to access secret version %s", name)
+	}
+
+	plaintext, err := base64.StdEncoding.DecodeString(resp.Payload.Data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode secret version %s", name)
+	}
+
+	return plaintext, nil
+}
+
+// SMCreateRequest is used as input to a Secret Manager create request.
+type SMCreateRequest struct {
+	// Project is the GCP project ID or number the secret should live in.
+	Project string
+
+	// Secret is the name of the secret to create in Secret Manager.
+	Secret string
+
+	// Plaintext is the plaintext secret data to store as the secret's first
+	// version.
+	Plaintext []byte
+}
+
+// SMCreate creates a new Secret Manager secret, using Google-managed
+// automatic replication, and adds Plaintext as its first version. If the
+// secret already exists, SMCreate only adds a new version.
+func (c *Client) SMCreate(ctx context.Context, i *SMCreateRequest) error {
+	if c.secretManagerClient == nil {
+		return errBackendUnsupported
+	}
+
+	if i == nil {
+		return errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return errors.New("missing project")
+	}
+
+	secret := i.Secret
+	if secret == "" {
+		return errors.New("missing secret name")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"project": project,
+		"secret":  secret,
+	})
+
+	logger.Debug("smcreate.start")
+	defer logger.Debug("smcreate.finish")
+
+	name := "projects/" + project + "/secrets/" + secret
+
+	if _, err := c.secretManagerClient.Projects.Secrets.Get(name).Context(ctx).Do(); err != nil {
+		logger.Debug("secret does not exist, creating")
+
+		if _, err := c.secretManagerClient.Projects.Secrets.Create("projects/"+project, &secretmanagerv1.Secret{
+			Replication: &secretmanagerv1.Replication{
+				Automatic: &secretmanagerv1.Automatic{},
+			},
+		}).SecretId(secret).Context(ctx).Do(); err != nil {
+			return errors.Wrapf(err, "failed to create secret %s", name)
+		}
+	}
+
+	if _, err := c.secretManagerClient.Projects.Secrets.AddVersion(name, &secretmanagerv1.AddSecretVersionRequest{
+		Payload: &secretmanagerv1.SecretPayload{
+			Data: base64.StdEncoding.EncodeToString(i.Plaintext),
+		},
+	}).Context(ctx).Do(); err != nil {
+		return errors.Wrapf(err, "failed to add version to secret %s", name)
+	}
+
+	return nil
+}
+
+// SMListRequest is used as input to a Secret Manager list request.
+type SMListRequest struct {
+	// Project is the GCP project ID or number to list secrets in.
+	Project string
+}
+
+// SMList returns the IDs of every secret in the given project.
+func (c *Client) SMList(ctx context.Context, i *SMListRequest) ([]string, error) {
+	if c.secretManagerClient == nil {
+		return nil, errBackendUnsupported
+	}
+
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return nil, errors.New("missing project")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"project": project,
+	})
+
+	logger.Debug("smlist.start")
+	defer logger.Debug("smlist.finish")
+
+	parent := "projects/" + project
+
+	var ids []string
+	if err := c.secretManagerClient.Projects.Secrets.List(parent).Pages(ctx, func(page *secretmanagerv1.ListSecretsResponse) error {
+		for _, secret := range page.Secrets {
+			ids = append(ids, secret.Name[len(parent+"/secrets/"):])
+		}
+		return nil
+	}); err != nil {
+		return nil, errors.Wrapf(err, "failed to list secrets in %s", parent)
+	}
+
+	return ids, nil
+}
+
+// SMGrantRequest is used as input to a Secret Manager grant request.
+type SMGrantRequest struct {
+	// Project is the GCP project ID or number the secret lives in.
+	Project string
+
+	// Secret is the name of the secret in Secret Manager.
+	Secret string
+
+	// Members is the list of membership bindings. This should be in the
+	// format described at https://godoc.org/google.golang.org/api/iam/v1#Binding.
+	Members []string
+}
+
+// SMGrant adds the "roles/secretmanager.secretAccessor" IAM role to the given
+// members on the secret, so they can call SMAccess against it.
+func (c *Client) SMGrant(ctx context.Context, i *SMGrantRequest) error {
+	if c.secretManagerClient == nil {
+		return errBackendUnsupported
+	}
+
+	if i == nil {
+		return errors.New("missing request")
+	}
+
+	project := i.Project
+	if project == "" {
+		return errors.New("missing project")
+	}
+
+	secret := i.Secret
+	if secret == "" {
+		return errors.New("missing secret name")
+	}
+
+	members := i.Members
+	if len(members) == 0 {
+		return nil
+	}
+
+	name := "projects/" + project + "/secrets/" + secret
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"project": project,
+		"secret":  secret,
+		"members": members,
+	})
+
+	logger.Debug("smgrant.start")
+	defer logger.Debug("smgrant.finish")
+
+	policy, err := c.secretManagerClient.Projects.Secrets.GetIamPolicy(name).Context(ctx).Do()
+	if err != nil {
+		return errors.Wrapf(err, "failed to get IAM policy for %s", name)
+	}
+
+	policy.Bindings = append(policy.Bindings, &secretmanagerv1.Binding{
+		Role:    "roles/secretmanager.secretAccessor",
+		Members: members,
+	})
+
+	if _, err := c.secretManagerClient.Projects.Secrets.SetIamPolicy(name, &secretmanagerv1.SetIamPolicyRequest{
+		Policy: policy,
+	}).Context(ctx).Do(); err != nil {
+		return errors.Wrapf(err, "failed to set IAM policy for %s", name)
+	}
+
+	return nil
+}
@@ -0,0 +1,463 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// streamChunkSize is the amount of plaintext sealed into a single AES-GCM
+// chunk under EnvelopeFormatChunked. Bounding it keeps peak memory for a
+// multi-hundred-MB secret proportional to this constant rather than to the
+// secret's total size when read or written through CreateFromReader and
+// AccessReader.
+const streamChunkSize = 64 * 1024
+
+// CreateFromReader is a top-level package function for creating a new
+// encrypted secret from a stream. For large volumes of secrets, please
+// create a client instead.
+func CreateFromReader(ctx context.Context, i *CreateFromReaderRequest, r io.Reader) (*Secret, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.CreateFromReader(ctx, i, r)
+}
+
+// CreateFromReaderRequest is used as input to CreateFromReader. It mirrors
+// CreateRequest, but omits Plaintext and Format: the plaintext instead comes
+// from the io.Reader passed to CreateFromReader, and Format is fixed to
+// EnvelopeFormatChunked rather than selectable.
+type CreateFromReaderRequest struct {
+	// Bucket is the name of the bucket where the secret lives.
+	Bucket string
+
+	// Object is the name of the object in Cloud Storage.
+	Object string
+
+	// Key is the fully qualified KMS key id.
+	Key string
+
+	// AdditionalKeys is passed through to CreateRequest.AdditionalKeys.
+	AdditionalKeys []string
+
+	// Asymmetric is passed through to CreateRequest.Asymmetric.
+	Asymmetric bool
+
+	// RetainUntil, if non-zero, prevents the secret from being deleted or
+	// overwritten until this time has passed. Attempts to do so return an
+	// error satisfying IsSecretRetainedErr.
+	RetainUntil time.Time
+}
+
+// AccessReader is a top-level package function for accessing a secret as a
+// stream. For large volumes of secrets, please create a client instead.
+func AccessReader(ctx context.Context, i *AccessRequest) (io.ReadCloser, error) {
+	client, err := New(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return client.AccessReader(ctx, i)
+}
+
+// CreateFromReader creates a new encrypted secret on GCS in
+// EnvelopeFormatChunked, encrypting r in streamChunkSize chunks as it is
+// read instead of buffering the entire plaintext in memory first, the way
+// Create requires. This is intended for multi-hundred-MB (or larger)
+// artifacts such as keystores or model weights.
+//
+// The underlying SecretStorage.WriteObject call still writes the resulting
+// ciphertext in a single call, so this does not reduce the memory the
+// storage backend itself needs to hold the object; it only removes the need
+// for the caller (and this client) to also hold the plaintext in one
+// contiguous buffer up front.
+//
+// If the secret already exists, an error is returned.
+func (c *Client) CreateFromReader(ctx context.Context, i *CreateFromReaderRequest, r io.Reader) (*Secret, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	object := i.Object
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+
+	key := i.Key
+	if key == "" {
+		return nil, errors.New("missing key name")
+	}
+
+	if r == nil {
+		return nil, errors.New("missing reader")
+	}
+
+	if i.Asymmetric && IsLocalKey(key) {
+		return nil, errors.New("asymmetric encryption is not supported with local development keys")
+	}
+
+	if i.Asymmetric && IsPassphraseKey(key) {
+		return nil, errors.New("asymmetric encryption is not supported with passphrase keys")
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"bucket":     bucket,
+		"object":     object,
+		"key":        key,
+		"asymmetric": i.Asymmetric,
+	})
+
+	logger.Debug("createFromReader.start")
+	defer logger.Debug("createFromReader.finish")
+
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random key bytes")
+	}
+
+	aesgcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	aad := []byte(envelopeAAD(bucket, object))
+
+	var size int64
+	var body []byte
+	hasher := sha256.New()
+	buf := make([]byte, streamChunkSize)
+	for {
+		n, readErr := io.ReadFull(r, buf)
+		if n > 0 {
+			chunk, err := sealStreamChunk(aesgcm, buf[:n], aad)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to encrypt chunk")
+			}
+			body = append(body, chunk...)
+			hasher.Write(buf[:n])
+			size += int64(n)
+		}
+		if readErr == io.EOF || readErr == io.ErrUnexpectedEOF {
+			break
+		}
+		if readErr != nil {
+			return nil, errors.Wrap(readErr, "failed to read plaintext")
+		}
+	}
+
+	switch {
+	case IsLocalKey(key):
+		logger.Warn("using a local development key; this secret is NOT protected by Cloud KMS and must not be used in production")
+	case IsPassphraseKey(key):
+		logger.Warn("using a passphrase key; this secret is NOT protected by Cloud KMS and is only as strong as the passphrase")
+	default:
+		logger.Debug("encrypting envelope")
+	}
+	encDEK, err := c.wrapDEK(ctx, key, string(aad), dek, i.Asymmetric)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt dek")
+	}
+
+	additionalKeysMeta, err := c.wrapAdditionalDEKs(ctx, i.AdditionalKeys, string(aad), dek, i.Asymmetric)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt dek with additional keys")
+	}
+
+	blob := fmt.Sprintf("%s:%s",
+		base64.StdEncoding.EncodeToString(encDEK),
+		base64.StdEncoding.EncodeToString(body))
+
+	metadata := map[string]string{
+		MetadataIDKey:             "1",
+		MetadataKMSKey:            kmsKeyTrimVersion(key),
+		MetadataEnvelopeFormatKey: string(EnvelopeFormatChunked),
+		MetadataAADKey:            envelopeAADVersion1,
+		MetadataIntegrityKey:      hex.EncodeToString(hasher.Sum(nil)),
+	}
+	if v := kmsKeyVersion(key); v != "" {
+		metadata[MetadataKMSKeyVersionKey] = v
+	}
+	if i.Asymmetric {
+		metadata[MetadataAsymmetricKey] = "1"
+	}
+	if additionalKeysMeta != "" {
+		metadata[MetadataAdditionalKeysKey] = additionalKeysMeta
+	}
+	if !i.RetainUntil.IsZero() {
+		metadata[MetadataRetainUntilKey] = i.RetainUntil.UTC().Format(time.RFC3339)
+	}
+
+	logger.WithField("metadata", metadata).Debug("writing object to storage")
+	var obj *StorageObject
+	err = c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		obj, err = c.secretStorage.WriteObject(ctx, bucket, object, []byte(blob), metadata, StorageConditions{DoesNotExist: true})
+		return err
+	})
+	if err != nil {
+		return nil, wrapAPIErr(err, "failed to save encrypted ciphertext to storage")
+	}
+
+	secret := secretFromStorageObject(object, obj, nil)
+	secret.Size = size
+	return secret, nil
+}
+
+// AccessReader reads the contents of the secret from the bucket, decrypting
+// it with Cloud KMS and returning the plaintext as a stream. Unlike Access,
+// it never holds the whole plaintext in memory at once, decrypting it
+// streamChunkSize bytes at a time as the caller reads - intended for
+// multi-hundred-MB (or larger) secrets, most usefully ones written with
+// CreateFromReader. The caller must Close the returned reader.
+//
+// Secrets written by Create (or Update, etc.) can also be read with
+// AccessReader, but since their ciphertext is a single AES-GCM seal, the
+// whole plaintext is still decrypted up front; AccessReader only avoids an
+// extra copy at the io.Reader boundary in that case.
+func (c *Client) AccessReader(ctx context.Context, i *AccessRequest) (io.ReadCloser, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	object := i.Object
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+
+	bucket, object, err := c.resolveAliasRef(ctx, bucket, object)
+	if err != nil {
+		return nil, err
+	}
+
+	generation := i.Generation
+	if generation == 0 {
+		generation = -1
+	}
+
+	var data []byte
+	var obj *StorageObject
+	err = c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		data, obj, err = c.secretStorage.ReadObject(ctx, bucket, object, generation)
+		return err
+	})
+	if err != nil {
+		return nil, wrapAPIErr(err, "failed to access secret")
+	}
+	if obj.Metadata == nil || obj.Metadata[MetadataKMSKey] == "" {
+		return nil, errors.New("missing kms key in secret metadata")
+	}
+	key := obj.Metadata[MetadataKMSKey]
+
+	parts := strings.SplitN(string(data), ":", 2)
+	if len(parts) < 2 {
+		return nil, errors.New("invalid ciphertext: not enough parts")
+	}
+
+	encDEK, err := base64.StdEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errors.New("invalid ciphertext: failed to parse dek")
+	}
+
+	body, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errors.New("invalid ciphertext: failed to parse ciphertext")
+	}
+
+	dekAAD, contentAAD := readEnvelopeAAD(bucket, object, obj.Metadata)
+
+	dek, err := c.unwrapAnyDEK(ctx, key, dekAAD, encDEK, obj.Metadata[MetadataAsymmetricKey] == "1", obj.Metadata)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to decrypt dek")
+	}
+
+	if EnvelopeFormat(obj.Metadata[MetadataEnvelopeFormatKey]) != EnvelopeFormatChunked {
+		plaintext, err := envelopeDecrypt(dek, body, contentAAD)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to decrypt envelope")
+		}
+		if err := verifyIntegrity(plaintext, obj.Metadata); err != nil {
+			return nil, err
+		}
+		return ioutil.NopCloser(bytes.NewReader(plaintext)), nil
+	}
+
+	aesgcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		hasher := sha256.New()
+		if err := streamStreamChunks(aesgcm, body, contentAAD, io.MultiWriter(pw, hasher)); err != nil {
+			pw.CloseWithError(err)
+			return
+		}
+		if want := obj.Metadata[MetadataIntegrityKey]; want != "" && hex.EncodeToString(hasher.Sum(nil)) != want {
+			pw.CloseWithError(errIntegrityCheckFailed)
+			return
+		}
+		pw.Close()
+	}()
+	return pr, nil
+}
+
+// newGCM constructs an AES-GCM AEAD from dek.
+func newGCM(dek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dek)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cipher from dek")
+	}
+	aesgcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create gcm from dek")
+	}
+	return aesgcm, nil
+}
+
+// sealStreamChunk seals a single plaintext chunk, prefixed with a random
+// nonce and the sealed chunk's length so streamStreamChunks can parse it
+// back out of the concatenated body. aad is bound to every chunk; see
+// envelopeAAD.
+func sealStreamChunk(aesgcm cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aesgcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, errors.Wrap(err, "failed to generate random nonce bytes")
+	}
+
+	sealed := aesgcm.Seal(nil, nonce, plaintext, aad)
+
+	record := make([]byte, 0, len(nonce)+4+len(sealed))
+	record = append(record, nonce...)
+	record = appendUint32(record, uint32(len(sealed)))
+	record = append(record, sealed...)
+	return record, nil
+}
+
+// streamStreamChunks parses and decrypts the chunks written by
+// CreateFromReader out of body, writing each chunk's plaintext to w as soon
+// as it is decrypted. aad must match what was passed to sealStreamChunk, or
+// nil if the ciphertext predates MetadataAADKey (see readEnvelopeAAD).
+func streamStreamChunks(aesgcm cipher.AEAD, body, aad []byte, w io.Writer) error {
+	nonceSize := aesgcm.NonceSize()
+
+	for len(body) > 0 {
+		if len(body) < nonceSize+4 {
+			return errors.New("malformed streamed ciphertext: truncated chunk header")
+		}
+		nonce := body[:nonceSize]
+		length := binary.BigEndian.Uint32(body[nonceSize : nonceSize+4])
+		body = body[nonceSize+4:]
+
+		if uint64(len(body)) < uint64(length) {
+			return errors.New("malformed streamed ciphertext: truncated chunk body")
+		}
+		sealed := body[:length]
+		body = body[length:]
+
+		plaintext, err := aesgcm.Open(nil, nonce, sealed, aad)
+		if err != nil {
+			return errors.Wrap(err, "failed to decrypt chunk")
+		}
+		if _, err := w.Write(plaintext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var tmp [4]byte
+	binary.BigEndian.PutUint32(tmp[:], v)
+	return append(b, tmp[:]...)
+}
+
+// chunkedEnvelopeEncrypt is envelopeEncrypt's EnvelopeFormatChunked
+// counterpart: it generates a unique DEK and seals plaintext as a sequence
+// of streamChunkSize chunks (see sealStreamChunk), each bound to aad,
+// instead of one single AES-GCM seal. Used by encryptAndWrite for in-memory
+// Create/Update calls that request EnvelopeFormatChunked; CreateFromReader
+// seals chunks incrementally as it reads instead of calling this directly.
+func chunkedEnvelopeEncrypt(plaintext, aad []byte) (dek, body []byte, err error) {
+	dek = make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return nil, nil, errors.Wrap(err, "failed to generate random key bytes")
+	}
+
+	aesgcm, err := newGCM(dek)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for offset := 0; offset < len(plaintext); offset += streamChunkSize {
+		end := offset + streamChunkSize
+		if end > len(plaintext) {
+			end = len(plaintext)
+		}
+
+		chunk, err := sealStreamChunk(aesgcm, plaintext[offset:end], aad)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "failed to encrypt chunk")
+		}
+		body = append(body, chunk...)
+	}
+
+	return dek, body, nil
+}
+
+// chunkedEnvelopeDecrypt is envelopeDecrypt's EnvelopeFormatChunked
+// counterpart, used by Read to decode a chunked secret's ciphertext body in
+// full. aad must match what was passed to chunkedEnvelopeEncrypt, or nil if
+// the ciphertext predates MetadataAADKey (see readEnvelopeAAD). See
+// AccessReader to decode one without buffering the whole plaintext at once.
+func chunkedEnvelopeDecrypt(dek, body, aad []byte) ([]byte, error) {
+	aesgcm, err := newGCM(dek)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := streamStreamChunks(aesgcm, body, aad, &buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,257 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"testing"
+)
+
+func Test_Client_CreateFromReader_AccessReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Exercise multiple chunk boundaries: smaller than a chunk, exactly one
+	// chunk, and several chunks plus a partial one.
+	sizes := []int{0, 10, streamChunkSize, streamChunkSize*3 + 17}
+	for i, size := range sizes {
+		plaintext := make([]byte, size)
+		if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+			t.Fatal(err)
+		}
+
+		object := fmt.Sprintf("my-object-%d", i)
+		if _, err := client.CreateFromReader(ctx, &CreateFromReaderRequest{
+			Bucket: "my-bucket",
+			Object: object,
+			Key:    "my-key",
+		}, bytes.NewReader(plaintext)); err != nil {
+			t.Fatalf("size %d: create: %v", size, err)
+		}
+
+		rc, err := client.AccessReader(ctx, &AccessRequest{Bucket: "my-bucket", Object: object})
+		if err != nil {
+			t.Fatalf("size %d: access: %v", size, err)
+		}
+		got, err := ioutil.ReadAll(rc)
+		if err != nil {
+			t.Fatalf("size %d: read: %v", size, err)
+		}
+		if err := rc.Close(); err != nil {
+			t.Fatalf("size %d: close: %v", size, err)
+		}
+
+		if !bytes.Equal(got, plaintext) {
+			t.Errorf("size %d: got %d bytes, want %d bytes, contents differ", size, len(got), len(plaintext))
+		}
+	}
+}
+
+func Test_Client_AccessReader_nonStreamedSecret(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	rc, err := client.AccessReader(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	got, err := ioutil.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(got), "my secret value"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+func Test_Client_Create_chunked(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := make([]byte, streamChunkSize*2+5)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		t.Fatal(err)
+	}
+
+	created, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Key:       "my-key",
+		Plaintext: plaintext,
+		Format:    EnvelopeFormatChunked,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.Format != EnvelopeFormatChunked {
+		t.Errorf("created.Format = %q, want %q", created.Format, EnvelopeFormatChunked)
+	}
+
+	// Read (not AccessReader) must transparently decode the chunked format.
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret.Plaintext, plaintext) {
+		t.Error("read plaintext does not match what was created")
+	}
+	if secret.Format != EnvelopeFormatChunked {
+		t.Errorf("secret.Format = %q, want %q", secret.Format, EnvelopeFormatChunked)
+	}
+
+	// Updating without specifying Format preserves the existing chunked
+	// envelope rather than silently reverting to EnvelopeFormatDefault.
+	updated, err := client.Update(ctx, &UpdateRequest{
+		Bucket:    "my-bucket",
+		Object:    "my-object",
+		Plaintext: []byte("short value now"),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Format != EnvelopeFormatChunked {
+		t.Errorf("updated.Format = %q, want %q (format should be preserved)", updated.Format, EnvelopeFormatChunked)
+	}
+
+	plaintext2, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := string(plaintext2), "short value now"; act != exp {
+		t.Errorf("plaintext = %q, want %q", act, exp)
+	}
+}
+
+func Test_Client_Read_streamedSecret(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := make([]byte, streamChunkSize+42)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateFromReader(ctx, &CreateFromReaderRequest{
+		Bucket: "my-bucket",
+		Object: "my-object",
+		Key:    "my-key",
+	}, bytes.NewReader(plaintext)); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := client.Read(ctx, &ReadRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret.Plaintext, plaintext) {
+		t.Error("read plaintext does not match what was streamed in")
+	}
+}
+
+// Test_Client_AccessReader_integrityTamperDetected verifies that tampering
+// with a streamed secret's recorded integrity metadata is caught once the
+// reader drains, since AccessReader cannot hash the plaintext until it has
+// streamed all of it.
+func Test_Client_AccessReader_integrityTamperDetected(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	storage := newMemSecretStorage()
+	client, err := NewWithBackends(ctx, storage, &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintext := make([]byte, streamChunkSize+42)
+	if _, err := io.ReadFull(rand.Reader, plaintext); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.CreateFromReader(ctx, &CreateFromReaderRequest{
+		Bucket: "my-bucket",
+		Object: "my-object",
+		Key:    "my-key",
+	}, bytes.NewReader(plaintext)); err != nil {
+		t.Fatal(err)
+	}
+
+	storage.mu.Lock()
+	storage.objects[storage.key("my-bucket", "my-object")].metadata[MetadataIntegrityKey] = integrityDigest([]byte("not the secret"))
+	storage.mu.Unlock()
+
+	rc, err := client.AccessReader(ctx, &AccessRequest{Bucket: "my-bucket", Object: "my-object"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rc.Close()
+
+	if _, err := ioutil.ReadAll(rc); !IsIntegrityCheckFailedErr(err) {
+		t.Errorf("expected IsIntegrityCheckFailedErr, got %v", err)
+	}
+}
+
+func Test_Client_CreateFromReader_alreadyExists(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := &CreateFromReaderRequest{Bucket: "my-bucket", Object: "my-object", Key: "my-key"}
+	if _, err := client.CreateFromReader(ctx, req, bytes.NewReader([]byte("v1"))); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateFromReader(ctx, req, bytes.NewReader([]byte("v2"))); !IsSecretAlreadyExistsErr(err) {
+		t.Errorf("expected IsSecretAlreadyExistsErr, got %v", err)
+	}
+}
@@ -16,13 +16,18 @@ package berglas
 
 import (
 	"context"
+	"time"
 
 	"cloud.google.com/go/iam"
-	"cloud.google.com/go/storage"
+	"github.com/GoogleCloudPlatform/berglas/pkg/retry"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
 )
 
+// updateDefaultMaxRetries is the number of times Update will retry a
+// MergeFunc after a generation precondition conflict before giving up.
+const updateDefaultMaxRetries = 5
+
 // Update is a top-level package function for creating a secret. For large
 // volumes of secrets, please update a client instead.
 func Update(ctx context.Context, i *UpdateRequest) (*Secret, error) {
@@ -56,11 +61,41 @@ type UpdateRequest struct {
 	// CreateIfMissing indicates that the updater should create a secret with the
 	// given parameters if one does not already exist.
 	CreateIfMissing bool
+
+	// MergeFunc, if given, is invoked with the secret's current plaintext to
+	// compute the new plaintext to write. When set, Plaintext, Generation, and
+	// Metageneration are ignored; Update reads the latest generation itself and
+	// automatically retries the merge (up to MaxRetries times) if a concurrent
+	// writer changes the secret in the meantime, giving callers safe
+	// concurrent-update semantics without implementing their own CAS loop.
+	MergeFunc func(current []byte) ([]byte, error)
+
+	// MaxRetries bounds the number of retry attempts performed when MergeFunc
+	// is set and a precondition conflict occurs. If 0, updateDefaultMaxRetries
+	// is used.
+	MaxRetries int
+
+	// Format selects the envelope format used to wrap the DEK. Defaults to
+	// EnvelopeFormatDefault. Ignored when MergeFunc or CreateIfMissing with an
+	// existing secret reuse the secret's current envelope.
+	Format EnvelopeFormat
+
+	// RetainUntil, if non-zero, prevents the secret from being deleted or
+	// overwritten until this time has passed. If zero and the secret already
+	// has a RetainUntil set, the existing value is preserved. Ignored by
+	// MergeFunc, which always preserves the secret's existing RetainUntil.
+	RetainUntil time.Time
 }
 
 // Update changes the contents of an existing secret. If the secret does not
 // exist, an error is returned.
-func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error) {
+func (c *Client) Update(ctx context.Context, i *UpdateRequest) (_ *Secret, err error) {
+	defer func() {
+		if err != nil {
+			recordError("update")
+		}
+	}()
+
 	if i == nil {
 		return nil, errors.New("missing request")
 	}
@@ -75,6 +110,14 @@ func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error)
 		return nil, errors.New("missing object name")
 	}
 
+	if i.MergeFunc != nil {
+		return c.updateWithMerge(ctx, bucket, object, i.MergeFunc, i.MaxRetries)
+	}
+
+	if err := i.Format.validate(); err != nil {
+		return nil, errors.Wrap(err, "unsupported envelope format")
+	}
+
 	// Key and Plaintext may be required depending on whether the object exists.
 	key := i.Key
 	plaintext := i.Plaintext
@@ -97,39 +140,43 @@ func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error)
 
 	// If no specific generations were given, lookup the latest generation to make
 	// sure we don't conflict with another write.
-	attrs, err := c.storageClient.
-		Bucket(bucket).
-		Object(object).
-		Attrs(ctx)
-	switch err {
-	case nil:
+	obj, err := c.secretStorage.StatObject(ctx, bucket, object, 0)
+	switch {
+	case err == nil:
 		logger = logger.WithFields(logrus.Fields{
-			"existing.bucket":         attrs.Bucket,
-			"existing.name":           attrs.Name,
-			"existing.size":           attrs.Size,
-			"existing.metadata":       attrs.Metadata,
-			"existing.generation":     attrs.Generation,
-			"existing.metageneration": attrs.Metageneration,
-			"existing.created":        attrs.Created,
-			"existing.deleted":        attrs.Deleted,
-			"existing.updated":        attrs.Updated,
+			"existing.size":           obj.Size,
+			"existing.metadata":       obj.Metadata,
+			"existing.generation":     obj.Generation,
+			"existing.metageneration": obj.Metageneration,
+			"existing.created":        obj.Created,
+			"existing.updated":        obj.Updated,
 		})
 		logger.Debug("found existing storage object")
 
+		existingRetainUntil := retainUntilFromMetadata(obj.Metadata)
+		if err := checkRetention(existingRetainUntil); err != nil {
+			return nil, errors.Wrap(err, "failed to update secret")
+		}
+
+		retainUntil := i.RetainUntil
+		if retainUntil.IsZero() {
+			retainUntil = existingRetainUntil
+		}
+
 		if generation == 0 {
-			generation = attrs.Generation
+			generation = obj.Generation
 			logger = logger.WithField("generation", generation)
 			logger.Debug("setting generation")
 		}
 
 		if metageneration == 0 {
-			metageneration = attrs.Metageneration
+			metageneration = obj.Metageneration
 			logger = logger.WithField("metageneration", metageneration)
 			logger.Debug("setting metageneration")
 		}
 
 		if key == "" {
-			key = attrs.Metadata[MetadataKMSKey]
+			key = obj.Metadata[MetadataKMSKey]
 			logger = logger.WithField("key", key)
 			logger.Debug("setting key")
 		}
@@ -147,38 +194,57 @@ func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error)
 			}
 		}
 
-		// Get existing IAM policies
-		logger.Debug("getting iam policies")
+		// Get existing IAM policies. Storage object ACLs are specific to
+		// Cloud Storage, so there is nothing to copy on a NewWithBackends
+		// client that has no storageIAMClient.
+		var storageHandle *iam.Handle
+		var storageP *iam.Policy
+		if c.storageIAMClient != nil {
+			logger.Debug("getting iam policies")
 
-		storageHandle := c.storageIAM(bucket, object)
-		storageP, err := getIAMPolicy(ctx, storageHandle)
-		if err != nil {
-			return nil, errors.Wrap(err, "failed to get IAM policy")
+			storageHandle = c.storageIAM(bucket, object)
+			storageP, err = getIAMPolicy(ctx, storageHandle)
+			if err != nil {
+				return nil, errors.Wrap(err, "failed to get IAM policy")
+			}
 		}
 
 		// Update the secret
 		logger.Debug("updating secret")
 
-		secret, err := c.encryptAndWrite(ctx, bucket, object, key, plaintext,
-			generation, metageneration)
+		format := i.Format
+		if format == EnvelopeFormatDefault {
+			format = EnvelopeFormat(obj.Metadata[MetadataEnvelopeFormatKey])
+		}
+
+		// Asymmetric wrapping and AdditionalKeys, unlike Format, are not
+		// settable from UpdateRequest; they are always carried over from the
+		// existing secret.
+		asymmetric := obj.Metadata[MetadataAsymmetricKey] == "1"
+		additionalKeys := additionalKMSKeysFromMetadata(obj.Metadata)
+
+		secret, err := c.encryptAndWrite(ctx, bucket, object, key, additionalKeys, plaintext, format, asymmetric,
+			generation, metageneration, retainUntil)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to update secret")
 		}
 
 		// Copy over the existing IAM memberships, if any
-		logger.Debug("updating iam policies")
-
-		if err := updateIAMPolicy(ctx, storageHandle, func(p *iam.Policy) *iam.Policy {
-			// Copy any IAM permissions from the old object over to the new object.
-			for _, m := range storageP.Members(iamObjectReader) {
-				p.Add(m, iamObjectReader)
+		if storageHandle != nil {
+			logger.Debug("updating iam policies")
+
+			if err := updateIAMPolicy(ctx, storageHandle, func(p *iam.Policy) *iam.Policy {
+				// Copy any IAM permissions from the old object over to the new object.
+				for _, m := range storageP.Members(iamObjectReader) {
+					p.Add(m, iamObjectReader)
+				}
+				return p
+			}); err != nil {
+				return nil, errors.Wrapf(err, "failed to update Storage IAM policy for %s", object)
 			}
-			return p
-		}); err != nil {
-			return nil, errors.Wrapf(err, "failed to update Storage IAM policy for %s", object)
 		}
 		return secret, nil
-	case storage.ErrObjectNotExist:
+	case IsSecretDoesNotExistErr(err):
 		logger.Debug("secret does not exist")
 
 		if !createIfMissing {
@@ -196,8 +262,8 @@ func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error)
 		logger.Debug("creating secret")
 
 		// Update the secret.
-		secret, err := c.encryptAndWrite(ctx, bucket, object, key, plaintext,
-			generation, metageneration)
+		secret, err := c.encryptAndWrite(ctx, bucket, object, key, nil, plaintext, i.Format, false,
+			generation, metageneration, i.RetainUntil)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to update secret")
 		}
@@ -206,3 +272,83 @@ func (c *Client) Update(ctx context.Context, i *UpdateRequest) (*Secret, error)
 		return nil, errors.Wrap(err, "failed to fetch existing secret")
 	}
 }
+
+// updateWithMerge implements the MergeFunc retry loop described on
+// UpdateRequest. The secret must already exist.
+func (c *Client) updateWithMerge(ctx context.Context, bucket, object string,
+	mergeFunc func([]byte) ([]byte, error), maxRetries int) (*Secret, error) {
+
+	if maxRetries == 0 {
+		maxRetries = updateDefaultMaxRetries
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"bucket":      bucket,
+		"object":      object,
+		"max_retries": maxRetries,
+	})
+
+	logger.Debug("update.merge.start")
+	defer logger.Debug("update.merge.finish")
+
+	var secret *Secret
+	if err := retry.RetryFib(ctx, 100*time.Millisecond, maxRetries, func() error {
+		existing, err := c.Read(ctx, &ReadRequest{
+			Bucket: bucket,
+			Object: object,
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to read existing secret")
+		}
+
+		if err := checkRetention(existing.RetainUntil); err != nil {
+			return err
+		}
+
+		plaintext, err := mergeFunc(existing.Plaintext)
+		if err != nil {
+			return errors.Wrap(err, "merge func failed")
+		}
+
+		// Storage object ACLs are specific to Cloud Storage, so there is
+		// nothing to copy on a NewWithBackends client that has no
+		// storageIAMClient.
+		var storageHandle *iam.Handle
+		var storageP *iam.Policy
+		if c.storageIAMClient != nil {
+			storageHandle = c.storageIAM(bucket, object)
+			storageP, err = getIAMPolicy(ctx, storageHandle)
+			if err != nil {
+				return errors.Wrap(err, "failed to get IAM policy")
+			}
+		}
+
+		s, err := c.encryptAndWrite(ctx, bucket, object, existing.KMSKey, existing.AdditionalKMSKeys, plaintext, existing.Format, existing.Asymmetric,
+			existing.Generation, existing.Metageneration, existing.RetainUntil)
+		if err != nil {
+			if IsSecretModifiedErr(err) {
+				logger.Debug("secret modified between read and write, retrying")
+				return retry.RetryableError(err)
+			}
+			return err
+		}
+
+		if storageHandle != nil {
+			if err := updateIAMPolicy(ctx, storageHandle, func(p *iam.Policy) *iam.Policy {
+				for _, m := range storageP.Members(iamObjectReader) {
+					p.Add(m, iamObjectReader)
+				}
+				return p
+			}); err != nil {
+				return errors.Wrapf(err, "failed to update Storage IAM policy for %s", object)
+			}
+		}
+
+		secret = s
+		return nil
+	}); err != nil {
+		return nil, errors.Wrap(err, "failed to update secret")
+	}
+
+	return secret, nil
+}
@@ -0,0 +1,380 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// VaultAddrEnvVar and VaultTokenEnvVar are the environment variables the
+// vault CLI itself reads. ImportVaultRequest.Addr and .Token fall back to
+// them, so a migration can reuse whatever "vault login" already set up
+// instead of duplicating credentials into a flag.
+const (
+	VaultAddrEnvVar  = "VAULT_ADDR"
+	VaultTokenEnvVar = "VAULT_TOKEN"
+)
+
+// ImportVaultRequest is used as input to ImportVault.
+type ImportVaultRequest struct {
+	// Addr is the address of the Vault server, e.g. "https://vault.corp.example:8200".
+	// Defaults to the VAULT_ADDR environment variable.
+	Addr string
+
+	// Token is the Vault token used to authenticate to Addr. Defaults to the
+	// VAULT_TOKEN environment variable.
+	Token string
+
+	// Mount is the path the KV version 2 secrets engine is mounted at.
+	// Defaults to "secret".
+	Mount string
+
+	// Path is the path, relative to Mount, to migrate. Every secret at and
+	// below this path is imported, recursively.
+	Path string
+
+	// Bucket is the name of the destination Cloud Storage bucket.
+	Bucket string
+
+	// Key is the fully qualified KMS key id (or a local-key:// reference,
+	// see IsLocalKey) used to encrypt the imported secrets.
+	Key string
+
+	// ResumeFrom, if given, skips every object whose name sorts at or before
+	// it, so a migration interrupted partway through can continue without
+	// re-importing objects it already wrote. See ImportVaultResult.Imported.
+	ResumeFrom string
+}
+
+// ImportVaultResult is the outcome of an ImportVault call.
+type ImportVaultResult struct {
+	// Imported is the berglas object names that were created, in the order
+	// they were imported.
+	Imported []string
+
+	// Skipped is the berglas object names that already existed in Bucket and
+	// so were left untouched - either from a previous, interrupted run of
+	// this same import or an unrelated secret that happened to collide.
+	Skipped []string
+
+	// Failed maps a berglas object name to the error that occurred while
+	// importing it. ImportVault keeps going past individual failures so a
+	// handful of unreadable Vault secrets don't block the rest of the
+	// migration.
+	Failed map[string]error
+}
+
+// ImportVault reads every KV version 2 secret at and below i.Path on a Vault
+// server and writes each one to i.Bucket as a berglas secret, preserving
+// Vault's "/"-separated path hierarchy in the object name. It is meant as a
+// one-shot migration tool for teams moving off Vault.
+//
+// A Vault secret with exactly one field named "value" (the shape "vault kv
+// put path value=..." produces) is imported as a single object named after
+// its Vault path. A secret with any other field or fields is imported as one
+// object per field, named "<vault-path>/<field>", since there is no single
+// value to collapse it to.
+//
+// ImportVault is safe to interrupt and re-run: secrets are processed in
+// ascending object-name order, already-imported objects are left alone
+// rather than overwritten, and i.ResumeFrom skips ahead to the last object
+// name reported in a previous, interrupted ImportVaultResult so a large
+// migration does not re-read everything from the beginning.
+func (c *Client) ImportVault(ctx context.Context, i *ImportVaultRequest) (*ImportVaultResult, error) {
+	if i == nil {
+		return nil, errors.New("missing request")
+	}
+
+	bucket := i.Bucket
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+
+	path := i.Path
+	if path == "" {
+		return nil, errors.New("missing vault path")
+	}
+
+	key := i.Key
+	if key == "" {
+		return nil, errors.New("missing key name")
+	}
+
+	mount := i.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	vc, err := newVaultClient(i.Addr, i.Token)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := c.Logger().WithFields(logrus.Fields{
+		"vault_addr": vc.addr,
+		"mount":      mount,
+		"path":       path,
+		"bucket":     bucket,
+	})
+
+	logger.Debug("importvault.start")
+	defer logger.Debug("importvault.finish")
+
+	secretPaths, err := vc.list(ctx, mount, path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list vault secrets")
+	}
+	sort.Strings(secretPaths)
+
+	result := &ImportVaultResult{Failed: make(map[string]error)}
+
+	for _, secretPath := range secretPaths {
+		// The objects a secretPath expands to are either secretPath itself
+		// (a single "value" field) or "secretPath/<field>" (anything else),
+		// both of which sort at or after secretPath. So once secretPath
+		// itself sorts at or before ResumeFrom, every object it could
+		// expand to is also <= ResumeFrom and skippable without reading it
+		// - unless ResumeFrom falls inside this secretPath's own field
+		// namespace, in which case some fields may still sort after it and
+		// the secret must be read to tell which.
+		if i.ResumeFrom != "" && secretPath <= i.ResumeFrom && !strings.HasPrefix(i.ResumeFrom, secretPath+"/") {
+			continue
+		}
+
+		data, err := vc.read(ctx, mount, secretPath)
+		if err != nil {
+			result.Failed[secretPath] = err
+			continue
+		}
+
+		for _, obj := range vaultObjects(secretPath, data) {
+			if i.ResumeFrom != "" && obj.name <= i.ResumeFrom {
+				continue
+			}
+
+			if _, rerr := c.Read(ctx, &ReadRequest{Bucket: bucket, Object: obj.name}); rerr == nil {
+				result.Skipped = append(result.Skipped, obj.name)
+				continue
+			} else if !IsSecretDoesNotExistErr(rerr) {
+				result.Failed[obj.name] = rerr
+				continue
+			}
+
+			if _, cerr := c.Create(ctx, &CreateRequest{
+				Bucket:    bucket,
+				Object:    obj.name,
+				Key:       key,
+				Plaintext: obj.value,
+			}); cerr != nil {
+				result.Failed[obj.name] = cerr
+				continue
+			}
+
+			result.Imported = append(result.Imported, obj.name)
+		}
+	}
+
+	return result, nil
+}
+
+// vaultObject is one berglas secret to create, derived from a single Vault
+// KV version 2 secret.
+type vaultObject struct {
+	name  string
+	value []byte
+}
+
+// vaultObjects flattens a Vault KV version 2 secret's data into the one or
+// more berglas objects it should become. See ImportVault's doc comment for
+// the naming rule.
+func vaultObjects(secretPath string, data map[string]interface{}) []vaultObject {
+	if v, ok := data["value"]; ok && len(data) == 1 {
+		return []vaultObject{{name: secretPath, value: vaultFieldBytes(v)}}
+	}
+
+	fields := make([]string, 0, len(data))
+	for field := range data {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	objects := make([]vaultObject, 0, len(fields))
+	for _, field := range fields {
+		objects = append(objects, vaultObject{
+			name:  secretPath + "/" + field,
+			value: vaultFieldBytes(data[field]),
+		})
+	}
+	return objects
+}
+
+// vaultFieldBytes renders a decoded Vault secret field as the plaintext
+// bytes a berglas secret should hold: a string field is stored as-is, so a
+// plain "vault kv put path key=val" round-trips byte for byte, and anything
+// else (Vault also allows numbers, booleans, and nested objects) is
+// re-marshaled as JSON.
+func vaultFieldBytes(v interface{}) []byte {
+	if s, ok := v.(string); ok {
+		return []byte(s)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return []byte(fmt.Sprintf("%v", v))
+	}
+	return b
+}
+
+// vaultClient is a minimal HTTP client for the subset of Vault's KV version
+// 2 API that ImportVault needs: listing and reading secrets. It is
+// intentionally hand-rolled rather than a dependency on Vault's API client,
+// since ImportVault is the only thing in berglas that talks to Vault.
+type vaultClient struct {
+	addr  string
+	token string
+	hc    *http.Client
+}
+
+// newVaultClient builds a vaultClient from addr and token, falling back to
+// VaultAddrEnvVar and VaultTokenEnvVar (matching the vault CLI's own
+// defaults) when either is empty.
+func newVaultClient(addr, token string) (*vaultClient, error) {
+	if addr == "" {
+		addr = os.Getenv(VaultAddrEnvVar)
+	}
+	if addr == "" {
+		return nil, errors.Errorf("missing vault address (set Addr or %s)", VaultAddrEnvVar)
+	}
+
+	if token == "" {
+		token = os.Getenv(VaultTokenEnvVar)
+	}
+	if token == "" {
+		return nil, errors.Errorf("missing vault token (set Token or %s)", VaultTokenEnvVar)
+	}
+
+	return &vaultClient{
+		addr:  strings.TrimSuffix(addr, "/"),
+		token: token,
+		hc:    http.DefaultClient,
+	}, nil
+}
+
+// list recursively lists the path of every leaf secret at and below path
+// under mount, using KV version 2's "list" metadata operation. If path
+// itself has no children (it addresses a secret directly, not a folder of
+// secrets), list returns just path.
+func (vc *vaultClient) list(ctx context.Context, mount, path string) ([]string, error) {
+	keys, ok, err := vc.listOne(ctx, mount, path)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return []string{strings.Trim(path, "/")}, nil
+	}
+
+	var leaves []string
+	for _, key := range keys {
+		full := strings.Trim(strings.TrimSuffix(path, "/")+"/"+strings.TrimPrefix(key, "/"), "/")
+		if strings.HasSuffix(key, "/") {
+			children, err := vc.list(ctx, mount, full)
+			if err != nil {
+				return nil, err
+			}
+			leaves = append(leaves, children...)
+			continue
+		}
+		leaves = append(leaves, full)
+	}
+	return leaves, nil
+}
+
+// listOne lists the immediate children of path, returning ok=false if path
+// has no "list" metadata at all (it is a leaf secret, not a folder).
+func (vc *vaultClient) listOne(ctx context.Context, mount, path string) ([]string, bool, error) {
+	url := fmt.Sprintf("%s/v1/%s/metadata/%s?list=true", vc.addr, mount, strings.Trim(path, "/"))
+
+	var body struct {
+		Data struct {
+			Keys []string `json:"keys"`
+		} `json:"data"`
+	}
+	ok, err := vc.doJSON(ctx, http.MethodGet, url, &body)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return body.Data.Keys, true, nil
+}
+
+// read returns the decoded data fields of the current version of the secret
+// at path under mount.
+func (vc *vaultClient) read(ctx context.Context, mount, path string) (map[string]interface{}, error) {
+	url := fmt.Sprintf("%s/v1/%s/data/%s", vc.addr, mount, strings.Trim(path, "/"))
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	ok, err := vc.doJSON(ctx, http.MethodGet, url, &body)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, errors.Errorf("secret %s not found", path)
+	}
+	return body.Data.Data, nil
+}
+
+// doJSON issues method against url with the Vault token header and decodes
+// a JSON response body into out. It returns ok=false, with a nil error, for
+// a 404 response - the one non-error outcome callers need to distinguish,
+// since Vault uses it both for "does not exist" and "not a listable folder".
+func (vc *vaultClient) doJSON(ctx context.Context, method, url string, out interface{}) (bool, error) {
+	req, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("X-Vault-Token", vc.token)
+
+	resp, err := vc.hc.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return false, errors.Errorf("vault request to %s failed: %s: %s", url, resp.Status, strings.TrimSpace(string(b)))
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, errors.Wrapf(err, "failed to decode vault response from %s", url)
+	}
+	return true, nil
+}
@@ -0,0 +1,282 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglas
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// fakeVaultServer is a minimal KV version 2 server backing ImportVault's
+// tests: just enough of the "list" and "data" endpoints to exercise
+// recursive listing and reading, keyed by the same paths a real Vault
+// server would use.
+type fakeVaultServer struct {
+	secrets map[string]map[string]interface{}
+	reads   int32 // count of "data" (read) requests served, for resume-from tests
+}
+
+func newFakeVaultServer(secrets map[string]map[string]interface{}) (*httptest.Server, *fakeVaultServer) {
+	fs := &fakeVaultServer{secrets: secrets}
+	return httptest.NewServer(http.HandlerFunc(fs.handle)), fs
+}
+
+func (fs *fakeVaultServer) handle(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("X-Vault-Token") != "test-token" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	switch {
+	case strings.Contains(r.URL.Path, "/metadata/") && r.URL.Query().Get("list") == "true":
+		fs.handleList(w, r)
+	case strings.Contains(r.URL.Path, "/data/"):
+		fs.handleRead(w, r)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (fs *fakeVaultServer) handleList(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.TrimPrefix(strings.SplitN(r.URL.Path, "/metadata/", 2)[1], "")
+	prefix = strings.Trim(prefix, "/")
+
+	seen := make(map[string]bool)
+	var keys []string
+	for path := range fs.secrets {
+		if prefix != "" && !strings.HasPrefix(path, prefix+"/") {
+			continue
+		}
+		rest := strings.TrimPrefix(strings.TrimPrefix(path, prefix), "/")
+		if rest == "" {
+			continue
+		}
+		if i := strings.Index(rest, "/"); i >= 0 {
+			child := rest[:i+1]
+			if !seen[child] {
+				seen[child] = true
+				keys = append(keys, child)
+			}
+			continue
+		}
+		if !seen[rest] {
+			seen[rest] = true
+			keys = append(keys, rest)
+		}
+	}
+
+	if len(keys) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeVaultJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{"keys": keys},
+	})
+}
+
+func (fs *fakeVaultServer) handleRead(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&fs.reads, 1)
+
+	path := strings.Trim(strings.SplitN(r.URL.Path, "/data/", 2)[1], "/")
+
+	data, ok := fs.secrets[path]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	writeVaultJSON(w, map[string]interface{}{
+		"data": map[string]interface{}{"data": data},
+	})
+}
+
+func writeVaultJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestImportVault(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newFakeVaultServer(map[string]map[string]interface{}{
+		"myapp/api-key":     {"value": "abcd1234"},
+		"myapp/db/username": {"value": "app"},
+		"myapp/db/password": {"value": "hunter2"},
+		"myapp/multi":       {"one": "1", "two": "2"},
+	})
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.ImportVault(ctx, &ImportVaultRequest{
+		Addr:   srv.URL,
+		Token:  "test-token",
+		Path:   "myapp",
+		Bucket: "my-bucket",
+		Key:    "my-key",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Failed) != 0 {
+		t.Fatalf("expected no failures, got %v", result.Failed)
+	}
+
+	want := map[string]string{
+		"myapp/api-key":     "abcd1234",
+		"myapp/db/username": "app",
+		"myapp/db/password": "hunter2",
+		"myapp/multi/one":   "1",
+		"myapp/multi/two":   "2",
+	}
+	if len(result.Imported) != len(want) {
+		t.Fatalf("expected %d imported objects, got %v", len(want), result.Imported)
+	}
+
+	for name, plaintext := range want {
+		secret, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: name})
+		if err != nil {
+			t.Fatalf("access %s: %v", name, err)
+		}
+		if string(secret) != plaintext {
+			t.Errorf("expected %s to be %q, got %q", name, plaintext, secret)
+		}
+	}
+}
+
+func TestImportVault_skipsExisting(t *testing.T) {
+	t.Parallel()
+
+	srv, _ := newFakeVaultServer(map[string]map[string]interface{}{
+		"myapp/api-key": {"value": "abcd1234"},
+	})
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.Create(ctx, &CreateRequest{
+		Bucket:    "my-bucket",
+		Object:    "myapp/api-key",
+		Key:       "my-key",
+		Plaintext: []byte("already here"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.ImportVault(ctx, &ImportVaultRequest{
+		Addr:   srv.URL,
+		Token:  "test-token",
+		Path:   "myapp",
+		Bucket: "my-bucket",
+		Key:    "my-key",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Imported) != 0 {
+		t.Errorf("expected nothing imported, got %v", result.Imported)
+	}
+	if want := []string{"myapp/api-key"}; len(result.Skipped) != 1 || result.Skipped[0] != want[0] {
+		t.Errorf("expected skipped %v, got %v", want, result.Skipped)
+	}
+
+	secret, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "myapp/api-key"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret) != "already here" {
+		t.Errorf("expected existing secret to be untouched, got %q", secret)
+	}
+}
+
+func TestImportVault_resumeFrom(t *testing.T) {
+	t.Parallel()
+
+	srv, fs := newFakeVaultServer(map[string]map[string]interface{}{
+		"myapp/a": {"value": "1"},
+		"myapp/b": {"value": "2"},
+		"myapp/c": {"value": "3"},
+	})
+	defer srv.Close()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := client.ImportVault(ctx, &ImportVaultRequest{
+		Addr:       srv.URL,
+		Token:      "test-token",
+		Path:       "myapp",
+		Bucket:     "my-bucket",
+		Key:        "my-key",
+		ResumeFrom: "myapp/a",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"myapp/b", "myapp/c"}
+	if len(result.Imported) != len(want) {
+		t.Fatalf("expected %v, got %v", want, result.Imported)
+	}
+	for i, name := range want {
+		if result.Imported[i] != name {
+			t.Errorf("expected %v, got %v", want, result.Imported)
+			break
+		}
+	}
+
+	if _, err := client.Access(ctx, &AccessRequest{Bucket: "my-bucket", Object: "myapp/a"}); !IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected myapp/a to have been skipped by resume-from, got %v", err)
+	}
+
+	if reads := atomic.LoadInt32(&fs.reads); reads != 2 {
+		t.Errorf("vault read %d secrets, want 2 - resume-from should skip reading myapp/a entirely", reads)
+	}
+}
+
+func TestImportVault_missingCredentials(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	client, err := NewWithBackends(ctx, newMemSecretStorage(), &memKeyManager{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := client.ImportVault(ctx, &ImportVaultRequest{
+		Path:   "myapp",
+		Bucket: "my-bucket",
+		Key:    "my-key",
+	}); err == nil {
+		t.Error("expected error for missing vault address")
+	}
+}
@@ -18,50 +18,67 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
-	"net/http"
+	"time"
 
-	"cloud.google.com/go/storage"
 	"github.com/pkg/errors"
 	"github.com/sirupsen/logrus"
-	"google.golang.org/api/googleapi"
-	kmspb "google.golang.org/genproto/googleapis/cloud/kms/v1"
 )
 
 // encryptAndWrite is a low-level function for encrypting and writing data.
 func (c *Client) encryptAndWrite(
-	ctx context.Context, bucket, object, key string, plaintext []byte,
-	generation, metageneration int64) (*Secret, error) {
+	ctx context.Context, bucket, object, key string, additionalKeys []string, plaintext []byte, format EnvelopeFormat, asymmetric bool,
+	generation, metageneration int64, retainUntil time.Time) (*Secret, error) {
 
 	logger := c.Logger().WithFields(logrus.Fields{
-		"bucket":         bucket,
-		"object":         object,
-		"key":            key,
-		"generation":     generation,
-		"metageneration": metageneration,
+		"bucket":          bucket,
+		"object":          object,
+		"key":             key,
+		"additional_keys": additionalKeys,
+		"format":          format,
+		"asymmetric":      asymmetric,
+		"generation":      generation,
+		"metageneration":  metageneration,
+		"retain_until":    retainUntil,
 	})
 
 	logger.Debug("encryptAndWrite.start")
 	defer logger.Debug("encryptAndWrite.finish")
 
 	// Generate a unique DEK and encrypt the plaintext locally (useful for large
-	// pieces of data).
+	// pieces of data), binding it to this secret's bucket/object so its
+	// ciphertext cannot be substituted for another secret's undetected.
 	logger.Debug("generating envelope")
-	dek, ciphertext, err := envelopeEncrypt(plaintext)
+	aad := envelopeAAD(bucket, object)
+	var dek, ciphertext []byte
+	var err error
+	if format == EnvelopeFormatChunked {
+		dek, ciphertext, err = chunkedEnvelopeEncrypt(plaintext, []byte(aad))
+	} else {
+		dek, ciphertext, err = envelopeEncrypt(plaintext, []byte(aad))
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to perform envelope encryption")
 	}
 
-	// Encrypt the plaintext using a KMS key
-	logger.Debug("encrypting envelope")
-	kmsResp, err := c.kmsClient.Encrypt(ctx, &kmspb.EncryptRequest{
-		Name:                        key,
-		Plaintext:                   dek,
-		AdditionalAuthenticatedData: []byte(object),
-	})
+	// Encrypt the DEK using a local development key, a passphrase key, or a
+	// Cloud KMS key.
+	switch {
+	case IsLocalKey(key):
+		logger.Warn("using a local development key; this secret is NOT protected by Cloud KMS and must not be used in production")
+	case IsPassphraseKey(key):
+		logger.Warn("using a passphrase key; this secret is NOT protected by Cloud KMS and is only as strong as the passphrase")
+	default:
+		logger.Debug("encrypting envelope")
+	}
+	encDEK, err := c.wrapDEK(ctx, key, aad, dek, asymmetric)
 	if err != nil {
-		return nil, errors.Wrap(err, "failed to encrypt secret")
+		return nil, errors.Wrap(err, "failed to encrypt dek")
+	}
+
+	additionalKeysMeta, err := c.wrapAdditionalDEKs(ctx, additionalKeys, aad, dek, asymmetric)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt dek with additional keys")
 	}
-	encDEK := kmsResp.Ciphertext
 
 	// Build the storage object contents. Contents will be of the format:
 	//
@@ -73,59 +90,51 @@ func (c *Client) encryptAndWrite(
 	// If generation and metageneration are 0, then we should only create the
 	// object if it does not exist. Otherwise, we should only perform an update if
 	// the metagenerations match.
-	var conds storage.Conditions
+	var conds StorageConditions
 	if generation == 0 || metageneration == 0 {
-		conds = storage.Conditions{
+		conds = StorageConditions{
 			DoesNotExist: true,
 		}
 	} else {
-		conds = storage.Conditions{
+		conds = StorageConditions{
 			GenerationMatch:     generation,
 			MetagenerationMatch: metageneration,
 		}
 	}
 
-	// Create the writer
-	iow := c.storageClient.
-		Bucket(bucket).
-		Object(object).
-		If(conds).
-		NewWriter(ctx)
-
-	iow.ObjectAttrs.CacheControl = CacheControl
-	iow.ChunkSize = ChunkSize
-
-	if iow.Metadata == nil {
-		iow.Metadata = make(map[string]string)
+	metadata := map[string]string{
+		MetadataIDKey:        "1",
+		MetadataKMSKey:       kmsKeyTrimVersion(key),
+		MetadataAADKey:       envelopeAADVersion1,
+		MetadataIntegrityKey: integrityDigest(plaintext),
 	}
-	iow.Metadata[MetadataIDKey] = "1"
-	iow.Metadata[MetadataKMSKey] = kmsKeyTrimVersion(key)
-
-	// Write
-	logger.WithField("metadata", iow.Metadata).Debug("writing object to storage")
-	if _, err := iow.Write([]byte(blob)); err != nil {
-		return nil, errors.Wrap(err, "failed to save encrypted ciphertext to storage")
+	if v := kmsKeyVersion(key); v != "" {
+		metadata[MetadataKMSKeyVersionKey] = v
+	}
+	if format != EnvelopeFormatDefault {
+		metadata[MetadataEnvelopeFormatKey] = string(format)
+	}
+	if asymmetric {
+		metadata[MetadataAsymmetricKey] = "1"
+	}
+	if additionalKeysMeta != "" {
+		metadata[MetadataAdditionalKeysKey] = additionalKeysMeta
+	}
+	if !retainUntil.IsZero() {
+		metadata[MetadataRetainUntilKey] = retainUntil.UTC().Format(time.RFC3339)
 	}
 
-	// Close and flush
-	logger.Debug("finalizing writer")
-	if err := iow.Close(); err != nil {
-		logger.WithError(err).Error("failed to finalize writer")
-
-		if terr, ok := err.(*googleapi.Error); ok {
-			switch terr.Code {
-			case http.StatusNotFound:
-				return nil, errors.New("bucket does not exist")
-			case http.StatusPreconditionFailed:
-				if conds.DoesNotExist {
-					return nil, errSecretAlreadyExists
-				}
-				return nil, errSecretModified
-			}
-		}
-
-		return nil, errors.Wrap(err, "failed to write to bucket")
+	// Write
+	logger.WithField("metadata", metadata).Debug("writing object to storage")
+	var obj *StorageObject
+	err = c.withRetry(ctx, func(ctx context.Context) error {
+		var err error
+		obj, err = c.secretStorage.WriteObject(ctx, bucket, object, []byte(blob), metadata, conds)
+		return err
+	})
+	if err != nil {
+		return nil, wrapAPIErr(err, "failed to save encrypted ciphertext to storage")
 	}
 
-	return secretFromAttrs(iow.Attrs(), plaintext), nil
+	return secretFromStorageObject(object, obj, plaintext), nil
 }
@@ -0,0 +1,206 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package berglastest provides an in-process test harness for exercising
+// the berglas client library without a real GCP project.
+//
+// The harness starts a fake Cloud Storage backend and wires a real
+// *berglas.Client to it, so downstream projects can write integration
+// tests for their berglas usage (Create, Access, Update, Delete, List,
+// Grant, Revoke, IAMMembers, Append, and the Bundle helpers) without
+// depending on credentials or network access. Secrets are encrypted with a
+// generated local-key:// development key (see the top-level berglas
+// package) instead of Cloud KMS, so no KMS backend - real or fake - is
+// required; Grant and Revoke correspondingly only manage the fake Cloud
+// Storage object's IAM policy, skipping the (nonexistent) KMS key grant.
+//
+// The fake backend only implements the subset of the GCS JSON API that
+// berglas's data plane uses, and only for objects that fit in a single
+// upload chunk (berglas.ChunkSize bytes). It does not retain historical
+// generations: requests for a generation other than the current one are
+// reported as not found. Bootstrap is a bucket-creation operation that
+// talks to Cloud Storage's bucket API directly; the fake backend does not
+// implement it, so tests that exercise it still need a real GCP project.
+package berglastest
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/pkg/errors"
+	"google.golang.org/api/option"
+	"google.golang.org/grpc"
+)
+
+// emulatorEnvMu serializes access to the STORAGE_EMULATOR_HOST environment
+// variable, which cloud.google.com/go/storage reads once at client
+// construction time. Only the brief window around berglas.New needs it set,
+// but since it's process-global, concurrent Harnesses must take turns.
+var emulatorEnvMu sync.Mutex
+
+const storageEmulatorHostEnvVar = "STORAGE_EMULATOR_HOST"
+
+// Harness is a fake berglas backend backed by an in-process fake GCS server.
+// It provides a *berglas.Client that talks to that fake server and a
+// local-key:// Key that the client can use for envelope encryption.
+type Harness struct {
+	// Client is a berglas client configured to talk to the fake backend.
+	Client *berglas.Client
+
+	// Bucket is the name of a bucket that already "exists" on the fake
+	// backend; secrets can be created in it without a prior bootstrap step.
+	Bucket string
+
+	// Key is a local-key:// reference usable as the Key field of
+	// berglas.CreateRequest, berglas.UpdateRequest, and so on.
+	Key string
+
+	server *fakeGCSServer
+}
+
+// New starts a fake GCS backend and returns a Harness wired up to it. The
+// backend and any on-disk local key material are torn down when the
+// returned cleanup function is called; callers should always defer it.
+func New(tb testing.TB) (*Harness, func()) {
+	tb.Helper()
+
+	srv := newFakeGCSServer()
+
+	key, keyCleanup, err := generateTestKey()
+	if err != nil {
+		srv.Close()
+		tb.Fatal(errors.Wrap(err, "failed to generate local test key"))
+	}
+
+	ctx := context.Background()
+	client, err := newFakeClient(ctx, srv)
+	if err != nil {
+		keyCleanup()
+		srv.Close()
+		tb.Fatal(errors.Wrap(err, "failed to create berglas client"))
+	}
+
+	const bucket = "berglastest-bucket"
+	if err := bootstrapBasePath(ctx, client, bucket, key); err != nil {
+		keyCleanup()
+		srv.Close()
+		tb.Fatal(errors.Wrap(err, "failed to bootstrap fake storage client"))
+	}
+
+	h := &Harness{
+		Client: client,
+		Bucket: bucket,
+		Key:    key,
+		server: srv,
+	}
+
+	cleanup := func() {
+		keyCleanup()
+		srv.Close()
+	}
+
+	return h, cleanup
+}
+
+// newFakeClient constructs a *berglas.Client pointed at the fake GCS server.
+// The KMS and storage IAM sub-clients never actually talk to srv - they're
+// only constructed so berglas.New succeeds - so it's enough to make their
+// construction not require real credentials.
+func newFakeClient(ctx context.Context, srv *fakeGCSServer) (*berglas.Client, error) {
+	emulatorEnvMu.Lock()
+	defer emulatorEnvMu.Unlock()
+
+	prev, hadPrev := os.LookupEnv(storageEmulatorHostEnvVar)
+	if err := os.Setenv(storageEmulatorHostEnvVar, srv.Host()); err != nil {
+		return nil, err
+	}
+	defer func() {
+		if hadPrev {
+			os.Setenv(storageEmulatorHostEnvVar, prev)
+		} else {
+			os.Unsetenv(storageEmulatorHostEnvVar)
+		}
+	}()
+
+	return berglas.New(ctx, berglas.WithClientOptions(
+		option.WithoutAuthentication(),
+		option.WithGRPCDialOption(grpc.WithInsecure()),
+		// STORAGE_EMULATOR_HOST alone only redirects cloud.google.com/go/storage,
+		// and only for downloads until the base-path quirk below resolves
+		// itself. storagev1.NewService (used directly for Grant, Revoke, and
+		// IAMMembers) has no emulator-host handling at all, so it needs an
+		// explicit endpoint to reach the fake server.
+		option.WithEndpoint("http://"+srv.Host()+"/storage/v1/"),
+	))
+}
+
+// bootstrapBasePath works around a quirk of cloud.google.com/go/storage: the
+// JSON API base path the client uses for Get/List/Delete isn't pointed at
+// STORAGE_EMULATOR_HOST until after the first write. This issues and
+// immediately removes a throwaway secret so that quirk is already resolved
+// before the Harness is handed back to the caller.
+func bootstrapBasePath(ctx context.Context, client *berglas.Client, bucket, key string) error {
+	const object = ".berglastest-bootstrap"
+
+	if _, err := client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    bucket,
+		Object:    object,
+		Key:       key,
+		Plaintext: []byte("bootstrap"),
+	}); err != nil {
+		return err
+	}
+
+	return client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket: bucket,
+		Object: object,
+	})
+}
+
+// generateTestKey generates a fresh local-key:// development key in a
+// temporary directory and returns it along with a cleanup function that
+// removes that directory.
+func generateTestKey() (string, func(), error) {
+	dir, err := ioutil.TempDir("", "berglastest")
+	if err != nil {
+		return "", nil, errors.Wrap(err, "failed to create temp dir for local key")
+	}
+	cleanup := func() { os.RemoveAll(dir) }
+
+	raw, err := berglas.GenerateLocalKey()
+	if err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	path := filepath.Join(dir, "key")
+	if err := berglas.WriteLocalKeyFile(path, raw); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+
+	return berglas.LocalKeyPrefix + path, cleanup, nil
+}
+
+// URL returns the address of the fake GCS server backing this harness. It is
+// exposed primarily for tests that want to assert against the fake backend
+// directly rather than through the berglas client.
+func (h *Harness) URL() string {
+	return h.server.URL()
+}
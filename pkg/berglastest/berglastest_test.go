@@ -0,0 +1,160 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglastest
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+)
+
+func TestHarness(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	plaintext := []byte("my secret value")
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "my-secret",
+		Key:       h.Key,
+		Plaintext: plaintext,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "my-secret",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(secret, plaintext) {
+		t.Errorf("expected %q to be %q", secret, plaintext)
+	}
+
+	list, err := h.Client.List(ctx, &berglas.ListRequest{Bucket: h.Bucket})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Secrets) != 1 || list.Secrets[0].Name != "my-secret" {
+		t.Errorf("expected exactly one secret named %q, got %+v", "my-secret", list.Secrets)
+	}
+
+	if err := h.Client.Delete(ctx, &berglas.DeleteRequest{
+		Bucket: h.Bucket,
+		Object: "my-secret",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.Access(ctx, &berglas.AccessRequest{
+		Bucket: h.Bucket,
+		Object: "my-secret",
+	}); !berglas.IsSecretDoesNotExistErr(err) {
+		t.Errorf("expected secret to no longer exist, got %v", err)
+	}
+}
+
+func TestHarness_grantRevoke(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "shared-secret",
+		Key:       h.Key,
+		Plaintext: []byte("my secret value"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := h.Client.Grant(ctx, &berglas.GrantRequest{
+		Bucket:  h.Bucket,
+		Object:  "shared-secret",
+		Members: []string{"user:a@example.com", "user:b@example.com"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err := h.Client.IAMMembers(ctx, h.Bucket, "shared-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"user:a@example.com", "user:b@example.com"}; !equalStrings(members, exp) {
+		t.Errorf("expected members %v to be %v", members, exp)
+	}
+
+	if err := h.Client.Revoke(ctx, &berglas.RevokeRequest{
+		Bucket:  h.Bucket,
+		Object:  "shared-secret",
+		Members: []string{"user:a@example.com"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	members, err = h.Client.IAMMembers(ctx, h.Bucket, "shared-secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if exp := []string{"user:b@example.com"}; !equalStrings(members, exp) {
+		t.Errorf("expected members %v to be %v", members, exp)
+	}
+}
+
+func equalStrings(act, exp []string) bool {
+	if len(act) != len(exp) {
+		return false
+	}
+	for i := range act {
+		if act[i] != exp[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestHarness_createAlreadyExists(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	req := &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "dup-secret",
+		Key:       h.Key,
+		Plaintext: []byte("value"),
+	}
+
+	if _, err := h.Client.Create(ctx, req); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := h.Client.Create(ctx, req); !berglas.IsSecretAlreadyExistsErr(err) {
+		t.Errorf("expected secret-already-exists error, got %v", err)
+	}
+}
@@ -0,0 +1,387 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package berglastest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	rawstorage "google.golang.org/api/storage/v1"
+)
+
+// fakeGCSServer is a minimal, in-memory stand-in for the GCS JSON API. It
+// only implements the handful of calls berglas's data plane makes: a
+// multipart object insert, metadata get, plain download, delete, and list.
+// See the package doc comment for what is intentionally left out.
+type fakeGCSServer struct {
+	httpSrv *httptest.Server
+
+	mu             sync.Mutex
+	objects        map[string]map[string]*fakeObject
+	nextGeneration int64
+}
+
+type fakeObject struct {
+	data           []byte
+	contentType    string
+	metadata       map[string]string
+	generation     int64
+	metageneration int64
+	updated        time.Time
+	policy         *rawstorage.Policy
+}
+
+func newFakeGCSServer() *fakeGCSServer {
+	s := &fakeGCSServer{
+		objects: make(map[string]map[string]*fakeObject),
+	}
+	s.httpSrv = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL returns the base URL of the fake server, e.g. "http://127.0.0.1:54321".
+func (s *fakeGCSServer) URL() string {
+	return s.httpSrv.URL
+}
+
+// Host returns the host:port of the fake server, suitable for
+// STORAGE_EMULATOR_HOST.
+func (s *fakeGCSServer) Host() string {
+	return strings.TrimPrefix(s.httpSrv.URL, "http://")
+}
+
+// Close shuts down the fake server.
+func (s *fakeGCSServer) Close() {
+	s.httpSrv.Close()
+}
+
+// handle routes requests the way the real JSON API would distinguish them:
+// by method and path shape. Metadata requests may arrive either as
+// "/b/{bucket}/o/..." or, once the storage client has redirected its base
+// path at the emulator host after a write, as "/storage/v1/b/{bucket}/o/...";
+// both are accepted.
+func (s *fakeGCSServer) handle(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.Path
+
+	switch {
+	case r.Method == http.MethodPost && strings.HasPrefix(path, "/upload/storage/v1/b/"):
+		s.handleInsert(w, r)
+	case r.Method == http.MethodGet && strings.HasSuffix(trimAPIPrefix(path), "/o"):
+		s.handleList(w, r)
+	case (r.Method == http.MethodGet || r.Method == http.MethodPut) && isIAMPath(path):
+		if r.Method == http.MethodGet {
+			s.handleGetIAMPolicy(w, r)
+		} else {
+			s.handleSetIAMPolicy(w, r)
+		}
+	case (r.Method == http.MethodGet || r.Method == http.MethodDelete) && isObjectPath(path):
+		if r.Method == http.MethodGet {
+			s.handleGetMetadata(w, r)
+		} else {
+			s.handleDelete(w, r)
+		}
+	case (r.Method == http.MethodGet || r.Method == http.MethodHead) && !strings.HasPrefix(path, "/storage/v1/") && !strings.HasPrefix(path, "/upload/"):
+		s.handleDownload(w, r)
+	default:
+		writeAPIError(w, http.StatusNotFound, "unsupported fake GCS request: "+r.Method+" "+path)
+	}
+}
+
+// trimAPIPrefix strips the "/storage/v1" prefix the JSON API paths carry
+// once the storage client has corrected its base path; see berglastest.go.
+func trimAPIPrefix(path string) string {
+	return strings.TrimPrefix(path, "/storage/v1")
+}
+
+// isObjectPath reports whether path addresses a single object via
+// ".../b/{bucket}/o/{object}".
+func isObjectPath(path string) bool {
+	p := trimAPIPrefix(path)
+	idx := strings.Index(p, "/b/")
+	if idx < 0 {
+		return false
+	}
+	return strings.Contains(p[idx+len("/b/"):], "/o/")
+}
+
+// splitObjectPath extracts the bucket and object name from a
+// ".../b/{bucket}/o/{object}" path, where {object} may itself contain
+// slashes.
+func splitObjectPath(path string) (bucket, object string) {
+	rest := strings.SplitN(trimAPIPrefix(path), "/b/", 2)[1]
+	parts := strings.SplitN(rest, "/o/", 2)
+	return parts[0], parts[1]
+}
+
+// isIAMPath reports whether path addresses an object's IAM policy via
+// ".../b/{bucket}/o/{object}/iam".
+func isIAMPath(path string) bool {
+	return isObjectPath(path) && strings.HasSuffix(trimAPIPrefix(path), "/iam")
+}
+
+// splitIAMPath is splitObjectPath for an IAM policy path, stripping the
+// trailing "/iam" segment from the object name.
+func splitIAMPath(path string) (bucket, object string) {
+	bucket, object = splitObjectPath(path)
+	return bucket, strings.TrimSuffix(object, "/iam")
+}
+
+func bucketFromPath(path, prefix, suffix string) string {
+	rest := strings.TrimPrefix(trimAPIPrefix(path), prefix)
+	return strings.TrimSuffix(rest, suffix)
+}
+
+func (s *fakeGCSServer) handleInsert(w http.ResponseWriter, r *http.Request) {
+	bucket := bucketFromPath(r.URL.Path, "/upload/storage/v1/b/", "/o")
+
+	mediaType, params, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		writeAPIError(w, http.StatusBadRequest, "expected a multipart upload")
+		return
+	}
+
+	mr := multipart.NewReader(r.Body, params["boundary"])
+
+	metaPart, err := mr.NextPart()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "missing metadata part")
+		return
+	}
+	var meta rawstorage.Object
+	if err := json.NewDecoder(metaPart).Decode(&meta); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid metadata part: "+err.Error())
+		return
+	}
+
+	mediaPart, err := mr.NextPart()
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "missing media part")
+		return
+	}
+	data, err := ioutil.ReadAll(mediaPart)
+	if err != nil {
+		writeAPIError(w, http.StatusBadRequest, "failed to read media part: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing := s.objects[bucket][meta.Name]
+
+	if v := r.URL.Query().Get("ifGenerationMatch"); v != "" {
+		want, _ := strconv.ParseInt(v, 10, 64)
+		var have int64
+		if existing != nil {
+			have = existing.generation
+		}
+		if have != want {
+			writeAPIError(w, http.StatusPreconditionFailed, "generation precondition failed")
+			return
+		}
+	}
+	if v := r.URL.Query().Get("ifMetagenerationMatch"); v != "" && existing != nil {
+		want, _ := strconv.ParseInt(v, 10, 64)
+		if existing.metageneration != want {
+			writeAPIError(w, http.StatusPreconditionFailed, "metageneration precondition failed")
+			return
+		}
+	}
+
+	s.nextGeneration++
+	obj := &fakeObject{
+		data:        data,
+		contentType: meta.ContentType,
+		metadata:    meta.Metadata,
+		generation:  s.nextGeneration,
+		updated:     time.Now().UTC(),
+	}
+	if existing != nil {
+		obj.metageneration = existing.metageneration + 1
+	} else {
+		obj.metageneration = 1
+	}
+
+	if s.objects[bucket] == nil {
+		s.objects[bucket] = make(map[string]*fakeObject)
+	}
+	s.objects[bucket][meta.Name] = obj
+
+	writeJSON(w, http.StatusOK, objectToRaw(bucket, meta.Name, obj))
+}
+
+func (s *fakeGCSServer) handleGetMetadata(w http.ResponseWriter, r *http.Request) {
+	bucket, object := splitObjectPath(r.URL.Path)
+
+	s.mu.Lock()
+	obj := s.objects[bucket][object]
+	s.mu.Unlock()
+
+	if obj == nil {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+	writeJSON(w, http.StatusOK, objectToRaw(bucket, object, obj))
+}
+
+func (s *fakeGCSServer) handleDelete(w http.ResponseWriter, r *http.Request) {
+	bucket, object := splitObjectPath(r.URL.Path)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.objects[bucket] == nil || s.objects[bucket][object] == nil {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+	delete(s.objects[bucket], object)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *fakeGCSServer) handleGetIAMPolicy(w http.ResponseWriter, r *http.Request) {
+	bucket, object := splitIAMPath(r.URL.Path)
+
+	s.mu.Lock()
+	obj := s.objects[bucket][object]
+	s.mu.Unlock()
+
+	if obj == nil {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	policy := obj.policy
+	if policy == nil {
+		policy = &rawstorage.Policy{}
+	}
+	writeJSON(w, http.StatusOK, policy)
+}
+
+func (s *fakeGCSServer) handleSetIAMPolicy(w http.ResponseWriter, r *http.Request) {
+	bucket, object := splitIAMPath(r.URL.Path)
+
+	var policy rawstorage.Policy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		writeAPIError(w, http.StatusBadRequest, "invalid policy body: "+err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	obj := s.objects[bucket][object]
+	if obj == nil {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+	obj.policy = &policy
+
+	writeJSON(w, http.StatusOK, &policy)
+}
+
+func (s *fakeGCSServer) handleList(w http.ResponseWriter, r *http.Request) {
+	bucket := bucketFromPath(r.URL.Path, "/b/", "/o")
+	prefix := r.URL.Query().Get("prefix")
+
+	s.mu.Lock()
+	var names []string
+	for name := range s.objects[bucket] {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	items := make([]*rawstorage.Object, 0, len(names))
+	for _, name := range names {
+		items = append(items, objectToRaw(bucket, name, s.objects[bucket][name]))
+	}
+	s.mu.Unlock()
+
+	writeJSON(w, http.StatusOK, &rawstorage.Objects{Items: items})
+}
+
+func (s *fakeGCSServer) handleDownload(w http.ResponseWriter, r *http.Request) {
+	parts := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	if len(parts) != 2 {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+	bucket, object := parts[0], parts[1]
+
+	s.mu.Lock()
+	obj := s.objects[bucket][object]
+	s.mu.Unlock()
+
+	if obj == nil {
+		writeAPIError(w, http.StatusNotFound, "object not found")
+		return
+	}
+
+	w.Header().Set("X-Goog-Generation", strconv.FormatInt(obj.generation, 10))
+	w.Header().Set("X-Goog-Metageneration", strconv.FormatInt(obj.metageneration, 10))
+	w.Header().Set("Content-Type", obj.contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(obj.data)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(obj.data)
+	}
+}
+
+func objectToRaw(bucket, name string, obj *fakeObject) *rawstorage.Object {
+	return &rawstorage.Object{
+		Bucket:         bucket,
+		Name:           name,
+		ContentType:    obj.contentType,
+		Metadata:       obj.metadata,
+		Generation:     obj.generation,
+		Metageneration: obj.metageneration,
+		Size:           uint64(len(obj.data)),
+		Updated:        obj.updated.Format(time.RFC3339),
+		TimeCreated:    obj.updated.Format(time.RFC3339),
+	}
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// apiErrorBody mirrors the shape of a real GCS JSON API error response, so
+// that google-api-go-client's googleapi.CheckResponse parses out the right
+// status code and message.
+type apiErrorBody struct {
+	Error struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+func writeAPIError(w http.ResponseWriter, code int, msg string) {
+	body := apiErrorBody{}
+	body.Error.Code = code
+	body.Error.Message = msg
+	writeJSON(w, code, body)
+}
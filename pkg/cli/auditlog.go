@@ -0,0 +1,136 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/pubsub"
+)
+
+// auditFileEnv overrides the path of the local access-justification audit
+// file. auditPubsubTopicEnv, if set to a full topic name
+// ("projects/P/topics/T"), also publishes each event there.
+const (
+	auditFileEnv        = "BERGLAS_AUDIT_FILE"
+	auditPubsubTopicEnv = "BERGLAS_AUDIT_PUBSUB_TOPIC"
+)
+
+// accessReasonEvent is a single justified access, edit, or delete, recorded
+// when the operator passes --reason. Unlike telemetryEvent, it deliberately
+// does record the bucket and object: the whole point of an access
+// justification is to tie a reason to the specific secret it was given for.
+type accessReasonEvent struct {
+	Time    time.Time `json:"time"`
+	Command string    `json:"command"`
+	Bucket  string    `json:"bucket"`
+	Object  string    `json:"object"`
+	Reason  string    `json:"reason"`
+}
+
+// auditFilePath returns the path of the local access-justification audit
+// file, honoring BERGLAS_AUDIT_FILE. Returns "" if no home directory can be
+// determined and the override is unset.
+func auditFilePath() string {
+	if f := os.Getenv(auditFileEnv); f != "" {
+		return f
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".berglas", "audit.jsonl")
+}
+
+// parsePubsubTopic splits a "projects/P/topics/T" resource name into its
+// project and topic ID. ok is false if name is not in that format.
+func parsePubsubTopic(name string) (project, topic string, ok bool) {
+	parts := strings.Split(name, "/")
+	if len(parts) != 4 || parts[0] != "projects" || parts[2] != "topics" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// recordAccessReason appends an accessReasonEvent for command against
+// bucket/object to the local audit file and, if BERGLAS_AUDIT_PUBSUB_TOPIC is
+// set, best-effort publishes it there too, so an access-justification policy
+// can alert on (or simply archive) every reason given during an incident.
+// It is a no-op if reason is empty - unlike telemetry, this audit trail is
+// opt-in per invocation via --reason, not a standing setting. Failures are
+// never surfaced to the user or treated as a command failure: a broken audit
+// sink must not block an operator from completing emergency access.
+func (c *CLI) recordAccessReason(ctx context.Context, impersonate, command, bucket, object, reason string) {
+	if reason == "" {
+		return
+	}
+
+	ev := accessReasonEvent{
+		Time:    c.Now(),
+		Command: command,
+		Bucket:  bucket,
+		Object:  object,
+		Reason:  reason,
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	if path := auditFilePath(); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+			if f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+				f.Write(append(data, '\n'))
+				f.Close()
+			}
+		}
+	}
+
+	if topicName := os.Getenv(auditPubsubTopicEnv); topicName != "" {
+		c.publishAccessReason(ctx, impersonate, topicName, data)
+	}
+}
+
+// publishAccessReason best-effort publishes data to topicName, reusing the
+// same credentials (including --impersonate) the rest of the command used.
+func (c *CLI) publishAccessReason(ctx context.Context, impersonate, topicName string, data []byte) {
+	project, topic, ok := parsePubsubTopic(topicName)
+	if !ok {
+		return
+	}
+
+	opts, err := c.clientOptions(ctx, impersonate, "", "", "")
+	if err != nil {
+		return
+	}
+
+	client, err := pubsub.NewClient(ctx, project, opts...)
+	if err != nil {
+		return
+	}
+	defer client.Close()
+
+	result := client.Topic(topic).Publish(ctx, &pubsub.Message{Data: data})
+	if _, err := result.Get(ctx); err != nil {
+		return
+	}
+}
@@ -0,0 +1,1614 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package cli implements the berglas command-line interface as an
+// importable cobra command tree, so other tools can embed berglas
+// subcommands and so the CLI's flag plumbing can be exercised by tests
+// instead of only by the "berglas" binary.
+package cli
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/server"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/api/option"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// APIExitCode is the exit code returned with an upstream API call fails.
+	APIExitCode = 60
+
+	// MisuseExitCode is the exit code returned when the user did something wrong
+	// such as misused a flag.
+	MisuseExitCode = 61
+)
+
+// CLI holds the dependencies of the berglas command tree: where it reads and
+// writes, and how it constructs a berglas client. Tests and embedders
+// construct a CLI with New and Option overrides instead of relying on
+// package-level state, so multiple CLIs (e.g. run concurrently in tests) do
+// not share flag or stdio state.
+type CLI struct {
+	// Stdout and Stderr are where command output and log/error output are
+	// written, respectively. Stdin is where commands that read from standard
+	// input (e.g. "create SECRET -") read from.
+	Stdout io.Writer
+	Stderr io.Writer
+	Stdin  io.Reader
+
+	// NewClient constructs a berglas client for a command to use. Defaults to
+	// berglas.New. Override in tests to inject a fake or pre-configured
+	// client without a live Cloud Storage/KMS dependency.
+	NewClient func(ctx context.Context, opts ...berglas.Option) (*berglas.Client, error)
+
+	// Now returns the current time, used anywhere the CLI needs "now" (for
+	// example, to make time-dependent output deterministic in tests). Defaults
+	// to time.Now.
+	Now func() time.Time
+}
+
+// Option configures a CLI constructed with New.
+type Option func(*CLI)
+
+// WithStdout overrides the writer commands print their output to.
+func WithStdout(w io.Writer) Option {
+	return func(c *CLI) { c.Stdout = w }
+}
+
+// WithStderr overrides the writer commands print logs and errors to.
+func WithStderr(w io.Writer) Option {
+	return func(c *CLI) { c.Stderr = w }
+}
+
+// WithStdin overrides the reader commands read standard input from.
+func WithStdin(r io.Reader) Option {
+	return func(c *CLI) { c.Stdin = r }
+}
+
+// WithClientFactory overrides how commands construct a berglas client.
+func WithClientFactory(fn func(ctx context.Context, opts ...berglas.Option) (*berglas.Client, error)) Option {
+	return func(c *CLI) { c.NewClient = fn }
+}
+
+// WithClock overrides how commands determine the current time.
+func WithClock(fn func() time.Time) Option {
+	return func(c *CLI) { c.Now = fn }
+}
+
+// New constructs a CLI using os.Stdout, os.Stderr, os.Stdin, berglas.New, and
+// time.Now unless overridden by opts.
+func New(opts ...Option) *CLI {
+	c := &CLI{
+		Stdout:    os.Stdout,
+		Stderr:    os.Stderr,
+		Stdin:     os.Stdin,
+		NewClient: berglas.New,
+		Now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Run builds the berglas command tree, executes it against args, and returns
+// the process exit code. Any error is printed to Stderr.
+func (c *CLI) Run(args []string) int {
+	cmd, telemetry := c.newRootCmd()
+	cmd.SetArgs(args)
+	cmd.SetOut(c.Stdout)
+	cmd.SetErr(c.Stderr)
+	cmd.SetIn(c.Stdin)
+
+	executed, err := cmd.ExecuteC()
+
+	commandPath := cmd.Name()
+	if executed != nil {
+		commandPath = executed.CommandPath()
+	}
+	recordTelemetry(telemetry, telemetryEvent{
+		Time:       c.Now(),
+		Command:    commandPath,
+		ErrorClass: telemetryErrorClass(err),
+	})
+
+	if err != nil {
+		if terr, ok := err.(*exitError); ok {
+			if !terr.printed {
+				fmt.Fprintf(c.Stderr, "%s\n", err)
+			}
+			return terr.code
+		}
+		fmt.Fprintf(c.Stderr, "%s\n", err)
+		return 1
+	}
+	return 0
+}
+
+// exitError is a typed error to return.
+type exitError struct {
+	err  error
+	code int
+
+	// printed is true if the error has already been written to Stderr (see
+	// (*CLI).apiErrorJSON), in which case Run must not print it again.
+	printed bool
+}
+
+// Error implements error.
+func (e *exitError) Error() string {
+	if e.err == nil {
+		return "<missing error>"
+	}
+	return e.err.Error()
+}
+
+// exitWithCode prints exits with the specified error and exit code.
+func exitWithCode(code int, err error) *exitError {
+	return &exitError{
+		err:  err,
+		code: code,
+	}
+}
+
+// apiError returns the given error with an API error exit code.
+func apiError(err error) *exitError {
+	return exitWithCode(APIExitCode, err)
+}
+
+// misuseError returns the given error with a userland exit code.
+func misuseError(err error) *exitError {
+	return exitWithCode(MisuseExitCode, err)
+}
+
+// structuredError is the JSON object written to Stderr, instead of a prose
+// message, when a command running with "--output json" fails, so
+// orchestration tooling can branch on Code instead of parsing error text.
+type structuredError struct {
+	Code      string `json:"code"`
+	Message   string `json:"message"`
+	Reference string `json:"reference,omitempty"`
+	ExitCode  int    `json:"exit_code"`
+}
+
+// errorCode classifies err into one of a small, stable set of machine
+// readable codes. Anything that isn't one of Berglas's own sentinel errors
+// is reported as KMS_ERROR, since most other backend failures at this point
+// originate from the underlying Cloud Storage or Cloud KMS client libraries.
+func errorCode(err error) string {
+	switch {
+	case berglas.IsSecretDoesNotExistErr(err):
+		return "NOT_FOUND"
+	case berglas.IsPermissionDeniedErr(err):
+		return "PERMISSION_DENIED"
+	case berglas.IsSecretModifiedErr(err):
+		return "PRECONDITION_FAILED"
+	default:
+		return "KMS_ERROR"
+	}
+}
+
+// apiErrorJSON is like apiError, except it also writes a structuredError
+// describing err to Stderr as JSON in place of the usual prose message.
+// Commands should call this instead of apiError once they know they are
+// running with "--output json". reference is the secret reference the
+// command was operating on (e.g. "my-secrets/api-key"), included so
+// orchestration tooling doesn't have to re-parse it out of the message.
+func (c *CLI) apiErrorJSON(reference string, err error) *exitError {
+	exit := apiError(err)
+
+	data, jerr := json.MarshalIndent(&structuredError{
+		Code:      errorCode(exit.err),
+		Message:   exit.err.Error(),
+		Reference: reference,
+		ExitCode:  exit.code,
+	}, "", "  ")
+	if jerr != nil {
+		return exit
+	}
+
+	fmt.Fprintf(c.Stderr, "%s\n", data)
+	exit.printed = true
+	return exit
+}
+
+// errReadOnly is returned by mutating commands when --read-only is set.
+var errReadOnly = errors.New("refusing to run a mutating command because --read-only is set")
+
+// writeStructuredOutput marshals v as JSON or YAML, according to format
+// ("json" or "yaml"), and writes it to c.Stdout. Callers are expected to
+// have already validated format.
+func (c *CLI) writeStructuredOutput(format string, v interface{}) error {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(v, "", "  ")
+		if err != nil {
+			return apiError(errors.Wrap(err, "failed to marshal result"))
+		}
+		fmt.Fprintf(c.Stdout, "%s\n", data)
+	case "yaml":
+		data, err := yaml.Marshal(v)
+		if err != nil {
+			return apiError(errors.Wrap(err, "failed to marshal result"))
+		}
+		fmt.Fprintf(c.Stdout, "%s", data)
+	default:
+		return misuseError(errors.Errorf("unknown output format %q", format))
+	}
+	return nil
+}
+
+// warnIfLocalKey prints a loud warning to Stderr if key identifies a local
+// development key (file- or keychain-backed) or a passphrase key, since the
+// configured log level may otherwise suppress the package's own warning log
+// line.
+func (c *CLI) warnIfLocalKey(key string) {
+	switch {
+	case berglas.IsLocalKey(key):
+		fmt.Fprintf(c.Stderr, "WARNING: using a local development key; this secret is NOT "+
+			"protected by Cloud KMS and must never be used in production.\n\n")
+	case berglas.IsPassphraseKey(key):
+		fmt.Fprintf(c.Stderr, "WARNING: using a passphrase key; this secret is NOT protected "+
+			"by Cloud KMS and is only as strong as the passphrase.\n\n")
+	}
+}
+
+// logger returns the logger for this cli.
+func (c *CLI) logger(logFormat, logLevel string) (*logrus.Logger, error) {
+	level, err := logrus.ParseLevel(logLevel)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse log level")
+	}
+
+	var formatter logrus.Formatter
+	switch logFormat {
+	case "console", "text":
+		formatter = new(logrus.TextFormatter)
+	case "json":
+		formatter = new(berglas.LogFormatterStackdriver)
+	default:
+		return nil, errors.Errorf("unknown log format %q", logFormat)
+	}
+
+	return &logrus.Logger{
+		Out:       c.Stderr,
+		Formatter: formatter,
+		Hooks:     make(logrus.LevelHooks),
+		Level:     level,
+	}, nil
+}
+
+// clientWithContext returns an instantiated berglas client and context with a
+// closer. extra, if given, is appended to the client options computed from
+// logFormat/logLevel/impersonate - callers use this to attach a per-command
+// option.WithRequestReason for access-justification logging.
+func (c *CLI) clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject string, retries int, retryMaxElapsed, timeout time.Duration, extra ...option.ClientOption) (*berglas.Client, context.Context, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	go func() {
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	logger, err := c.logger(logFormat, logLevel)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to setup logger")
+	}
+
+	opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	opts = append(opts, extra...)
+
+	client, err := c.NewClient(ctx,
+		berglas.WithClientOptions(opts...),
+		berglas.WithLogger(berglas.NewLogrusLogger(logger)),
+		berglas.WithRetryPolicy(berglas.RetryPolicy{
+			MaxRetries:     retries,
+			MaxElapsedTime: retryMaxElapsed,
+		}),
+		berglas.WithTimeout(timeout),
+	)
+	if err != nil {
+		return nil, nil, nil, errors.Wrap(err, "failed to create berglas client")
+	}
+
+	return client, ctx, cancel, nil
+}
+
+// clientWithGracefulContext is like clientWithContext, but the first SIGINT
+// closes stopCh instead of canceling ctx, giving a long-running bulk
+// operation (e.g. "rotate --all") a chance to finish its in-flight item and
+// stop cleanly between items rather than having that item's write aborted
+// mid-flight. A second SIGINT cancels ctx exactly as clientWithContext would,
+// for a caller that does not watch stopCh or wants to force an immediate
+// abort.
+func (c *CLI) clientWithGracefulContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject string, retries int, retryMaxElapsed, timeout time.Duration) (*berglas.Client, context.Context, <-chan struct{}, func(), error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	stopCh := make(chan struct{})
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+
+	go func() {
+		select {
+		case <-sigCh:
+			close(stopCh)
+		case <-ctx.Done():
+			return
+		}
+
+		select {
+		case <-sigCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	logger, err := c.logger(logFormat, logLevel)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "failed to setup logger")
+	}
+
+	opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+	if err != nil {
+		return nil, nil, nil, nil, err
+	}
+
+	client, err := c.NewClient(ctx,
+		berglas.WithClientOptions(opts...),
+		berglas.WithLogger(berglas.NewLogrusLogger(logger)),
+		berglas.WithRetryPolicy(berglas.RetryPolicy{
+			MaxRetries:     retries,
+			MaxElapsedTime: retryMaxElapsed,
+		}),
+		berglas.WithTimeout(timeout),
+	)
+	if err != nil {
+		return nil, nil, nil, nil, errors.Wrap(err, "failed to create berglas client")
+	}
+
+	return client, ctx, stopCh, cancel, nil
+}
+
+// clientOptions builds the option.ClientOptions that should be passed to
+// NewClient, configuring impersonation via --impersonate, Cloud Storage/Cloud
+// KMS emulator endpoints via --storage-endpoint/--kms-endpoint, and a
+// billing/quota project via --billing-project, if they were given.
+func (c *CLI) clientOptions(ctx context.Context, impersonate, storageEndpoint, kmsEndpoint, billingProject string) ([]option.ClientOption, error) {
+	var opts []option.ClientOption
+
+	if impersonate != "" {
+		opt, err := berglas.WithImpersonation(ctx, impersonate)
+		if err != nil {
+			return nil, misuseError(errors.Wrap(err, "failed to configure impersonation"))
+		}
+		opts = append(opts, opt)
+	}
+
+	if storageEndpoint != "" {
+		opts = append(opts, berglas.WithStorageEndpoint(storageEndpoint))
+	}
+
+	if kmsEndpoint != "" {
+		opts = append(opts, berglas.WithKMSEndpoint(kmsEndpoint))
+	}
+
+	if billingProject != "" {
+		opts = append(opts, berglas.WithQuotaProject(billingProject))
+	}
+
+	return opts, nil
+}
+
+// readData reads the given string. If the string starts with an "@", it is
+// assumed to be a filepath. If the string starts with "env:", the rest is an
+// environment variable name to read from, so a value can be handed to
+// berglas without it ever appearing in the process's argv (and therefore
+// "ps" output) - many CI systems already inject secrets this way. If the
+// string starts with "https://", it is fetched over TLS. If the string
+// starts with a "-", data is read from Stdin. If the data starts with a
+// "\", it is assumed to be an escape character only when specified as the
+// first character.
+func (c *CLI) readData(s string) ([]byte, error) {
+	switch {
+	case strings.HasPrefix(s, "@"):
+		return ioutil.ReadFile(s[1:])
+	case strings.HasPrefix(s, "env:"):
+		name := strings.TrimPrefix(s, "env:")
+		v, ok := os.LookupEnv(name)
+		if !ok {
+			return nil, errors.Errorf("environment variable %q is not set", name)
+		}
+		return []byte(v), nil
+	case strings.HasPrefix(s, "https://"):
+		return c.readDataURL(s)
+	case strings.HasPrefix(s, "-"):
+		r := bufio.NewReader(c.Stdin)
+		return r.ReadBytes('\n')
+	case strings.HasPrefix(s, "\\"):
+		return []byte(s[1:]), nil
+	default:
+		return []byte(s), nil
+	}
+}
+
+// readDataURL fetches u over TLS, for readData's "https://" data source.
+func (c *CLI) readDataURL(u string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+
+	resp, err := client.Get(u)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch %s", u)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return nil, errors.Errorf("failed to fetch %s: got status %s", u, resp.Status)
+	}
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read response body from %s", u)
+	}
+	return body, nil
+}
+
+// parseRef parses a secret ref into a bucket, secret path, and any errors.
+func parseRef(s string) (string, string, error) {
+	s = strings.TrimPrefix(s, "gs://")
+	s = strings.TrimPrefix(s, "berglas://")
+
+	ss := strings.SplitN(s, "/", 2)
+	if len(ss) < 2 {
+		return "", "", errors.Errorf("secret does not match format gs://<bucket>/<secret> or the format berglas://<bucket>/<secret>: %s", s)
+	}
+
+	return ss[0], ss[1], nil
+}
+
+// parseBundlePairs parses a list of "KEY=VALUE" arguments into a Bundle.
+func parseBundlePairs(args []string) (berglas.Bundle, error) {
+	b := make(berglas.Bundle, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid key/value pair %q: expected KEY=VALUE", arg)
+		}
+		b[parts[0]] = parts[1]
+	}
+	return b, nil
+}
+
+// parseKVPairs parses "KEY=VALUE" arguments the same way parseBundlePairs
+// does, except each VALUE is passed through c.readData first, so "kv put"
+// accepts a value from a file ("@path") or stdin ("-"), the same
+// conventions "create" already applies to its whole-secret DATA argument -
+// handy for a field like a certificate that doesn't fit comfortably on a
+// command line.
+func (c *CLI) parseKVPairs(args []string) (berglas.Bundle, error) {
+	b := make(berglas.Bundle, len(args))
+	for _, arg := range args {
+		parts := strings.SplitN(arg, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid key/value pair %q: expected KEY=VALUE", arg)
+		}
+
+		data, err := c.readData(parts[1])
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read value for key %q", parts[0])
+		}
+		b[parts[0]] = string(data)
+	}
+	return b, nil
+}
+
+// mustMarshalBundle marshals a bundle, panicking on failure. Bundle only
+// holds strings, so marshaling cannot fail.
+func mustMarshalBundle(b berglas.Bundle) []byte {
+	data, err := b.Marshal()
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// membersNotIn returns the members of candidates that are not present in
+// existing, preserving candidates' order - used by "grant --dry-run" to
+// report which members would actually be newly granted.
+func membersNotIn(existing, candidates []string) []string {
+	have := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		have[m] = true
+	}
+
+	var out []string
+	for _, m := range candidates {
+		if !have[m] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// membersIn returns the members of candidates that are present in existing,
+// preserving candidates' order - used by "revoke --dry-run" to report which
+// members would actually be removed.
+func membersIn(existing, candidates []string) []string {
+	have := make(map[string]bool, len(existing))
+	for _, m := range existing {
+		have[m] = true
+	}
+
+	var out []string
+	for _, m := range candidates {
+		if have[m] {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// formatDrift renders a list of configuration drift descriptions for a
+// single tabwriter column, or "-" if there is none.
+func formatDrift(drift []string) string {
+	if len(drift) == 0 {
+		return "-"
+	}
+	return strings.Join(drift, "; ")
+}
+
+// applyFileChange is one entry in a "berglas apply -f changes.yaml" file.
+type applyFileChange struct {
+	// Secret is a secret ref in bucket/secret format.
+	Secret string `yaml:"secret"`
+
+	// Key is the fully qualified KMS key id. Required if Secret does not
+	// already exist; if it does, and Key is empty, its existing KMS key is
+	// reused.
+	Key string `yaml:"key"`
+
+	// Data is the new plaintext value, in the same "literal / @path / -
+	// (stdin) / \-escaped" format accepted everywhere else in the CLI. See
+	// CLI.readData.
+	Data string `yaml:"data"`
+
+	// Members, if present, is the desired IAM membership on the secret; see
+	// berglas.ChangeSet.Members. Omitting the key entirely leaves IAM
+	// untouched, while an explicit empty list ("members: []") revokes every
+	// current member, so this is a pointer to distinguish the two.
+	Members *[]string `yaml:"members"`
+}
+
+// applyFile is the top-level document read by "berglas apply -f".
+type applyFile struct {
+	Changes []applyFileChange `yaml:"changes"`
+}
+
+// parseApplyFile parses the contents of a "berglas apply -f" file into a
+// list of berglas.ChangeSet, resolving each entry's Data with CLI.readData.
+func (c *CLI) parseApplyFile(data []byte) ([]*berglas.ChangeSet, error) {
+	var f applyFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrap(err, "failed to parse apply file")
+	}
+
+	if len(f.Changes) == 0 {
+		return nil, errors.New("apply file declares no changes")
+	}
+
+	changes := make([]*berglas.ChangeSet, len(f.Changes))
+	for i, ch := range f.Changes {
+		bucket, object, err := parseRef(ch.Secret)
+		if err != nil {
+			return nil, errors.Wrapf(err, "change %d", i+1)
+		}
+
+		plaintext, err := c.readData(ch.Data)
+		if err != nil {
+			return nil, errors.Wrapf(err, "change %d: failed to read data for %s", i+1, ch.Secret)
+		}
+
+		var members []string
+		if ch.Members != nil {
+			members = *ch.Members
+		}
+
+		changes[i] = &berglas.ChangeSet{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       ch.Key,
+			Plaintext: plaintext,
+			Members:   members,
+		}
+	}
+	return changes, nil
+}
+
+// pinsFile is the top-level document read and written by "berglas pin" and
+// consumed by "access --pins" / "exec --pins". It maps each pinned secret's
+// "bucket/object" ref to the generation pinned for it.
+type pinsFile struct {
+	Pins map[string]int64 `yaml:"pins"`
+}
+
+// readPinsFile parses the pins file at path into a "bucket/object" ->
+// generation map (see pinsFile). A missing file is treated as an empty map
+// so "berglas pin" can create one from scratch.
+func (c *CLI) readPinsFile(path string) (map[string]int64, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return map[string]int64{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read pins file %s", path)
+	}
+
+	var f pinsFile
+	if err := yaml.Unmarshal(data, &f); err != nil {
+		return nil, errors.Wrap(err, "failed to parse pins file")
+	}
+	if f.Pins == nil {
+		f.Pins = map[string]int64{}
+	}
+	return f.Pins, nil
+}
+
+// writePinsFile serializes pins (see readPinsFile) to path.
+func (c *CLI) writePinsFile(path string, pins map[string]int64) error {
+	data, err := yaml.Marshal(&pinsFile{Pins: pins})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal pins file")
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return errors.Wrapf(err, "failed to write pins file %s", path)
+	}
+	return nil
+}
+
+// pinnedGeneration looks up the generation pinned for bucket/object in pins,
+// failing loudly if there is none - a reference the pins file doesn't cover
+// should not silently fall through to "latest".
+func pinnedGeneration(pins map[string]int64, bucket, object string) (int64, error) {
+	generation, ok := pins[bucket+"/"+object]
+	if !ok {
+		return 0, errors.Errorf("no pinned generation for %s/%s in pins file", bucket, object)
+	}
+	return generation, nil
+}
+
+// pinRun lists every secret in bucket matching prefix and records its
+// current generation into the pins file at path, merging with (and only
+// overwriting the matched entries of) whatever the file already contains.
+func (c *CLI) pinRun(client *berglas.Client, ctx context.Context, path, bucket, prefix string) error {
+	pins, err := c.readPinsFile(path)
+	if err != nil {
+		return err
+	}
+
+	list, err := client.List(ctx, &berglas.ListRequest{
+		Bucket: bucket,
+		Prefix: prefix,
+	})
+	if err != nil {
+		return apiError(err)
+	}
+	if len(list.Secrets) == 0 {
+		return apiError(errors.Errorf("no secrets in %s match prefix %q", bucket, prefix))
+	}
+
+	for _, s := range list.Secrets {
+		pins[bucket+"/"+s.Name] = s.Generation
+	}
+
+	return c.writePinsFile(path, pins)
+}
+
+// resolveWithPins resolves ref via ec.Resolve, except when pins is non-nil
+// and ref is a "berglas://" reference, in which case the generation pinned
+// for its bucket/object (see pinnedGeneration) overrides whatever
+// generation, if any, the reference itself specifies.
+func (c *CLI) resolveWithPins(ec *berglas.Client, ctx context.Context, pins map[string]int64, ref string) ([]byte, error) {
+	if pins == nil || !strings.HasPrefix(ref, berglas.ReferencePrefix) {
+		return ec.Resolve(ctx, ref)
+	}
+
+	parsed, err := berglas.ParseReference(ref)
+	if err != nil {
+		return ec.Resolve(ctx, ref)
+	}
+
+	generation, err := pinnedGeneration(pins, parsed.Bucket(), parsed.Object())
+	if err != nil {
+		return nil, err
+	}
+
+	return ec.ResolvePinned(ctx, ref, generation)
+}
+
+// parseRetainUntil parses a "--retain-until" flag value as a calendar date
+// in the "2006-01-02" format, returning midnight UTC on that date. An empty
+// string returns the zero time, indicating no retention.
+func parseRetainUntil(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is not a valid date, expected format YYYY-MM-DD", s)
+	}
+	return t, nil
+}
+
+// parseUnusedFor parses a "berglas list --unused-for" flag value, which is
+// either a Go duration ("4320h") or a plain day count with a "d" suffix
+// ("180d") for convenience, since day counts are the natural unit for
+// retirement policies but time.ParseDuration doesn't support them.
+func parseUnusedFor(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, errors.Errorf("%q is not a valid duration, expected a number of days like \"180d\" or a Go duration like \"4320h\"", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Errorf("%q is not a valid duration, expected a number of days like \"180d\" or a Go duration like \"4320h\"", s)
+	}
+	return d, nil
+}
+
+// parseEnvMapFile parses the contents of a "berglas exec --map" file: one
+// "ENV_NAME=VALUE" pair per line, in declaration order, with blank lines and
+// lines starting with "#" ignored. VALUE is not required to be a berglas
+// reference; callers pass non-reference values through unresolved.
+func parseEnvMapFile(data []byte) ([][2]string, error) {
+	var pairs [][2]string
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		parts := strings.SplitN(line, "=", 2)
+		k := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || k == "" {
+			return nil, errors.Errorf("line %d: %q is not in ENV_NAME=VALUE format", i+1, line)
+		}
+
+		pairs = append(pairs, [2]string{k, strings.TrimSpace(parts[1])})
+	}
+	return pairs, nil
+}
+
+// parseSecretFilePair parses a "berglas exec --secret-file" flag value in
+// "PATH=REF" format, where PATH is the file to materialize the resolved
+// secret to and REF is a berglas reference.
+func parseSecretFilePair(s string) (string, string, error) {
+	parts := strings.SplitN(s, "=", 2)
+	path := strings.TrimSpace(parts[0])
+	if len(parts) != 2 || path == "" || strings.TrimSpace(parts[1]) == "" {
+		return "", "", errors.Errorf("invalid --secret-file value %q: expected PATH=REF", s)
+	}
+	return path, strings.TrimSpace(parts[1]), nil
+}
+
+// shredFile overwrites path with zeroes before removing it, so a secret
+// materialized to disk by "berglas exec --secret-file" does not linger in
+// free disk space or a filesystem snapshot after it is "deleted". A missing
+// file is not an error, since the caller may be cleaning up after a child
+// that already removed it.
+func shredFile(path string) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := ioutil.WriteFile(path, make([]byte, info.Size()), info.Mode().Perm()); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// writeFileAtomically writes data to path by writing to a temporary file in
+// the same directory and renaming it over path, so a reader never observes
+// a partially written file and a crash mid-write never corrupts the
+// original - used by "berglas resolve-file" to update a checked-out config
+// file in place.
+func writeFileAtomically(path string, data []byte, perm os.FileMode) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".berglas-*")
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	defer os.Remove(tmp)
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp, perm); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// detectFileFormat guesses "berglas resolve-file"'s document format from
+// path's extension, so --format only needs to be given when the file
+// doesn't end in ".json", ".yaml", or ".yml".
+func detectFileFormat(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json", nil
+	case ".yaml", ".yml":
+		return "yaml", nil
+	default:
+		return "", errors.Errorf("could not detect format of %s; pass --format explicitly", path)
+	}
+}
+
+// embeddedReferencePattern matches a "${scheme://...}" reference embedded in
+// a larger string, e.g. the "berglas://bucket/db-pass" in
+// "postgres://user:${berglas://bucket/db-pass}@host/db".
+var embeddedReferencePattern = regexp.MustCompile(`\$\{([a-zA-Z][a-zA-Z0-9+.-]*://[^}]+)\}`)
+
+// hasEmbeddedReference reports whether s contains one or more
+// "${scheme://...}" references that interpolateReferences would resolve,
+// distinct from s being itself a single whole reference.
+func hasEmbeddedReference(s string) bool {
+	return embeddedReferencePattern.MatchString(s)
+}
+
+// interpolateReferences replaces every "${scheme://...}" reference embedded
+// in s with the plaintext resolve returns for it, so "exec" can build a
+// composite value - a connection string, say - out of multiple secrets
+// without a wrapper script. resolve is called once per embedded reference,
+// in order; the first error it returns aborts interpolation.
+func interpolateReferences(s string, resolve func(ref string) (string, error)) (string, error) {
+	var firstErr error
+	out := embeddedReferencePattern.ReplaceAllStringFunc(s, func(match string) string {
+		if firstErr != nil {
+			return match
+		}
+
+		ref := embeddedReferencePattern.FindStringSubmatch(match)[1]
+		v, err := resolve(ref)
+		if err != nil {
+			firstErr = err
+			return match
+		}
+		return v
+	})
+	if firstErr != nil {
+		return "", firstErr
+	}
+	return out, nil
+}
+
+// parseSignal parses a signal name accepted by "exec --on-change", such as
+// "HUP", "SIGHUP", or "sighup", into an os.Signal. Matching is
+// case-insensitive and tolerates an optional "SIG" prefix, since both forms
+// are common in the wild. The set of recognized names is platform-dependent;
+// see signalsByName.
+func parseSignal(name string) (os.Signal, error) {
+	key := strings.TrimPrefix(strings.ToUpper(name), "SIG")
+	sig, ok := signalsByName[key]
+	if !ok {
+		return nil, errors.Errorf("unknown signal %q", name)
+	}
+	return sig, nil
+}
+
+// stopGracefully asks cmd's process to terminate with sig and, if it is
+// still running after grace, escalates to a hard kill. It's used by
+// "exec --watch" to stop the child before restarting it with
+// freshly-resolved secrets, and by "exec" itself when asked to stop (see
+// "-stop-signal"/"-stop-timeout"). A grace of zero or less skips straight to
+// the hard kill.
+func stopGracefully(cmd *exec.Cmd, sig os.Signal, grace time.Duration) {
+	if cmd.Process == nil {
+		return
+	}
+
+	if err := cmd.Process.Signal(sig); err != nil {
+		return
+	}
+
+	if grace <= 0 {
+		return
+	}
+
+	time.Sleep(grace)
+
+	// The process may have already exited in response to sig; Kill on an
+	// already-finished process just returns a harmless error.
+	_ = cmd.Process.Kill()
+}
+
+// isStopTriggerSignal reports whether s is one of stopTriggerSignals, the
+// signals that ask berglas itself (rather than just the child) to stop -
+// see "exec -stop-signal"/"-stop-timeout".
+func isStopTriggerSignal(s os.Signal) bool {
+	for _, t := range stopTriggerSignals {
+		if s == t {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveUser resolves "exec --user" (a uid or a username) to a uid and, if
+// it could be determined, that user's primary gid - so a caller that didn't
+// also pass "--group" can fall back to it, the same way "su - user" would.
+// hasGid is false when s was given as a bare uid, since there's no
+// passwd/directory-service lookup to find a primary group for a uid that
+// isn't backed by a named user.
+func resolveUser(s string) (uid, gid uint32, hasGid bool, err error) {
+	if n, perr := strconv.ParseUint(s, 10, 32); perr == nil {
+		return uint32(n), 0, false, nil
+	}
+
+	u, err := user.Lookup(s)
+	if err != nil {
+		return 0, 0, false, errors.Wrapf(err, "failed to look up user %q", s)
+	}
+
+	uid64, err := strconv.ParseUint(u.Uid, 10, 32)
+	if err != nil {
+		return 0, 0, false, errors.Wrapf(err, "user %q has non-numeric uid %q", s, u.Uid)
+	}
+
+	gid64, err := strconv.ParseUint(u.Gid, 10, 32)
+	if err != nil {
+		return uint32(uid64), 0, false, nil
+	}
+
+	return uint32(uid64), uint32(gid64), true, nil
+}
+
+// resolveGroup resolves "exec --group" (a gid or a group name) to a gid.
+func resolveGroup(s string) (uint32, error) {
+	if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+		return uint32(n), nil
+	}
+
+	g, err := user.LookupGroup(s)
+	if err != nil {
+		return 0, errors.Wrapf(err, "failed to look up group %q", s)
+	}
+
+	gid64, err := strconv.ParseUint(g.Gid, 10, 32)
+	if err != nil {
+		return 0, errors.Wrapf(err, "group %q has non-numeric gid %q", s, g.Gid)
+	}
+
+	return uint32(gid64), nil
+}
+
+// parseTokenAuthorizer parses a list of "TOKEN=PATH_PREFIX[,PATH_PREFIX...]"
+// pairs into a server.TokenAuthorizer.
+func parseTokenAuthorizer(pairs []string) (server.TokenAuthorizer, error) {
+	authz := make(server.TokenAuthorizer, len(pairs))
+	for _, pair := range pairs {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errors.Errorf("invalid --token value %q: expected TOKEN=PATH_PREFIX", pair)
+		}
+		authz[parts[0]] = strings.Split(parts[1], ",")
+	}
+	return authz, nil
+}
+
+// jsonMergePatch applies an RFC 7386 JSON merge patch to the given document,
+// returning the merged result.
+func jsonMergePatch(doc, patch []byte) ([]byte, error) {
+	var target interface{}
+	if len(doc) > 0 {
+		if err := json.Unmarshal(doc, &target); err != nil {
+			return nil, errors.Wrap(err, "existing secret is not valid JSON")
+		}
+	}
+
+	var patchVal interface{}
+	if err := json.Unmarshal(patch, &patchVal); err != nil {
+		return nil, errors.Wrap(err, "patch is not valid JSON")
+	}
+
+	merged, err := json.Marshal(mergePatch(target, patchVal))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal merged document")
+	}
+	return merged, nil
+}
+
+// mergePatch recursively applies the RFC 7386 merge patch algorithm.
+func mergePatch(target, patch interface{}) interface{} {
+	patchMap, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetMap, ok := target.(map[string]interface{})
+	if !ok {
+		targetMap = make(map[string]interface{})
+	}
+
+	for k, v := range patchMap {
+		if v == nil {
+			delete(targetMap, k)
+			continue
+		}
+		targetMap[k] = mergePatch(targetMap[k], v)
+	}
+	return targetMap
+}
+
+// createFromFilesRun creates one secret per "--from-file name=path" pair in
+// the given bucket and prints a consolidated report of the results.
+func (c *CLI) createFromFilesRun(client *berglas.Client, ctx context.Context, bucket, key, envelopeFormat string, asymmetric bool, additionalKeys []string, retainUntil time.Time, fromFiles []string, dryRun bool) error {
+	type result struct {
+		object     string
+		generation int64
+		err        error
+	}
+
+	results := make([]result, len(fromFiles))
+	for i, pair := range fromFiles {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return misuseError(errors.Errorf("invalid --from-file value %q: expected NAME=PATH", pair))
+		}
+		object, path := parts[0], parts[1]
+
+		plaintext, err := ioutil.ReadFile(path)
+		if err != nil {
+			results[i] = result{object: object, err: errors.Wrapf(err, "failed to read %s", path)}
+			continue
+		}
+
+		if dryRun {
+			results[i] = result{object: object}
+			continue
+		}
+
+		secret, err := client.Create(ctx, &berglas.CreateRequest{
+			Bucket:         bucket,
+			Object:         object,
+			Key:            key,
+			AdditionalKeys: additionalKeys,
+			Plaintext:      plaintext,
+			Format:         berglas.EnvelopeFormat(envelopeFormat),
+			Asymmetric:     asymmetric,
+			RetainUntil:    retainUntil,
+		})
+		if err != nil {
+			results[i] = result{object: object, err: err}
+			continue
+		}
+		results[i] = result{object: object, generation: secret.Generation}
+	}
+
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	if dryRun {
+		fmt.Fprintf(tw, "SECRET\tSTATUS\n")
+	} else {
+		fmt.Fprintf(tw, "SECRET\tGENERATION\tSTATUS\n")
+	}
+
+	var failed bool
+	for _, r := range results {
+		if r.err != nil {
+			failed = true
+			if dryRun {
+				fmt.Fprintf(tw, "%s\t%s\n", r.object, r.err)
+			} else {
+				fmt.Fprintf(tw, "%s\t-\t%s\n", r.object, r.err)
+			}
+			continue
+		}
+		if dryRun {
+			fmt.Fprintf(tw, "%s\twould create\n", r.object)
+		} else {
+			fmt.Fprintf(tw, "%s\t%d\tok\n", r.object, r.generation)
+		}
+	}
+	tw.Flush()
+
+	if failed {
+		return apiError(errors.New("one or more secrets failed to create"))
+	}
+	return nil
+}
+
+// dotenvPair is one KEY=VALUE line from a dotenv file.
+type dotenvPair struct {
+	key   string
+	value string
+}
+
+// parseDotenv parses the KEY=VALUE lines of a dotenv file, skipping blank
+// lines and lines beginning with "#". A line may optionally start with
+// "export " (as shells and some dotenv tooling allow), and a value may be
+// wrapped in matching single or double quotes to preserve leading or
+// trailing whitespace or a literal "#" that would otherwise start a comment.
+func parseDotenv(data []byte) ([]dotenvPair, error) {
+	var pairs []dotenvPair
+	for i, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line = strings.TrimPrefix(line, "export ")
+
+		parts := strings.SplitN(line, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errors.Errorf("invalid dotenv line %d: expected KEY=VALUE: %q", i+1, line)
+		}
+
+		key := strings.TrimSpace(parts[0])
+		value := strings.TrimSpace(parts[1])
+		if len(value) >= 2 {
+			if (value[0] == '"' && value[len(value)-1] == '"') || (value[0] == '\'' && value[len(value)-1] == '\'') {
+				value = value[1 : len(value)-1]
+			}
+		}
+
+		pairs = append(pairs, dotenvPair{key: key, value: value})
+	}
+	return pairs, nil
+}
+
+// writeDotenv writes pairs as KEY=VALUE lines, quoting a value that
+// contains whitespace or a "#" so it round-trips through parseDotenv.
+func writeDotenv(w io.Writer, pairs []dotenvPair) error {
+	for _, p := range pairs {
+		value := p.value
+		if strings.ContainsAny(value, " \t#\n") {
+			value = `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+		}
+		if _, err := fmt.Fprintf(w, "%s=%s\n", p.key, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// dotenvImportRun bulk-creates one secret per KEY=VALUE line read from path,
+// named prefix+KEY, reporting a per-secret summary and continuing past
+// individual failures rather than stopping the batch.
+func (c *CLI) dotenvImportRun(client *berglas.Client, ctx context.Context, path, bucket, prefix, key string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return misuseError(errors.Wrapf(err, "failed to read %s", path))
+	}
+
+	pairs, err := parseDotenv(data)
+	if err != nil {
+		return misuseError(err)
+	}
+
+	type result struct {
+		object     string
+		generation int64
+		err        error
+	}
+
+	results := make([]result, len(pairs))
+	for i, p := range pairs {
+		object := prefix + p.key
+
+		secret, err := client.Create(ctx, &berglas.CreateRequest{
+			Bucket:    bucket,
+			Object:    object,
+			Key:       key,
+			Plaintext: []byte(p.value),
+		})
+		if err != nil {
+			results[i] = result{object: object, err: err}
+			continue
+		}
+		results[i] = result{object: object, generation: secret.Generation}
+	}
+
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "SECRET\tGENERATION\tSTATUS\n")
+
+	var failed bool
+	for _, r := range results {
+		if r.err != nil {
+			failed = true
+			fmt.Fprintf(tw, "%s\t-\t%s\n", r.object, r.err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\tok\n", r.object, r.generation)
+	}
+	tw.Flush()
+
+	if failed {
+		return apiError(errors.New("one or more secrets failed to import"))
+	}
+	return nil
+}
+
+// dotenvExportRun lists every secret in bucket under prefix, decrypts each
+// one, and writes a KEY=VALUE line per secret - with prefix stripped from
+// its name - to c.Stdout.
+func (c *CLI) dotenvExportRun(client *berglas.Client, ctx context.Context, bucket, prefix string) error {
+	resp, err := client.List(ctx, &berglas.ListRequest{Bucket: bucket, Prefix: prefix})
+	if err != nil {
+		return apiError(err)
+	}
+
+	secrets := resp.Secrets
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	pairs := make([]dotenvPair, 0, len(secrets))
+	for _, s := range secrets {
+		plaintext, err := client.Access(ctx, &berglas.AccessRequest{Bucket: bucket, Object: s.Name})
+		if err != nil {
+			return apiError(errors.Wrapf(err, "failed to access %s", s.Name))
+		}
+		pairs = append(pairs, dotenvPair{key: strings.TrimPrefix(s.Name, prefix), value: string(plaintext)})
+	}
+
+	return writeDotenv(c.Stdout, pairs)
+}
+
+// updatePatchRun reads the existing JSON payload of the secret at
+// bucket/object, applies the RFC 7386 JSON merge patch given by patch, and
+// writes the result back under a precondition on the generation that was
+// read, so a concurrent updater is detected rather than silently overwritten.
+func (c *CLI) updatePatchRun(client *berglas.Client, ctx context.Context, bucket, object, patch string) error {
+	existing, err := client.Read(ctx, &berglas.ReadRequest{
+		Bucket: bucket,
+		Object: object,
+	})
+	if err != nil {
+		return apiError(errors.Wrap(err, "failed to read existing secret"))
+	}
+
+	merged, err := jsonMergePatch(existing.Plaintext, []byte(patch))
+	if err != nil {
+		return misuseError(errors.Wrap(err, "failed to apply patch"))
+	}
+
+	secret, err := client.Update(ctx, &berglas.UpdateRequest{
+		Bucket:         bucket,
+		Object:         object,
+		Key:            existing.KMSKey,
+		Plaintext:      merged,
+		Generation:     existing.Generation,
+		Metageneration: existing.Metageneration,
+	})
+	if err != nil {
+		return apiError(err)
+	}
+
+	fmt.Fprintf(c.Stdout, "Successfully updated secret [%s] to generation [%d]\n",
+		object, secret.Generation)
+	return nil
+}
+
+// sidecarCacheEntry is the most recently served plaintext for a secret and
+// the generation it was read at.
+type sidecarCacheEntry struct {
+	generation int64
+	plaintext  []byte
+}
+
+// sidecarCache memoizes decrypted secret values in memory, keyed by
+// "bucket/object", so that "berglas sidecar" can revalidate a secret against
+// its current Cloud Storage generation before serving it instead of always
+// re-downloading and re-decrypting on every request.
+type sidecarCache struct {
+	mu      sync.Mutex
+	entries map[string]*sidecarCacheEntry
+}
+
+// newSidecarCache creates an empty sidecarCache.
+func newSidecarCache() *sidecarCache {
+	return &sidecarCache{entries: make(map[string]*sidecarCacheEntry)}
+}
+
+// get returns the decrypted contents of bucket/object, serving the cached
+// copy without a download or KMS decrypt if the secret's generation has not
+// changed since it was last fetched. On a cache miss it re-reads the secret
+// to learn its new generation, which costs one extra download and decrypt
+// beyond what Access alone would - paid only when the secret actually
+// changes, not on every poll.
+func (s *sidecarCache) get(ctx context.Context, client *berglas.Client, bucket, object string) ([]byte, error) {
+	key := bucket + "/" + object
+
+	s.mu.Lock()
+	entry := s.entries[key]
+	s.mu.Unlock()
+
+	var knownGeneration int64
+	if entry != nil {
+		knownGeneration = entry.generation
+	}
+
+	plaintext, err := client.Access(ctx, &berglas.AccessRequest{
+		Bucket:                bucket,
+		Object:                object,
+		IfNoneMatchGeneration: knownGeneration,
+	})
+	if berglas.IsSecretNotModifiedErr(err) {
+		return entry.plaintext, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := client.Read(ctx, &berglas.ReadRequest{Bucket: bucket, Object: object})
+	if err != nil {
+		// The secret was already decrypted successfully above; serve it even
+		// though the cache could not be refreshed with its new generation.
+		return plaintext, nil
+	}
+
+	s.mu.Lock()
+	s.entries[key] = &sidecarCacheEntry{generation: secret.Generation, plaintext: plaintext}
+	s.mu.Unlock()
+
+	return plaintext, nil
+}
+
+// k8sRewriteRun reads the manifest at path, applies rewrite to it, and
+// writes the result to output - or back to path if output is empty - or to
+// c.Stdout if output is "-".
+func (c *CLI) k8sRewriteRun(path, output string, rewrite func([]byte) ([]byte, error)) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return misuseError(errors.Wrap(err, "failed to read manifest"))
+	}
+
+	rewritten, err := rewrite(data)
+	if err != nil {
+		return misuseError(err)
+	}
+
+	if output == "-" {
+		_, err := c.Stdout.Write(rewritten)
+		return err
+	}
+
+	dest := path
+	if output != "" {
+		dest = output
+	}
+	if err := ioutil.WriteFile(dest, rewritten, 0644); err != nil {
+		return misuseError(errors.Wrap(err, "failed to write manifest"))
+	}
+	return nil
+}
+
+// rotateAllRun rotates every secret in bucket onto key (the latest version of
+// each secret's existing key, if key is empty), reporting a per-secret
+// summary and continuing past individual failures rather than stopping the
+// batch.
+//
+// Secrets are processed in ascending name order so that resumeFrom (the
+// "SECRET" printed in the checkpoint message after a graceful stop) has a
+// stable meaning: every secret with a name less than or equal to resumeFrom
+// is skipped. stopCh, if it fires, stops the batch before starting its next
+// secret - the secret in flight when it fires is still allowed to finish -
+// and the checkpoint is printed so the run can continue later with
+// --resume-from.
+func (c *CLI) rotateAllRun(client *berglas.Client, ctx context.Context, stopCh <-chan struct{}, bucket, key, resumeFrom string) error {
+	resp, err := client.List(ctx, &berglas.ListRequest{Bucket: bucket})
+	if err != nil {
+		return apiError(err)
+	}
+
+	secrets := resp.Secrets
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "SECRET\tGENERATION\tSTATUS\n")
+
+	var failed bool
+	var lastProcessed string
+	var interrupted bool
+
+	for _, s := range secrets {
+		if resumeFrom != "" && s.Name <= resumeFrom {
+			continue
+		}
+
+		select {
+		case <-stopCh:
+			interrupted = true
+		default:
+		}
+		if interrupted {
+			break
+		}
+
+		secret, err := client.Update(ctx, &berglas.UpdateRequest{
+			Bucket: bucket,
+			Object: s.Name,
+			Key:    key,
+		})
+		lastProcessed = s.Name
+		if err != nil {
+			failed = true
+			fmt.Fprintf(tw, "%s\t-\t%s\n", s.Name, err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\tok\n", s.Name, secret.Generation)
+	}
+	tw.Flush()
+
+	if interrupted {
+		fmt.Fprintf(c.Stderr, "berglas: stopped after interrupt; resume with --resume-from %s\n", lastProcessed)
+		return apiError(errors.New("interrupted before rotating every secret"))
+	}
+
+	if failed {
+		return apiError(errors.New("one or more secrets failed to rotate"))
+	}
+	return nil
+}
+
+// reencryptResult is one secret's outcome from reencryptRun.
+type reencryptResult struct {
+	name       string
+	generation int64
+	err        error
+}
+
+// reencryptRun re-encrypts every secret in bucket (optionally limited to
+// those whose name has the given prefix) with its existing KMS key's current
+// primary version, using up to concurrency secrets in flight at once.
+//
+// Unlike rotateAllRun, reencryptRun never changes a secret's KMS key - it
+// exists for the narrower case of catching up every secret in a bucket after
+// a key's primary version changed, and favors throughput (bounded
+// concurrency) over the resumability rotateAllRun offers for --all.
+func (c *CLI) reencryptRun(client *berglas.Client, ctx context.Context, bucket, prefix string, concurrency int) error {
+	resp, err := client.List(ctx, &berglas.ListRequest{Bucket: bucket, Prefix: prefix})
+	if err != nil {
+		return apiError(err)
+	}
+
+	secrets := resp.Secrets
+	sort.Slice(secrets, func(i, j int) bool { return secrets[i].Name < secrets[j].Name })
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]reencryptResult, len(secrets))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				s := secrets[i]
+				secret, err := client.Update(ctx, &berglas.UpdateRequest{
+					Bucket: bucket,
+					Object: s.Name,
+				})
+				if err != nil {
+					results[i] = reencryptResult{name: s.Name, err: err}
+					continue
+				}
+				results[i] = reencryptResult{name: s.Name, generation: secret.Generation}
+			}
+		}()
+	}
+	for i := range secrets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "SECRET\tGENERATION\tSTATUS\n")
+
+	var failed bool
+	for _, r := range results {
+		if r.err != nil {
+			failed = true
+			fmt.Fprintf(tw, "%s\t-\t%s\n", r.name, r.err)
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\tok\n", r.name, r.generation)
+	}
+	tw.Flush()
+
+	if failed {
+		return apiError(errors.New("one or more secrets failed to re-encrypt"))
+	}
+	return nil
+}
+
+// reportMigrateResult prints a per-secret summary of a MigrateToSM or
+// MigrateFromSM result and returns an error if anything needs the
+// operator's attention - a failure or a naming conflict - so the caller can
+// surface it as the command's exit status.
+func (c *CLI) reportMigrateResult(result *berglas.MigrateResult) error {
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "SECRET\tSTATUS\n")
+
+	for _, name := range result.Migrated {
+		fmt.Fprintf(tw, "%s\tmigrated\n", name)
+	}
+	for _, name := range result.Skipped {
+		fmt.Fprintf(tw, "%s\tskipped (already exists)\n", name)
+	}
+	for to, from := range result.Conflicts {
+		fmt.Fprintf(tw, "%s\tconflict: %s all map to this name\n", to, strings.Join(from, ", "))
+	}
+	for name, err := range result.Failed {
+		fmt.Fprintf(tw, "%s\t%s\n", name, err)
+	}
+	tw.Flush()
+
+	if len(result.Conflicts) > 0 {
+		return apiError(errors.New("one or more secrets could not be migrated because of naming conflicts"))
+	}
+	if len(result.Failed) > 0 {
+		return apiError(errors.New("one or more secrets failed to migrate"))
+	}
+	return nil
+}
@@ -0,0 +1,1295 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+)
+
+func Test_readData(t *testing.T) {
+	t.Parallel()
+
+	t.Run("text", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := new(CLI).readData("blob")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := r, []byte("blob"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := ioutil.TempFile("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		if err := ioutil.WriteFile(f.Name(), []byte("fileblob"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := new(CLI).readData("@" + f.Name())
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := r, []byte("fileblob"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("escape", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := new(CLI).readData("\\@file.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := r, []byte("@file.txt"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("env", func(t *testing.T) {
+		os.Setenv("BERGLAS_TEST_READDATA", "envblob")
+		defer os.Unsetenv("BERGLAS_TEST_READDATA")
+
+		r, err := new(CLI).readData("env:BERGLAS_TEST_READDATA")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := r, []byte("envblob"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("env missing", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := new(CLI).readData("env:BERGLAS_TEST_READDATA_MISSING"); err == nil {
+			t.Error("expected error for unset environment variable")
+		}
+	})
+
+	t.Run("url", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "urlblob")
+		}))
+		defer srv.Close()
+
+		// readDataURL only fetches the given URL, regardless of scheme;
+		// readData's "https://" prefix check is what routes real callers
+		// here, but is exercised separately from the network round trip so
+		// the test doesn't need a trusted TLS certificate.
+		r, err := new(CLI).readDataURL(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := r, []byte("urlblob"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("url not found", func(t *testing.T) {
+		t.Parallel()
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer srv.Close()
+
+		if _, err := new(CLI).readDataURL(srv.URL); err == nil {
+			t.Error("expected error for a non-2xx response")
+		}
+	})
+}
+
+func Test_parseKVPairs(t *testing.T) {
+	t.Parallel()
+
+	t.Run("literal", func(t *testing.T) {
+		t.Parallel()
+
+		b, err := new(CLI).parseKVPairs([]string{"user=admin", "pass=\\@literal"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := berglas.Bundle{"user": "admin", "pass": "@literal"}
+		if !reflect.DeepEqual(b, exp) {
+			t.Errorf("expected %#v to be %#v", b, exp)
+		}
+	})
+
+	t.Run("file", func(t *testing.T) {
+		t.Parallel()
+
+		f, err := ioutil.TempFile("", "")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+
+		if err := ioutil.WriteFile(f.Name(), []byte("cert-data"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		b, err := new(CLI).parseKVPairs([]string{"cert=@" + f.Name()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		exp := berglas.Bundle{"cert": "cert-data"}
+		if !reflect.DeepEqual(b, exp) {
+			t.Errorf("expected %#v to be %#v", b, exp)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := new(CLI).parseKVPairs([]string{"noequals"}); err == nil {
+			t.Error("expected error for missing =")
+		}
+	})
+}
+
+func Test_parseRef(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name           string
+		s              string
+		bucket, secret string
+		err            bool
+	}{
+		{
+			"empty",
+			"",
+			"", "",
+			true,
+		},
+		{
+			"space",
+			"    ",
+			"", "",
+			true,
+		},
+		{
+			"no-slash",
+			"foo",
+			"", "",
+			true,
+		},
+		{
+			"slash",
+			"foo/bar",
+			"foo", "bar",
+			false,
+		},
+		{
+			"gs-prefix",
+			"gs://foo/bar",
+			"foo", "bar",
+			false,
+		},
+		{
+			"folder",
+			"gs://foo/bar/baz/bacon",
+			"foo", "bar/baz/bacon",
+			false,
+		},
+		{
+			"berglas-prefix",
+			"berglas://foo/bar",
+			"foo", "bar",
+			false,
+		},
+		{
+			"berglas + folder",
+			"berglas://foo/bar/baz/bacon",
+			"foo", "bar/baz/bacon",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			bucket, secret, err := parseRef(tc.s)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act, exp := bucket, tc.bucket; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+
+			if act, exp := secret, tc.secret; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+}
+
+func Test_jsonMergePatch(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name       string
+		doc, patch string
+		exp        string
+		err        bool
+	}{
+		{
+			"replace field",
+			`{"username":"alice","password":"old"}`,
+			`{"password":"new"}`,
+			`{"password":"new","username":"alice"}`,
+			false,
+		},
+		{
+			"add field",
+			`{"username":"alice"}`,
+			`{"password":"new"}`,
+			`{"password":"new","username":"alice"}`,
+			false,
+		},
+		{
+			"remove field",
+			`{"username":"alice","password":"old"}`,
+			`{"password":null}`,
+			`{"username":"alice"}`,
+			false,
+		},
+		{
+			"nested merge",
+			`{"tls":{"cert":"a","key":"b"}}`,
+			`{"tls":{"key":"c"}}`,
+			`{"tls":{"cert":"a","key":"c"}}`,
+			false,
+		},
+		{
+			"invalid existing document",
+			`not json`,
+			`{"password":"new"}`,
+			``,
+			true,
+		},
+		{
+			"invalid patch",
+			`{}`,
+			`not json`,
+			``,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := jsonMergePatch([]byte(tc.doc), []byte(tc.patch))
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if tc.err {
+				return
+			}
+
+			var actVal, expVal interface{}
+			if err := json.Unmarshal(act, &actVal); err != nil {
+				t.Fatal(err)
+			}
+			if err := json.Unmarshal([]byte(tc.exp), &expVal); err != nil {
+				t.Fatal(err)
+			}
+
+			actJSON, _ := json.Marshal(actVal)
+			expJSON, _ := json.Marshal(expVal)
+			if string(actJSON) != string(expJSON) {
+				t.Errorf("expected %s to be %s", act, tc.exp)
+			}
+		})
+	}
+}
+
+func Test_parseEnvMapFile(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		in   string
+		exp  [][2]string
+		err  bool
+	}{
+		{
+			"empty",
+			"",
+			nil,
+			false,
+		},
+		{
+			"comments and blank lines",
+			"# a comment\n\nFOO=bar\n\n# another\nBAZ=berglas://bucket/object\n",
+			[][2]string{{"FOO", "bar"}, {"BAZ", "berglas://bucket/object"}},
+			false,
+		},
+		{
+			"trims whitespace",
+			"  FOO = bar  \n",
+			[][2]string{{"FOO", "bar"}},
+			false,
+		},
+		{
+			"no equals",
+			"FOO\n",
+			nil,
+			true,
+		},
+		{
+			"empty name",
+			"=bar\n",
+			nil,
+			true,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := parseEnvMapFile([]byte(tc.in))
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if tc.err {
+				return
+			}
+
+			if !reflect.DeepEqual(act, tc.exp) {
+				t.Errorf("expected %#v to be %#v", act, tc.exp)
+			}
+		})
+	}
+}
+
+func Test_parseUnusedFor(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		s    string
+		exp  time.Duration
+		err  bool
+	}{
+		{"days", "180d", 180 * 24 * time.Hour, false},
+		{"go duration", "4320h", 4320 * time.Hour, false},
+		{"invalid days", "nope-d", 0, true},
+		{"invalid duration", "nope", 0, true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := parseUnusedFor(tc.s)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act != tc.exp {
+				t.Errorf("expected %v to be %v", act, tc.exp)
+			}
+		})
+	}
+}
+
+func TestCLI_parseApplyFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`
+changes:
+  - secret: my-secrets/tls-cert
+    key: projects/p/locations/l/keyRings/r/cryptoKeys/k
+    data: cert-data
+  - secret: my-secrets/tls-key
+    data: key-data
+`)
+
+		c := new(CLI)
+		changes, err := c.parseApplyFile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(changes) != 2 {
+			t.Fatalf("expected 2 changes, got %d", len(changes))
+		}
+
+		if act, exp := changes[0].Bucket, "my-secrets"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+		if act, exp := changes[0].Object, "tls-cert"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+		if act, exp := string(changes[0].Plaintext), "cert-data"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+		if act, exp := changes[1].Key, ""; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("no changes", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		if _, err := c.parseApplyFile([]byte(`changes: []`)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("invalid secret ref", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		if _, err := c.parseApplyFile([]byte(`
+changes:
+  - secret: not-a-ref
+    data: value
+`)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		if _, err := c.parseApplyFile([]byte(`not: [valid`)); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("members", func(t *testing.T) {
+		t.Parallel()
+
+		data := []byte(`
+changes:
+  - secret: my-secrets/tls-cert
+    data: cert-data
+    members:
+      - user:a@example.com
+  - secret: my-secrets/tls-key
+    data: key-data
+    members: []
+  - secret: my-secrets/tls-chain
+    data: chain-data
+`)
+
+		c := new(CLI)
+		changes, err := c.parseApplyFile(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if want := []string{"user:a@example.com"}; !reflect.DeepEqual(changes[0].Members, want) {
+			t.Errorf("expected Members %v, got %v", want, changes[0].Members)
+		}
+		if changes[1].Members == nil || len(changes[1].Members) != 0 {
+			t.Errorf("expected an explicit empty Members slice, got %v", changes[1].Members)
+		}
+		if changes[2].Members != nil {
+			t.Errorf("expected a nil Members when omitted, got %v", changes[2].Members)
+		}
+	})
+}
+
+func TestCLI_readWritePinsFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("missing file is empty", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		pins, err := c.readPinsFile(filepath.Join(t.TempDir(), "missing.lock"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(pins) != 0 {
+			t.Errorf("expected no pins, got %#v", pins)
+		}
+	})
+
+	t.Run("round-trips through write and read", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		path := filepath.Join(t.TempDir(), "pins.lock")
+
+		pins := map[string]int64{"my-secrets/api-key": 1563925940580201}
+		if err := c.writePinsFile(path, pins); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := c.readPinsFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := got["my-secrets/api-key"], pins["my-secrets/api-key"]; act != exp {
+			t.Errorf("expected %d to be %d", act, exp)
+		}
+	})
+
+	t.Run("invalid yaml", func(t *testing.T) {
+		t.Parallel()
+
+		c := new(CLI)
+		path := filepath.Join(t.TempDir(), "pins.lock")
+		if err := ioutil.WriteFile(path, []byte(`not: [valid`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := c.readPinsFile(path); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func Test_pinnedGeneration(t *testing.T) {
+	t.Parallel()
+
+	pins := map[string]int64{"my-secrets/api-key": 42}
+
+	generation, err := pinnedGeneration(pins, "my-secrets", "api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := generation, int64(42); act != exp {
+		t.Errorf("expected %d to be %d", act, exp)
+	}
+
+	if _, err := pinnedGeneration(pins, "my-secrets", "missing"); err == nil {
+		t.Error("expected an error for an unpinned secret")
+	}
+}
+
+func Test_parseSecretFilePair(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		s         string
+		path, ref string
+		err       bool
+	}{
+		{
+			"empty",
+			"",
+			"", "",
+			true,
+		},
+		{
+			"no-equals",
+			"/tmp/secret",
+			"", "",
+			true,
+		},
+		{
+			"empty-ref",
+			"/tmp/secret=",
+			"", "",
+			true,
+		},
+		{
+			"valid",
+			"/tmp/secret=berglas://bucket/object",
+			"/tmp/secret", "berglas://bucket/object",
+			false,
+		},
+		{
+			"trims whitespace",
+			" /tmp/secret = berglas://bucket/object ",
+			"/tmp/secret", "berglas://bucket/object",
+			false,
+		},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			path, ref, err := parseSecretFilePair(tc.s)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+
+			if act, exp := path, tc.path; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+
+			if act, exp := ref, tc.ref; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+}
+
+func Test_hasEmbeddedReference(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		s    string
+		exp  bool
+	}{
+		{"plain", "hello", false},
+		{"whole-reference", "berglas://bucket/secret", false},
+		{"embedded", "postgres://user:${berglas://bucket/db-pass}@host/db", true},
+		{"embedded-sm", "${sm://project/secret}", true},
+		{"unterminated", "${berglas://bucket/secret", false},
+		{"empty-braces", "${}", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if act, exp := hasEmbeddedReference(tc.s), tc.exp; act != exp {
+				t.Errorf("expected %v to be %v", act, exp)
+			}
+		})
+	}
+}
+
+func Test_interpolateReferences(t *testing.T) {
+	t.Parallel()
+
+	resolve := func(ref string) (string, error) {
+		if ref == "berglas://bucket/missing" {
+			return "", errors.New("secret does not exist")
+		}
+		return "resolved(" + ref + ")", nil
+	}
+
+	t.Run("single", func(t *testing.T) {
+		t.Parallel()
+
+		act, err := interpolateReferences("postgres://user:${berglas://bucket/db-pass}@host/db", resolve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := "postgres://user:resolved(berglas://bucket/db-pass)@host/db"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		t.Parallel()
+
+		act, err := interpolateReferences("${berglas://bucket/a}:${berglas://bucket/b}", resolve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := "resolved(berglas://bucket/a):resolved(berglas://bucket/b)"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("resolve error", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := interpolateReferences("${berglas://bucket/missing}", resolve); err == nil {
+			t.Error("expected an error")
+		}
+	})
+
+	t.Run("no references", func(t *testing.T) {
+		t.Parallel()
+
+		act, err := interpolateReferences("plain-value", resolve)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exp := "plain-value"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+}
+
+func Test_isStopTriggerSignal(t *testing.T) {
+	t.Parallel()
+
+	for _, sig := range stopTriggerSignals {
+		if !isStopTriggerSignal(sig) {
+			t.Errorf("expected %v to be a stop-trigger signal", sig)
+		}
+	}
+	if isStopTriggerSignal(signalsByName["KILL"]) {
+		t.Error("expected KILL not to be a stop-trigger signal")
+	}
+}
+
+func Test_parseSignal(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		s    string
+		exp  os.Signal
+		err  bool
+	}{
+		{"bare", "INT", signalsByName["INT"], false},
+		{"sig-prefix", "SIGINT", signalsByName["INT"], false},
+		{"lowercase", "sigint", signalsByName["INT"], false},
+		{"unknown", "BOGUS", nil, true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			act, err := parseSignal(tc.s)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if act != tc.exp {
+				t.Errorf("expected %v to be %v", act, tc.exp)
+			}
+		})
+	}
+}
+
+func Test_shredFile(t *testing.T) {
+	t.Parallel()
+
+	t.Run("removes an existing file", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "secret")
+		if err := ioutil.WriteFile(path, []byte("sensitive"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := shredFile(path); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got err=%v", path, err)
+		}
+	})
+
+	t.Run("missing file is not an error", func(t *testing.T) {
+		t.Parallel()
+
+		path := filepath.Join(t.TempDir(), "missing")
+		if err := shredFile(path); err != nil {
+			t.Errorf("expected no error, got %v", err)
+		}
+	})
+}
+
+func Test_writeFileAtomically(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(path, []byte("original"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := writeFileAtomically(path, []byte("updated"), 0640); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "updated"; string(got) != want {
+		t.Errorf("expected %q, got %q", want, string(got))
+	}
+
+	entries, err := ioutil.ReadDir(filepath.Dir(path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 {
+		t.Errorf("expected the temporary file to be cleaned up, found %v", entries)
+	}
+}
+
+func Test_detectFileFormat(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		path string
+		want string
+	}{
+		{"values.yaml", "yaml"},
+		{"values.YML", "yaml"},
+		{"config.json", "json"},
+	}
+	for _, tc := range cases {
+		got, err := detectFileFormat(tc.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("detectFileFormat(%q) = %q, want %q", tc.path, got, tc.want)
+		}
+	}
+
+	if _, err := detectFileFormat("config.txt"); err == nil {
+		t.Error("expected error for an unrecognized extension")
+	}
+}
+
+func Test_telemetryErrorClass(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		exp  string
+	}{
+		{"nil", nil, "ok"},
+		{"misuse", misuseError(bytes.ErrTooLarge), "misuse"},
+		{"api", apiError(bytes.ErrTooLarge), "api"},
+		{"other", bytes.ErrTooLarge, "error"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if act, exp := telemetryErrorClass(tc.err), tc.exp; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+}
+
+func TestCLI_telemetryShowRun(t *testing.T) {
+	t.Run("no file", func(t *testing.T) {
+		t.Setenv("BERGLAS_TELEMETRY_FILE", filepath.Join(t.TempDir(), "missing.jsonl"))
+
+		var stdout bytes.Buffer
+		c := New(WithStdout(&stdout))
+		if err := c.telemetryShowRun(); err != nil {
+			t.Fatal(err)
+		}
+
+		if act, exp := stdout.String(), "No telemetry recorded.\n"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("summarizes recorded events", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "telemetry.jsonl")
+		t.Setenv("BERGLAS_TELEMETRY_FILE", path)
+
+		cfg := &telemetryConfig{Enabled: true}
+		recordTelemetry(cfg, telemetryEvent{Command: "berglas access", ErrorClass: "ok"})
+		recordTelemetry(cfg, telemetryEvent{Command: "berglas access", ErrorClass: "misuse"})
+		recordTelemetry(cfg, telemetryEvent{Command: "berglas create", ErrorClass: "ok"})
+
+		if _, err := os.Stat(path); err != nil {
+			t.Fatal(err)
+		}
+
+		var stdout bytes.Buffer
+		c := New(WithStdout(&stdout))
+		if err := c.telemetryShowRun(); err != nil {
+			t.Fatal(err)
+		}
+
+		out := stdout.String()
+		for _, want := range []string{"berglas access", "berglas create", "misuse"} {
+			if !bytes.Contains([]byte(out), []byte(want)) {
+				t.Errorf("expected output to contain %q, got %q", want, out)
+			}
+		}
+	})
+}
+
+func Test_parsePubsubTopic(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name      string
+		topic     string
+		project   string
+		topicName string
+		exp       bool
+	}{
+		{"valid", "projects/my-project/topics/my-topic", "my-project", "my-topic", true},
+		{"missing parts", "projects/my-project", "", "", false},
+		{"wrong shape", "my-project/topics/my-topic", "", "", false},
+		{"empty", "", "", "", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			project, topic, ok := parsePubsubTopic(tc.topic)
+			if ok != tc.exp {
+				t.Fatalf("parsePubsubTopic(%q) ok = %t, want %t", tc.topic, ok, tc.exp)
+			}
+			if ok && (project != tc.project || topic != tc.topicName) {
+				t.Errorf("parsePubsubTopic(%q) = (%q, %q), want (%q, %q)", tc.topic, project, topic, tc.project, tc.topicName)
+			}
+		})
+	}
+}
+
+func TestCLI_recordAccessReason(t *testing.T) {
+	t.Run("no-op without a reason", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		t.Setenv("BERGLAS_AUDIT_FILE", path)
+
+		c := New()
+		c.recordAccessReason(context.Background(), "", "access", "my-secrets", "api-key", "")
+
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Fatalf("expected no audit file to be written, got err=%v", err)
+		}
+	})
+
+	t.Run("appends a reason to the local audit file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "audit.jsonl")
+		t.Setenv("BERGLAS_AUDIT_FILE", path)
+
+		c := New()
+		c.recordAccessReason(context.Background(), "", "access", "my-secrets", "api-key", "INC-1234")
+
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var ev accessReasonEvent
+		if err := json.Unmarshal(bytes.TrimSpace(data), &ev); err != nil {
+			t.Fatal(err)
+		}
+		if ev.Command != "access" || ev.Bucket != "my-secrets" || ev.Object != "api-key" || ev.Reason != "INC-1234" {
+			t.Errorf("unexpected recorded event: %+v", ev)
+		}
+	})
+}
+
+func TestCLI_Run(t *testing.T) {
+	t.Parallel()
+
+	t.Run("misuse exit code", func(t *testing.T) {
+		t.Parallel()
+
+		var stdout, stderr bytes.Buffer
+		c := New(WithStdout(&stdout), WithStderr(&stderr))
+
+		if act, exp := c.Run([]string{"completion", "nope"}), MisuseExitCode; act != exp {
+			t.Errorf("expected exit code %d to be %d", act, exp)
+		}
+	})
+
+	t.Run("read-only refuses mutating commands", func(t *testing.T) {
+		t.Parallel()
+
+		cases := []struct {
+			name string
+			args []string
+		}{
+			{"create", []string{"create", "my-secrets/foo", "bar", "--key", "k"}},
+			{"update", []string{"update", "my-secrets/foo", "bar"}},
+			{"delete", []string{"delete", "my-secrets/foo"}},
+			{"grant", []string{"grant", "my-secrets/foo"}},
+			{"revoke", []string{"revoke", "my-secrets/foo"}},
+			{"bootstrap", []string{"bootstrap", "--project", "p", "--bucket", "b"}},
+			{"rotate", []string{"rotate", "my-secrets/foo"}},
+			{"reencrypt", []string{"reencrypt", "my-secrets"}},
+			{"alias set", []string{"alias", "set", "prod-db-pass", "my-secrets/password", "--key", "k"}},
+			{"alias delete", []string{"alias", "delete", "my-secrets", "prod-db-pass"}},
+			{"copy", []string{"copy", "my-secrets/foo", "my-secrets/bar"}},
+			{"move", []string{"move", "my-secrets/foo", "my-secrets/bar"}},
+		}
+
+		for _, tc := range cases {
+			tc := tc
+
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+
+				var stdout, stderr bytes.Buffer
+				c := New(WithStdout(&stdout), WithStderr(&stderr))
+
+				args := append([]string{"--read-only"}, tc.args...)
+				if act, exp := c.Run(args), MisuseExitCode; act != exp {
+					t.Errorf("expected exit code %d to be %d, stderr=%q", act, exp, stderr.String())
+				}
+			})
+		}
+	})
+
+	t.Run("independent flag state", func(t *testing.T) {
+		t.Parallel()
+
+		// Two CLIs built from the same call site must not share flag state -
+		// regression test for the package main globals this package replaced.
+		var stdout1, stdout2 bytes.Buffer
+		c1 := New(WithStdout(&stdout1))
+		c2 := New(WithStdout(&stdout2))
+
+		c1.Run([]string{"completion", "nope"})
+		c2.Run([]string{"completion", "bash"})
+
+		if stdout1.Len() != 0 {
+			t.Errorf("expected c1 to have written nothing, got %q", stdout1.String())
+		}
+		if stdout2.Len() == 0 {
+			t.Errorf("expected c2 to have written bash completion output")
+		}
+	})
+}
+
+func Test_errorCode(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name string
+		err  error
+		exp  string
+	}{
+		{"not found", berglas.ErrSecretNotFound, "NOT_FOUND"},
+		{"permission denied", berglas.ErrPermissionDenied, "PERMISSION_DENIED"},
+		{"precondition failed", berglas.ErrPreconditionFailed, "PRECONDITION_FAILED"},
+		{"anything else", errors.New("boom"), "KMS_ERROR"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if act, exp := errorCode(tc.err), tc.exp; act != exp {
+				t.Errorf("errorCode(%v) = %q, want %q", tc.err, act, exp)
+			}
+		})
+	}
+}
+
+func Test_apiErrorJSON(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+	c := New(WithStderr(&stderr))
+
+	exit := c.apiErrorJSON("my-secrets/api-key", berglas.ErrSecretNotFound)
+
+	if !exit.printed {
+		t.Error("expected the error to be marked as already printed")
+	}
+	if exit.code != APIExitCode {
+		t.Errorf("expected exit code %d, got %d", APIExitCode, exit.code)
+	}
+
+	var got structuredError
+	if err := json.Unmarshal(stderr.Bytes(), &got); err != nil {
+		t.Fatalf("expected valid JSON on stderr, got %q: %s", stderr.String(), err)
+	}
+	if got.Code != "NOT_FOUND" || got.Reference != "my-secrets/api-key" || got.ExitCode != APIExitCode {
+		t.Errorf("unexpected structured error: %+v", got)
+	}
+}
+
+func Test_parseDotenv(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`
+# a comment
+API_KEY=abcd1234
+
+export DB_URL=postgres://localhost/app
+QUOTED="hello world"
+SINGLE_QUOTED='with space'
+`)
+
+	pairs, err := parseDotenv(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []dotenvPair{
+		{key: "API_KEY", value: "abcd1234"},
+		{key: "DB_URL", value: "postgres://localhost/app"},
+		{key: "QUOTED", value: "hello world"},
+		{key: "SINGLE_QUOTED", value: "with space"},
+	}
+	if len(pairs) != len(want) {
+		t.Fatalf("expected %+v, got %+v", want, pairs)
+	}
+	for i := range want {
+		if pairs[i] != want[i] {
+			t.Errorf("expected %+v, got %+v", want[i], pairs[i])
+		}
+	}
+}
+
+func Test_parseDotenv_invalid(t *testing.T) {
+	t.Parallel()
+
+	if _, err := parseDotenv([]byte("NOT_A_PAIR\n")); err == nil {
+		t.Error("expected error for line missing '='")
+	}
+}
+
+func Test_writeDotenv(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	pairs := []dotenvPair{
+		{key: "API_KEY", value: "abcd1234"},
+		{key: "MESSAGE", value: "hello world"},
+	}
+	if err := writeDotenv(&buf, pairs); err != nil {
+		t.Fatal(err)
+	}
+
+	if exp := "API_KEY=abcd1234\nMESSAGE=\"hello world\"\n"; buf.String() != exp {
+		t.Errorf("expected %q, got %q", exp, buf.String())
+	}
+}
+
+func Test_membersNotIn(t *testing.T) {
+	t.Parallel()
+
+	existing := []string{"user:a@example.com", "user:b@example.com"}
+	candidates := []string{"user:b@example.com", "user:c@example.com"}
+
+	if want, got := []string{"user:c@example.com"}, membersNotIn(existing, candidates); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func Test_membersIn(t *testing.T) {
+	t.Parallel()
+
+	existing := []string{"user:a@example.com", "user:b@example.com"}
+	candidates := []string{"user:b@example.com", "user:c@example.com"}
+
+	if want, got := []string{"user:b@example.com"}, membersIn(existing, candidates); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func Test_resolveUser(t *testing.T) {
+	t.Parallel()
+
+	t.Run("numeric-uid", func(t *testing.T) {
+		t.Parallel()
+
+		uid, gid, hasGid, err := resolveUser("1000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hasGid {
+			t.Errorf("expected hasGid to be false for a bare uid, got gid %d", gid)
+		}
+		if uid != 1000 {
+			t.Errorf("expected uid to be 1000, got %d", uid)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		if _, _, _, err := resolveUser("berglas-nonexistent-user"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
+
+func Test_resolveGroup(t *testing.T) {
+	t.Parallel()
+
+	t.Run("numeric-gid", func(t *testing.T) {
+		t.Parallel()
+
+		gid, err := resolveGroup("1000")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if gid != 1000 {
+			t.Errorf("expected gid to be 1000, got %d", gid)
+		}
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := resolveGroup("berglas-nonexistent-group"); err == nil {
+			t.Error("expected error")
+		}
+	})
+}
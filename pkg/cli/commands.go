@@ -0,0 +1,5663 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/agent"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/server"
+	"github.com/atotto/clipboard"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"google.golang.org/api/option"
+)
+
+const (
+	// defaultAgentClientID and defaultAgentClientSecret identify the berglas
+	// agent as an OAuth "installed application" for the device authorization
+	// flow used by "berglas agent login". As with other installed-application
+	// clients, this secret is not confidential; it is distributed with the
+	// binary and cannot be used without an accompanying device code.
+	defaultAgentClientID     = "berglas-agent.apps.googleusercontent.com"
+	defaultAgentClientSecret = ""
+)
+
+// newRootCmd builds the "berglas" command tree. Every flag that used to be a
+// package-level global is a local variable here instead, so each call to
+// newRootCmd (and therefore each CLI.Run) gets its own isolated flag state -
+// safe to call concurrently or repeatedly, unlike the original package main
+// globals.
+func (c *CLI) newRootCmd() (*cobra.Command, *telemetryConfig) {
+	telemetry := &telemetryConfig{}
+
+	var (
+		logFormat       string
+		logLevel        string
+		readOnly        bool
+		dryRun          bool
+		retries         int
+		retryMaxElapsed time.Duration
+		timeout         time.Duration
+		storageEndpoint string
+		kmsEndpoint     string
+		billingProject  string
+
+		accessGeneration int64
+		ifNoneMatch      int64
+		expectKey        string
+		accessOffset     int64
+		accessLength     int64
+		accessPins       string
+		accessOutput     string
+		accessReason     string
+		accessJSONPath   string
+		accessOut        string
+		accessMode       string
+		accessClipboard  bool
+		accessClearAfter time.Duration
+
+		editReason string
+
+		deleteReason           string
+		deleteIfGeneration     int64
+		deleteIfMetageneration int64
+
+		listGenerations bool
+		listPrefix      string
+		listProject     string
+		listFormat      string
+		listUnusedFor   string
+
+		versionsFormat string
+
+		keyReportFormat  string
+		keyReportProject string
+
+		hashOutput string
+
+		lintPrefix        string
+		lintDecrypt       bool
+		lintCheckBreached bool
+		lintYes           bool
+		lintFormat        string
+
+		key                  string
+		execLocal            bool
+		fromFiles            []string
+		createOutput         string
+		createPrompt         bool
+		createAsymmetric     bool
+		createAdditionalKeys []string
+		updatePrompt         bool
+
+		applyFilePath string
+		applyDryRun   bool
+
+		execMapFile string
+
+		execSupervise        bool
+		execRestartOnFailure bool
+		execMaxRestarts      int
+		execBackoff          time.Duration
+
+		execSecretFiles []string
+		execKeepFiles   bool
+		execPins        string
+
+		execWatch         bool
+		execWatchInterval time.Duration
+		execOnChange      string
+		execWatchGrace    time.Duration
+
+		execMissing string
+
+		execMetricsAddr string
+
+		execCacheDir string
+		execMaxStale time.Duration
+
+		execResolveConcurrency int
+
+		execUser  string
+		execGroup string
+		execChdir string
+		execUmask string
+
+		execForwardSignals string
+		execStopSignal     string
+		execStopTimeout    time.Duration
+		execTTY            bool
+
+		runConfigPath string
+
+		envLocal              bool
+		envMapFile            string
+		envPins               string
+		envMissing            string
+		envResolveConcurrency int
+		envFormat             string
+
+		renderOutput string
+		renderPins   string
+
+		resolveFileFormat string
+
+		pinFilePath string
+
+		editor                 string
+		editInsecureTempdir    bool
+		createIfMissing        bool
+		updatePatch            string
+		updateIfGeneration     int64
+		updateIfMetageneration int64
+
+		createRetainUntil string
+		updateRetainUntil string
+
+		restoreGeneration int64
+
+		reportTo      string
+		reportProject string
+
+		serverAddr   string
+		serverTokens []string
+
+		leaseAddr  string
+		leaseToken string
+		leaseTTL   time.Duration
+
+		sidecarListen string
+		sidecarAllow  []string
+
+		rotateKey        string
+		rotateAll        bool
+		rotateResumeFrom string
+
+		reencryptPrefix      string
+		reencryptConcurrency int
+
+		k8sOutput string
+
+		copyOverwrite bool
+
+		moveKeepIAM bool
+
+		agentClientID     string
+		agentClientSecret string
+
+		impersonate string
+
+		keyOut     string
+		keyKeyring string
+
+		envelopeFormat string
+
+		members []string
+
+		projectID      string
+		bucket         string
+		bucketLocation string
+		kmsLocation    string
+		kmsKeyRing     string
+		kmsCryptoKey   string
+
+		discoverProject string
+
+		importVaultAddr       string
+		importVaultToken      string
+		importVaultMount      string
+		importVaultPath       string
+		importVaultResumeFrom string
+
+		migrateProject string
+
+		exportDotenvPrefix string
+	)
+
+	rootCmd := &cobra.Command{
+		Use:   "berglas",
+		Short: "Interact with encrypted secrets",
+		Long: strings.Trim(`
+berglas is a CLI tool to reading, writing, and deleting secrets from a Cloud
+Storage bucket encrypted with a Google Cloud KMS key. Secrets are encrypted
+locally using envelope encryption before being uploaded to Cloud Storage.
+
+Secrets are specified in the format:
+
+    <bucket>/<secret>
+
+For example:
+
+    my-gcs-bucket/my-secret
+    my-gcs-bucket/foo/bar/baz
+
+For more information and examples, see the help text for a specific command.
+`, "\n"),
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		Version:       berglas.Version,
+	}
+	rootCmd.SetVersionTemplate(`{{printf "%s\n" .Version}}`)
+
+	rootCmd.PersistentFlags().StringVarP(&logFormat, "log-format", "f", "console",
+		"Format in which to log")
+	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "fatal",
+		"Level at which to log")
+	rootCmd.PersistentFlags().StringVar(&impersonate, "impersonate", "",
+		"Service account to impersonate, or a comma-separated delegate chain "+
+			"ending in the service account to impersonate, in the format "+
+			"sa1,sa2,target")
+	rootCmd.PersistentFlags().StringVar(&impersonate, "impersonate-service-account", "",
+		"Alias for --impersonate")
+	rootCmd.PersistentFlags().StringVar(&storageEndpoint, "storage-endpoint", os.Getenv("STORAGE_EMULATOR_HOST"),
+		"Cloud Storage API endpoint to use instead of the real Cloud Storage "+
+			"service, for running against fake-gcs-server or another emulator "+
+			"in tests and CI. Can also be set with STORAGE_EMULATOR_HOST")
+	rootCmd.PersistentFlags().StringVar(&kmsEndpoint, "kms-endpoint", os.Getenv("KMS_ENDPOINT"),
+		"Cloud KMS API endpoint to use instead of the real Cloud KMS service, "+
+			"for running against a KMS emulator in tests and CI. Can also be "+
+			"set with KMS_ENDPOINT")
+	rootCmd.PersistentFlags().StringVar(&billingProject, "billing-project", os.Getenv("BERGLAS_BILLING_PROJECT"),
+		"Project to bill for Cloud Storage and Cloud KMS API calls, sent as "+
+			"the X-Goog-User-Project header. Required for callers with only "+
+			"user credentials (not a service account) accessing a "+
+			"requester-pays bucket or a project behind VPC Service Controls. "+
+			"Can also be set with BERGLAS_BILLING_PROJECT")
+	rootCmd.PersistentFlags().BoolVar(&telemetry.Enabled, "telemetry", os.Getenv("BERGLAS_TELEMETRY") == "1",
+		"Opt in to recording anonymous command usage counts and error classes "+
+			"locally (see \"berglas telemetry show\") and, if --telemetry-endpoint "+
+			"is set, reporting them there. Never records secret names or values. "+
+			"Can also be enabled with BERGLAS_TELEMETRY=1")
+	rootCmd.PersistentFlags().StringVar(&telemetry.Endpoint, "telemetry-endpoint", os.Getenv(telemetryEndpointEnv),
+		"Endpoint to POST telemetry events to when --telemetry is set. Can also "+
+			"be set with "+telemetryEndpointEnv)
+	rootCmd.PersistentFlags().BoolVar(&readOnly, "read-only", os.Getenv("BERGLAS_READ_ONLY") == "1",
+		"Refuse to run mutating commands (create, update, delete, grant, revoke, "+
+			"bootstrap). Useful for shared troubleshooting sessions and restricted "+
+			"operator shells. Can also be set with BERGLAS_READ_ONLY=1")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", os.Getenv("BERGLAS_DRY_RUN") == "1",
+		"Print what create, update, delete, grant, revoke, and bootstrap would do, "+
+			"including the resulting IAM membership for grant/revoke, without "+
+			"calling the mutating API. Useful for previewing changes in a CI plan "+
+			"step. Can also be set with BERGLAS_DRY_RUN=1")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 0,
+		"Number of times to retry a Cloud Storage or Cloud KMS operation that "+
+			"fails with a transient error (429, 5xx, or KMS RESOURCE_EXHAUSTED), "+
+			"with exponential backoff and jitter between attempts. Zero (the "+
+			"default) disables retries. Useful for batch jobs that would "+
+			"otherwise fail outright on a brief KMS quota spike")
+	rootCmd.PersistentFlags().DurationVar(&retryMaxElapsed, "retry-max-elapsed", 0,
+		"Maximum total time to spend retrying, across all attempts allowed by "+
+			"--retries. Zero (the default) means no limit beyond --retries")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0,
+		"Maximum time to wait for a single operation's Cloud Storage and Cloud "+
+			"KMS calls, including every attempt allowed by --retries, before "+
+			"failing with a deadline exceeded error. Zero (the default) means no "+
+			"timeout. Useful to keep a hung connection from blocking an init "+
+			"container's startup indefinitely")
+
+	accessCmd := &cobra.Command{
+		Use:   "access SECRET",
+		Short: "Access a secret's contents",
+		Long: strings.Trim(`
+Accesses the contents of a secret by reading the encrypted data from Google
+Cloud Storage and decrypting it with Google Cloud KMS.
+
+The result will be the raw value without any additional formatting or newline
+characters.
+
+Run with --if-none-match set to a previously-seen generation to skip the
+download and KMS decrypt entirely when the secret has not changed. When the
+secret is unchanged, this command exits successfully without printing the
+secret value, so watch loops and agents can poll frequently without paying
+for repeated downloads and decrypts.
+
+Run with --expect-key set to a KMS key id to fail the access if the secret
+was not encrypted with that key, protecting pipelines from consuming a
+secret that was re-encrypted with an unauthorized or downgraded key.
+
+Run with --length (and optionally --offset) to print only part of the
+secret, e.g. to sniff a large secret's format or read a fixed-size header.
+The full secret is still downloaded and decrypted - berglas's envelope
+format does not support partial decryption - only the printed output is
+truncated.
+
+Run with --pins set to a lock file written by "berglas pin" to read the
+generation it recorded for this secret, instead of whatever is currently
+live - useful for reproducing exactly what a prior deployment read, even if
+the secret has rotated since. Mutually exclusive with --generation. Fails
+if the pins file has no entry for this secret.
+
+Run with --output json or --output yaml to get the bucket, name, and
+base64-encoded value as structured output instead of the raw bytes, for
+scripts that need to tell a missing secret apart from an empty one or that
+want to embed the result in a larger document. With --output json, a
+failure prints a JSON error object to stderr instead of prose - with a
+stable "code" (NOT_FOUND, PERMISSION_DENIED, PRECONDITION_FAILED, or
+KMS_ERROR), the secret reference, and the exit code - so orchestration
+tooling can branch on the failure category instead of parsing text.
+
+Run with --jsonpath set to a JSONPath expression (e.g. "$.database.password")
+to parse the secret as JSON and print just the selected field, instead of
+piping the plaintext through jq - which on some CI systems leaves the
+secret sitting in shell history or in the process list while jq runs. Only
+a subset of JSONPath is supported: dotted keys and bracketed array indexes,
+no wildcards or filter expressions.
+
+Run with --out (-o) set to a file path to write the secret there atomically
+(via a temp file and rename) with the permission mode given by --mode
+(default 0600), instead of printing it - shell redirection ("> file")
+creates the file with the umask's default mode, which is usually
+world-readable, and mangles binary secrets in some shells. --output does
+not apply when --out is given; the raw bytes are always written.
+
+Run with --clipboard to copy the secret to the system clipboard instead of
+printing it, so it never lands in terminal scrollback where a screen share
+or a shoulder-surfer could read it back later. Pair with --clear-after to
+wipe the clipboard after a timeout (e.g. "30s"); this command blocks until
+then, and only clears the clipboard if it still holds the secret it copied
+there, so it doesn't clobber something the user copied afterward.
+--clipboard requires the "xclip" or "xsel" command on Linux, and is
+mutually exclusive with --out.
+
+Run with --reason set to a ticket or incident ID to justify the access. The
+reason is sent as an X-Goog-Request-Reason header, so it appears on the
+Cloud Audit Log entry for the underlying Cloud Storage and KMS calls, and is
+also appended to the local audit file (BERGLAS_AUDIT_FILE, by default
+~/.berglas/audit.jsonl) and, if BERGLAS_AUDIT_PUBSUB_TOPIC is set, published
+to that Pub/Sub topic - useful for access-justification policies that page
+someone whenever a reason is given outside business hours.
+
+SECRET may instead be an "sm://project/secret" or
+"sm://project/secret#version" reference to read a Google Secret Manager
+secret version directly. In that case --generation, --if-none-match,
+--expect-key, and --pins do not apply, since Secret Manager has its own
+notion of versions and does not use KMS.
+
+SECRET may also be a "file:///path/to/secret" reference to read a secret
+from the local filesystem instead of Cloud Storage, for development
+workflows that run without any GCP credentials. As with Secret Manager
+references, --generation, --if-none-match, --expect-key, and --pins do not
+apply.
+`, "\n"),
+		Example: strings.Trim(`
+  # Read a secret named "api-key" from the bucket "my-secrets"
+  berglas access my-secrets/api-key
+
+  # Read version 3 of a Secret Manager secret
+  berglas access sm://my-project/api-key#3
+
+  # Read a secret from a local file, for development without GCP credentials
+  berglas access file:///etc/secrets/api-key
+
+  # Read it as structured JSON instead of raw bytes
+  berglas access my-secrets/api-key --output json
+
+  # Justify an emergency read during an incident
+  berglas access my-secrets/api-key --reason INC-1234
+
+  # Read generation 1563925940580201 of a secret named "api-key" from the bucket "my-secrets"
+  berglas access my-secrets/api-key --generation 1563925940580201
+
+  # Skip the download if the secret is still at generation 1563925940580201
+  berglas access my-secrets/api-key --if-none-match 1563925940580201
+
+  # Fail if the secret is not encrypted with the expected key
+  berglas access my-secrets/api-key --expect-key projects/my-p/locations/global/keyRings/my-kr/cryptoKeys/my-k
+
+  # Print only the first 16 bytes of a secret
+  berglas access my-secrets/large-blob --length 16
+
+  # Print 16 bytes starting at offset 32
+  berglas access my-secrets/large-blob --offset 32 --length 16
+
+  # Read the generation pinned for this secret in pins.lock
+  berglas access my-secrets/api-key --pins pins.lock
+
+  # Extract a single field from a JSON secret
+  berglas access my-secrets/config --jsonpath '$.database.password'
+
+  # Write the secret to a file readable only by its owner
+  berglas access my-secrets/tls-key --out /run/secrets/tls-key --mode 0400
+
+  # Copy the secret to the clipboard and wipe it after 30 seconds
+  berglas access my-secrets/api-key --clipboard --clear-after 30s
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			var extraOpts []option.ClientOption
+			if accessReason != "" {
+				extraOpts = append(extraOpts, option.WithRequestReason(accessReason))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout, extraOpts...)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			outMode := os.FileMode(0600)
+			if accessMode != "" {
+				m, err := strconv.ParseUint(accessMode, 8, 32)
+				if err != nil {
+					return misuseError(errors.Wrapf(err, "invalid --mode value %q", accessMode))
+				}
+				outMode = os.FileMode(m)
+			}
+
+			// writeOut writes plaintext to --out atomically, if given, and
+			// reports whether it did so; if it did, the caller should skip its
+			// normal --output handling, since --out always writes the raw
+			// bytes regardless of --output.
+			writeOut := func(plaintext []byte) (bool, error) {
+				if accessOut == "" {
+					return false, nil
+				}
+				if err := writeFileAtomically(accessOut, plaintext, outMode); err != nil {
+					return true, apiError(errors.Wrapf(err, "failed to write secret to %s", accessOut))
+				}
+				fmt.Fprintf(c.Stderr, "Wrote secret to %s\n", accessOut)
+				return true, nil
+			}
+
+			if accessClipboard && accessOut != "" {
+				return misuseError(errors.New("--clipboard and --out are mutually exclusive"))
+			}
+			if accessClearAfter > 0 && !accessClipboard {
+				return misuseError(errors.New("--clear-after requires --clipboard"))
+			}
+
+			// writeClipboard copies plaintext to the system clipboard, if
+			// --clipboard was given, and reports whether it did so; if it
+			// did, the caller should skip its normal --output handling,
+			// since --clipboard never writes the secret to stdout. If
+			// --clear-after is set, this blocks until the timeout elapses
+			// and then clears the clipboard, but only if it still holds the
+			// secret this command copied there.
+			writeClipboard := func(plaintext []byte) (bool, error) {
+				if !accessClipboard {
+					return false, nil
+				}
+				if err := clipboard.WriteAll(string(plaintext)); err != nil {
+					return true, apiError(errors.Wrap(err, "failed to copy secret to clipboard"))
+				}
+				fmt.Fprintln(c.Stderr, "Copied secret to clipboard")
+
+				if accessClearAfter > 0 {
+					fmt.Fprintf(c.Stderr, "Clipboard will be cleared in %s\n", accessClearAfter)
+					time.Sleep(accessClearAfter)
+
+					current, err := clipboard.ReadAll()
+					if err == nil && current == string(plaintext) {
+						clipboard.WriteAll("")
+						fmt.Fprintln(c.Stderr, "Cleared clipboard")
+					}
+				}
+				return true, nil
+			}
+
+			if berglas.IsSMReference(args[0]) {
+				smRef, err := berglas.ParseSMReference(args[0])
+				if err != nil {
+					return misuseError(err)
+				}
+
+				plaintext, err := client.SMAccess(ctx, &berglas.SMAccessRequest{
+					Project: smRef.Project(),
+					Secret:  smRef.Secret(),
+					Version: smRef.Version(),
+				})
+				if err != nil {
+					if accessOutput == "json" {
+						return c.apiErrorJSON(args[0], err)
+					}
+					return apiError(err)
+				}
+
+				if accessJSONPath != "" {
+					plaintext, err = extractJSONPath(plaintext, accessJSONPath)
+					if err != nil {
+						return misuseError(err)
+					}
+				}
+
+				if wrote, err := writeOut(plaintext); wrote {
+					return err
+				}
+
+				if wrote, err := writeClipboard(plaintext); wrote {
+					return err
+				}
+
+				switch accessOutput {
+				case "", "text":
+					fmt.Fprintf(c.Stdout, "%s", plaintext)
+				case "json", "yaml":
+					return c.writeStructuredOutput(accessOutput, struct {
+						Project string `json:"project" yaml:"project"`
+						Name    string `json:"name" yaml:"name"`
+						Value   string `json:"value" yaml:"value"`
+					}{
+						Project: smRef.Project(),
+						Name:    smRef.Secret(),
+						Value:   base64.StdEncoding.EncodeToString(plaintext),
+					})
+				default:
+					return misuseError(errors.Errorf("unknown output format %q", accessOutput))
+				}
+				return nil
+			}
+
+			if berglas.IsFileReference(args[0]) {
+				fileRef, err := berglas.ParseFileReference(args[0])
+				if err != nil {
+					return misuseError(err)
+				}
+
+				plaintext, err := client.FileAccess(ctx, &berglas.FileAccessRequest{
+					Path: fileRef.Path(),
+				})
+				if err != nil {
+					if accessOutput == "json" {
+						return c.apiErrorJSON(args[0], err)
+					}
+					return apiError(err)
+				}
+
+				if accessJSONPath != "" {
+					plaintext, err = extractJSONPath(plaintext, accessJSONPath)
+					if err != nil {
+						return misuseError(err)
+					}
+				}
+
+				if wrote, err := writeOut(plaintext); wrote {
+					return err
+				}
+
+				if wrote, err := writeClipboard(plaintext); wrote {
+					return err
+				}
+
+				switch accessOutput {
+				case "", "text":
+					fmt.Fprintf(c.Stdout, "%s", plaintext)
+				case "json", "yaml":
+					return c.writeStructuredOutput(accessOutput, struct {
+						Name  string `json:"name" yaml:"name"`
+						Value string `json:"value" yaml:"value"`
+					}{
+						Name:  fileRef.Path(),
+						Value: base64.StdEncoding.EncodeToString(plaintext),
+					})
+				default:
+					return misuseError(errors.Errorf("unknown output format %q", accessOutput))
+				}
+				return nil
+			}
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			c.recordAccessReason(ctx, impersonate, "access", bucket, object, accessReason)
+
+			if accessPins != "" {
+				if accessGeneration != 0 {
+					return misuseError(errors.New("--pins and --generation are mutually exclusive"))
+				}
+
+				pins, err := c.readPinsFile(accessPins)
+				if err != nil {
+					return misuseError(err)
+				}
+
+				generation, err := pinnedGeneration(pins, bucket, object)
+				if err != nil {
+					return misuseError(err)
+				}
+				accessGeneration = generation
+			}
+
+			plaintext, err := client.Access(ctx, &berglas.AccessRequest{
+				Bucket:                bucket,
+				Object:                object,
+				Generation:            accessGeneration,
+				IfNoneMatchGeneration: ifNoneMatch,
+				ExpectKey:             expectKey,
+				Offset:                accessOffset,
+				Length:                accessLength,
+			})
+			if err != nil {
+				if berglas.IsSecretNotModifiedErr(err) {
+					fmt.Fprintf(c.Stderr, "secret [%s] is unchanged\n", object)
+					return nil
+				}
+				if accessOutput == "json" {
+					return c.apiErrorJSON(bucket+"/"+object, err)
+				}
+				return apiError(err)
+			}
+
+			if accessJSONPath != "" {
+				plaintext, err = extractJSONPath(plaintext, accessJSONPath)
+				if err != nil {
+					return misuseError(err)
+				}
+			}
+
+			if wrote, err := writeOut(plaintext); wrote {
+				return err
+			}
+
+			if wrote, err := writeClipboard(plaintext); wrote {
+				return err
+			}
+
+			switch accessOutput {
+			case "", "text":
+				fmt.Fprintf(c.Stdout, "%s", plaintext)
+			case "json", "yaml":
+				return c.writeStructuredOutput(accessOutput, struct {
+					Bucket string `json:"bucket" yaml:"bucket"`
+					Name   string `json:"name" yaml:"name"`
+					Value  string `json:"value" yaml:"value"`
+				}{
+					Bucket: bucket,
+					Name:   object,
+					Value:  base64.StdEncoding.EncodeToString(plaintext),
+				})
+			default:
+				return misuseError(errors.Errorf("unknown output format %q", accessOutput))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(accessCmd)
+	accessCmd.Flags().Int64Var(&accessGeneration, "generation", 0,
+		"Get a specific generation")
+	accessCmd.Flags().Int64Var(&ifNoneMatch, "if-none-match", 0,
+		"Skip the download and decrypt if the secret's generation still matches "+
+			"this value")
+	accessCmd.Flags().StringVar(&expectKey, "expect-key", "",
+		"Fail if the secret is not encrypted with this KMS key")
+	accessCmd.Flags().Int64Var(&accessOffset, "offset", 0,
+		"Byte offset into the secret to start printing from; only honored with --length")
+	accessCmd.Flags().Int64Var(&accessLength, "length", 0,
+		"Print at most this many bytes of the secret, starting at --offset, "+
+			"instead of the entire secret")
+	accessCmd.Flags().StringVar(&accessPins, "pins", "",
+		"Path to a lock file written by \"berglas pin\"; read the generation it pinned "+
+			"for this secret instead of the latest (mutually exclusive with --generation)")
+	accessCmd.Flags().StringVar(&accessOutput, "output", "text",
+		"Output format: \"text\" (raw secret bytes), \"json\", or \"yaml\" (both base64-encode "+
+			"the value, since it may not be valid text)")
+	accessCmd.Flags().StringVar(&accessReason, "reason", "",
+		"Justification (e.g. a ticket ID) for this access, recorded in Cloud Audit Logs and the "+
+			"local access-justification audit log")
+	accessCmd.Flags().StringVar(&accessJSONPath, "jsonpath", "",
+		"Parse the secret as JSON and print only the field selected by this JSONPath expression "+
+			"(e.g. \"$.database.password\"), instead of the whole secret")
+	accessCmd.Flags().StringVarP(&accessOut, "out", "o", "",
+		"Write the secret to this file atomically instead of printing it, ignoring --output")
+	accessCmd.Flags().StringVar(&accessMode, "mode", "0600",
+		"Octal permission mode for the file written by --out")
+	accessCmd.Flags().BoolVar(&accessClipboard, "clipboard", false,
+		"Copy the secret to the system clipboard instead of printing it")
+	accessCmd.Flags().DurationVar(&accessClearAfter, "clear-after", 0,
+		"Clear the clipboard after this duration (e.g. \"30s\"); requires --clipboard")
+
+	applyCmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Apply a set of related secret changes together",
+		Long: strings.Trim(`
+Stages updates to several secrets declared in a YAML file and commits them
+together, so a coordinated rotation (a cert, its key, and its chain) never
+leaves the secrets in a mismatched state.
+
+Cloud Storage has no multi-object transaction primitive, so this is not a
+true atomic commit: each individual secret is still protected by its own
+generation precondition, but if a change fails partway through, berglas
+rolls back every change already committed in this run - restoring each
+secret's previous value, or deleting it if this run created it - before
+returning the error. A failure during rollback itself is logged rather than
+silently swallowed, so it can be reconciled by hand.
+
+The file declares a list of changes, each naming a secret in bucket/secret
+format, the new plaintext value, an optional KMS key (required only for
+secrets that do not already exist), and optionally the secret's desired IAM
+members. The value accepts the same "literal / @path / env:VARNAME /
+https://url / - (stdin) / \-escaped" forms as every other command that
+reads secret data. A change
+whose plaintext and key already match the secret is left untouched, and IAM
+membership is reconciled to match "members" exactly - granting anyone
+missing and revoking anyone not listed - without being covered by the
+rollback described above; omitting "members" entirely leaves a secret's IAM
+bindings alone.
+
+Pass --dry-run to see the plan - what would be created, updated, left
+unchanged, granted, and revoked - without changing anything.
+`, "\n"),
+		Example: strings.Trim(`
+  # changes.yaml:
+  #   changes:
+  #     - secret: my-secrets/tls-cert
+  #       data: "@./tls.crt"
+  #       members:
+  #         - serviceAccount:my-app@my-project.iam.gserviceaccount.com
+  #     - secret: my-secrets/tls-key
+  #       data: "@./tls.key"
+
+  berglas apply --file changes.yaml
+  berglas apply --file changes.yaml --dry-run
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if readOnly && !applyDryRun {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			data, err := ioutil.ReadFile(applyFilePath)
+			if err != nil {
+				return misuseError(errors.Wrapf(err, "failed to read %s", applyFilePath))
+			}
+
+			changes, err := c.parseApplyFile(data)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			resp, err := client.Apply(ctx, &berglas.ApplyRequest{Changes: changes, DryRun: applyDryRun})
+			if err != nil {
+				return apiError(err)
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "SECRET\tACTION\tGRANTED\tREVOKED\n")
+			for _, a := range resp.Actions {
+				fmt.Fprintf(tw, "%s/%s\t%s\t%s\t%s\n",
+					a.Bucket, a.Object, a.SecretAction, formatDrift(a.Granted), formatDrift(a.Revoked))
+			}
+			tw.Flush()
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(applyCmd)
+	applyCmd.Flags().StringVar(&applyFilePath, "file", "",
+		"Path to a YAML file declaring the changes to apply")
+	applyCmd.Flags().BoolVar(&applyDryRun, "dry-run", false,
+		"Print the plan without creating, updating, granting, or revoking anything")
+	if err := applyCmd.MarkFlagRequired("file"); err != nil {
+		panic(err)
+	}
+
+	agentCmd := &cobra.Command{
+		Use:   "agent",
+		Short: "Manage credentials for long-running berglas agents",
+	}
+	rootCmd.AddCommand(agentCmd)
+
+	agentLoginCmd := &cobra.Command{
+		Use:   "login",
+		Short: "Authenticate the berglas agent via an OAuth device flow",
+		Long: strings.Trim(`
+Authenticates as a user via the OAuth 2.0 device authorization flow and
+caches the resulting refresh token in the OS keychain (Keychain on macOS,
+Credential Manager on Windows, Secret Service on Linux).
+
+Once logged in, "berglas server" and other long-running agents pick up the
+cached credentials automatically, without gcloud being installed or an
+Application Default Credentials file on disk. The cached token survives
+reboots and is refreshed automatically as it expires.
+`, "\n"),
+		Example: strings.Trim(`
+  # Authenticate the agent
+  berglas agent login
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+
+			tok, err := agent.Login(ctx, agentClientID, agentClientSecret, nil, c.Stdout)
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to log in"))
+			}
+
+			if err := agent.SaveToken(tok); err != nil {
+				return apiError(errors.Wrap(err, "failed to save credentials"))
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully logged in. Credentials cached in the OS keychain.\n")
+			return nil
+		},
+	}
+	agentCmd.AddCommand(agentLoginCmd)
+	agentLoginCmd.Flags().StringVar(&agentClientID, "client-id", defaultAgentClientID,
+		"OAuth client ID to use for the device authorization flow")
+	agentLoginCmd.Flags().StringVar(&agentClientSecret, "client-secret", defaultAgentClientSecret,
+		"OAuth client secret to use for the device authorization flow")
+
+	appendCmd := &cobra.Command{
+		Use:   "append SECRET DATA",
+		Short: "Append data to an existing secret",
+		Long: strings.Trim(`
+Atomically appends data to the end of an existing secret's plaintext value.
+The secret is read and written in a loop that retries on a generation
+precondition conflict, so concurrent appenders do not clobber each other's
+data the way a separate read-modify-write would.
+`, "\n"),
+		Example: strings.Trim(`
+  # Append a new authorized key to a secret named "authorized-keys"
+  berglas append my-secrets/authorized-keys "$(cat new-key.pub)"
+`, "\n"),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			data, err := c.readData(strings.TrimSpace(args[1]))
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Append(ctx, &berglas.AppendRequest{
+				Bucket: bucket,
+				Object: object,
+				Data:   data,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully appended to secret [%s] with generation [%d]\n",
+				object, secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(appendCmd)
+
+	bootstrapCmd := &cobra.Command{
+		Use:   "bootstrap",
+		Short: "Bootstrap a berglas environment",
+		Long: strings.Trim(`
+Bootstrap a Berglas environment by creating a Cloud Storage bucket and a Cloud
+KMS key with properly scoped permissions to the caller.
+
+This command will create a new Cloud Storage bucket with "private" ACLs and
+grant permission only to the caller in the specified project. It will enable
+versioning on the bucket, configured to retain the last 10 verions. If the
+bucket already exists, an error is returned.
+
+This command will also create a Cloud KMS key ring and crypto key in the
+specified project. If the key ring or crypto key already exist, no errors are
+returned.
+`, "\n"),
+		Example: strings.Trim(`
+  # Bootstrap a berglas environment
+  berglas bootstrap --project my-project --bucket my-bucket
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			kmsKeyID := fmt.Sprintf("projects/%s/locations/%s/keyRings/%s/cryptoKeys/%s",
+				projectID, kmsLocation, kmsKeyRing, kmsCryptoKey)
+
+			if dryRun {
+				fmt.Fprintf(c.Stdout, "Would create bucket [%s] in project [%s]\n", bucket, projectID)
+				fmt.Fprintf(c.Stdout, "Would create KMS key [%s]\n", kmsKeyID)
+				return nil
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			if err := client.Bootstrap(ctx, &berglas.BootstrapRequest{
+				ProjectID:      projectID,
+				Bucket:         bucket,
+				BucketLocation: bucketLocation,
+				KMSLocation:    kmsLocation,
+				KMSKeyRing:     kmsKeyRing,
+				KMSCryptoKey:   kmsCryptoKey,
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully created berglas environment:\n")
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "  Bucket: %s\n", bucket)
+			fmt.Fprintf(c.Stdout, "  KMS key: %s\n", kmsKeyID)
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "To create a secret:\n")
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "  berglas create %s/my-secret abcd1234 \\\n", bucket)
+			fmt.Fprintf(c.Stdout, "    --key %s\n", kmsKeyID)
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "To grant access to that secret:\n")
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "  berglas grant %s/my-secret \\\n", bucket)
+			fmt.Fprintf(c.Stdout, "    --member user:jane.doe@mycompany.com\n")
+			fmt.Fprintf(c.Stdout, "\n")
+			fmt.Fprintf(c.Stdout, "For more help and examples, please run \"berglas -h\".\n")
+			return nil
+		},
+	}
+	rootCmd.AddCommand(bootstrapCmd)
+	bootstrapCmd.Flags().StringVar(&projectID, "project", "",
+		"Google Cloud Project ID")
+	if err := bootstrapCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	bootstrapCmd.Flags().StringVar(&bucket, "bucket", "",
+		"Name of the Cloud Storage bucket to create")
+	if err := bootstrapCmd.MarkFlagRequired("bucket"); err != nil {
+		panic(err)
+	}
+	bootstrapCmd.Flags().StringVar(&bucketLocation, "bucket-location", "US",
+		"Location in which to create Cloud Storage bucket")
+	bootstrapCmd.Flags().StringVar(&kmsLocation, "kms-location", "global",
+		"Location in which to create the Cloud KMS key ring")
+	bootstrapCmd.Flags().StringVar(&kmsKeyRing, "kms-keyring", "berglas",
+		"Name of the KMS key ring to create")
+	bootstrapCmd.Flags().StringVar(&kmsCryptoKey, "kms-key", "berglas-key",
+		"Name of the KMS key to create")
+
+	discoverCmd := &cobra.Command{
+		Use:   "discover",
+		Short: "Discover berglas environments in a project",
+		Long: strings.Trim(`
+Finds the buckets and Cloud KMS keys that "berglas bootstrap" created or
+labeled across a project, and reports how their configuration has drifted
+from Bootstrap's defaults (versioning, lifecycle rules, key rotation period,
+protection level).
+
+This is intended for platform teams doing org-wide inventory of berglas
+environments that other teams may have bootstrapped without a central
+record - so-called "shadow" environments.
+`, "\n"),
+		Example: strings.Trim(`
+  # Discover all berglas environments in a project
+  berglas discover --project my-project
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			result, err := client.Discover(ctx, &berglas.DiscoverRequest{
+				Project: discoverProject,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			if len(result.Buckets) == 0 {
+				fmt.Fprintf(c.Stdout, "No berglas-managed buckets found in project %s.\n", discoverProject)
+				return nil
+			}
+
+			fmt.Fprintf(c.Stdout, "Buckets:\n\n")
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "NAME\tLOCATION\tVERSIONING\tDRIFT\n")
+			for _, b := range result.Buckets {
+				fmt.Fprintf(tw, "%s\t%s\t%t\t%s\n", b.Name, b.Location, b.VersioningEnabled, formatDrift(b.Drift))
+			}
+			tw.Flush()
+
+			if len(result.KMSKeys) > 0 {
+				fmt.Fprintf(c.Stdout, "\nKMS keys:\n\n")
+				tw = new(tabwriter.Writer)
+				tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+				fmt.Fprintf(tw, "NAME\tROTATION\tPROTECTION LEVEL\tDRIFT\n")
+				for _, k := range result.KMSKeys {
+					fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", k.Name, k.RotationPeriod, k.ProtectionLevel, formatDrift(k.Drift))
+				}
+				tw.Flush()
+			}
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(discoverCmd)
+	discoverCmd.Flags().StringVar(&discoverProject, "project", "",
+		"Google Cloud Project ID to search")
+	if err := discoverCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+
+	bundleCmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage multiple key/value pairs stored in a single secret",
+	}
+	rootCmd.AddCommand(bundleCmd)
+
+	bundleCreateCmd := &cobra.Command{
+		Use:   "create BUNDLE KEY=VALUE [KEY=VALUE...]",
+		Short: "Create a new bundle secret",
+		Long: strings.Trim(`
+Creates a new bundle: a single secret whose plaintext is a JSON object
+mapping each given KEY to its VALUE. If the bundle already exists, an error
+is returned; use "bundle set" to add or change values in an existing bundle.
+`, "\n"),
+		Example: strings.Trim(`
+  # Create a bundle named "app-config" with two values
+  berglas bundle create my-secrets/app-config timeout=30s retries=3 \
+    --key projects/my-p/locations/global/keyRings/my-kr/cryptoKeys/my-k
+`, "\n"),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			values, err := parseBundlePairs(args[1:])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Create(ctx, &berglas.CreateRequest{
+				Bucket:    bucket,
+				Object:    object,
+				Key:       key,
+				Plaintext: mustMarshalBundle(values),
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully created bundle [%s] with generation [%d]\n", object, secret.Generation)
+			return nil
+		},
+	}
+	bundleCmd.AddCommand(bundleCreateCmd)
+	bundleCreateCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption")
+	if err := bundleCreateCmd.MarkFlagRequired("key"); err != nil {
+		panic(err)
+	}
+
+	bundleSetCmd := &cobra.Command{
+		Use:   "set BUNDLE KEY=VALUE [KEY=VALUE...]",
+		Short: "Set one or more values in a bundle",
+		Long: strings.Trim(`
+Atomically sets one or more key/value pairs in the bundle secret, leaving
+any other keys already in the bundle untouched. The bundle is read and
+written in a loop that retries on a generation precondition conflict, so
+concurrent setters do not clobber each other's values.
+
+If the bundle does not already exist, it is created with exactly the given
+values; "--key" is required in that case.
+`, "\n"),
+		Example: strings.Trim(`
+  # Set a single value in an existing bundle
+  berglas bundle set my-secrets/app-config retries=5
+
+  # Create the bundle if it doesn't already exist
+  berglas bundle set my-secrets/app-config timeout=30s --key projects/...
+`, "\n"),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			values, err := parseBundlePairs(args[1:])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.BundleSet(ctx, &berglas.BundleSetRequest{
+				Bucket: bucket,
+				Object: object,
+				Key:    key,
+				Values: values,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully set values in bundle [%s] at generation [%d]\n", object, secret.Generation)
+			return nil
+		},
+	}
+	bundleCmd.AddCommand(bundleSetCmd)
+	bundleSetCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption if the bundle does not already exist")
+
+	bundleGetCmd := &cobra.Command{
+		Use:   "get BUNDLE [KEY]",
+		Short: "Read a bundle or a single value from it",
+		Long: strings.Trim(`
+Prints the bundle's values as a JSON object. If KEY is given, prints just
+that key's value instead.
+`, "\n"),
+		Example: strings.Trim(`
+  # Print the entire bundle
+  berglas bundle get my-secrets/app-config
+
+  # Print a single value
+  berglas bundle get my-secrets/app-config retries
+`, "\n"),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Read(ctx, &berglas.ReadRequest{
+				Bucket: bucket,
+				Object: object,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			b, err := berglas.ParseBundle(secret.Plaintext)
+			if err != nil {
+				return apiError(err)
+			}
+
+			if len(args) > 1 {
+				k := args[1]
+				v, ok := b[k]
+				if !ok {
+					return apiError(errors.Errorf("bundle [%s] has no key %q", object, k))
+				}
+				fmt.Fprintf(c.Stdout, "%s\n", v)
+				return nil
+			}
+
+			data, err := json.MarshalIndent(b, "", "  ")
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to marshal bundle"))
+			}
+			fmt.Fprintf(c.Stdout, "%s\n", data)
+			return nil
+		},
+	}
+	bundleCmd.AddCommand(bundleGetCmd)
+
+	bundleUnsetCmd := &cobra.Command{
+		Use:   "unset BUNDLE KEY [KEY...]",
+		Short: "Remove one or more values from a bundle",
+		Long: strings.Trim(`
+Atomically removes one or more keys from the bundle secret. Keys that are
+not present in the bundle are ignored.
+`, "\n"),
+		Example: strings.Trim(`
+  # Remove a value from a bundle
+  berglas bundle unset my-secrets/app-config retries
+`, "\n"),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.BundleUnset(ctx, &berglas.BundleUnsetRequest{
+				Bucket: bucket,
+				Object: object,
+				Keys:   args[1:],
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully removed values from bundle [%s] at generation [%d]\n", object, secret.Generation)
+			return nil
+		},
+	}
+	bundleCmd.AddCommand(bundleUnsetCmd)
+
+	kvCmd := &cobra.Command{
+		Use:   "kv",
+		Short: "Store and read individual fields of a structured secret",
+		Long: strings.Trim(`
+"kv" reads and writes the same JSON-object secrets as "berglas bundle"
+("kv put" is "bundle set", and "kv get" is "bundle get"), with one
+addition: "kv put" accepts a value from a file or stdin with the same
+"@path"/"-" syntax "create" accepts for a whole secret, for a field like a
+certificate that doesn't fit comfortably on a command line.
+
+Once a field is stored, it can be resolved on its own with a
+"berglas://bucket/secret#field" reference - see "berglas exec" - instead of
+extracting it client-side and leaking every other field into the process
+that does the extracting.
+`, "\n"),
+	}
+	rootCmd.AddCommand(kvCmd)
+
+	kvPutCmd := &cobra.Command{
+		Use:   "put SECRET KEY=VALUE [KEY=VALUE...]",
+		Short: "Set one or more fields of a structured secret",
+		Long: strings.Trim(`
+Atomically sets one or more fields in the secret, leaving any other fields
+already there untouched. If the secret does not already exist, it is
+created with exactly the given fields; "--key" is required in that case.
+
+Each VALUE may be a literal string, "@path" to read the value from a file,
+"env:VARNAME" to read it from an environment variable, "https://url" to
+fetch it over TLS, or "-" to read it from stdin.
+`, "\n"),
+		Example: strings.Trim(`
+  # Set a single field in an existing secret
+  berglas kv put my-secrets/db user=admin
+
+  # Create the secret if it doesn't already exist, reading one field from a file
+  berglas kv put my-secrets/db user=admin password=@./password.txt --key projects/...
+`, "\n"),
+		Args: cobra.MinimumNArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			values, err := c.parseKVPairs(args[1:])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.BundleSet(ctx, &berglas.BundleSetRequest{
+				Bucket: bucket,
+				Object: object,
+				Key:    key,
+				Values: values,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully set fields in secret [%s] at generation [%d]\n", object, secret.Generation)
+			return nil
+		},
+	}
+	kvCmd.AddCommand(kvPutCmd)
+	kvPutCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption if the secret does not already exist")
+
+	kvGetCmd := &cobra.Command{
+		Use:   "get SECRET [FIELD]",
+		Short: "Read a structured secret or a single field from it",
+		Long: strings.Trim(`
+Prints the secret's fields as a JSON object. If FIELD is given, prints just
+that field's value instead.
+`, "\n"),
+		Example: strings.Trim(`
+  # Print every field
+  berglas kv get my-secrets/db
+
+  # Print a single field
+  berglas kv get my-secrets/db password
+`, "\n"),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Read(ctx, &berglas.ReadRequest{
+				Bucket: bucket,
+				Object: object,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			b, err := berglas.ParseBundle(secret.Plaintext)
+			if err != nil {
+				return apiError(err)
+			}
+
+			if len(args) > 1 {
+				k := args[1]
+				v, ok := b[k]
+				if !ok {
+					return apiError(errors.Errorf("secret [%s] has no field %q", object, k))
+				}
+				fmt.Fprintf(c.Stdout, "%s\n", v)
+				return nil
+			}
+
+			data, err := json.MarshalIndent(b, "", "  ")
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to marshal secret"))
+			}
+			fmt.Fprintf(c.Stdout, "%s\n", data)
+			return nil
+		},
+	}
+	kvCmd.AddCommand(kvGetCmd)
+
+	createCmd := &cobra.Command{
+		Use:   "create SECRET DATA",
+		Short: "Create a secret",
+		Long: strings.Trim(`
+Creates a new secret with the given name and contents, encrypted with the
+provided Cloud KMS key. If the secret already exists, an error is returned.
+
+Use the "edit" or "update" commands to update an existing secret.
+
+To create several secrets at once, specify a bucket instead of a secret and
+supply one or more "--from-file" flags in the format "name=path". Each file's
+contents are created as a separate secret, and a consolidated report is
+printed once all secrets have been created.
+
+Run with "--retain-until" set to a date (YYYY-MM-DD) to prevent the secret
+from being deleted or overwritten before that date, for records-retention
+requirements on certain credentials. Retention status is shown by the "list"
+command.
+
+Pass "--key" with a trailing "/cryptoKeyVersions/N" to pin encryption to
+that specific CryptoKeyVersion instead of whichever version is currently
+primary. The pinned version is recorded in the secret's metadata and shown
+by "berglas key-report", so secrets still pinned to an old version can be
+found and re-created before that version is destroyed; IAM grants made
+with "grant" still target the key as a whole, since Cloud KMS IAM has no
+per-version permissions.
+
+Run with "--additional-key" one or more times to also wrap the secret's DEK
+under each of those Cloud KMS keys, independently of "--key". "access" and
+"read" try the additional keys, in order, if "--key" fails to decrypt, so an
+outage affecting a single KMS key (for example, a single region) does not
+make the secret unreadable as long as one of the others still works.
+
+Run with "--asymmetric" to wrap the DEK with "--key"'s public half, fetched
+via Cloud KMS's GetPublicKey, instead of calling Cloud KMS to encrypt it.
+This means creating the secret needs no decrypt permission on "--key" at
+all - only an asymmetric Cloud KMS key itself, and whatever permission lets
+the caller fetch its public key. It is intended for write-only producers,
+such as a CI pipeline, that should be able to create secrets they can never
+read back; reading one back requires a separate principal with decrypt
+permission to run "berglas access".
+
+Run with "--output json" or "--output yaml" to print the created secret's
+bucket, name, and generation as structured output instead of the default
+human-readable line. Ignored with "--from-file", which always prints its own
+consolidated report. With "--output json", a failure prints a JSON error
+object to stderr instead of prose - with a stable "code" (NOT_FOUND,
+PERMISSION_DENIED, PRECONDITION_FAILED, or KMS_ERROR), the secret
+reference, and the exit code - so orchestration tooling can branch on the
+failure category instead of parsing text.
+
+Run with --prompt instead of a DATA argument to type the secret value at an
+interactive, echo-disabled terminal prompt (like "ssh-keygen"'s passphrase
+prompt), entered twice for confirmation - a literal DATA argument is
+otherwise visible in shell history and in "ps" output for the life of the
+process.
+
+SECRET may instead be an "sm://project/secret" reference to create (or add a
+new version to) a Google Secret Manager secret. Secret Manager encrypts
+secret material itself, so "--key" is not required or used in that case, and
+none of "--from-file", "--retain-until", or "--format" apply.
+
+SECRET may also be a "file:///path/to/secret" reference to create a secret
+on the local filesystem instead of Cloud Storage, for development workflows
+that run without any GCP credentials. "--key" is optional in that case: pass
+a "local-key://" or "keyring-key://" key (see "berglas keygen") to encrypt
+the file's contents, or omit it to write the plaintext as-is. None of
+"--from-file", "--retain-until", or "--format" apply.
+
+Pass "--key passphrase://" to encrypt the DEK with a passphrase-derived key
+instead of Cloud KMS, read from the BERGLAS_PASSPHRASE environment variable,
+for field deployments with no KMS connectivity at all (e.g. air-gapped
+environments). Like "local-key://", this is NOT protected by Cloud KMS - the
+secret is only as strong as the passphrase. "age://" keys are recognized but
+not yet supported.
+`, "\n"),
+		Example: strings.Trim(`
+  # Create a secret named "api-key" with the contents "abcd1234"
+  berglas create my-secrets/api-key abcd1234 \
+    --key projects/my-p/locations/global/keyRings/my-kr/cryptoKeys/my-k
+
+  # Create (or add a version to) a Secret Manager secret
+  berglas create sm://my-project/api-key abcd1234
+
+  # Create a plaintext secret file, for development without GCP credentials
+  berglas create file:///etc/secrets/api-key abcd1234
+
+  # Read a secret from stdin
+  echo ${SECRET} | berglas create my-secrets/api-key - --key...
+
+  # Read a secret from a local file
+  berglas create my-secrets/api-key @/path/to/file --key...
+
+  # Create multiple secrets from local files in one invocation
+  berglas create my-secrets \
+    --from-file api-key=./key.txt --from-file tls/cert=./cert.pem \
+    --key...
+
+  # Create a secret that cannot be deleted or overwritten before 2027-01-01
+  berglas create my-secrets/api-key abcd1234 --key... --retain-until 2027-01-01
+
+  # Type the secret value at a hidden prompt instead of on the command line
+  berglas create my-secrets/api-key --prompt --key...
+
+  # Create a secret a write-only CI pipeline can create but never read back
+  berglas create my-secrets/api-key abcd1234 --key... --asymmetric
+
+  # Create a secret whose DEK is wrapped under keys in two regions, so a
+  # single region's KMS outage does not make the secret unreadable
+  berglas create my-secrets/api-key abcd1234 --key=keyA --additional-key=keyB
+
+  # Create a secret in an air-gapped environment with no Cloud KMS access
+  BERGLAS_PASSPHRASE=... berglas create my-secrets/api-key abcd1234 --key passphrase://
+`, "\n"),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			if berglas.IsSMReference(args[0]) {
+				client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+				if err != nil {
+					return misuseError(err)
+				}
+				defer closer()
+
+				smRef, err := berglas.ParseSMReference(args[0])
+				if err != nil {
+					return misuseError(err)
+				}
+
+				plaintext, err := c.createReadPlaintext(args, createPrompt)
+				if err != nil {
+					return misuseError(err)
+				}
+
+				if dryRun {
+					fmt.Fprintf(c.Stdout, "Would create secret [%s] in project [%s]\n", smRef.Secret(), smRef.Project())
+					return nil
+				}
+
+				if err := client.SMCreate(ctx, &berglas.SMCreateRequest{
+					Project:   smRef.Project(),
+					Secret:    smRef.Secret(),
+					Plaintext: plaintext,
+				}); err != nil {
+					return apiError(err)
+				}
+
+				fmt.Fprintf(c.Stdout, "Successfully created secret [%s] in project [%s]\n", smRef.Secret(), smRef.Project())
+				return nil
+			}
+
+			if berglas.IsFileReference(args[0]) {
+				client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+				if err != nil {
+					return misuseError(err)
+				}
+				defer closer()
+
+				fileRef, err := berglas.ParseFileReference(args[0])
+				if err != nil {
+					return misuseError(err)
+				}
+
+				plaintext, err := c.createReadPlaintext(args, createPrompt)
+				if err != nil {
+					return misuseError(err)
+				}
+
+				if dryRun {
+					fmt.Fprintf(c.Stdout, "Would create secret file [%s]\n", fileRef.Path())
+					return nil
+				}
+
+				if err := client.FileCreate(ctx, &berglas.FileCreateRequest{
+					Path:      fileRef.Path(),
+					Plaintext: plaintext,
+					Key:       key,
+				}); err != nil {
+					return apiError(err)
+				}
+
+				fmt.Fprintf(c.Stdout, "Successfully created secret file [%s]\n", fileRef.Path())
+				return nil
+			}
+
+			if key == "" {
+				return misuseError(errors.New("required flag(s) \"key\" not set"))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			retainUntil, err := parseRetainUntil(createRetainUntil)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if len(fromFiles) > 0 {
+				bucket := strings.TrimPrefix(strings.TrimPrefix(args[0], "gs://"), "berglas://")
+				return c.createFromFilesRun(client, ctx, bucket, key, envelopeFormat, createAsymmetric, createAdditionalKeys, retainUntil, fromFiles, dryRun)
+			}
+
+			switch createOutput {
+			case "", "text", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown output format %q", createOutput))
+			}
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			plaintext, err := c.createReadPlaintext(args, createPrompt)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if dryRun {
+				fmt.Fprintf(c.Stdout, "Would create secret [%s] with key [%s]\n", object, key)
+				return nil
+			}
+
+			var secret *berglas.Secret
+			if secret, err = client.Create(ctx, &berglas.CreateRequest{
+				Bucket:         bucket,
+				Object:         object,
+				Key:            key,
+				AdditionalKeys: createAdditionalKeys,
+				Plaintext:      plaintext,
+				Format:         berglas.EnvelopeFormat(envelopeFormat),
+				Asymmetric:     createAsymmetric,
+				RetainUntil:    retainUntil,
+			}); err != nil {
+				if createOutput == "json" {
+					return c.apiErrorJSON(bucket+"/"+object, err)
+				}
+				return apiError(err)
+			}
+
+			if createOutput == "json" || createOutput == "yaml" {
+				return c.writeStructuredOutput(createOutput, struct {
+					Bucket     string `json:"bucket" yaml:"bucket"`
+					Name       string `json:"name" yaml:"name"`
+					Generation int64  `json:"generation" yaml:"generation"`
+				}{
+					Bucket:     bucket,
+					Name:       object,
+					Generation: secret.Generation,
+				})
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully created secret [%s] with generation [%d]\n", object, secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(createCmd)
+	createCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption. Required unless SECRET is an \"sm://\" reference")
+	createCmd.Flags().StringArrayVar(&fromFiles, "from-file", nil,
+		"Create a secret named NAME with the contents of the file at PATH, in "+
+			"the format NAME=PATH. May be specified multiple times to create "+
+			"several secrets in one invocation")
+	createCmd.Flags().StringVar(&envelopeFormat, "format", "",
+		`Envelope format to wrap the DEK in. One of "" (default) or "tink"`)
+	createCmd.Flags().StringVar(&createRetainUntil, "retain-until", "",
+		"Prevent the secret from being deleted or overwritten before this date, in the format YYYY-MM-DD")
+	createCmd.Flags().StringVar(&createOutput, "output", "text",
+		"Output format for the result: \"text\", \"json\", or \"yaml\" (ignored with --from-file)")
+	createCmd.Flags().BoolVar(&createPrompt, "prompt", false,
+		"Read the secret value from an interactive, echo-disabled terminal prompt instead of DATA")
+	createCmd.Flags().BoolVar(&createAsymmetric, "asymmetric", false,
+		"Wrap the DEK with --key's public half instead of encrypting it, so creating the secret "+
+			"needs no KMS decrypt permission on --key. --key must be an asymmetric Cloud KMS key")
+	createCmd.Flags().StringArrayVar(&createAdditionalKeys, "additional-key", nil,
+		"Also wrap the secret's DEK under this KMS key, independently of --key. May be specified "+
+			"multiple times; access and read fall back to these, in order, if --key fails to decrypt")
+
+	deleteCmd := &cobra.Command{
+		Use:   "delete SECRET",
+		Short: "Remove a secret",
+		Long: strings.Trim(`
+Deletes a secret from a Google Cloud Storage bucket by deleting the underlying
+GCS object. If the secret does not exist, this operation is a no-op.
+
+This command will exit successfully even if the secret does not exist.
+
+Run with --reason set to a ticket or incident ID to justify the deletion; see
+"berglas access --help" for where it ends up recorded.
+
+Run with --if-generation and/or --if-metageneration to only delete the
+secret if its current live generation/metageneration still matches, so two
+concurrent operations (e.g. two rotations) racing on the same secret can't
+silently stomp each other; a mismatch fails the delete instead.
+`, "\n"),
+		Example: strings.Trim(`
+  # Delete a secret named "api-key"
+  berglas delete my-secrets/api-key
+
+  # Delete it as part of an incident cleanup
+  berglas delete my-secrets/api-key --reason INC-1234
+
+  # Only delete if it's still the generation this caller last read
+  berglas delete my-secrets/api-key --if-generation 1566336000000000
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			var extraOpts []option.ClientOption
+			if deleteReason != "" {
+				extraOpts = append(extraOpts, option.WithRequestReason(deleteReason))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout, extraOpts...)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if dryRun {
+				fmt.Fprintf(c.Stdout, "Would delete secret [%s] if it exists\n", object)
+				return nil
+			}
+
+			c.recordAccessReason(ctx, impersonate, "delete", bucket, object, deleteReason)
+
+			if err := client.Delete(ctx, &berglas.DeleteRequest{
+				Bucket:           bucket,
+				Object:           object,
+				IfGeneration:     deleteIfGeneration,
+				IfMetageneration: deleteIfMetageneration,
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully deleted secret [%s] if it existed\n", object)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(deleteCmd)
+	deleteCmd.Flags().StringVar(&deleteReason, "reason", "",
+		"Justification (e.g. a ticket ID) for this deletion, recorded in Cloud Audit Logs and the "+
+			"local access-justification audit log")
+	deleteCmd.Flags().Int64Var(&deleteIfGeneration, "if-generation", 0,
+		"Only delete if the secret's current live generation matches this value")
+	deleteCmd.Flags().Int64Var(&deleteIfMetageneration, "if-metageneration", 0,
+		"Only delete if the secret's current live metageneration matches this value")
+
+	editCmd := &cobra.Command{
+		Use:   "edit SECRET",
+		Short: "Edit an existing secret",
+		Long: strings.Trim(`
+Updates the contents of an existing secret by reading the encrypted data from
+Google Cloud Storage, decrypting it with Google Cloud KMS, editing it in-place
+using an editor, encrypting the updated content using Google Cloud KMS, writing
+it back into Google Cloud Storage.
+
+The file must be saved with changes and editor must exit with exit code 0 for
+the secret to be updated.
+
+Run with --reason set to a ticket or incident ID to justify the edit; see
+"berglas access --help" for where it ends up recorded.
+
+The editor to run is resolved, in order, from --editor, the "editor" key in
+the config file (~/.berglas/config.yaml, or $BERGLAS_CONFIG_FILE), $VISUAL,
+$EDITOR, and finally a platform default. The resolved value is parsed with
+shell-style word splitting, so an editor command with arguments (e.g.
+"code --wait") can be given as a single string.
+
+Run with --create-if-missing to create the secret (using --key) if it
+doesn't already exist, opening the editor on an empty file instead of
+failing.
+
+If someone else updates the secret while it's open in your editor, the
+write is not silently overwritten or discarded: you're shown a diff of
+your changes and theirs against the version you started from and asked to
+retry (overwrite with your version), merge (re-open the editor with
+git-style conflict markers), or abort, in which case your edits are not
+saved.
+
+The decrypted plaintext is written to a 0600 tempfile in a RAM-backed
+directory (e.g. /dev/shm) when one is available, falling back to the
+default temp directory otherwise, and its contents are overwritten before
+it's deleted. Run with --insecure-tempdir to allow that directory even if
+it's world-writable without the sticky bit set; otherwise berglas refuses
+to run rather than risk another local user reading or replacing the
+tempfile.
+`, "\n"),
+		Example: strings.Trim(`
+  # Edit a secret named "api-key" from the bucket "my-secrets"
+  berglas edit my-secrets/api-key
+
+  # Edit a secret named "api-key" from the bucket "my-secrets" using emacs
+  berglas edit my-secrets/api-key --editor emacs
+
+  # Edit it as part of an incident fix, with a recorded justification
+  berglas edit my-secrets/api-key --reason INC-1234
+
+  # Create the secret if it doesn't already exist
+  berglas edit my-secrets/api-key --create-if-missing --key projects/p/locations/l/keyRings/r/cryptoKeys/k
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			var extraOpts []option.ClientOption
+			if editReason != "" {
+				extraOpts = append(extraOpts, option.WithRequestReason(editReason))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout, extraOpts...)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			// Find the editor
+			editorBin, err := resolveEditor(editor)
+			if err != nil {
+				return apiError(err)
+			}
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if createIfMissing && key == "" {
+				err := errors.New("--key is required when --create-if-missing is set")
+				return misuseError(err)
+			}
+
+			c.recordAccessReason(ctx, impersonate, "edit", bucket, object, editReason)
+
+			// Get the existing secret, or fall back to an empty one if
+			// --create-if-missing is set and the secret doesn't exist yet.
+			originalSecret, err := client.Read(ctx, &berglas.ReadRequest{
+				Bucket: bucket,
+				Object: object,
+			})
+			creating := false
+			if err != nil {
+				if !createIfMissing || !berglas.IsSecretDoesNotExistErr(err) {
+					return apiError(err)
+				}
+				creating = true
+				originalSecret = &berglas.Secret{KMSKey: key}
+			}
+
+			c.warnIfLocalKey(originalSecret.KMSKey)
+
+			newPlaintext, err := c.runEditor(editorBin, originalSecret.Plaintext, editInsecureTempdir)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			// Error if the secret is empty
+			if len(newPlaintext) == 0 {
+				err := errors.New("secret is empty")
+				return misuseError(err)
+			}
+
+			if bytes.Equal(newPlaintext, originalSecret.Plaintext) {
+				err := errors.New("secret unchanged - not going to update")
+				return misuseError(err)
+			}
+
+			// Update the secret, retrying against the latest generation if
+			// someone else updated it while we had it open. current is the
+			// secret we last read; it starts as what we opened in the editor
+			// and is refreshed on every conflict.
+			current := originalSecret
+			for {
+				updatedSecret, err := client.Update(ctx, &berglas.UpdateRequest{
+					Bucket:          bucket,
+					Object:          object,
+					Generation:      current.Generation,
+					Key:             current.KMSKey,
+					Metageneration:  current.Metageneration,
+					Plaintext:       newPlaintext,
+					CreateIfMissing: creating,
+				})
+				if err == nil {
+					fmt.Fprintf(c.Stdout, "Successfully updated secret [%s] with generation [%d]\n",
+						object, updatedSecret.Generation)
+					return nil
+				}
+				if !berglas.IsSecretModifiedErr(err) {
+					err = errors.Wrapf(err, "failed to update secret")
+					return misuseError(err)
+				}
+
+				remoteSecret, rerr := client.Read(ctx, &berglas.ReadRequest{
+					Bucket: bucket,
+					Object: object,
+				})
+				if rerr != nil {
+					return apiError(rerr)
+				}
+
+				fmt.Fprintf(c.Stderr, "conflict: %s was updated to generation %d while you had it open\n\n",
+					object, remoteSecret.Generation)
+				if diff, derr := unifiedDiff(current.Plaintext, newPlaintext, "base", "yours"); derr == nil {
+					fmt.Fprint(c.Stderr, diff)
+				}
+				if diff, derr := unifiedDiff(current.Plaintext, remoteSecret.Plaintext, "base", "theirs"); derr == nil {
+					fmt.Fprint(c.Stderr, diff)
+				}
+
+				choice, err := c.promptEditConflict()
+				if err != nil {
+					return misuseError(err)
+				}
+				switch choice {
+				case "retry":
+					creating = false
+				case "merge":
+					merged, err := c.runEditor(editorBin,
+						conflictMarkers(current.Plaintext, newPlaintext, remoteSecret.Plaintext), editInsecureTempdir)
+					if err != nil {
+						return misuseError(err)
+					}
+					newPlaintext = merged
+					creating = false
+				default:
+					err := errors.New("update aborted due to conflict; your edits were not saved")
+					return misuseError(err)
+				}
+				current = remoteSecret
+			}
+		},
+	}
+	rootCmd.AddCommand(editCmd)
+	editCmd.Flags().StringVar(&editor, "editor", "",
+		"Editor program to use. If unspecified, this defaults to $VISUAL or "+
+			"$EDITOR in that order.")
+	editCmd.Flags().BoolVar(&createIfMissing, "create-if-missing", false,
+		"Create the secret if it doesn't exist")
+	editCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption (only used when secret doesn't exist)")
+	editCmd.Flags().StringVar(&editReason, "reason", "",
+		"Justification (e.g. a ticket ID) for this edit, recorded in Cloud Audit Logs and the "+
+			"local access-justification audit log")
+	editCmd.Flags().BoolVar(&editInsecureTempdir, "insecure-tempdir", false,
+		"Allow writing the secret tempfile to a world-writable directory that doesn't have "+
+			"the sticky bit set")
+
+	execCmd := &cobra.Command{
+		Use:   "exec -- SUBCOMMAND",
+		Short: "Spawn an environment with secrets",
+		Long: strings.Trim(`
+Parse berglas references and spawn the given command with the secrets in the
+childprocess environment similar to exec(1). This is very useful in Docker
+containers or languages that do not support auto-import.
+
+By default, this command attempts to communicate with the Cloud APIs to find the
+list of environment variables set on a resource. If you are not running inside a
+supported runtime, you can specify "-local" to parse the local environment
+variables instead.
+
+Alternatively, "-map" points at a file declaring ENV_NAME=berglas://...
+pairs to resolve, one per line ("#"-prefixed lines and blank lines are
+ignored). This decouples secret wiring from whatever environment variables
+the runtime platform happens to set, and the map file can be reviewed and
+diffed in git like any other config.
+
+Berglas will remain the parent process, but stdin, stdout, stderr, and any
+signals are proxied to the child process. By default, a safe subset of
+signals is forwarded as-is (not, for example, SIGCHLD or SIGURG, which
+confuse children that don't expect them); pass "-forward-signals" with a
+comma-separated list of names to use a different set. INT and TERM are
+never forwarded raw - they instead ask the child to stop with
+"-stop-signal" (SIGTERM by default), escalating to a hard kill after
+"-stop-timeout", the same way "-watch" stops the child before a
+"-on-change=restart".
+
+With "-tty", berglas allocates a pseudo-terminal for the child instead of
+piping its stdin/stdout directly, so an interactive program (a shell, a
+prompt-driven CLI) behaves as if it were run directly from a terminal.
+Only supported on platforms with POSIX process credentials.
+
+On Windows, where there is no POSIX process group to lean on, the child is
+started in its own console process group (so "-stop-signal" reaches it via
+GenerateConsoleCtrlEvent) and confined to a job object with
+kill-on-job-close semantics, so the child and anything it spawns are torn
+down with berglas rather than surviving as orphans.
+
+References are resolved concurrently, "-resolve-concurrency" at a time (10
+by default), sharing a single client, so a service with dozens of them
+doesn't pay for each one serially at startup.
+
+With "-user" (and optionally "-group", "-chdir", and "-umask"), berglas
+drops privileges and spawns the child as that user after resolving secrets
+and writing any "-secret-file", letting it serve as a minimal container
+entrypoint in place of gosu/su-exec. "-user" accepts a name or a uid; if
+"-group" is not also given, the named user's primary group is used.
+"-user"/"-group"/"-umask" are only supported on platforms with POSIX
+process credentials.
+
+With "-supervise", berglas acts as a minimal init/supervisor instead of
+exiting alongside the child: combined with "-restart-on-failure", it
+restarts the child (re-resolving all secrets, in case they have rotated)
+whenever it exits non-zero, up to "-max-restarts" times, waiting "-backoff"
+between attempts. This is useful for giving a container a crash-restart loop
+without adding another supervisor binary to the image.
+
+At "-log-level debug", berglas reports which environment variables were
+identified as references, how long each took to resolve, and whether it was
+served from exec's per-run cache of already-resolved references - useful for
+troubleshooting slow or failing startups. Resolved values are never included
+in that report.
+
+With "-secret-file PATH=berglas://...", a resolved secret is materialized to
+PATH on disk instead of being exported as an environment variable. Every
+file written this way is tracked and, by default, shredded (overwritten
+with zeroes) and removed once the child exits, including when it is
+terminated by a forwarded signal. Pass "-keep-files" to leave them in
+place instead.
+
+A reference can also carry its own destination, e.g.
+"berglas://bucket/secret?destination=/run/secrets/db-pass" - the secret is
+written to that path instead, and the environment variable that held the
+reference is replaced with the path rather than the plaintext, so a
+process that expects to read its secrets from files (rather than the
+environment) can be pointed at a tmpfs mount without an extra
+"-secret-file" flag per secret. Add "&mode=0440" to control the file's
+permissions; it defaults to 0600. "?destination=tmpfile" writes to a
+generated temporary file instead of a fixed path.
+
+A reference can also be embedded inside a larger value with
+"${berglas://bucket/secret}" syntax, e.g.
+"DATABASE_URL=postgres://user:${berglas://bucket/db-pass}@host/db", so a
+composite value can be built out of one or more secrets without a wrapper
+script. This works for any reference scheme ("sm://", "file://", or a
+custom resolver's), but does not support "?destination=" - that only
+applies to a variable whose entire value is a single reference.
+
+With "-pins pins.lock", every reference this command resolves (whether from
+the environment, "-map", or "-secret-file") is pinned to the generation
+"berglas pin" recorded for it, instead of whatever is live or embedded in
+the reference itself - giving a deployment reproducible secrets immune to
+mid-rollout changes. Resolving a reference with no entry in the pins file
+fails the command.
+
+With "-watch", berglas polls every "-watch-interval" for a changed secret
+(covering the environment, "-map", and every "-secret-file") and reacts via
+"-on-change": the default, "restart", stops the child (requires
+"-supervise", since it relies on the same restart loop) and starts it again
+with freshly-resolved secrets; a signal name such as "SIGHUP" is sent to the
+running child instead, without restarting it, for services that reload
+their own configuration on that signal. A "restart" waits "-watch-grace"
+after asking the child to stop before killing it outright. This is what
+lets a long-running service pick up rotated credentials without a
+deploy.
+
+By default, a single reference that fails to resolve aborts the launch
+before the child ever spawns. Pass "-missing warn" to instead log a warning
+and start the child without that reference (it is simply absent from the
+environment or "-secret-file"), or "-missing skip" to do the same silently.
+This is useful in environments - a local dev machine without every
+credential, or a staging project with a leaner secret set - where a hard
+failure is worse than starting with a gap.
+
+WARNING: Using berglas exec exposes secrets in plaintext in environment
+variables or, with "-secret-file", on disk. You should have a strong
+understanding of your software supply chain security before blindly
+running a process with berglas exec. The resolved secrets will be in
+plaintext and available to the entire process.
+`, "\n"),
+		Example: strings.Trim(`
+  # Spawn a subshell with secrets populated
+  berglas exec -- ${SHELL}
+
+  # Run "myapp" after parsing local references
+  berglas exec --local -- myapp --with-args
+
+  # Restart "myapp" with freshly-resolved secrets whenever it crashes
+  berglas exec --supervise --restart-on-failure --max-restarts 5 --backoff 10s -- myapp
+
+  # Resolve secrets from a checked-in map file instead of the platform's env
+  berglas exec --map secrets.map -- myapp
+
+  # Materialize a secret to a file, shredded and removed when myapp exits
+  berglas exec --secret-file /tmp/tls.key=berglas://bucket/tls-key -- myapp
+
+  # Resolve every reference to the generation pinned in pins.lock
+  berglas exec --pins pins.lock -- myapp
+
+  # Restart "myapp" whenever a watched secret rotates
+  berglas exec --supervise --watch -- myapp
+
+  # Send SIGHUP instead of restarting when a watched secret rotates
+  berglas exec --watch --on-change SIGHUP -- myapp
+
+  # Start "myapp" even if some secrets don't resolve, logging a warning
+  berglas exec --missing warn -- myapp
+
+  # DATABASE_URL=postgres://user:${berglas://bucket/db-pass}@host/db in the
+  # environment resolves to a single composite connection string
+  berglas exec -- myapp
+
+  # Resolve secrets as root, then drop to "myapp" before spawning it
+  berglas exec --user myapp --chdir /srv/myapp -- ./myapp
+
+  # Give "myapp" 30s to shut down on SIGHUP before it's killed
+  berglas exec --stop-signal SIGHUP --stop-timeout 30s -- myapp
+
+  # Run an interactive shell with secrets populated, with a real terminal
+  berglas exec --tty -- ${SHELL}
+`, "\n"),
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			if execCacheDir != "" {
+				client.ApplyOptions(berglas.WithDiskCache(execCacheDir, execMaxStale))
+			}
+
+			if execMetricsAddr != "" {
+				metricsSrv := server.New(execMetricsAddr)
+				metricsSrv.SetReady(true)
+				go func() {
+					if err := metricsSrv.Start(ctx); err != nil && err != http.ErrServerClosed {
+						fmt.Fprintf(c.Stderr, "berglas: metrics server exited: %s\n", err)
+					}
+				}()
+				fmt.Fprintf(c.Stdout, "Serving health checks and metrics on %s\n", execMetricsAddr)
+			}
+
+			if execRestartOnFailure && !execSupervise {
+				return misuseError(errors.New("--restart-on-failure requires --supervise"))
+			}
+			if execMapFile != "" && execLocal {
+				return misuseError(errors.New("--map and --local are mutually exclusive"))
+			}
+			if execWatch && execOnChange == "restart" && !execSupervise {
+				return misuseError(errors.New("--watch --on-change=restart requires --supervise"))
+			}
+			switch execMissing {
+			case "fail", "warn", "skip":
+				// ok
+			default:
+				return misuseError(errors.Errorf("invalid --missing value %q: expected fail, warn, or skip", execMissing))
+			}
+			if execResolveConcurrency < 1 {
+				return misuseError(errors.New("--resolve-concurrency must be at least 1"))
+			}
+			if (execUser != "" || execGroup != "") && !credentialsSupported {
+				return misuseError(errors.New("--user and --group are not supported on this platform"))
+			}
+			if execUmask != "" && !umaskSupported {
+				return misuseError(errors.New("--umask is not supported on this platform"))
+			}
+
+			var uid, gid uint32
+			var haveCredential bool
+			if execUser != "" {
+				haveCredential = true
+				u, g, hasGid, err := resolveUser(execUser)
+				if err != nil {
+					return misuseError(err)
+				}
+				uid, gid = u, g
+				if !hasGid && execGroup == "" {
+					return misuseError(errors.Errorf("could not determine a primary group for user %q; pass --group explicitly", execUser))
+				}
+			}
+			if execGroup != "" {
+				haveCredential = true
+				g, err := resolveGroup(execGroup)
+				if err != nil {
+					return misuseError(err)
+				}
+				gid = g
+			}
+
+			var umask int
+			haveUmask := execUmask != ""
+			if haveUmask {
+				m, err := strconv.ParseUint(execUmask, 8, 32)
+				if err != nil {
+					return misuseError(errors.Wrapf(err, "invalid --umask value %q", execUmask))
+				}
+				umask = int(m)
+			}
+
+			if execTTY && !ttySupported {
+				return misuseError(errors.New("--tty is not supported on this platform"))
+			}
+
+			// forwardSignals is what "-forward-signals" restricts raw signal
+			// forwarding to; it defaults to a safe subset instead of every signal
+			// the OS can deliver, since blindly forwarding things like SIGCHLD or
+			// SIGURG confuses children that don't expect them. stopTriggerSignals
+			// (INT and TERM, or platform equivalent) are always watched in
+			// addition, and are never forwarded raw: they instead ask the child to
+			// stop with "-stop-signal", escalating to a hard kill after
+			// "-stop-timeout", so a supervisor's `kill` behaves the same as it
+			// would against the child directly.
+			forwardSignalNames := defaultForwardSignalNames
+			if execForwardSignals != "" {
+				forwardSignalNames = strings.Split(execForwardSignals, ",")
+			}
+			var forwardSignals []os.Signal
+			for _, name := range forwardSignalNames {
+				name = strings.TrimSpace(name)
+				if name == "" {
+					continue
+				}
+				sig, err := parseSignal(name)
+				if err != nil {
+					return misuseError(errors.Wrap(err, "invalid --forward-signals"))
+				}
+				forwardSignals = append(forwardSignals, sig)
+			}
+
+			stopSignal := terminateSignal
+			if execStopSignal != "" {
+				sig, err := parseSignal(execStopSignal)
+				if err != nil {
+					return misuseError(errors.Wrap(err, "invalid --stop-signal"))
+				}
+				stopSignal = sig
+			}
+
+			var watchSignal os.Signal
+			if execWatch && execOnChange != "restart" {
+				sig, err := parseSignal(execOnChange)
+				if err != nil {
+					return misuseError(errors.Wrapf(err, "invalid --on-change value %q", execOnChange))
+				}
+				watchSignal = sig
+			}
+
+			execCmd := args[0]
+			execArgs := args[1:]
+
+			var pins map[string]int64
+			if execPins != "" {
+				pins, err = c.readPinsFile(execPins)
+				if err != nil {
+					return misuseError(err)
+				}
+			}
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			ec, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(err)
+			}
+
+			// materializedFiles tracks every path written by writeSecretFiles so
+			// it can be shredded and removed once the child exits, regardless of
+			// how it exited, unless --keep-files was given.
+			materializedFiles := make(map[string]bool)
+			defer func() {
+				if execKeepFiles {
+					return
+				}
+				for path := range materializedFiles {
+					if err := shredFile(path); err != nil {
+						fmt.Fprintf(c.Stderr, "berglas: failed to remove secret file %s: %s\n", path, err)
+					}
+				}
+			}()
+
+			// resolveMissing applies "-missing" to an error encountered while
+			// resolving the single reference identified by desc (an environment
+			// variable name or "-secret-file" path). ok reports whether the
+			// caller should carry on without that reference - true for
+			// "-missing=skip", and for "-missing=warn" after logging a warning
+			// - or abort the launch by returning retErr - the default,
+			// "-missing=fail", and always for errors unrelated to resolution
+			// itself (e.g. a malformed map file).
+			resolveMissing := func(desc string, err error) (ok bool, retErr error) {
+				switch execMissing {
+				case "warn":
+					fmt.Fprintf(c.Stderr, "berglas: warning: failed to resolve %s: %s\n", desc, err)
+					return true, nil
+				case "skip":
+					return true, nil
+				default:
+					return false, err
+				}
+			}
+
+			// writeSecretFiles resolves every "-secret-file" pair and materializes
+			// the resulting plaintext to disk. It's called again on every
+			// supervised restart, alongside resolveEnv, so the files reflect any
+			// rotated secrets.
+			writeSecretFiles := func() error {
+				for _, pair := range execSecretFiles {
+					path, ref, err := parseSecretFilePair(pair)
+					if err != nil {
+						return misuseError(err)
+					}
+
+					plaintext, err := c.resolveWithPins(ec, ctx, pins, ref)
+					if err != nil {
+						if ok, rerr := resolveMissing(path, apiError(err)); !ok {
+							return rerr
+						}
+						continue
+					}
+
+					if err := ioutil.WriteFile(path, plaintext, 0600); err != nil {
+						return misuseError(errors.Wrapf(err, "failed to write secret file %s", path))
+					}
+					materializedFiles[path] = true
+				}
+				return nil
+			}
+
+			// resolveEnv re-resolves every berglas reference in the environment.
+			// It's called again on every supervised restart in case a secret has
+			// rotated in the meantime. The heavy lifting is shared with "berglas
+			// env" via resolveEnvironment.
+			envOpts := envResolveOptions{
+				MapFile:            execMapFile,
+				Local:              execLocal,
+				Pins:               pins,
+				Missing:            execMissing,
+				ResolveConcurrency: execResolveConcurrency,
+				LogSource:          "exec",
+			}
+			resolveEnv := func() ([]string, error) {
+				return c.resolveEnvironment(ctx, client, ec, envOpts)
+			}
+
+			// watchHash digests every value this run of exec would apply right
+			// now - the resolved environment and every "-secret-file" - so
+			// runChild's watch goroutine can detect a rotated secret without
+			// tracking a generation per reference type (berglas://, sm://,
+			// file://, and custom resolvers all funnel through here uniformly).
+			watchHash := func() (string, error) {
+				env, err := resolveEnv()
+				if err != nil {
+					return "", err
+				}
+
+				sorted := append([]string(nil), env...)
+				sort.Strings(sorted)
+
+				h := sha256.New()
+				for _, e := range sorted {
+					io.WriteString(h, e)
+					h.Write([]byte{0})
+				}
+				for _, pair := range execSecretFiles {
+					path, ref, err := parseSecretFilePair(pair)
+					if err != nil {
+						return "", misuseError(err)
+					}
+					plaintext, err := c.resolveWithPins(ec, ctx, pins, ref)
+					if err != nil {
+						if ok, rerr := resolveMissing(path, apiError(err)); !ok {
+							return "", rerr
+						}
+						continue
+					}
+					h.Write(plaintext)
+					h.Write([]byte{0})
+				}
+				return hex.EncodeToString(h.Sum(nil)), nil
+			}
+
+			// runChild resolves the environment, spawns the child, proxies signals
+			// to it, and waits for it to finish. exitErr is non-nil (and code
+			// reflects the child's exit code) when the child exited non-zero; err
+			// is non-nil for anything that kept the child from running to
+			// completion at all. watchTriggered reports whether the child was
+			// stopped because "-watch" detected a secret change and
+			// "-on-change=restart", rather than exiting or crashing on its own.
+			runChild := func() (code int, exitErr error, watchTriggered bool, err error) {
+				env, err := resolveEnv()
+				if err != nil {
+					return 0, nil, false, err
+				}
+
+				if err := writeSecretFiles(); err != nil {
+					return 0, nil, false, err
+				}
+
+				cmd := exec.Command(execCmd, execArgs...)
+				cmd.Env = env
+				if execChdir != "" {
+					cmd.Dir = execChdir
+				}
+				if haveCredential {
+					applyCredential(cmd, uid, gid, nil)
+				}
+				prepareChildProcessGroup(cmd)
+
+				doneCh := make(chan struct{})
+
+				var ptmx *os.File
+				startErr := func() error {
+					if haveUmask {
+						// Umask is inherited by the child at fork time; restore ours
+						// immediately after so it doesn't affect files berglas itself
+						// writes for the rest of this run (e.g. a supervised
+						// restart's -secret-file rewrite).
+						old := applyUmask(umask)
+						defer applyUmask(old)
+					}
+					if execTTY {
+						f, err := startWithTTY(cmd)
+						ptmx = f
+						return err
+					}
+					cmd.Stdin = c.Stdin
+					cmd.Stdout = c.Stdout
+					cmd.Stderr = c.Stderr
+					return cmd.Start()
+				}()
+				if startErr != nil {
+					return 0, nil, false, misuseError(startErr)
+				}
+
+				if ptmx != nil {
+					defer ptmx.Close()
+					if stdinFile, ok := c.Stdin.(*os.File); ok {
+						watchWindowSize(stdinFile, ptmx, doneCh)
+					}
+					go io.Copy(ptmx, c.Stdin)
+					go io.Copy(c.Stdout, ptmx)
+				}
+
+				// Confine the child (and anything it spawns) to a job object on
+				// Windows, so it's torn down with berglas rather than surviving as
+				// an orphan; a no-op on platforms with real process groups.
+				if job, err := confineToJobObject(cmd.Process.Pid); err != nil {
+					fmt.Fprintf(c.Stderr, "berglas: failed to confine child process, orphans may survive berglas: %s\n", err)
+				} else if job != nil {
+					defer job.Close()
+				}
+
+				// Listen for signals and either forward them to the child as-is or,
+				// for a stop-trigger signal, ask it to stop with "-stop-signal"
+				// instead (escalating to a hard kill after "-stop-timeout").
+				signalCh := make(chan os.Signal, 1)
+				signal.Notify(signalCh, append(append([]os.Signal{}, forwardSignals...), stopTriggerSignals...)...)
+				go func() {
+					var stopping bool
+					for {
+						select {
+						case s := <-signalCh:
+							if cmd.Process == nil {
+								return
+							}
+							if isStopTriggerSignal(s) {
+								if stopping {
+									continue
+								}
+								stopping = true
+								go stopGracefully(cmd, stopSignal, execStopTimeout)
+								continue
+							}
+							if signalErr := cmd.Process.Signal(s); signalErr != nil {
+								fmt.Fprintf(c.Stderr, "failed to signal command: %s\n", signalErr)
+							}
+						case <-doneCh:
+							signal.Stop(signalCh)
+							close(signalCh)
+							return
+						}
+					}
+				}()
+
+				// With "-watch", poll every "-watch-interval" for a rotated secret.
+				// For a named "-on-change" signal, forward it to the child and keep
+				// watching. For the default "restart", stop the child ("-stop-signal",
+				// then a hard kill after "-watch-grace") and close watchStopCh so the
+				// supervise loop below restarts it with freshly-resolved secrets.
+				watchDoneCh := make(chan struct{})
+				watchStopCh := make(chan struct{})
+				if execWatch {
+					baseline, err := watchHash()
+					if err != nil {
+						fmt.Fprintf(c.Stderr, "berglas: watch: failed to capture initial secret state, disabling --watch for this run: %s\n", err)
+					} else {
+						go func() {
+							ticker := time.NewTicker(execWatchInterval)
+							defer ticker.Stop()
+
+							for {
+								select {
+								case <-watchDoneCh:
+									return
+								case <-ticker.C:
+									current, err := watchHash()
+									if err != nil {
+										fmt.Fprintf(c.Stderr, "berglas: watch: failed to check secrets: %s\n", err)
+										continue
+									}
+									if current == baseline {
+										continue
+									}
+									baseline = current
+
+									if watchSignal != nil {
+										fmt.Fprintf(c.Stderr, "berglas: watch: secrets changed, sending %s to child\n", execOnChange)
+										if cmd.Process != nil {
+											if signalErr := cmd.Process.Signal(watchSignal); signalErr != nil {
+												fmt.Fprintf(c.Stderr, "berglas: watch: failed to signal command: %s\n", signalErr)
+											}
+										}
+										continue
+									}
+
+									fmt.Fprintf(c.Stderr, "berglas: watch: secrets changed, restarting child\n")
+									close(watchStopCh)
+									stopGracefully(cmd, stopSignal, execWatchGrace)
+									return
+								}
+							}
+						}()
+					}
+				}
+
+				waitErr := cmd.Wait()
+				close(doneCh)
+				close(watchDoneCh)
+
+				watchTriggered = false
+				select {
+				case <-watchStopCh:
+					watchTriggered = true
+				default:
+				}
+
+				if waitErr != nil {
+					if terr, ok := waitErr.(*exec.ExitError); ok && terr.ProcessState != nil {
+						return terr.ProcessState.ExitCode(), errors.Wrap(terr, "process exited non-zero"), watchTriggered, nil
+					}
+					return 0, nil, false, misuseError(waitErr)
+				}
+				return 0, nil, watchTriggered, nil
+			}
+
+			if !execSupervise {
+				code, exitErr, _, err := runChild()
+				if err != nil {
+					return err
+				}
+				if exitErr != nil {
+					return exitWithCode(code, exitErr)
+				}
+				return nil
+			}
+
+			var restarts int
+			for {
+				code, exitErr, watchTriggered, err := runChild()
+				if err != nil {
+					return err
+				}
+				if exitErr == nil {
+					return nil
+				}
+				if watchTriggered {
+					fmt.Fprintf(c.Stderr, "berglas: watch: restarting child with refreshed secrets\n")
+					continue
+				}
+				if !execRestartOnFailure {
+					return exitWithCode(code, exitErr)
+				}
+				if execMaxRestarts >= 0 && restarts >= execMaxRestarts {
+					return exitWithCode(code, errors.Wrap(exitErr, "max restarts exhausted"))
+				}
+
+				restarts++
+				fmt.Fprintf(c.Stderr, "berglas: %s, restarting in %s (attempt %d)\n", exitErr, execBackoff, restarts)
+				time.Sleep(execBackoff)
+			}
+		},
+	}
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().BoolVar(&execLocal, "local", false,
+		"Parse local environment variables for secrets instead of querying the Cloud APIs")
+	execCmd.Flags().StringVar(&execMapFile, "map", "",
+		"Path to a file declaring ENV_NAME=berglas://... pairs to resolve, instead of scanning "+
+			"the runtime platform's environment variables (mutually exclusive with --local)")
+	execCmd.Flags().BoolVar(&execSupervise, "supervise", false,
+		"Act as a minimal supervisor for the child process instead of exiting alongside it")
+	execCmd.Flags().BoolVar(&execRestartOnFailure, "restart-on-failure", false,
+		"Restart the child and re-resolve secrets when it exits non-zero (requires --supervise)")
+	execCmd.Flags().IntVar(&execMaxRestarts, "max-restarts", -1,
+		"Maximum number of times to restart the child before giving up; -1 means unlimited")
+	execCmd.Flags().DurationVar(&execBackoff, "backoff", 1*time.Second,
+		"How long to wait before restarting the child")
+	execCmd.Flags().StringArrayVar(&execSecretFiles, "secret-file", nil,
+		"Materialize a resolved secret to a file instead of an environment variable, "+
+			"in PATH=berglas://... format; may be repeated")
+	execCmd.Flags().BoolVar(&execKeepFiles, "keep-files", false,
+		"Do not shred and remove files written by --secret-file when the child exits")
+	execCmd.Flags().StringVar(&execPins, "pins", "",
+		"Path to a lock file written by \"berglas pin\"; resolve every reference to the "+
+			"generation it pinned instead of whatever is live")
+	execCmd.Flags().BoolVar(&execWatch, "watch", false,
+		"Poll resolved secrets every --watch-interval and act on --on-change if any changed")
+	execCmd.Flags().DurationVar(&execWatchInterval, "watch-interval", 30*time.Second,
+		"How often to check whether a watched secret has changed")
+	execCmd.Flags().StringVar(&execOnChange, "on-change", "restart",
+		"What to do when --watch detects a changed secret: \"restart\" (requires --supervise) "+
+			"or a signal name such as \"SIGHUP\" to send to the child without restarting it")
+	execCmd.Flags().DurationVar(&execWatchGrace, "watch-grace", 10*time.Second,
+		"How long to wait after asking the child to stop for --on-change=restart before killing it")
+	execCmd.Flags().StringVar(&execMissing, "missing", "fail",
+		"What to do when a reference fails to resolve: \"fail\" (the default) aborts before the "+
+			"child is spawned, \"warn\" logs a warning and continues without it, and \"skip\" "+
+			"continues without it silently")
+	execCmd.Flags().IntVar(&execResolveConcurrency, "resolve-concurrency", 10,
+		"Maximum number of references to resolve at once")
+	execCmd.Flags().StringVar(&execMetricsAddr, "metrics-addr", "",
+		"If given, serve Prometheus metrics and health checks on this address for the life of the child process")
+	execCmd.Flags().StringVar(&execCacheDir, "cache-dir", "",
+		"If given, cache resolved secrets in this directory, encrypted at rest, and fall back to the "+
+			"last cached value (see --max-stale) when a secret cannot be resolved live, to survive a "+
+			"Cloud Storage or Cloud KMS outage across a restart")
+	execCmd.Flags().DurationVar(&execMaxStale, "max-stale", 24*time.Hour,
+		"With --cache-dir, the oldest a cached secret may be before it is no longer used as a fallback")
+	execCmd.Flags().StringVar(&execUser, "user", "",
+		"Run the child as this user (a name or a uid) instead of berglas's own user; "+
+			"if --group is not also given, the user's primary group is used")
+	execCmd.Flags().StringVar(&execGroup, "group", "",
+		"Run the child as this group (a name or a gid) instead of berglas's own group")
+	execCmd.Flags().StringVar(&execChdir, "chdir", "",
+		"Working directory for the child")
+	execCmd.Flags().StringVar(&execUmask, "umask", "",
+		"Octal umask for the child, e.g. \"0027\"")
+	execCmd.Flags().StringVar(&execForwardSignals, "forward-signals", "",
+		"Comma-separated list of signal names to forward to the child as-is, e.g. \"HUP,USR1\"; "+
+			"defaults to a safe subset that excludes signals such as SIGCHLD and SIGURG. "+
+			"INT and TERM are always handled via --stop-signal instead of forwarded raw")
+	execCmd.Flags().StringVar(&execStopSignal, "stop-signal", "",
+		"Signal to send the child, instead of forwarding INT/TERM as-is, when berglas is asked "+
+			"to stop; defaults to the platform's terminate signal (SIGTERM on POSIX)")
+	execCmd.Flags().DurationVar(&execStopTimeout, "stop-timeout", 10*time.Second,
+		"How long to wait after --stop-signal before killing the child")
+	execCmd.Flags().BoolVar(&execTTY, "tty", false,
+		"Allocate a pseudo-terminal for the child, so interactive programs behave as if run "+
+			"directly instead of piped; only supported on platforms with POSIX process credentials")
+
+	runCmd := &cobra.Command{
+		Use:   "run [-- SUBCOMMAND]",
+		Short: "Spawn an environment with secrets from a declarative config file",
+		Long: strings.Trim(`
+A thin wrapper around "berglas exec" driven by a config file (default
+"berglas.yaml") instead of flags, so the mapping from environment variables
+and file destinations to references can be reviewed and versioned next to
+the app instead of encoded into a deployment's environment:
+
+  env:
+    DATABASE_PASSWORD: berglas://bucket/db-password
+  files:
+    /etc/secrets/tls.key: berglas://bucket/tls-key
+  command: ["myapp", "--flag"]
+
+"env" and "files" behave exactly like "exec --map" and repeated
+"exec --secret-file", respectively - either or both may be omitted. The
+command to run is taken from the arguments after "--" if given, otherwise
+from "command" in the config file; at least one is required.
+`, "\n"),
+		Example: strings.Trim(`
+  # Resolve secrets from ./berglas.yaml and run the command it declares
+  berglas run
+
+  # Use a config file elsewhere, overriding its declared command
+  berglas run --config config/secrets.yaml -- myapp --with-args
+`, "\n"),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			cfg, err := loadRunConfig(runConfigPath)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			cmdArgs := args
+			if len(cmdArgs) == 0 {
+				cmdArgs = cfg.Command
+			}
+			if len(cmdArgs) == 0 {
+				return misuseError(errors.New(
+					"no command given: pass it after \"--\" or set \"command\" in the config file"))
+			}
+
+			if len(cfg.Env) > 0 {
+				f, err := ioutil.TempFile("", "berglas-run-*.map")
+				if err != nil {
+					return misuseError(errors.Wrap(err, "failed to create a temporary map file"))
+				}
+				defer os.Remove(f.Name())
+
+				if _, err := f.Write(renderEnvMapFile(cfg.Env)); err != nil {
+					f.Close()
+					return misuseError(errors.Wrap(err, "failed to write a temporary map file"))
+				}
+				if err := f.Close(); err != nil {
+					return misuseError(err)
+				}
+				execMapFile = f.Name()
+			}
+			execSecretFiles = secretFilePairs(cfg.Files)
+
+			return execCmd.RunE(execCmd, cmdArgs)
+		},
+	}
+	rootCmd.AddCommand(runCmd)
+	runCmd.Flags().StringVar(&runConfigPath, "config", "berglas.yaml",
+		"Path to the declarative secrets mapping file")
+
+	envCmd := &cobra.Command{
+		Use:   "env",
+		Short: "Resolve secrets and print the environment instead of spawning a command",
+		Long: strings.Trim(`
+Resolves berglas references the same way "berglas exec" does - from
+"--map", "--local", or by detecting and querying the runtime platform - but
+prints the result instead of using it to launch a child process, so it can
+be inspected, piped into another tool, or eval'd into the current shell.
+`, "\n"),
+		Example: strings.Trim(`
+  # Print every resolved reference the runtime platform reports
+  berglas env
+
+  # Populate the current shell with secrets declared in a map file
+  eval "$(berglas env --map secrets.map --format export)"
+
+  # Emit resolved secrets as JSON for another tool to consume
+  berglas env --format json
+`, "\n"),
+		Args: cobra.NoArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			if envMapFile != "" && envLocal {
+				return misuseError(errors.New("--map and --local are mutually exclusive"))
+			}
+			switch envMissing {
+			case "fail", "warn", "skip":
+				// ok
+			default:
+				return misuseError(errors.Errorf("invalid --missing value %q: expected fail, warn, or skip", envMissing))
+			}
+			if envResolveConcurrency < 1 {
+				return misuseError(errors.New("--resolve-concurrency must be at least 1"))
+			}
+			switch envFormat {
+			case "dotenv", "export", "json":
+				// ok
+			default:
+				return misuseError(errors.Errorf("invalid --format value %q: expected dotenv, export, or json", envFormat))
+			}
+
+			var pins map[string]int64
+			if envPins != "" {
+				pins, err = c.readPinsFile(envPins)
+				if err != nil {
+					return misuseError(err)
+				}
+			}
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			ec, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(err)
+			}
+
+			env, err := c.resolveEnvironment(ctx, client, ec, envResolveOptions{
+				MapFile:            envMapFile,
+				Local:              envLocal,
+				Pins:               pins,
+				Missing:            envMissing,
+				ResolveConcurrency: envResolveConcurrency,
+				LogSource:          "env",
+			})
+			if err != nil {
+				return err
+			}
+
+			switch envFormat {
+			case "export":
+				fmt.Fprintf(c.Stdout, "%s", renderEnvExport(env))
+			case "json":
+				return c.writeStructuredOutput("json", envPairs(env))
+			default:
+				fmt.Fprintf(c.Stdout, "%s", renderEnvDotenv(env))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().BoolVar(&envLocal, "local", false,
+		"Parse local environment variables for secrets instead of querying the Cloud APIs")
+	envCmd.Flags().StringVar(&envMapFile, "map", "",
+		"Path to a file declaring ENV_NAME=berglas://... pairs to resolve, instead of scanning "+
+			"the runtime platform's environment variables (mutually exclusive with --local)")
+	envCmd.Flags().StringVar(&envPins, "pins", "",
+		"Path to a lock file written by \"berglas pin\"; resolve every reference to the "+
+			"generation it pinned instead of whatever is live")
+	envCmd.Flags().StringVar(&envMissing, "missing", "fail",
+		"What to do when a reference fails to resolve: \"fail\" (the default) aborts, \"warn\" "+
+			"logs a warning and continues without it, and \"skip\" continues without it silently")
+	envCmd.Flags().IntVar(&envResolveConcurrency, "resolve-concurrency", 10,
+		"Maximum number of references to resolve at once")
+	envCmd.Flags().StringVar(&envFormat, "format", "dotenv",
+		"Output format: \"dotenv\" (NAME=value), \"export\" (shell export statements), or \"json\"")
+
+	renderCmd := &cobra.Command{
+		Use:   "render TEMPLATE",
+		Short: "Render a Go template, resolving secrets into a config file",
+		Long: strings.Trim(`
+Renders TEMPLATE as a Go text/template, with a "secret" function that
+resolves a berglas reference to its plaintext, plus a small set of
+sprig-style string helpers (default, upper, lower, trim, trimPrefix,
+trimSuffix, replace, contains, quote, indent, nindent, b64enc, b64dec).
+
+Many applications consume a config file rather than environment variables;
+"render" avoids hand-rolling an envsubst pipeline to get secrets into one.
+`, "\n"),
+		Example: strings.Trim(`
+  # Render a config file, resolving every "secret" call in it
+  berglas render nginx.conf.tmpl --output /etc/nginx/nginx.conf
+
+  # A template referencing "berglas://bucket/db-password"
+  # password = {{ secret "berglas://bucket/db-password" | quote }}
+
+  # Write the rendered output to stdout instead of a file
+  berglas render config.tmpl
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			_, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			var pins map[string]int64
+			if renderPins != "" {
+				pins, err = c.readPinsFile(renderPins)
+				if err != nil {
+					return misuseError(err)
+				}
+			}
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			ec, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(err)
+			}
+
+			templatePath := args[0]
+			data, err := ioutil.ReadFile(templatePath)
+			if err != nil {
+				return misuseError(errors.Wrapf(err, "failed to read template %s", templatePath))
+			}
+
+			resolve := func(ref string) (string, error) {
+				plaintext, err := c.resolveWithPins(ec, ctx, pins, ref)
+				if err != nil {
+					return "", apiError(err)
+				}
+				return string(plaintext), nil
+			}
+
+			rendered, err := renderTemplate(templatePath, data, resolve)
+			if err != nil {
+				// Unwrap a *template.ExecError to surface the underlying
+				// resolution failure's exit code instead of always exiting 1.
+				if uw, ok := err.(interface{ Unwrap() error }); ok {
+					if terr, ok := uw.Unwrap().(*exitError); ok {
+						return terr
+					}
+				}
+				return misuseError(err)
+			}
+
+			if renderOutput == "" {
+				_, err := c.Stdout.Write(rendered)
+				return err
+			}
+			if err := ioutil.WriteFile(renderOutput, rendered, 0600); err != nil {
+				return misuseError(errors.Wrapf(err, "failed to write %s", renderOutput))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(renderCmd)
+	renderCmd.Flags().StringVarP(&renderOutput, "output", "o", "",
+		"Path to write the rendered template to instead of stdout")
+	renderCmd.Flags().StringVar(&renderPins, "pins", "",
+		"Path to a lock file written by \"berglas pin\"; resolve every reference to the "+
+			"generation it pinned instead of whatever is live")
+
+	resolveFileCmd := &cobra.Command{
+		Use:   "resolve-file FILE",
+		Short: "Resolve berglas references embedded in a JSON or YAML file, in place",
+		Long: strings.Trim(`
+Walks the JSON or YAML document at FILE, replaces any string value that is
+a berglas reference with its decrypted plaintext, and overwrites FILE with
+the result. The document format is guessed from FILE's extension unless
+--format is given.
+
+This lets checked-in Helm values files and app configs keep encrypted
+references in version control, resolved into plaintext only where they're
+actually consumed.
+`, "\n"),
+		Example: strings.Trim(`
+  # Resolve every "berglas://..." reference embedded in values.yaml
+  berglas resolve-file values.yaml
+
+  # The document format can't be guessed from an unusual extension
+  berglas resolve-file config.txt --format json
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			_, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			path := args[0]
+			format := resolveFileFormat
+			if format == "" {
+				format, err = detectFileFormat(path)
+				if err != nil {
+					return misuseError(err)
+				}
+			}
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			ec, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(err)
+			}
+
+			info, err := os.Stat(path)
+			if err != nil {
+				return misuseError(errors.Wrapf(err, "failed to stat %s", path))
+			}
+
+			data, err := ioutil.ReadFile(path)
+			if err != nil {
+				return misuseError(errors.Wrapf(err, "failed to read %s", path))
+			}
+
+			resolved, err := ec.ResolveFile(ctx, &berglas.ResolveFileRequest{
+				Data:   data,
+				Format: format,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			if err := writeFileAtomically(path, resolved, info.Mode().Perm()); err != nil {
+				return misuseError(errors.Wrapf(err, "failed to write %s", path))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(resolveFileCmd)
+	resolveFileCmd.Flags().StringVar(&resolveFileFormat, "format", "",
+		"Document format: \"json\" or \"yaml\"; guessed from FILE's extension if omitted")
+
+	grantCmd := &cobra.Command{
+		Use:   "grant SECRET",
+		Short: "Grant access to a secret",
+		Long: strings.Trim(`
+Grant IAM access to an existing secret for a given list of members. The secret
+must exist before access can be granted.
+
+When executed, this command grants each specified member two IAM permissions:
+
+  - roles/storage.legacyObjectReader on the Cloud Storage object
+  - roles/cloudkms.cryptoKeyDecrypter on the Cloud KMS crypto key
+
+Members must be specified with their type, for example:
+
+  - domain:mydomain.com
+  - group:group@mydomain.com
+  - serviceAccount:xyz@gserviceaccount.com
+  - user:user@mydomain.com
+
+SECRET may instead be an "sm://project/secret" reference, in which case this
+grants "roles/secretmanager.secretAccessor" on the Secret Manager secret
+instead of the Storage/KMS roles above.
+`, "\n"),
+		Example: strings.Trim(`
+  # Grant access to a user
+  berglas grant my-secrets/api-key --member user:user@mydomain.com
+
+  # Grant access to a Secret Manager secret
+  berglas grant sm://my-project/api-key --member user:user@mydomain.com
+
+  # Grant access to service account
+  berglas grant my-secrets/api-key \
+    --member serviceAccount:sa@project.iam.gserviceaccount.com
+
+  # Add multiple members
+  berglas grant my-secrets/api-key \
+    --member user:user@mydomain.com \
+    --member serviceAccount:sa@project.iam.gserviceaccount.com
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			sort.Strings(members)
+
+			if berglas.IsSMReference(args[0]) {
+				smRef, err := berglas.ParseSMReference(args[0])
+				if err != nil {
+					return misuseError(err)
+				}
+
+				if dryRun {
+					fmt.Fprintf(c.Stdout, "Would grant permission on [%s] to: \n- %s\n",
+						smRef.Secret(), strings.Join(members, "\n- "))
+					return nil
+				}
+
+				if err := client.SMGrant(ctx, &berglas.SMGrantRequest{
+					Project: smRef.Project(),
+					Secret:  smRef.Secret(),
+					Members: members,
+				}); err != nil {
+					return apiError(err)
+				}
+
+				fmt.Fprintf(c.Stdout, "Successfully granted permission on [%s] to: \n- %s\n",
+					smRef.Secret(), strings.Join(members, "\n- "))
+				return nil
+			}
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if dryRun {
+				current, err := client.IAMMembers(ctx, bucket, object)
+				if err != nil {
+					return apiError(err)
+				}
+				toGrant := membersNotIn(current, members)
+				if len(toGrant) == 0 {
+					fmt.Fprintf(c.Stdout, "No changes: [%s] already grants permission to all of: \n- %s\n",
+						object, strings.Join(members, "\n- "))
+					return nil
+				}
+				fmt.Fprintf(c.Stdout, "Would grant permission on [%s] to: \n- %s\n",
+					object, strings.Join(toGrant, "\n- "))
+				return nil
+			}
+
+			if err := client.Grant(ctx, &berglas.GrantRequest{
+				Bucket:  bucket,
+				Object:  object,
+				Members: members,
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully granted permission on [%s] to: \n- %s\n",
+				object, strings.Join(members, "\n- "))
+			return nil
+		},
+	}
+	rootCmd.AddCommand(grantCmd)
+	grantCmd.Flags().StringSliceVar(&members, "member", nil,
+		"Member to add")
+
+	keygenCmd := &cobra.Command{
+		Use:   "keygen",
+		Short: "Generate a local development key",
+		Long: strings.Trim(`
+Generates a random local development key and stores it with --out or
+--keyring. The resulting "local-key://" or "keyring-key://" value can be
+passed as the --key value to create, update, and edit so the full berglas
+workflow can be run against a real or fake bucket without any Cloud KMS
+access.
+
+--keyring stores the key material in the OS keychain (Keychain on macOS,
+Credential Manager on Windows, Secret Service/D-Bus on Linux) instead of a
+plaintext file, at the cost of only being usable on the machine that
+generated it. --out writes a plaintext file that can be copied between
+machines or checked into a development-only secrets store.
+
+Secrets encrypted with a local key are NOT protected by Cloud KMS: anyone who
+can read the key material can decrypt them. This mode is intended for local
+development only and must never be used in production.
+`, "\n"),
+		Example: strings.Trim(`
+  # Generate a local development key in a plaintext file
+  berglas keygen --out ~/.berglas/dev.key
+
+  # Generate a local development key in the OS keychain instead
+  berglas keygen --keyring dev
+
+  # Create a secret using the local key instead of Cloud KMS
+  berglas create my-secrets/api-key abcd1234 --key local-key://$HOME/.berglas/dev.key
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if (keyOut == "") == (keyKeyring == "") {
+				return misuseError(errors.New("exactly one of --out or --keyring is required"))
+			}
+
+			key, err := berglas.GenerateLocalKey()
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to generate local key"))
+			}
+
+			fmt.Fprintf(c.Stderr, "WARNING: local development keys are not protected by Cloud KMS "+
+				"and must never be used in production.\n\n")
+
+			if keyKeyring != "" {
+				if err := berglas.WriteKeyringKey(keyKeyring, key); err != nil {
+					return apiError(errors.Wrap(err, "failed to write local key"))
+				}
+
+				fmt.Fprintf(c.Stdout, "Successfully generated local development key %q in the OS keychain\n\n", keyKeyring)
+				fmt.Fprintf(c.Stdout, "To use it, pass the following as --key:\n\n    %s%s\n",
+					berglas.KeyringKeyPrefix, keyKeyring)
+				return nil
+			}
+
+			if err := berglas.WriteLocalKeyFile(keyOut, key); err != nil {
+				return apiError(errors.Wrap(err, "failed to write local key"))
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully generated local development key at %s\n\n", keyOut)
+			fmt.Fprintf(c.Stdout, "To use it, pass the following as --key:\n\n    %s%s\n",
+				berglas.LocalKeyPrefix, keyOut)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(keygenCmd)
+	keygenCmd.Flags().StringVar(&keyOut, "out", "",
+		"Path at which to write the generated local development key")
+	keygenCmd.Flags().StringVar(&keyKeyring, "keyring", "",
+		"Name under which to store the generated local development key in the OS keychain")
+
+	listCmd := &cobra.Command{
+		Use:   "list [BUCKET...]",
+		Short: "List secrets in one or more buckets",
+		Long: strings.Trim(`
+Lists secrets by name in the given Google Cloud Storage buckets. It does not
+read their values, only their key names. To retrieve the value of a secret,
+use the "access" command instead.
+
+Multiple buckets may be given, and are listed concurrently; the output gains
+a BUCKET column to distinguish them. With "-project", every bucket in the
+project labeled as berglas-managed (i.e. created by "berglas bootstrap") is
+listed as well, which is useful for org-wide secret inventory without having
+to know every bucket name up front.
+
+The RETAIN UNTIL column shows the date, if any, before which "create" or
+"update --retain-until" has blocked the secret from being deleted or
+overwritten.
+
+The LAST ACCESSED column shows the date a secret was last read via "access"
+or "exec", if known; "never" means it has not been read since last-accessed
+tracking began, which includes every secret read before this feature
+existed. Last-accessed times lag real reads by up to an hour, since writes
+to it are sampled to limit metadata churn on hot secrets.
+
+With "-unused-for", only secrets that have never been read, or have not
+been read for at least that long, are shown - useful for finding and
+retiring secrets nothing reads anymore. The duration may be a plain day
+count like "180d" or a Go duration like "4320h".
+`, "\n"),
+		Example: strings.Trim(`
+  # List all secrets in the bucket "my-secrets"
+  berglas list my-secrets
+
+  # List all secrets with names starting with "secret" in the bucket "my-secrets"
+  berglas list my-secrets --prefix secret
+
+  # List all generations of all secrets in the bucket "my-secrets"
+  berglas list my-secrets --all-generations
+
+  # List secrets across two buckets as JSON
+  berglas list bucketA bucketB --format json
+
+  # List secrets as YAML, for piping into another tool that expects it
+  berglas list my-secrets --format yaml
+
+  # List secrets in every berglas-managed bucket in a project
+  berglas list --project my-project
+
+  # Find secrets nothing has read in the last 180 days
+  berglas list my-secrets --unused-for 180d
+`, "\n"),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			buckets := make([]string, 0, len(args))
+			seen := make(map[string]bool, len(args))
+			for _, a := range args {
+				b := strings.TrimPrefix(a, "gs://")
+				if !seen[b] {
+					seen[b] = true
+					buckets = append(buckets, b)
+				}
+			}
+
+			if listProject != "" {
+				discovered, err := client.ListBuckets(ctx, &berglas.ListBucketsRequest{
+					Project: listProject,
+				})
+				if err != nil {
+					return apiError(err)
+				}
+				for _, b := range discovered {
+					if !seen[b] {
+						seen[b] = true
+						buckets = append(buckets, b)
+					}
+				}
+			}
+
+			if len(buckets) == 0 {
+				return misuseError(errors.New("must specify at least one bucket or --project"))
+			}
+
+			switch listFormat {
+			case "", "table", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown format %q", listFormat))
+			}
+
+			var unusedCutoff time.Time
+			if listUnusedFor != "" {
+				d, err := parseUnusedFor(listUnusedFor)
+				if err != nil {
+					return misuseError(err)
+				}
+				unusedCutoff = c.Now().Add(-d)
+			}
+
+			type bucketSecret struct {
+				Bucket       string    `json:"bucket" yaml:"bucket"`
+				Name         string    `json:"name" yaml:"name"`
+				Generation   int64     `json:"generation" yaml:"generation"`
+				UpdatedAt    time.Time `json:"updated_at" yaml:"updated_at"`
+				RetainUntil  time.Time `json:"retain_until,omitempty" yaml:"retain_until,omitempty"`
+				LastAccessed time.Time `json:"last_accessed,omitempty" yaml:"last_accessed,omitempty"`
+			}
+
+			var (
+				mu      sync.Mutex
+				results []bucketSecret
+				listErr error
+			)
+
+			// List buckets concurrently, bounded the same way the library bounds
+			// its own per-object worker pools.
+			ws := runtime.NumCPU()
+			if ws > len(buckets) {
+				ws = len(buckets)
+			}
+			sem := make(chan struct{}, ws)
+			var wg sync.WaitGroup
+
+			for _, bucket := range buckets {
+				bucket := bucket
+				wg.Add(1)
+				sem <- struct{}{}
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					list, err := client.List(ctx, &berglas.ListRequest{
+						Bucket:      bucket,
+						Prefix:      listPrefix,
+						Generations: listGenerations,
+					})
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						if listErr == nil {
+							listErr = errors.Wrapf(err, "failed to list bucket %s", bucket)
+						}
+						return
+					}
+					for _, s := range list.Secrets {
+						if !unusedCutoff.IsZero() && !s.LastAccessed.IsZero() && s.LastAccessed.After(unusedCutoff) {
+							continue
+						}
+						results = append(results, bucketSecret{
+							Bucket:       bucket,
+							Name:         s.Name,
+							Generation:   s.Generation,
+							UpdatedAt:    s.UpdatedAt,
+							RetainUntil:  s.RetainUntil,
+							LastAccessed: s.LastAccessed,
+						})
+					}
+				}()
+			}
+			wg.Wait()
+
+			if listErr != nil {
+				return apiError(listErr)
+			}
+
+			sort.Slice(results, func(i, j int) bool {
+				if results[i].Bucket != results[j].Bucket {
+					return results[i].Bucket < results[j].Bucket
+				}
+				return results[i].Name < results[j].Name
+			})
+
+			if listFormat == "json" || listFormat == "yaml" {
+				return c.writeStructuredOutput(listFormat, results)
+			}
+
+			if len(results) == 0 {
+				return nil
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "BUCKET\tNAME\tGENERATION\tUPDATED\tRETAIN UNTIL\tLAST ACCESSED\n")
+			for _, s := range results {
+				retainUntil := "-"
+				if !s.RetainUntil.IsZero() {
+					retainUntil = s.RetainUntil.Local().Format("2006-01-02")
+				}
+				lastAccessed := "never"
+				if !s.LastAccessed.IsZero() {
+					lastAccessed = s.LastAccessed.Local().Format("2006-01-02")
+				}
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\n", s.Bucket, s.Name, s.Generation, s.UpdatedAt.Local(), retainUntil, lastAccessed)
+			}
+			tw.Flush()
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(listCmd)
+	listCmd.Flags().BoolVar(&listGenerations, "all-generations", false,
+		"List all versions of secrets")
+	listCmd.Flags().StringVar(&listPrefix, "prefix", "",
+		"List secrets that match prefix")
+	listCmd.Flags().StringVar(&listProject, "project", "",
+		"Also list every berglas-managed bucket in this GCP project")
+	listCmd.Flags().StringVar(&listFormat, "format", "table",
+		"Output format: \"table\", \"json\", or \"yaml\"")
+	listCmd.Flags().StringVar(&listUnusedFor, "unused-for", "",
+		"Only show secrets that have never been read, or not been read for at least this long, "+
+			"e.g. \"180d\" or \"4320h\"")
+
+	versionsCmd := &cobra.Command{
+		Use:   "versions SECRET",
+		Short: "List a single secret's generation history",
+		Long: strings.Trim(`
+Lists every Cloud Storage generation of a single secret, newest first, with
+its created and updated timestamps, encrypted object size, and the KMS key
+used to encrypt it.
+
+This is "list --all-generations" narrowed to one secret, so finding a
+secret's history does not mean listing and grepping the whole bucket.
+`, "\n"),
+		Example: strings.Trim(`
+  # Show every generation of "api-key" in the bucket "my-secrets"
+  berglas versions my-secrets/api-key
+
+  # Show it as JSON
+  berglas versions my-secrets/api-key --format json
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			switch versionsFormat {
+			case "", "table", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown format %q", versionsFormat))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			list, err := client.List(ctx, &berglas.ListRequest{
+				Bucket:      bucket,
+				Prefix:      object,
+				Generations: true,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			type version struct {
+				Generation int64     `json:"generation" yaml:"generation"`
+				CreatedAt  time.Time `json:"created_at" yaml:"created_at"`
+				UpdatedAt  time.Time `json:"updated_at" yaml:"updated_at"`
+				Size       int64     `json:"size" yaml:"size"`
+				KMSKey     string    `json:"kms_key" yaml:"kms_key"`
+			}
+
+			var versions []version
+			for _, s := range list.Secrets {
+				if s.Name == object {
+					versions = append(versions, version{
+						Generation: s.Generation,
+						CreatedAt:  s.CreatedAt,
+						UpdatedAt:  s.UpdatedAt,
+						Size:       s.Size,
+						KMSKey:     s.KMSKey,
+					})
+				}
+			}
+
+			if len(versions) == 0 {
+				return apiError(errors.Errorf("secret [%s] does not exist", object))
+			}
+
+			if versionsFormat == "json" || versionsFormat == "yaml" {
+				return c.writeStructuredOutput(versionsFormat, versions)
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "GENERATION\tCREATED\tUPDATED\tSIZE\tKMS KEY\n")
+			for _, s := range versions {
+				fmt.Fprintf(tw, "%d\t%s\t%s\t%d\t%s\n",
+					s.Generation, s.CreatedAt.Local(), s.UpdatedAt.Local(), s.Size, s.KMSKey)
+			}
+			tw.Flush()
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(versionsCmd)
+	versionsCmd.Flags().StringVar(&versionsFormat, "format", "table",
+		"Output format: \"table\", \"json\", or \"yaml\"")
+
+	keyReportCmd := &cobra.Command{
+		Use:   "key-report [BUCKET...]",
+		Short: "Report which KMS key and version protects each secret",
+		Long: strings.Trim(`
+Lists every secret's current generation alongside the Cloud KMS key, and
+key version if pinned (see "--key .../cryptoKeyVersions/N" on "create"),
+that protects it.
+
+This is meant for finding stragglers after rotating or disabling a Cloud
+KMS key version: secrets still pinned to the old version, via the KEY
+VERSION column, need to be re-created or updated before that version can
+safely be destroyed. The KEY column alone cannot tell you this, since a
+secret's wrapped DEK stays encrypted under whichever version was primary
+when it was last written, even after the key's primary version moves on.
+
+Secrets with no corresponding Cloud KMS key - encrypted with a local
+development or passphrase key - are listed with "-" in both columns.
+
+As with "list", multiple buckets may be given and are reported on
+concurrently, and "--project" also reports on every berglas-managed bucket
+in a project.
+`, "\n"),
+		Example: strings.Trim(`
+  # Report every secret's KMS key and version in "my-secrets"
+  berglas key-report my-secrets
+
+  # Find every secret still pinned to an old key version, as JSON
+  berglas key-report my-secrets --format json
+
+  # Report across every berglas-managed bucket in a project
+  berglas key-report --project my-project
+`, "\n"),
+		Args: cobra.ArbitraryArgs,
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			buckets := make([]string, 0, len(args))
+			seen := make(map[string]bool, len(args))
+			for _, a := range args {
+				b := strings.TrimPrefix(a, "gs://")
+				if !seen[b] {
+					seen[b] = true
+					buckets = append(buckets, b)
+				}
+			}
+
+			if keyReportProject != "" {
+				discovered, err := client.ListBuckets(ctx, &berglas.ListBucketsRequest{
+					Project: keyReportProject,
+				})
+				if err != nil {
+					return apiError(err)
+				}
+				for _, b := range discovered {
+					if !seen[b] {
+						seen[b] = true
+						buckets = append(buckets, b)
+					}
+				}
+			}
+
+			if len(buckets) == 0 {
+				return misuseError(errors.New("must specify at least one bucket or --project"))
+			}
+
+			switch keyReportFormat {
+			case "", "table", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown format %q", keyReportFormat))
+			}
+
+			type keyReportEntry struct {
+				Bucket        string `json:"bucket" yaml:"bucket"`
+				Name          string `json:"name" yaml:"name"`
+				Generation    int64  `json:"generation" yaml:"generation"`
+				KMSKey        string `json:"kms_key" yaml:"kms_key"`
+				KMSKeyVersion string `json:"kms_key_version,omitempty" yaml:"kms_key_version,omitempty"`
+			}
+
+			var (
+				mu      sync.Mutex
+				results []keyReportEntry
+				repErr  error
+			)
+
+			ws := runtime.NumCPU()
+			if ws > len(buckets) {
+				ws = len(buckets)
+			}
+			sem := make(chan struct{}, ws)
+			var wg sync.WaitGroup
+
+			for _, bucket := range buckets {
+				bucket := bucket
+				wg.Add(1)
+				sem <- struct{}{}
+				go func() {
+					defer wg.Done()
+					defer func() { <-sem }()
+
+					list, err := client.List(ctx, &berglas.ListRequest{Bucket: bucket})
+
+					mu.Lock()
+					defer mu.Unlock()
+					if err != nil {
+						if repErr == nil {
+							repErr = errors.Wrapf(err, "failed to list bucket %s", bucket)
+						}
+						return
+					}
+					for _, s := range list.Secrets {
+						kmsKey := s.KMSKey
+						if kmsKey == "" {
+							kmsKey = "-"
+						}
+						kmsKeyVersion := s.KMSKeyVersion
+						if kmsKeyVersion == "" {
+							kmsKeyVersion = "-"
+						}
+						results = append(results, keyReportEntry{
+							Bucket:        bucket,
+							Name:          s.Name,
+							Generation:    s.Generation,
+							KMSKey:        kmsKey,
+							KMSKeyVersion: kmsKeyVersion,
+						})
+					}
+				}()
+			}
+			wg.Wait()
+
+			if repErr != nil {
+				return apiError(repErr)
+			}
+
+			sort.Slice(results, func(i, j int) bool {
+				if results[i].Bucket != results[j].Bucket {
+					return results[i].Bucket < results[j].Bucket
+				}
+				return results[i].Name < results[j].Name
+			})
+
+			if keyReportFormat == "json" || keyReportFormat == "yaml" {
+				return c.writeStructuredOutput(keyReportFormat, results)
+			}
+
+			if len(results) == 0 {
+				return nil
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "BUCKET\tNAME\tGENERATION\tKMS KEY\tKEY VERSION\n")
+			for _, r := range results {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\n", r.Bucket, r.Name, r.Generation, r.KMSKey, r.KMSKeyVersion)
+			}
+			tw.Flush()
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(keyReportCmd)
+	keyReportCmd.Flags().StringVar(&keyReportFormat, "format", "table",
+		"Output format: \"table\", \"json\", or \"yaml\"")
+	keyReportCmd.Flags().StringVar(&keyReportProject, "project", "",
+		"Also report on every berglas-managed bucket in this GCP project")
+
+	hashCmd := &cobra.Command{
+		Use:   "hash SECRET",
+		Short: "Print a secret's SHA-256 digest",
+		Long: strings.Trim(`
+Accesses a secret and prints the SHA-256 digest of its plaintext, hex
+encoded, instead of the plaintext itself.
+
+This lets two parties confirm they hold the same secret value - for
+example, after a rotation - without either one revealing it to the other.
+
+The digest is always computed fresh from the accessed plaintext, so it
+reflects the secret's actual current value whether or not berglas recorded
+an integrity digest for it at write time.
+`, "\n"),
+		Example: strings.Trim(`
+  # Print the digest of "api-key" in the bucket "my-secrets"
+  berglas hash my-secrets/api-key
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			switch hashOutput {
+			case "", "text", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown output format %q", hashOutput))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			plaintext, err := client.Access(ctx, &berglas.AccessRequest{Bucket: bucket, Object: object})
+			if err != nil {
+				if hashOutput == "json" {
+					return c.apiErrorJSON(bucket+"/"+object, err)
+				}
+				return apiError(err)
+			}
+
+			sum := sha256.Sum256(plaintext)
+			digest := hex.EncodeToString(sum[:])
+
+			switch hashOutput {
+			case "", "text":
+				fmt.Fprintf(c.Stdout, "%s\n", digest)
+			case "json", "yaml":
+				return c.writeStructuredOutput(hashOutput, struct {
+					Bucket string `json:"bucket" yaml:"bucket"`
+					Name   string `json:"name" yaml:"name"`
+					SHA256 string `json:"sha256" yaml:"sha256"`
+				}{
+					Bucket: bucket,
+					Name:   object,
+					SHA256: digest,
+				})
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(hashCmd)
+	hashCmd.Flags().StringVar(&hashOutput, "output", "text",
+		"Output format: \"text\" (just the hex digest), \"json\", or \"yaml\"")
+
+	lintCmd := &cobra.Command{
+		Use:   "lint BUCKET",
+		Short: "Flag secret hygiene issues in a bucket",
+		Long: strings.Trim(`
+Inspects the secrets in a bucket and reports hygiene issues: secrets with no
+retain-until (rotation) policy are always checked.
+
+Pass --decrypt to additionally check for weak values (too short) and values
+reused across more than one secret. This requires decrypting every matching
+secret, so it is opt-in and prompts for confirmation unless --yes is also
+given.
+
+Pass --check-breached (which implies --decrypt) to also check each decrypted
+value against the haveibeenpwned.com Pwned Passwords corpus, using its
+k-anonymity range API - only the first five hex characters of the value's
+SHA-1 hash are ever sent, never the value or the full hash.
+
+The report is sorted most urgent first: critical findings (breached values),
+then warnings (weak or reused values), then informational findings (missing
+rotation policy).
+`, "\n"),
+		Example: strings.Trim(`
+  # Check for missing rotation policies only (no decryption)
+  berglas lint my-secrets
+
+  # Also flag weak and reused values, without being prompted
+  berglas lint my-secrets --decrypt --yes
+
+  # Also check decrypted values against a known breach corpus
+  berglas lint my-secrets --check-breached --yes
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			switch lintFormat {
+			case "", "table", "json", "yaml":
+			default:
+				return misuseError(errors.Errorf("unknown format %q", lintFormat))
+			}
+
+			if lintCheckBreached {
+				lintDecrypt = true
+			}
+
+			if lintDecrypt && !lintYes {
+				fmt.Fprintf(c.Stderr, "This will decrypt every secret in %q matching prefix %q to check its value. Continue? [y/N]: ",
+					args[0], lintPrefix)
+				r := bufio.NewReader(c.Stdin)
+				line, _ := r.ReadString('\n')
+				if answer := strings.ToLower(strings.TrimSpace(line)); answer != "y" && answer != "yes" {
+					return misuseError(errors.New("aborted"))
+				}
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket := strings.TrimPrefix(args[0], "gs://")
+
+			resp, err := client.Lint(ctx, &berglas.LintRequest{
+				Bucket:        bucket,
+				Prefix:        lintPrefix,
+				Decrypt:       lintDecrypt,
+				CheckBreached: lintCheckBreached,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			if lintFormat == "json" || lintFormat == "yaml" {
+				type issue struct {
+					Severity   string `json:"severity" yaml:"severity"`
+					Secret     string `json:"secret" yaml:"secret"`
+					Generation int64  `json:"generation,omitempty" yaml:"generation,omitempty"`
+					Check      string `json:"check" yaml:"check"`
+					Message    string `json:"message" yaml:"message"`
+				}
+
+				issues := make([]issue, 0, len(resp.Issues))
+				for _, iss := range resp.Issues {
+					issues = append(issues, issue{
+						Severity:   iss.Severity,
+						Secret:     iss.Secret,
+						Generation: iss.Generation,
+						Check:      iss.Check,
+						Message:    iss.Message,
+					})
+				}
+
+				return c.writeStructuredOutput(lintFormat, issues)
+			}
+
+			if len(resp.Issues) == 0 {
+				fmt.Fprintln(c.Stdout, "No issues found.")
+				return nil
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "SEVERITY\tSECRET\tCHECK\tMESSAGE\n")
+			for _, iss := range resp.Issues {
+				fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", iss.Severity, iss.Secret, iss.Check, iss.Message)
+			}
+			tw.Flush()
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(lintCmd)
+	lintCmd.Flags().StringVar(&lintPrefix, "prefix", "",
+		"Only lint secrets that match prefix")
+	lintCmd.Flags().BoolVar(&lintDecrypt, "decrypt", false,
+		"Decrypt secrets to also check for weak and reused values")
+	lintCmd.Flags().BoolVar(&lintCheckBreached, "check-breached", false,
+		"Check decrypted values against the haveibeenpwned.com Pwned Passwords corpus (implies --decrypt)")
+	lintCmd.Flags().BoolVar(&lintYes, "yes", false,
+		"Skip the confirmation prompt before decrypting")
+	lintCmd.Flags().StringVar(&lintFormat, "format", "table",
+		"Output format: \"table\", \"json\", or \"yaml\"")
+
+	pinCmd := &cobra.Command{
+		Use:   "pin BUCKET/PREFIX",
+		Short: "Record the current generation of secrets into a lock file",
+		Long: strings.Trim(`
+Records the current generation of every secret matching BUCKET/PREFIX into a
+lock file, for use with "access --pins" and "exec --pins". This lets a
+deployment pin the exact secret generations it was built against, so a
+secret rotated mid-rollout cannot cause some instances to start with one
+value and others with another.
+
+PREFIX may end with "*" to match every secret sharing that prefix, matching
+the same convention as the server's path allowlists; without a trailing
+"*", only a secret with that exact name is matched.
+
+Running "pin" again against the same lock file merges into it, overwriting
+only the entries for secrets matched this time and leaving any other
+entries already in the file untouched - useful for refreshing a subset of a
+larger pins file as individual services redeploy.
+`, "\n"),
+		Example: strings.Trim(`
+  # Pin every secret in "my-secrets" into pins.lock
+  berglas pin --file pins.lock my-secrets/*
+
+  # Pin only secrets under the "prod/" prefix
+  berglas pin --file pins.lock my-secrets/prod/*
+
+  # Pin a single secret by its exact name
+  berglas pin --file pins.lock my-secrets/api-key
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			if pinFilePath == "" {
+				return misuseError(errors.New("missing -f"))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, prefix, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+			prefix = strings.TrimSuffix(prefix, "*")
+
+			if err := c.pinRun(client, ctx, pinFilePath, bucket, prefix); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(c.Stdout, "pinned secrets matching %s/%s* to %s\n", bucket, prefix, pinFilePath)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(pinCmd)
+	pinCmd.Flags().StringVar(&pinFilePath, "file", "",
+		"Path to the pins lock file to write (required)")
+
+	revokeCmd := &cobra.Command{
+		Use:   "revoke SECRET",
+		Short: "Revoke access to a secret",
+		Long: strings.Trim(`
+Revoke IAM access to an existing secret for a given list of members. The secret
+must exist for access to be revoked.
+
+When executed, this command revokes the following IAM permissions for each
+member:
+
+  - roles/storage.legacyObjectReader on the Cloud Storage object
+  - roles/cloudkms.cryptoKeyDecrypter on the Cloud KMS crypto key
+
+If the member is not granted the IAM permissions, no action is taken.
+Specifically, this does not return an error if the member did not originally
+have permission to access the secret.
+
+Members must be specified with their type, for example:
+
+  - domain:mydomain.com
+  - group:group@mydomain.com
+  - serviceAccount:xyz@gserviceaccount.com
+  - user:user@mydomain.com
+`, "\n"),
+		Example: strings.Trim(`
+  # Revoke access from a user
+  berglas revoke my-secrets/api-key --member user:user@mydomain.com
+
+  # Revoke revoke from a service account
+  berglas grant my-secrets/api-key \
+    --member serviceAccount:sa@project.iam.gserviceaccount.com
+
+  # Remove multiple members
+  berglas revoke my-secrets/api-key \
+    --member user:user@mydomain.com \
+    --member serviceAccount:sa@project.iam.gserviceaccount.com
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			sort.Strings(members)
+
+			if dryRun {
+				current, err := client.IAMMembers(ctx, bucket, object)
+				if err != nil {
+					return apiError(err)
+				}
+				toRevoke := membersIn(current, members)
+				if len(toRevoke) == 0 {
+					fmt.Fprintf(c.Stdout, "No changes: [%s] does not grant permission to any of: \n- %s\n",
+						object, strings.Join(members, "\n- "))
+					return nil
+				}
+				fmt.Fprintf(c.Stdout, "Would revoke permission on [%s] from: \n- %s\n",
+					object, strings.Join(toRevoke, "\n- "))
+				return nil
+			}
+
+			if err := client.Revoke(ctx, &berglas.RevokeRequest{
+				Bucket:  bucket,
+				Object:  object,
+				Members: members,
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully revoked permission on [%s] from: \n- %s\n",
+				object, strings.Join(members, "\n- "))
+			return nil
+		},
+	}
+	rootCmd.AddCommand(revokeCmd)
+	revokeCmd.Flags().StringSliceVar(&members, "member", nil,
+		"Member to remove")
+
+	serverCmd := &cobra.Command{
+		Use:   "server",
+		Short: "Run a health-checkable server/agent process",
+		Long: strings.Trim(`
+Runs berglas as a long-lived process exposing HTTP health, readiness, and
+secret access endpoints, intended for use as a sidecar or agent in
+orchestrated environments such as Kubernetes.
+
+The "/healthz" endpoint reports 200 OK as long as the process is alive. The
+"/readyz" endpoint reports 200 OK once a berglas client has been constructed
+successfully, and 503 Service Unavailable until then. The
+"/v1/access/<bucket>/<secret>" endpoint returns the decrypted contents of a
+secret.
+
+By default the access endpoint is unauthenticated. Pass one or more --token
+flags to require a bearer token scoped to a set of secret path prefixes.
+
+The process runs until it receives an interrupt signal.
+`, "\n"),
+		Example: strings.Trim(`
+  # Run the server, exposing health and access endpoints on :8080
+  berglas server
+
+  # Run the server on a custom address
+  berglas server --addr :9090
+
+  # Require a bearer token scoped to a single secret
+  berglas server --token mytoken=my-secrets/api-key
+
+  # Require a bearer token with access to all secrets
+  berglas server --token mytoken=*
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to create berglas client"))
+			}
+
+			srv := server.New(serverAddr)
+
+			if len(serverTokens) > 0 {
+				authz, err := parseTokenAuthorizer(serverTokens)
+				if err != nil {
+					return misuseError(err)
+				}
+				srv.SetAuthorizer(authz)
+			}
+
+			srv.Handle("/v1/access/", func(r *http.Request) string {
+				return strings.TrimPrefix(r.URL.Path, "/v1/access/")
+			}, func(w http.ResponseWriter, r *http.Request) {
+				ref := strings.TrimPrefix(r.URL.Path, "/v1/access/")
+				bucket, object, err := parseRef(ref)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				plaintext, err := client.Access(r.Context(), &berglas.AccessRequest{
+					Bucket: bucket,
+					Object: object,
+				})
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadGateway)
+					return
+				}
+
+				w.Write(plaintext)
+			})
+
+			leases := server.NewLeaseManager(client)
+
+			srv.Handle("/v1/lease/", func(r *http.Request) string {
+				return strings.TrimPrefix(r.URL.Path, "/v1/lease/")
+			}, func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+					return
+				}
+
+				ref := strings.TrimPrefix(r.URL.Path, "/v1/lease/")
+				bucket, object, err := parseRef(ref)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				ttl, err := parseLeaseTTL(r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				lease, err := leases.Acquire(r.Context(), bucket, object, ttl)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadGateway)
+					return
+				}
+
+				writeLeaseJSON(w, lease)
+			})
+
+			srv.Handle("/v1/leases/", func(r *http.Request) string {
+				id := strings.TrimPrefix(r.URL.Path, "/v1/leases/")
+				if lease, ok := leases.Get(id); ok {
+					return lease.Bucket + "/" + lease.Object
+				}
+				return ""
+			}, func(w http.ResponseWriter, r *http.Request) {
+				id := strings.TrimPrefix(r.URL.Path, "/v1/leases/")
+
+				switch r.Method {
+				case http.MethodPost:
+					ttl, err := parseLeaseTTL(r)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusBadRequest)
+						return
+					}
+
+					lease, err := leases.Renew(r.Context(), id, ttl)
+					if err != nil {
+						http.Error(w, err.Error(), http.StatusGone)
+						return
+					}
+
+					writeLeaseJSON(w, lease)
+				case http.MethodDelete:
+					leases.Revoke(id)
+					w.WriteHeader(http.StatusNoContent)
+				case http.MethodGet:
+					lease, ok := leases.Get(id)
+					if !ok {
+						http.Error(w, "lease not found", http.StatusGone)
+						return
+					}
+					select {
+					case <-r.Context().Done():
+					case <-lease.Done():
+					}
+					writeLeaseJSON(w, lease)
+				default:
+					http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+				}
+			})
+
+			srv.SetReady(true)
+
+			fmt.Fprintf(c.Stdout, "Serving health checks, metrics, secret access, and leases on %s\n", serverAddr)
+
+			if err := srv.Start(ctx); err != nil && err != http.ErrServerClosed {
+				return apiError(errors.Wrap(err, "server exited"))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(serverCmd)
+	serverCmd.Flags().StringVar(&serverAddr, "addr", ":8080",
+		"Address on which to serve the health, readiness, metrics, and access endpoints")
+	serverCmd.Flags().StringArrayVar(&serverTokens, "token", nil,
+		"Bearer token and the secret path prefixes it may access, in the "+
+			"format TOKEN=PREFIX[,PREFIX...]. Use PREFIX \"*\" to allow a token to "+
+			"access any secret. May be specified multiple times. If unset, the "+
+			"access endpoint is unauthenticated")
+
+	sidecarCmd := &cobra.Command{
+		Use:   "sidecar",
+		Short: "Serve secrets over HTTP to a co-located container",
+		Long: strings.Trim(`
+Runs berglas as a long-lived process serving decrypted secret values over
+plain HTTP to a container that shares its network (a Kubernetes pod sidecar,
+a Compose service on the same network) but cannot exec berglas itself.
+
+"GET /secret/<bucket>/<secret>" returns the decrypted contents of a secret.
+"/healthz" and "/readyz" behave as in "berglas server".
+
+Callers typically have no credentials to present, so access is scoped by
+path instead of by bearer token: pass one or more --allow
+bucket/prefix[/*] flags to restrict which secrets the endpoint will serve.
+If no --allow flags are given, any caller that can reach the listener may
+read any secret, so --listen should normally be bound to loopback or a
+network only the intended sidecar can reach - berglas has no way to verify
+caller identity over plain TCP without mTLS, which is out of scope here.
+
+Responses are revalidated against the secret's Cloud Storage generation on
+every request and served from an in-memory cache when unchanged, so a
+sidecar that polls frequently only pays for a download and KMS decrypt when
+the secret has actually changed.
+
+The process runs until it receives an interrupt signal.
+`, "\n"),
+		Example: strings.Trim(`
+  # Serve secrets on loopback, readable by any co-located process
+  berglas sidecar --listen 127.0.0.1:8123
+
+  # Restrict the endpoint to a single secret
+  berglas sidecar --listen 127.0.0.1:8123 --allow my-secrets/api-key
+
+  # Restrict the endpoint to every secret under a prefix
+  berglas sidecar --listen 127.0.0.1:8123 --allow my-secrets/*
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			sigCh := make(chan os.Signal, 1)
+			signal.Notify(sigCh, os.Interrupt)
+			go func() {
+				<-sigCh
+				cancel()
+			}()
+
+			opts, err := c.clientOptions(ctx, impersonate, storageEndpoint, kmsEndpoint, billingProject)
+			if err != nil {
+				return err
+			}
+
+			client, err := c.NewClient(ctx, berglas.WithClientOptions(opts...))
+			if err != nil {
+				return apiError(errors.Wrap(err, "failed to create berglas client"))
+			}
+
+			srv := server.New(sidecarListen)
+
+			if len(sidecarAllow) > 0 {
+				srv.SetAuthorizer(server.PathAllowlist(sidecarAllow))
+			}
+
+			cache := newSidecarCache()
+
+			srv.Handle("/secret/", func(r *http.Request) string {
+				return strings.TrimPrefix(r.URL.Path, "/secret/")
+			}, func(w http.ResponseWriter, r *http.Request) {
+				ref := strings.TrimPrefix(r.URL.Path, "/secret/")
+				bucket, object, err := parseRef(ref)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+
+				plaintext, err := cache.get(r.Context(), client, bucket, object)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadGateway)
+					return
+				}
+
+				w.Write(plaintext)
+			})
+
+			srv.SetReady(true)
+
+			fmt.Fprintf(c.Stdout, "Serving health checks, metrics, and secret access on %s\n", sidecarListen)
+
+			if err := srv.Start(ctx); err != nil && err != http.ErrServerClosed {
+				return apiError(errors.Wrap(err, "server exited"))
+			}
+			return nil
+		},
+	}
+	rootCmd.AddCommand(sidecarCmd)
+	sidecarCmd.Flags().StringVar(&sidecarListen, "listen", "127.0.0.1:8123",
+		"Address on which to serve the health, readiness, metrics, and secret endpoints")
+	sidecarCmd.Flags().StringArrayVar(&sidecarAllow, "allow", nil,
+		"Secret path prefix the endpoint may serve, in \"bucket/secret\" or "+
+			"\"bucket/prefix/*\" form. May be specified multiple times. If unset, "+
+			"the endpoint will serve any secret to any caller that can reach it")
+
+	leaseCmd := &cobra.Command{
+		Use:   "lease",
+		Short: "Acquire, renew, or revoke a short-lived secret lease from \"berglas server\"",
+		Long: strings.Trim(`
+Talks to a running "berglas server" process's lease endpoints, bringing
+Vault-style lease semantics to berglas-backed secrets: a lease has an
+opaque ID and an expiry, must be renewed before it runs out, and is killed
+early - server-side, without the client doing anything - if the server
+finds on its periodic recheck that the secret is no longer accessible.
+`, "\n"),
+	}
+	rootCmd.AddCommand(leaseCmd)
+	leaseCmd.PersistentFlags().StringVar(&leaseAddr, "addr", "http://127.0.0.1:8080",
+		"Address of the running \"berglas server\" process")
+	leaseCmd.PersistentFlags().StringVar(&leaseToken, "token", "",
+		"Bearer token to present to the server, if it requires one")
+
+	leaseAcquireCmd := &cobra.Command{
+		Use:   "acquire SECRET",
+		Short: "Acquire a lease on a secret",
+		Long: strings.Trim(`
+Requests a lease on SECRET from the server. The response includes the
+secret's decrypted value, a lease ID, and an expiry; the lease ID is
+required to renew or revoke it later. The server begins periodically
+rechecking access to SECRET in the background as soon as the lease is
+issued, and revokes it early if that recheck ever fails.
+`, "\n"),
+		Example: strings.Trim(`
+  # Acquire a 15 minute lease on "my-secrets/api-key"
+  berglas lease acquire my-secrets/api-key
+
+  # Acquire a 1 hour lease from a server requiring a bearer token
+  berglas lease acquire my-secrets/api-key --ttl 1h --token mytoken
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			ttl := leaseTTL
+			if ttl <= 0 {
+				ttl = defaultLeaseTTL
+			}
+
+			lc := &leaseClient{httpClient: &http.Client{}, addr: leaseAddr, token: leaseToken}
+			lease, err := lc.acquire(context.Background(), bucket, object, ttl)
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "lease: %s\nexpires: %s\nvalue: %s\n",
+				lease.ID, lease.ExpiresAt.Local(), lease.Value)
+			return nil
+		},
+	}
+	leaseCmd.AddCommand(leaseAcquireCmd)
+	leaseAcquireCmd.Flags().DurationVar(&leaseTTL, "ttl", defaultLeaseTTL,
+		"How long the lease should last before it must be renewed")
+
+	leaseRenewCmd := &cobra.Command{
+		Use:   "renew LEASE_ID",
+		Short: "Renew a lease before it expires",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := leaseIDArg(args)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			ttl := leaseTTL
+			if ttl <= 0 {
+				ttl = defaultLeaseTTL
+			}
+
+			lc := &leaseClient{httpClient: &http.Client{}, addr: leaseAddr, token: leaseToken}
+			lease, err := lc.renew(context.Background(), id, ttl)
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "lease: %s\nexpires: %s\n", lease.ID, lease.ExpiresAt.Local())
+			return nil
+		},
+	}
+	leaseCmd.AddCommand(leaseRenewCmd)
+	leaseRenewCmd.Flags().DurationVar(&leaseTTL, "ttl", defaultLeaseTTL,
+		"How much longer the lease should last from now")
+
+	leaseRevokeCmd := &cobra.Command{
+		Use:   "revoke LEASE_ID",
+		Short: "Revoke a lease",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := leaseIDArg(args)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			lc := &leaseClient{httpClient: &http.Client{}, addr: leaseAddr, token: leaseToken}
+			if err := lc.revoke(context.Background(), id); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully revoked lease [%s]\n", id)
+			return nil
+		},
+	}
+	leaseCmd.AddCommand(leaseRevokeCmd)
+
+	leaseWaitCmd := &cobra.Command{
+		Use:   "wait LEASE_ID",
+		Short: "Block until a lease is revoked or expires",
+		Long: strings.Trim(`
+Blocks until the server reports that the lease has been revoked - either
+explicitly or because its periodic access recheck failed - or until the
+lease's own expiry passes, whichever comes first.
+
+This is the proactive-notification half of berglas's lease semantics: a
+process holding a lease can run this in the background and react as soon
+as its access is pulled, instead of only finding out at the next renewal.
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			id, err := leaseIDArg(args)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			lc := &leaseClient{httpClient: &http.Client{}, addr: leaseAddr, token: leaseToken}
+			lease, err := lc.wait(context.Background(), id)
+			if err != nil {
+				return apiError(err)
+			}
+
+			if lease.Revoked {
+				fmt.Fprintf(c.Stdout, "lease [%s] was revoked\n", id)
+			} else {
+				fmt.Fprintf(c.Stdout, "lease [%s] expired without being revoked\n", id)
+			}
+			return nil
+		},
+	}
+	leaseCmd.AddCommand(leaseWaitCmd)
+
+	rotateCmd := &cobra.Command{
+		Use:   "rotate SECRET",
+		Short: "Re-encrypt a secret with the latest KMS key version",
+		Long: strings.Trim(`
+Reads a secret and writes its current plaintext back as a new generation,
+re-wrapping its data encryption key with the latest primary version of its
+Cloud KMS key. Run this after rotating a key so existing secrets are not
+left wrapped under a version that will eventually be disabled.
+
+Pass --key to re-wrap onto a different Cloud KMS key entirely, instead of
+the latest version of the secret's existing key.
+
+With --all, SECRET is instead a bucket name and every secret in it is
+rotated; failures are reported per secret and do not stop the rest of the
+batch.
+
+A first interrupt (Ctrl-C) during --all stops the batch after the secret
+currently in flight finishes, rather than aborting its write, and prints a
+--resume-from checkpoint so the batch can be continued later without
+re-rotating secrets it already processed. A second interrupt aborts
+immediately.
+`, "\n"),
+		Example: strings.Trim(`
+  # Rotate a secret onto the latest version of its current key
+  berglas rotate my-secrets/api-key
+
+  # Rotate a secret onto a different key entirely
+  berglas rotate my-secrets/api-key --key projects/p/locations/global/keyRings/kr/cryptoKeys/k2
+
+  # Rotate every secret in a bucket
+  berglas rotate my-secrets --all
+
+  # Resume a batch that was interrupted after "my-secret-b"
+  berglas rotate my-secrets --all --resume-from my-secret-b
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, stopCh, closer, err := c.clientWithGracefulContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			if rotateAll {
+				bucket := strings.TrimPrefix(args[0], "gs://")
+				return c.rotateAllRun(client, ctx, stopCh, bucket, rotateKey, rotateResumeFrom)
+			}
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Update(ctx, &berglas.UpdateRequest{
+				Bucket: bucket,
+				Object: object,
+				Key:    rotateKey,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully rotated secret [%s] to generation [%d]\n",
+				object, secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(rotateCmd)
+	rotateCmd.Flags().StringVar(&rotateKey, "key", "",
+		"Rotate onto this KMS key instead of the latest version of the secret's current key")
+	rotateCmd.Flags().BoolVar(&rotateAll, "all", false,
+		"Treat SECRET as a bucket name and rotate every secret in it")
+	rotateCmd.Flags().StringVar(&rotateResumeFrom, "resume-from", "",
+		"With --all, skip every secret whose name sorts at or before this checkpoint")
+
+	reencryptCmd := &cobra.Command{
+		Use:   "reencrypt BUCKET",
+		Short: "Re-encrypt every secret in a bucket with its key's current version",
+		Long: strings.Trim(`
+Re-encrypts every secret in BUCKET with the current primary version of its
+existing Cloud KMS key, without changing which key it is wrapped under. Use
+this to catch up secrets that are still wrapped under an old key version
+after that version was rotated.
+
+Pass --prefix to limit this to secrets whose name starts with a given
+prefix, and --concurrency to process more than one secret at a time.
+Failures are reported per secret and do not stop the rest of the batch.
+
+To move secrets onto a different KMS key entirely, use "berglas rotate
+--all --key" instead.
+`, "\n"),
+		Example: strings.Trim(`
+  # Re-encrypt every secret in a bucket
+  berglas reencrypt my-secrets
+
+  # Re-encrypt only secrets under a prefix, 8 at a time
+  berglas reencrypt my-secrets --prefix payments/ --concurrency 8
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket := strings.TrimPrefix(args[0], "gs://")
+			return c.reencryptRun(client, ctx, bucket, reencryptPrefix, reencryptConcurrency)
+		},
+	}
+	rootCmd.AddCommand(reencryptCmd)
+	reencryptCmd.Flags().StringVar(&reencryptPrefix, "prefix", "",
+		"Only re-encrypt secrets whose name starts with this prefix")
+	reencryptCmd.Flags().IntVar(&reencryptConcurrency, "concurrency", 1,
+		"Number of secrets to re-encrypt at once")
+
+	k8sCmd := &cobra.Command{
+		Use:   "k8s",
+		Short: "Adopt or roll back berglas injection on Kubernetes manifests",
+	}
+	rootCmd.AddCommand(k8sCmd)
+
+	k8sAnnotateCmd := &cobra.Command{
+		Use:   "annotate FILE",
+		Short: "Rewrite a manifest so containers with berglas:// secrets resolve them",
+		Long: strings.Trim(`
+Rewrites every container in FILE (a Kubernetes manifest containing one or
+more Pod, Deployment, StatefulSet, DaemonSet, or Job documents) that has a
+berglas:// reference in its environment, so it runs under "berglas exec
+--local --" instead of its original command, and adds the shared init
+container and volume that wrapped containers need to find the berglas
+binary. This is the same rewrite the MutatingWebhookConfiguration in
+examples/kubernetes applies at admission time, expressed as an explicit,
+reviewable diff to a manifest you already manage as YAML.
+
+This edits the file on disk; it does not talk to a cluster. Apply the
+result with your usual "kubectl apply" or GitOps pipeline. Each rewritten
+container's original command is preserved in a
+"berglas.cloud.google.com/original-command" field so "berglas k8s
+deannotate" can undo it later.
+
+By default the rewritten manifest is written back to FILE. Pass --output to
+write it elsewhere (or "-" for stdout) and leave FILE untouched.
+`, "\n"),
+		Example: strings.Trim(`
+  # Rewrite a Deployment manifest in place
+  berglas k8s annotate deploy/envserver.yaml
+
+  # Preview the rewrite without touching the original file
+  berglas k8s annotate deploy/envserver.yaml --output -
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return c.k8sRewriteRun(args[0], k8sOutput, k8sAnnotateManifests)
+		},
+	}
+	k8sCmd.AddCommand(k8sAnnotateCmd)
+	k8sAnnotateCmd.Flags().StringVar(&k8sOutput, "output", "",
+		`Write the rewritten manifest here instead of FILE ("-" for stdout)`)
+
+	k8sDeannotateCmd := &cobra.Command{
+		Use:   "deannotate FILE",
+		Short: "Undo a previous \"berglas k8s annotate\" rewrite",
+		Long: strings.Trim(`
+Reverses "berglas k8s annotate": restores each container's original
+command/args from its "berglas.cloud.google.com/original-command" field, and
+removes the shared init container and volume from any pod template that no
+longer needs them. Containers FILE's manifest never annotated are left
+untouched.
+
+By default the restored manifest is written back to FILE. Pass --output to
+write it elsewhere (or "-" for stdout) and leave FILE untouched.
+`, "\n"),
+		Example: strings.Trim(`
+  # Roll back a previously-annotated Deployment manifest in place
+  berglas k8s deannotate deploy/envserver.yaml
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			return c.k8sRewriteRun(args[0], k8sOutput, k8sDeannotateManifests)
+		},
+	}
+	k8sCmd.AddCommand(k8sDeannotateCmd)
+	k8sDeannotateCmd.Flags().StringVar(&k8sOutput, "output", "",
+		`Write the restored manifest here instead of FILE ("-" for stdout)`)
+
+	aliasCmd := &cobra.Command{
+		Use:   "alias",
+		Short: "Manage stable logical names for secrets",
+		Long: strings.Trim(`
+Aliases let references use a stable logical name for a secret, so the
+underlying object can move or be renamed without updating every berglas://
+reference or "berglas access" invocation that points at it.
+
+Each bucket has its own alias table, itself stored as a secret (see
+"berglas bundle"), mapping alias names to the "bucket/object" they
+currently point at. To use an alias in place of an object name anywhere
+berglas accepts one - "access", "exec", and berglas:// references - prefix
+it with "@", e.g. "my-bucket/@prod-db-pass".
+`, "\n"),
+	}
+	rootCmd.AddCommand(aliasCmd)
+
+	aliasSetCmd := &cobra.Command{
+		Use:   "set ALIAS TARGET",
+		Short: "Create or repoint an alias",
+		Long: strings.Trim(`
+Sets ALIAS to point at TARGET (a "bucket/object" secret ref). The alias is
+stored in TARGET's bucket's alias table, which is created automatically -
+pass --key the first time an alias is set in a given bucket.
+`, "\n"),
+		Example: strings.Trim(`
+  # Point "prod-db-pass" at a secret, creating the alias table
+  berglas alias set prod-db-pass my-bucket/postgres/primary/password --key projects/p/locations/global/keyRings/kr/cryptoKeys/k
+
+  # Repoint it after the underlying secret moves
+  berglas alias set prod-db-pass my-bucket/postgres/replica/password
+
+  # Use the alias anywhere a secret ref is accepted
+  berglas access my-bucket/@prod-db-pass
+`, "\n"),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket, _, err := parseRef(args[1])
+			if err != nil {
+				return misuseError(errors.Wrap(err, "invalid target"))
+			}
+
+			if _, err := client.SetAlias(ctx, &berglas.AliasSetRequest{
+				Bucket: bucket,
+				Key:    key,
+				Name:   args[0],
+				Target: args[1],
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully set alias [%s] to [%s]\n", args[0], args[1])
+			return nil
+		},
+	}
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasSetCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for encryption if the alias table does not already exist")
+
+	aliasDeleteCmd := &cobra.Command{
+		Use:   "delete BUCKET ALIAS",
+		Short: "Remove an alias",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			if _, err := client.DeleteAlias(ctx, &berglas.AliasDeleteRequest{
+				Bucket: args[0],
+				Name:   args[1],
+			}); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully deleted alias [%s]\n", args[1])
+			return nil
+		},
+	}
+	aliasCmd.AddCommand(aliasDeleteCmd)
+
+	aliasListCmd := &cobra.Command{
+		Use:   "list BUCKET",
+		Short: "List every alias defined in a bucket",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			resp, err := client.ListAliases(ctx, &berglas.AliasListRequest{Bucket: args[0]})
+			if err != nil {
+				return apiError(err)
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "ALIAS\tTARGET\n")
+			for name, target := range resp.Aliases {
+				fmt.Fprintf(tw, "%s\t%s\n", name, target)
+			}
+			tw.Flush()
+			return nil
+		},
+	}
+	aliasCmd.AddCommand(aliasListCmd)
+
+	copyCmd := &cobra.Command{
+		Use:   "copy SRC DST",
+		Short: "Copy a secret to a new bucket and/or object",
+		Long: strings.Trim(`
+Decrypts the secret at SRC and re-creates it at DST, a new generation
+encrypted with --key (or SRC's own KMS key, if --key is not given - this
+only works if DST's bucket's key ring can decrypt secrets encrypted with
+SRC's key). This promotes a secret between buckets, for example dev to
+staging to prod, without a separate access-then-create pipeline.
+
+The copy is a new secret: it starts at generation 1 and does not carry over
+SRC's IAM bindings, generation, or retention policy. If DST already exists,
+this fails unless --overwrite is given.
+`, "\n"),
+		Example: strings.Trim(`
+  # Promote a secret from staging to prod
+  berglas copy staging-secrets/api-key prod-secrets/api-key \
+    --key projects/p/locations/global/keyRings/prod/cryptoKeys/prod-key
+
+  # Copy within the same bucket under a new name, replacing it if it exists
+  berglas copy my-secrets/api-key my-secrets/api-key-v2 --overwrite
+`, "\n"),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			srcBucket, srcObject, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(errors.Wrap(err, "invalid source"))
+			}
+
+			dstBucket, dstObject, err := parseRef(args[1])
+			if err != nil {
+				return misuseError(errors.Wrap(err, "invalid destination"))
+			}
+
+			secret, err := client.Copy(ctx, &berglas.CopyRequest{
+				SourceBucket:      srcBucket,
+				SourceObject:      srcObject,
+				DestinationBucket: dstBucket,
+				DestinationObject: dstObject,
+				Key:               key,
+				Overwrite:         copyOverwrite,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully copied secret [%s] to [%s] at generation [%d]\n",
+				args[0], args[1], secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(copyCmd)
+	copyCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to encrypt the copy with, instead of reusing the source secret's key")
+	copyCmd.Flags().BoolVar(&copyOverwrite, "overwrite", false,
+		"Allow replacing an existing secret at the destination")
+
+	moveCmd := &cobra.Command{
+		Use:   "move SRC DST",
+		Short: "Rename a secret",
+		Long: strings.Trim(`
+Renames a secret by copying SRC to DST (see "copy"), verifying the copy
+reads back identically to SRC, and only then deleting SRC. This replaces
+the access, create, delete sequence a manual rename otherwise requires,
+which leaves a window where SRC and DST both exist and nothing has checked
+they actually agree - move refuses to touch SRC at all unless DST is
+confirmed intact first.
+
+Like "copy", the secret at DST is a new generation: it does not carry over
+SRC's generation or retention policy. Pass --keep-iam to carry over SRC's
+object-level IAM bindings and its KMS key's decrypter bindings as well;
+without it, DST starts with only the default access its own bucket and key
+already grant, and any per-secret grants made with "berglas grant" against
+the old name are lost.
+`, "\n"),
+		Example: strings.Trim(`
+  # Rename a secret within the same bucket
+  berglas move my-secrets/old-name my-secrets/new-name
+
+  # Rename a secret into a different bucket, carrying over its IAM grants
+  berglas move my-secrets/api-key other-secrets/api-key --keep-iam
+`, "\n"),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			srcBucket, srcObject, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(errors.Wrap(err, "invalid source"))
+			}
+
+			dstBucket, dstObject, err := parseRef(args[1])
+			if err != nil {
+				return misuseError(errors.Wrap(err, "invalid destination"))
+			}
+
+			secret, err := client.Move(ctx, &berglas.MoveRequest{
+				SourceBucket:      srcBucket,
+				SourceObject:      srcObject,
+				DestinationBucket: dstBucket,
+				DestinationObject: dstObject,
+				Key:               key,
+				KeepIAM:           moveKeepIAM,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully moved secret [%s] to [%s] at generation [%d]\n",
+				args[0], args[1], secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(moveCmd)
+	moveCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to encrypt the destination with, instead of reusing the source secret's key")
+	moveCmd.Flags().BoolVar(&moveKeepIAM, "keep-iam", false,
+		"Carry over the source secret's object-level and KMS key IAM bindings to the destination")
+
+	updateCmd := &cobra.Command{
+		Use:   "update SECRET [DATA]",
+		Short: "Update an existing secret",
+		Long: strings.Trim(`
+Update an existing secret. If the secret does not exist, an error is returned.
+
+Run with --create-if-missing to force creation of the secret if it does not
+already exist.
+
+Run with --patch to apply an RFC 7386 JSON merge patch to the secret's
+existing JSON payload instead of replacing it outright. The patch is applied
+to the generation that was read, and the write is rejected if the secret
+changes in the meantime.
+
+Run with "--retain-until" set to a date (YYYY-MM-DD) to set or extend the
+secret's retention; if omitted, an existing retention is preserved. While a
+secret is retained, any update (including this one) is rejected.
+
+Run with --prompt instead of a DATA argument to type the new secret value
+at an interactive, echo-disabled terminal prompt, entered twice for
+confirmation, instead of leaving it visible in shell history and "ps"
+output. Mutually exclusive with DATA and --patch.
+
+Run with --if-generation and/or --if-metageneration to only apply the
+update if the secret's current generation/metageneration still matches,
+giving compare-and-swap semantics so two concurrent updates (e.g. two
+rotations) racing on the same secret can't silently clobber each other.
+`, "\n"),
+		Example: strings.Trim(`
+  # Update the secret named "api-key" with the contents "new-contents"
+  berglas update my-secrets/api-key new-contents
+
+  # Update the secret named "api-key" with a new KMS encryption key, keeping
+  # the original secret value
+  berglas update my-secrets/api-key --key=...
+
+  # Update the secret named "api-key", creating it if it does not already exist
+  berglas update my-secrets/api-key abcd1234 --create-if-missing --key...
+
+  # Rotate a single field of a JSON secret without re-supplying the rest
+  berglas update my-secrets/api-key --patch '{"password":"new"}'
+
+  # Type the new secret value at a hidden prompt instead of on the command line
+  berglas update my-secrets/api-key --prompt
+
+  # Only update if it's still the generation this caller last read
+  berglas update my-secrets/api-key new-contents --if-generation 1566336000000000
+`, "\n"),
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly && !dryRun {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			c.warnIfLocalKey(key)
+
+			retainUntil, err := parseRetainUntil(updateRetainUntil)
+			if err != nil {
+				return misuseError(err)
+			}
+
+			if updatePatch != "" {
+				if len(args) > 1 {
+					return misuseError(errors.New("cannot specify DATA and --patch together"))
+				}
+				if updatePrompt {
+					return misuseError(errors.New("cannot specify --prompt and --patch together"))
+				}
+				if dryRun {
+					fmt.Fprintf(c.Stdout, "Would apply patch to secret [%s]\n", object)
+					return nil
+				}
+				return c.updatePatchRun(client, ctx, bucket, object, updatePatch)
+			}
+
+			var plaintext []byte
+			switch {
+			case updatePrompt:
+				if len(args) > 1 {
+					return misuseError(errors.New("cannot specify DATA and --prompt together"))
+				}
+				if plaintext, err = c.promptSecret(fmt.Sprintf("Enter new secret value for %s: ", object), true); err != nil {
+					return misuseError(err)
+				}
+			case len(args) > 1:
+				if plaintext, err = c.readData(strings.TrimSpace(args[1])); err != nil {
+					return misuseError(err)
+				}
+			}
+
+			if dryRun {
+				fmt.Fprintf(c.Stdout, "Would update secret [%s]\n", object)
+				return nil
+			}
+
+			secret, err := client.Update(ctx, &berglas.UpdateRequest{
+				Bucket:          bucket,
+				Object:          object,
+				Key:             key,
+				Plaintext:       plaintext,
+				CreateIfMissing: createIfMissing,
+				Generation:      updateIfGeneration,
+				Metageneration:  updateIfMetageneration,
+				Format:          berglas.EnvelopeFormat(envelopeFormat),
+				RetainUntil:     retainUntil,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully updated secret [%s] to generation [%d]\n",
+				object, secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(updateCmd)
+	updateCmd.Flags().BoolVar(&createIfMissing, "create-if-missing", false,
+		"Create the secret if it does not already exist")
+	updateCmd.Flags().StringVar(&key, "key", "",
+		"KMS key to use for re-encryption")
+	updateCmd.Flags().StringVar(&updatePatch, "patch", "",
+		"RFC 7386 JSON merge patch to apply to the secret's existing JSON payload")
+	updateCmd.Flags().StringVar(&envelopeFormat, "format", "",
+		`Envelope format to wrap the DEK in. One of "" (default) or "tink"`)
+	updateCmd.Flags().StringVar(&updateRetainUntil, "retain-until", "",
+		"Set or extend a retention date, in the format YYYY-MM-DD, preventing deletion or overwrite before then")
+	updateCmd.Flags().BoolVar(&updatePrompt, "prompt", false,
+		"Read the new secret value from an interactive, echo-disabled terminal prompt instead of DATA")
+	updateCmd.Flags().Int64Var(&updateIfGeneration, "if-generation", 0,
+		"Only update if the secret's current generation matches this value")
+	updateCmd.Flags().Int64Var(&updateIfMetageneration, "if-metageneration", 0,
+		"Only update if the secret's current metageneration matches this value")
+
+	restoreCmd := &cobra.Command{
+		Use:   "restore SECRET --generation N",
+		Short: "Roll back a secret to a prior generation",
+		Long: strings.Trim(`
+Reads the plaintext of a prior generation of a secret and writes it back as
+a new, live generation.
+
+This is the safe way to undo a bad update: "access --generation N | update"
+round-trips the plaintext through a shell pipeline, which can mangle binary
+values or add a trailing newline. Restore never leaves the process, so the
+restored value is byte-for-byte identical to the prior generation.
+
+The secret's current KMS key and IAM bindings are left in place. Restoring
+does not delete or renumber any generation - "versions" will still show
+both the generation restored from and the new one it was restored to.
+
+Use "berglas versions" to find the generation number to restore.
+`, "\n"),
+		Example: strings.Trim(`
+  # Find the generation to roll back to
+  berglas versions my-secrets/api-key
+
+  # Roll back "api-key" to generation 1566336000000000
+  berglas restore my-secrets/api-key --generation 1566336000000000
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket, object, err := parseRef(args[0])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			secret, err := client.Restore(ctx, &berglas.RestoreRequest{
+				Bucket:     bucket,
+				Object:     object,
+				Generation: restoreGeneration,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully restored secret [%s] from generation [%d] to generation [%d]\n",
+				object, restoreGeneration, secret.Generation)
+			return nil
+		},
+	}
+	rootCmd.AddCommand(restoreCmd)
+	restoreCmd.Flags().Int64Var(&restoreGeneration, "generation", 0,
+		"Generation of the secret to restore")
+	if err := restoreCmd.MarkFlagRequired("generation"); err != nil {
+		panic(err)
+	}
+
+	completionCmd := &cobra.Command{
+		Use:   "completion SHELL",
+		Args:  cobra.ExactArgs(1),
+		Short: "Outputs shell completion for the given shell (bash or zsh)",
+		Long: strings.Trim(
+			`Outputs shell completion for the given shell (bash or zsh)
+
+This depends on the bash-completion package. To install it:
+
+  # Mac OS X
+  brew install bash-completion
+
+  # Debian
+  apt-get install bash-completion
+
+Zsh users may also put the file somewhere on their $fpath, like
+/usr/local/share/zsh/site-functions
+`, "\n"),
+		Example: strings.Trim(`
+  # Enable completion for bash users
+  source <(berglas completion bash)
+
+  # Enable completion for zsh users
+  source <(berglas completion zsh)
+`, "\n"),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch shell := args[0]; shell {
+			case "bash":
+				if err := rootCmd.GenBashCompletion(c.Stdout); err != nil {
+					err = errors.Wrap(err, "failed to generate bash completion")
+					return apiError(err)
+				}
+			case "zsh":
+				if err := rootCmd.GenZshCompletion(c.Stdout); err != nil {
+					err = errors.Wrap(err, "failed to generate zsh completion")
+					return apiError(err)
+				}
+
+				// enable the `+"`"+`source <(berglas completion SHELL)`+"`"+` pattern for zsh
+				if _, err := io.WriteString(c.Stdout, "compdef _berglas berglas\n"); err != nil {
+					err = errors.Wrap(err, "failed to run compdef")
+					return apiError(err)
+				}
+			default:
+				err := errors.Errorf("unknown completion %q", shell)
+				return misuseError(err)
+			}
+
+			return nil
+		},
+	}
+	rootCmd.AddCommand(completionCmd)
+
+	telemetryCmd := &cobra.Command{
+		Use:   "telemetry",
+		Short: "Inspect locally recorded usage telemetry",
+	}
+	rootCmd.AddCommand(telemetryCmd)
+
+	telemetryShowCmd := &cobra.Command{
+		Use:   "show",
+		Short: "Summarize locally recorded command usage and error counts",
+		Long: strings.Trim(`
+Prints a summary of the command usage counts and error classes recorded
+while "--telemetry" was set, read from the local telemetry audit file. This
+file, and this command's output, only ever contain command paths,
+timestamps, and coarse error classes - never secret names or values.
+`, "\n"),
+		Example: strings.Trim(`
+  # Show a summary of locally recorded command usage
+  berglas telemetry show
+`, "\n"),
+		Args: cobra.ExactArgs(0),
+		RunE: func(_ *cobra.Command, _ []string) error {
+			if err := c.telemetryShowRun(); err != nil {
+				return apiError(err)
+			}
+			return nil
+		},
+	}
+	telemetryCmd.AddCommand(telemetryShowCmd)
+
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Export inventory and audit snapshots for dashboards",
+	}
+	rootCmd.AddCommand(reportCmd)
+
+	reportExportCmd := &cobra.Command{
+		Use:   "export BUCKET [BUCKET...] --to DESTINATION",
+		Short: "Export a secret inventory, IAM, and rotation-status snapshot",
+		Long: strings.Trim(`
+Lists every secret in the given buckets (or every berglas-managed bucket in
+--project) and writes one row per secret - its KMS key, generation, update
+and retention timestamps, last-accessed time, and current IAM members - to
+DESTINATION, in a stable schema.
+
+DESTINATION is either "bigquery://project.dataset.table" (rows are streamed
+in with the BigQuery Inserter; the table must already exist with a matching
+or auto-detected schema) or "gs://bucket/object" (written as a single
+newline-delimited JSON object, one line per row).
+
+This command takes one snapshot per run; it does not run on a schedule
+itself. Point cron, Cloud Scheduler, or a CI pipeline at it to produce
+periodic snapshots a dashboard can chart over time.
+`, "\n"),
+		Example: strings.Trim(`
+  # Snapshot two buckets into a BigQuery table
+  berglas report export my-secrets other-secrets --to bigquery://my-project.berglas.inventory
+
+  # Snapshot every berglas-managed bucket in a project to GCS
+  berglas report export --project my-project --to gs://my-reports/berglas/$(date +%Y%m%d).jsonl
+`, "\n"),
+		Args: cobra.MinimumNArgs(0),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if reportTo == "" {
+				return misuseError(errors.New("--to is required"))
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			seen := make(map[string]bool)
+			var buckets []string
+			for _, a := range args {
+				b := strings.TrimPrefix(a, "gs://")
+				if !seen[b] {
+					seen[b] = true
+					buckets = append(buckets, b)
+				}
+			}
+
+			if reportProject != "" {
+				discovered, err := client.ListBuckets(ctx, &berglas.ListBucketsRequest{
+					Project: reportProject,
+				})
+				if err != nil {
+					return apiError(err)
+				}
+				for _, b := range discovered {
+					if !seen[b] {
+						seen[b] = true
+						buckets = append(buckets, b)
+					}
+				}
+			}
+
+			if len(buckets) == 0 {
+				return misuseError(errors.New("must specify at least one bucket or --project"))
+			}
+
+			rows, err := collectReportRows(ctx, client, buckets, c.Now())
+			if err != nil {
+				return apiError(err)
+			}
+
+			if err := c.exportReportRows(ctx, impersonate, reportTo, rows); err != nil {
+				return apiError(err)
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully exported %d row(s) to %s\n", len(rows), reportTo)
+			return nil
+		},
+	}
+	reportCmd.AddCommand(reportExportCmd)
+	reportExportCmd.Flags().StringVar(&reportTo, "to", "",
+		`Export destination: "bigquery://project.dataset.table" or "gs://bucket/object"`)
+	reportExportCmd.Flags().StringVar(&reportProject, "project", "",
+		"Also export every berglas-managed bucket in this GCP project")
+
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Migrate secrets into berglas from another secret store",
+	}
+	rootCmd.AddCommand(importCmd)
+
+	importVaultCmd := &cobra.Command{
+		Use:   "vault BUCKET",
+		Short: "Import secrets from a HashiCorp Vault KV version 2 mount",
+		Long: strings.Trim(`
+Reads every secret at and below --path on a Vault server and writes each one
+to BUCKET as a berglas secret, preserving Vault's "/"-separated path
+hierarchy in the object name. A Vault secret with a single field named
+"value" becomes one object named after its Vault path; a secret with any
+other field or fields becomes one object per field, named
+"<vault-path>/<field>".
+
+--vault-addr and --vault-token default to the VAULT_ADDR and VAULT_TOKEN
+environment variables, so this reuses an existing "vault login" session by
+default.
+
+This is meant as a one-shot migration tool and is safe to interrupt and
+re-run: secrets that already exist in BUCKET are left untouched rather than
+overwritten, and --resume-from skips ahead to the last object name reported
+by a previous, interrupted run.
+`, "\n"),
+		Example: strings.Trim(`
+  # Import every secret under secret/myapp into my-secrets
+  berglas import vault my-secrets --path myapp \
+    --key projects/p/locations/global/keyRings/kr/cryptoKeys/k
+
+  # Import from a non-default KV mount
+  berglas import vault my-secrets --path myapp --mount kv --key local-key:///path/to/key
+
+  # Resume a migration that was interrupted after "myapp/db/password"
+  berglas import vault my-secrets --path myapp --key ... --resume-from myapp/db/password
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket := strings.TrimPrefix(args[0], "gs://")
+
+			result, err := client.ImportVault(ctx, &berglas.ImportVaultRequest{
+				Addr:       importVaultAddr,
+				Token:      importVaultToken,
+				Mount:      importVaultMount,
+				Path:       importVaultPath,
+				Bucket:     bucket,
+				Key:        key,
+				ResumeFrom: importVaultResumeFrom,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+
+			tw := new(tabwriter.Writer)
+			tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+			fmt.Fprintf(tw, "SECRET\tSTATUS\n")
+			for _, name := range result.Imported {
+				fmt.Fprintf(tw, "%s\timported\n", name)
+			}
+			for _, name := range result.Skipped {
+				fmt.Fprintf(tw, "%s\tskipped (already exists)\n", name)
+			}
+			for name, ferr := range result.Failed {
+				fmt.Fprintf(tw, "%s\t%s\n", name, ferr)
+			}
+			tw.Flush()
+
+			if len(result.Failed) > 0 {
+				return apiError(errors.New("one or more secrets failed to import"))
+			}
+
+			fmt.Fprintf(c.Stdout, "Successfully imported %d secret(s) from vault\n", len(result.Imported))
+			return nil
+		},
+	}
+	importCmd.AddCommand(importVaultCmd)
+	importVaultCmd.Flags().StringVar(&importVaultAddr, "vault-addr", os.Getenv(berglas.VaultAddrEnvVar),
+		"Address of the Vault server. Can also be set with "+berglas.VaultAddrEnvVar)
+	importVaultCmd.Flags().StringVar(&importVaultToken, "vault-token", os.Getenv(berglas.VaultTokenEnvVar),
+		"Vault token to authenticate with. Can also be set with "+berglas.VaultTokenEnvVar)
+	importVaultCmd.Flags().StringVar(&importVaultMount, "mount", "secret",
+		"Path the KV version 2 secrets engine is mounted at")
+	importVaultCmd.Flags().StringVar(&importVaultPath, "path", "",
+		"Path, relative to --mount, of the secret or folder of secrets to import")
+	importVaultCmd.Flags().StringVar(&key, "key", "",
+		"KMS key (or local-key:// reference) used to encrypt the imported secrets")
+	importVaultCmd.Flags().StringVar(&importVaultResumeFrom, "resume-from", "",
+		"Skip every secret whose object name sorts at or before this checkpoint")
+	if err := importVaultCmd.MarkFlagRequired("path"); err != nil {
+		panic(err)
+	}
+	if err := importVaultCmd.MarkFlagRequired("key"); err != nil {
+		panic(err)
+	}
+
+	importDotenvCmd := &cobra.Command{
+		Use:   "dotenv FILE BUCKET",
+		Short: "Bulk-create one secret per KEY in a dotenv file",
+		Long: strings.Trim(`
+Reads FILE as a dotenv file - one KEY=VALUE pair per line, blank lines and
+"#" comments ignored - and creates one secret per KEY under BUCKET. If
+BUCKET includes a path, e.g. "my-secrets/app/", it is used as a prefix on
+every created secret's name.
+
+Each secret is created independently and a failure (for example, one that
+already exists) is reported without stopping the rest of the batch.
+`, "\n"),
+		Example: strings.Trim(`
+  # Create one secret per line in .env under my-secrets
+  berglas import dotenv .env my-secrets/ \
+    --key projects/p/locations/global/keyRings/kr/cryptoKeys/k
+
+  # Prefix every created secret's name with "app/"
+  berglas import dotenv .env my-secrets/app/ --key local-key:///path/to/key
+`, "\n"),
+		Args: cobra.ExactArgs(2),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket, prefix, err := parseRef(args[1])
+			if err != nil {
+				return misuseError(err)
+			}
+
+			return c.dotenvImportRun(client, ctx, args[0], bucket, prefix, key)
+		},
+	}
+	importCmd.AddCommand(importDotenvCmd)
+	importDotenvCmd.Flags().StringVar(&key, "key", "",
+		"KMS key (or local-key:// reference) used to encrypt the imported secrets")
+	if err := importDotenvCmd.MarkFlagRequired("key"); err != nil {
+		panic(err)
+	}
+
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export secrets from berglas to another format",
+	}
+	rootCmd.AddCommand(exportCmd)
+
+	exportDotenvCmd := &cobra.Command{
+		Use:   "dotenv BUCKET",
+		Short: "Emit a dotenv file from a bucket's secrets",
+		Long: strings.Trim(`
+Lists every secret in BUCKET (optionally limited to those whose name starts
+with --prefix), decrypts each one, and writes a KEY=VALUE line per secret to
+stdout - a dotenv file suitable for redirecting to disk. --prefix, if given,
+is stripped from each secret's name to produce its KEY.
+`, "\n"),
+		Example: strings.Trim(`
+  # Write every secret in my-secrets to a dotenv file
+  berglas export dotenv my-secrets > .env
+
+  # Only secrets under "app/", with that prefix stripped from each KEY
+  berglas export dotenv my-secrets --prefix app/ > .env
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket := strings.TrimSuffix(strings.TrimPrefix(args[0], "gs://"), "/")
+			return c.dotenvExportRun(client, ctx, bucket, exportDotenvPrefix)
+		},
+	}
+	exportCmd.AddCommand(exportDotenvCmd)
+	exportDotenvCmd.Flags().StringVar(&exportDotenvPrefix, "prefix", "",
+		"Only export secrets whose name starts with this prefix, which is stripped from each KEY")
+
+	migrateCmd := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate secrets between berglas and Google Secret Manager",
+	}
+	rootCmd.AddCommand(migrateCmd)
+
+	migrateToSMCmd := &cobra.Command{
+		Use:   "to-sm BUCKET",
+		Short: "Copy every secret in a bucket to Secret Manager",
+		Long: strings.Trim(`
+Copies every secret in BUCKET to Secret Manager in --project, mapping each
+object name to a secret ID by replacing every "/" with a doubled
+underscore. If two secrets in BUCKET map to the same secret ID, neither is
+migrated and the collision is reported; rename one of them and run again.
+
+Copying a secret is idempotent: it adds a new version to the destination
+secret if one by that ID already exists, rather than failing, so this is
+safe to run again after fixing a reported failure or conflict.
+`, "\n"),
+		Example: strings.Trim(`
+  # Copy every secret in my-secrets to Secret Manager in project "p"
+  berglas migrate to-sm my-secrets --project p
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			bucket := strings.TrimPrefix(args[0], "gs://")
+
+			result, err := client.MigrateToSM(ctx, &berglas.MigrateToSMRequest{
+				Bucket:  bucket,
+				Project: migrateProject,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+			return c.reportMigrateResult(result)
+		},
+	}
+	migrateCmd.AddCommand(migrateToSMCmd)
+	migrateToSMCmd.Flags().StringVar(&migrateProject, "project", "",
+		"Google Cloud Project ID to create Secret Manager secrets in")
+	if err := migrateToSMCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+
+	migrateFromSMCmd := &cobra.Command{
+		Use:   "from-sm BUCKET",
+		Short: "Copy every secret in a Secret Manager project to a bucket",
+		Long: strings.Trim(`
+Copies the latest version of every secret in --project to BUCKET, mapping
+each Secret Manager secret ID to an object name by replacing every doubled
+underscore with a "/". If two secrets map to the same object name, neither
+is migrated and the collision is reported; rename one of them and run
+again.
+
+A destination object that already exists is left untouched and reported as
+skipped, so this is safe to run again after fixing a reported failure or
+conflict without re-copying secrets it already migrated.
+`, "\n"),
+		Example: strings.Trim(`
+  # Copy every secret in project "p" into my-secrets
+  berglas migrate from-sm my-secrets --project p \
+    --key projects/p/locations/global/keyRings/kr/cryptoKeys/k
+`, "\n"),
+		Args: cobra.ExactArgs(1),
+		RunE: func(_ *cobra.Command, args []string) error {
+			if readOnly {
+				return misuseError(errReadOnly)
+			}
+
+			client, ctx, closer, err := c.clientWithContext(logFormat, logLevel, impersonate, storageEndpoint, kmsEndpoint, billingProject, retries, retryMaxElapsed, timeout)
+			if err != nil {
+				return misuseError(err)
+			}
+			defer closer()
+
+			c.warnIfLocalKey(key)
+
+			bucket := strings.TrimPrefix(args[0], "gs://")
+
+			result, err := client.MigrateFromSM(ctx, &berglas.MigrateFromSMRequest{
+				Project: migrateProject,
+				Bucket:  bucket,
+				Key:     key,
+			})
+			if err != nil {
+				return apiError(err)
+			}
+			return c.reportMigrateResult(result)
+		},
+	}
+	migrateCmd.AddCommand(migrateFromSMCmd)
+	migrateFromSMCmd.Flags().StringVar(&migrateProject, "project", "",
+		"Google Cloud Project ID to read Secret Manager secrets from")
+	migrateFromSMCmd.Flags().StringVar(&key, "key", "",
+		"KMS key (or local-key:// reference) used to encrypt the migrated secrets")
+	if err := migrateFromSMCmd.MarkFlagRequired("project"); err != nil {
+		panic(err)
+	}
+	if err := migrateFromSMCmd.MarkFlagRequired("key"); err != nil {
+		panic(err)
+	}
+
+	return rootCmd, telemetry
+}
@@ -0,0 +1,99 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// Config is berglas's optional user-level configuration file, read from
+// BERGLAS_CONFIG_FILE or ~/.berglas/config.yaml by default (see
+// configFilePath). It currently only configures the "berglas edit" editor
+// fallback (see resolveEditor); it is not required to exist.
+type Config struct {
+	// Editor is the command "berglas edit" runs when neither --editor nor
+	// $VISUAL/$EDITOR is set.
+	Editor string `yaml:"editor"`
+}
+
+// configFilePath returns the path to berglas's user-level config file.
+func configFilePath() (string, error) {
+	if p := os.Getenv("BERGLAS_CONFIG_FILE"); p != "" {
+		return p, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.Wrap(err, "failed to determine home directory")
+	}
+	return filepath.Join(home, ".berglas", "config.yaml"), nil
+}
+
+// readConfig reads and parses the config file returned by configFilePath,
+// returning a zero-value Config (not an error) if it does not exist.
+func readConfig() (*Config, error) {
+	path, err := configFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &Config{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to read config file %s", path)
+	}
+
+	var cfg Config
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config file %s", path)
+	}
+	return &cfg, nil
+}
+
+// resolveEditor resolves the editor command "berglas edit" should run,
+// preferring, in order: flagValue (--editor), the config file's "editor"
+// key, $VISUAL, $EDITOR, and finally a sensible platform default.
+func resolveEditor(flagValue string) (string, error) {
+	if flagValue != "" {
+		return flagValue, nil
+	}
+
+	cfg, err := readConfig()
+	if err != nil {
+		return "", err
+	}
+	if cfg.Editor != "" {
+		return cfg.Editor, nil
+	}
+
+	for _, e := range []string{"VISUAL", "EDITOR"} {
+		if v := os.Getenv(e); v != "" {
+			return v, nil
+		}
+	}
+
+	if runtime.GOOS == "windows" {
+		return "notepad", nil
+	}
+	return "vi", nil
+}
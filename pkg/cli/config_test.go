@@ -0,0 +1,118 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func Test_readConfig(t *testing.T) {
+	t.Run("missing file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "berglas-config-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		os.Setenv("BERGLAS_CONFIG_FILE", filepath.Join(dir, "does-not-exist.yaml"))
+		defer os.Unsetenv("BERGLAS_CONFIG_FILE")
+
+		cfg, err := readConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cfg.Editor != "" {
+			t.Errorf("expected no editor, got %q", cfg.Editor)
+		}
+	})
+
+	t.Run("existing file", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "berglas-config-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		path := filepath.Join(dir, "config.yaml")
+		if err := ioutil.WriteFile(path, []byte("editor: emacs\n"), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		os.Setenv("BERGLAS_CONFIG_FILE", path)
+		defer os.Unsetenv("BERGLAS_CONFIG_FILE")
+
+		cfg, err := readConfig()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := cfg.Editor, "emacs"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+}
+
+func Test_resolveEditor(t *testing.T) {
+	dir, err := ioutil.TempDir("", "berglas-config-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv("BERGLAS_CONFIG_FILE", filepath.Join(dir, "does-not-exist.yaml"))
+	defer os.Unsetenv("BERGLAS_CONFIG_FILE")
+
+	t.Run("flag wins", func(t *testing.T) {
+		os.Setenv("EDITOR", "nano")
+		defer os.Unsetenv("EDITOR")
+
+		editor, err := resolveEditor("vim")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := editor, "vim"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("falls back to EDITOR", func(t *testing.T) {
+		os.Unsetenv("VISUAL")
+		os.Setenv("EDITOR", "nano")
+		defer os.Unsetenv("EDITOR")
+
+		editor, err := resolveEditor("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := editor, "nano"; act != exp {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("falls back to platform default", func(t *testing.T) {
+		os.Unsetenv("VISUAL")
+		os.Unsetenv("EDITOR")
+
+		editor, err := resolveEditor("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if editor == "" {
+			t.Error("expected a non-empty default editor")
+		}
+	})
+}
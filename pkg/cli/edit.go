@@ -0,0 +1,158 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/mattn/go-shellwords"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// runEditor writes initial to a new tempfile, opens it in editorBin (parsed
+// with shell-style word splitting, so arguments like "code --wait" work),
+// waits for the editor to exit 0, and returns the tempfile's final contents.
+//
+// The tempfile is created 0600 in a RAM-backed directory when one is
+// available (see secureTempDir), so the plaintext is never written to a
+// persistent disk. Unless insecureTempdir is true, the chosen directory is
+// refused if it's world-writable without the sticky bit set (see
+// checkTempDirSafe). Before the tempfile is removed, its contents are
+// overwritten so the plaintext doesn't linger in freed disk or page-cache
+// blocks.
+func (c *CLI) runEditor(editorBin string, initial []byte, insecureTempdir bool) ([]byte, error) {
+	dir := secureTempDir()
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if !insecureTempdir {
+		if err := checkTempDirSafe(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	f, err := ioutil.TempFile(dir, "berglas-")
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create tempfile for secret")
+	}
+	defer func() {
+		if err := shredFile(f.Name()); err != nil {
+			fmt.Fprintf(c.Stderr, "failed to cleanup tempfile %s: %s\n", f.Name(), err)
+		}
+	}()
+
+	if err := f.Chmod(0600); err != nil {
+		return nil, errors.Wrap(err, "failed to set permissions on tempfile for secret")
+	}
+	if _, err := f.Write(initial); err != nil {
+		return nil, errors.Wrap(err, "failed to write tempfile for secret")
+	}
+	if err := f.Sync(); err != nil {
+		return nil, errors.Wrap(err, "failed to sync tempfile for secret")
+	}
+	if err := f.Close(); err != nil {
+		return nil, errors.Wrap(err, "failed to close tempfile for secret")
+	}
+
+	editorSplit, err := shellwords.Parse(editorBin)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse editor command %q", editorBin)
+	}
+	if len(editorSplit) == 0 {
+		return nil, errors.Errorf("editor command %q is empty", editorBin)
+	}
+	editorCmd, editorArgs := editorSplit[0], editorSplit[1:]
+	editorArgs = append(editorArgs, f.Name())
+
+	cmd := exec.Command(editorCmd, editorArgs...)
+	cmd.Stdin = c.Stdin
+	cmd.Stdout = c.Stdout
+	cmd.Stderr = c.Stderr
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrap(err, "failed to start editor")
+	}
+	if err := cmd.Wait(); err != nil {
+		if terr, ok := err.(*exec.ExitError); ok && terr.ProcessState != nil {
+			code := terr.ProcessState.ExitCode()
+			return nil, exitWithCode(code, errors.Wrap(terr, "editor did not exit 0"))
+		}
+		return nil, errors.Wrap(err, "unknown failure in running editor")
+	}
+
+	edited, err := ioutil.ReadFile(f.Name())
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read secret tempfile")
+	}
+	return edited, nil
+}
+
+// unifiedDiff renders a human-readable unified diff from a to b.
+func unifiedDiff(a, b []byte, fromLabel, toLabel string) (string, error) {
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(a)),
+		B:        difflib.SplitLines(string(b)),
+		FromFile: fromLabel,
+		ToFile:   toLabel,
+		Context:  3,
+	})
+}
+
+// conflictMarkers renders mine and theirs around a base with git-style
+// conflict markers, for the caller to resolve by hand in an editor.
+func conflictMarkers(base, mine, theirs []byte) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintln(&buf, "<<<<<<< yours")
+	buf.Write(mine)
+	if !bytes.HasSuffix(mine, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintln(&buf, "||||||| base")
+	buf.Write(base)
+	if !bytes.HasSuffix(base, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintln(&buf, "=======")
+	buf.Write(theirs)
+	if !bytes.HasSuffix(theirs, []byte("\n")) {
+		buf.WriteString("\n")
+	}
+	fmt.Fprintln(&buf, ">>>>>>> theirs")
+	return buf.Bytes()
+}
+
+// promptEditConflict shows the caller the two diffs from base and asks how
+// to resolve a concurrent modification, returning one of "retry", "merge",
+// or "abort".
+func (c *CLI) promptEditConflict() (string, error) {
+	fmt.Fprint(c.Stderr, "Resolve how? [r]etry (overwrite with your version), "+
+		"[m]erge (edit again with conflict markers), [a]bort: ")
+	r := bufio.NewReader(c.Stdin)
+	line, _ := r.ReadString('\n')
+	switch answer := strings.ToLower(strings.TrimSpace(line)); answer {
+	case "r", "retry":
+		return "retry", nil
+	case "m", "merge":
+		return "merge", nil
+	default:
+		return "abort", nil
+	}
+}
@@ -0,0 +1,118 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func Test_runEditor(t *testing.T) {
+	t.Parallel()
+
+	if runtime.GOOS == "windows" {
+		t.Skip("fake editor is a shell script")
+	}
+
+	dir, err := ioutil.TempDir("", "berglas-runeditor-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	script := dir + "/fake-editor.sh"
+	if err := ioutil.WriteFile(script, []byte("#!/bin/sh\nprintf 'edited' > \"$1\"\n"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	c := new(CLI)
+	got, err := c.runEditor(script, []byte("original"), false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if act, exp := got, []byte("edited"); !bytes.Equal(act, exp) {
+		t.Errorf("expected %q to be %q", act, exp)
+	}
+}
+
+func Test_unifiedDiff(t *testing.T) {
+	t.Parallel()
+
+	diff, err := unifiedDiff([]byte("foo\nbar\n"), []byte("foo\nbaz\n"), "base", "yours")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"--- base", "+++ yours", "-bar", "+baz"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("expected diff %q to contain %q", diff, want)
+		}
+	}
+}
+
+func Test_conflictMarkers(t *testing.T) {
+	t.Parallel()
+
+	merged := conflictMarkers([]byte("base\n"), []byte("mine\n"), []byte("theirs\n"))
+	for _, want := range [][]byte{
+		[]byte("<<<<<<< yours"),
+		[]byte("mine"),
+		[]byte("||||||| base"),
+		[]byte("base"),
+		[]byte("======="),
+		[]byte("theirs"),
+		[]byte(">>>>>>> theirs"),
+	} {
+		if !bytes.Contains(merged, want) {
+			t.Errorf("expected merged output to contain %q", want)
+		}
+	}
+}
+
+func Test_promptEditConflict(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name  string
+		input string
+		exp   string
+	}{
+		{"retry", "r\n", "retry"},
+		{"merge", "merge\n", "merge"},
+		{"abort", "a\n", "abort"},
+		{"unrecognized defaults to abort", "whatever\n", "abort"},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			var stderr bytes.Buffer
+			c := New(WithStdin(strings.NewReader(tc.input)), WithStderr(&stderr))
+
+			choice, err := c.promptEditConflict()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if act, exp := choice, tc.exp; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+}
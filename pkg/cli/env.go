@@ -0,0 +1,76 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// envPairs splits "KEY=VALUE" environment entries (as returned by
+// resolveEnvironment) into a map, sorted for deterministic rendering by the
+// callers below.
+func envPairs(env []string) map[string]string {
+	pairs := make(map[string]string, len(env))
+	for _, e := range env {
+		p := strings.SplitN(e, "=", 2)
+		if len(p) < 2 {
+			continue
+		}
+		pairs[p[0]] = p[1]
+	}
+	return pairs
+}
+
+// renderEnvDotenv renders env in the same ENV_NAME=value format "exec --map"
+// reads, one pair per line, sorted by name.
+func renderEnvDotenv(env []string) []byte {
+	pairs := envPairs(env)
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s=%s\n", name, pairs[name])
+	}
+	return []byte(buf.String())
+}
+
+// renderEnvExport renders env as shell "export" statements, sorted by name,
+// so "eval $(berglas env --format export)" populates the current shell.
+func renderEnvExport(env []string) []byte {
+	pairs := envPairs(env)
+	names := make([]string, 0, len(pairs))
+	for name := range pairs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf strings.Builder
+	for _, name := range names {
+		fmt.Fprintf(&buf, "export %s=%s\n", name, shellQuote(pairs[name]))
+	}
+	return []byte(buf.String())
+}
+
+// shellQuote single-quotes s for a POSIX shell, escaping any embedded single
+// quotes so the result can be safely eval'd regardless of its contents.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
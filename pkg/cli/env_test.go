@@ -0,0 +1,61 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"reflect"
+	"testing"
+)
+
+func Test_envPairs(t *testing.T) {
+	t.Parallel()
+
+	env := []string{"B=2", "A=1", "MALFORMED"}
+	want := map[string]string{"A": "1", "B": "2"}
+	if got := envPairs(env); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+func Test_renderEnvDotenv(t *testing.T) {
+	t.Parallel()
+
+	env := []string{"B=2", "A=1"}
+	want := "A=1\nB=2\n"
+	if got := string(renderEnvDotenv(env)); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func Test_renderEnvExport(t *testing.T) {
+	t.Parallel()
+
+	env := []string{"B=it's", "A=1"}
+	want := "export A='1'\nexport B='it'\\''s'\n"
+	if got := string(renderEnvExport(env)); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func Test_shellQuote(t *testing.T) {
+	t.Parallel()
+
+	if got, want := shellQuote("plain"), "'plain'"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got, want := shellQuote("it's"), `'it'\''s'`; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
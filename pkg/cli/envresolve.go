@@ -0,0 +1,254 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// envResolveOptions configures resolveEnvironment - shared by "exec" (to
+// populate a child's environment) and "env" (to print it). MapFile and
+// Local mirror "-map"/"-local"; at most one may be set, and neither means
+// "detect and query the runtime platform".
+type envResolveOptions struct {
+	MapFile string
+	Local   bool
+
+	Pins               map[string]int64
+	Missing            string
+	ResolveConcurrency int
+
+	// LogSource prefixes debug-level log messages ("exec" or "env"), so
+	// -log-level=debug output reads the same as it always has for exec.
+	LogSource string
+}
+
+// resolveEnvironment resolves every berglas reference among the candidate
+// environment variables selected by opts, returning a full "KEY=VALUE"
+// environment. At debug log level, it reports which variables were
+// references, how long each took to resolve, and whether resolving it was
+// served from its own per-call cache (which collapses variables that
+// reference the same secret) - resolved values are never included in that
+// report.
+func (c *CLI) resolveEnvironment(ctx context.Context, client *berglas.Client, ec *berglas.Client, opts envResolveOptions) ([]string, error) {
+	env := os.Environ()
+	logger := client.Logger()
+
+	// resolveMissing applies "-missing" to an error encountered while
+	// resolving the single reference identified by desc (an environment
+	// variable name). ok reports whether the caller should carry on without
+	// that reference - true for "-missing=skip", and for "-missing=warn"
+	// after logging a warning - or abort by returning retErr - the default,
+	// "-missing=fail".
+	resolveMissing := func(desc string, err error) (ok bool, retErr error) {
+		switch opts.Missing {
+		case "warn":
+			fmt.Fprintf(c.Stderr, "berglas: warning: failed to resolve %s: %s\n", desc, err)
+			return true, nil
+		case "skip":
+			return true, nil
+		default:
+			return false, err
+		}
+	}
+
+	// cache collapses variables that reference the same secret. It's
+	// guarded by cacheMu since -resolve-concurrency lets many goroutines
+	// call resolveRef at once.
+	var cacheMu sync.Mutex
+	cache := make(map[string][]byte)
+
+	resolveRef := func(k, v string) (string, error) {
+		start := c.Now()
+
+		cacheMu.Lock()
+		plaintext, cacheHit := cache[v]
+		cacheMu.Unlock()
+
+		if !cacheHit {
+			p, err := c.resolveWithPins(ec, ctx, opts.Pins, v)
+			if err != nil {
+				return "", apiError(err)
+			}
+			plaintext = p
+
+			cacheMu.Lock()
+			cache[v] = plaintext
+			cacheMu.Unlock()
+		}
+
+		logger.WithFields(logrus.Fields{
+			"env":       k,
+			"cache_hit": cacheHit,
+			"duration":  c.Now().Sub(start).String(),
+		}).Debugf("%s: resolved berglas reference", opts.LogSource)
+
+		return string(plaintext), nil
+	}
+
+	// resolveValue resolves v, the value of environment variable k. It
+	// reports isRef as false, leaving s equal to v, when v is neither a
+	// whole reference nor a value with one or more "${scheme://...}"
+	// references embedded in it (see interpolateReferences), so callers can
+	// log and pass the value through unchanged exactly as before
+	// interpolation support was added.
+	resolveValue := func(k, v string) (s string, isRef bool, err error) {
+		switch {
+		case berglas.IsReference(v):
+			s, err := resolveRef(k, v)
+			return s, true, err
+		case hasEmbeddedReference(v):
+			s, err := interpolateReferences(v, func(ref string) (string, error) {
+				return resolveRef(k, ref)
+			})
+			return s, true, err
+		default:
+			return v, false, nil
+		}
+	}
+
+	// resolvedPair is the outcome of resolving one k/v pair via
+	// resolvePairs, preserving pairs' order regardless of which worker
+	// happened to finish it.
+	type resolvedPair struct {
+		k, v  string
+		isRef bool
+		err   error
+	}
+
+	// resolvePairs resolves every k/v pair concurrently, at most
+	// "-resolve-concurrency" at a time, so a service with dozens of
+	// references doesn't pay for each one serially - they still share the
+	// single client ec (and its underlying KMS/GCS clients) and
+	// resolveRef's cache.
+	resolvePairs := func(pairs [][2]string) []resolvedPair {
+		results := make([]resolvedPair, len(pairs))
+
+		sem := make(chan struct{}, opts.ResolveConcurrency)
+		var wg sync.WaitGroup
+		for i, pair := range pairs {
+			wg.Add(1)
+			sem <- struct{}{}
+			go func(i int, k, v string) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				s, isRef, err := resolveValue(k, v)
+				results[i] = resolvedPair{k: k, v: s, isRef: isRef, err: err}
+			}(i, pair[0], pair[1])
+		}
+		wg.Wait()
+
+		return results
+	}
+
+	if opts.MapFile != "" {
+		// Parse the map file, decoupling secret wiring from whatever
+		// environment variables the runtime platform happens to set.
+		data, err := ioutil.ReadFile(opts.MapFile)
+		if err != nil {
+			return nil, misuseError(errors.Wrapf(err, "failed to read map file %s", opts.MapFile))
+		}
+
+		pairs, err := parseEnvMapFile(data)
+		if err != nil {
+			return nil, misuseError(errors.Wrapf(err, "failed to parse map file %s", opts.MapFile))
+		}
+
+		for _, r := range resolvePairs(pairs) {
+			if !r.isRef {
+				logger.WithField("env", r.k).Debugf("%s: not a berglas reference, skipping", opts.LogSource)
+				env = append(env, fmt.Sprintf("%s=%s", r.k, r.v))
+				continue
+			}
+			if r.err != nil {
+				if ok, rerr := resolveMissing(r.k, r.err); !ok {
+					return nil, rerr
+				}
+				continue
+			}
+			env = append(env, fmt.Sprintf("%s=%s", r.k, r.v))
+		}
+	} else if opts.Local {
+		// Parse local env. localIdx[j] is the index into env that pairs[j]
+		// (and so resolvePairs's j'th result) came from, since only some of
+		// env's entries are candidates at all.
+		var pairs [][2]string
+		var localIdx []int
+		for i, e := range env {
+			p := strings.SplitN(e, "=", 2)
+			if len(p) < 2 {
+				continue
+			}
+			pairs = append(pairs, [2]string{p[0], p[1]})
+			localIdx = append(localIdx, i)
+		}
+
+		for j, r := range resolvePairs(pairs) {
+			if !r.isRef {
+				logger.WithField("env", r.k).Debugf("%s: not a berglas reference, skipping", opts.LogSource)
+				continue
+			}
+			if r.err != nil {
+				if ok, rerr := resolveMissing(r.k, r.err); !ok {
+					return nil, rerr
+				}
+				continue
+			}
+			env[localIdx[j]] = fmt.Sprintf("%s=%s", r.k, r.v)
+		}
+	} else {
+		// Parse remote env
+		runtimeEnv, err := client.DetectRuntimeEnvironment()
+		if err != nil {
+			return nil, misuseError(errors.Wrap(err, "failed to detect runtime environment"))
+		}
+
+		envvars, err := runtimeEnv.EnvVars(ctx)
+		if err != nil {
+			return nil, misuseError(errors.Wrap(err, "failed to find environment variables"))
+		}
+
+		var pairs [][2]string
+		for k, v := range envvars {
+			pairs = append(pairs, [2]string{k, v})
+		}
+
+		for _, r := range resolvePairs(pairs) {
+			if !r.isRef {
+				logger.WithField("env", r.k).Debugf("%s: not a berglas reference, skipping", opts.LogSource)
+				continue
+			}
+			if r.err != nil {
+				if ok, rerr := resolveMissing(r.k, r.err); !ok {
+					return nil, rerr
+				}
+				continue
+			}
+			env = append(env, fmt.Sprintf("%s=%s", r.k, r.v))
+		}
+	}
+
+	return env, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// jsonPathSegment is one step of a parsed JSONPath expression: exactly one
+// of key or index is meaningful, selected by isIndex.
+type jsonPathSegment struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses a small subset of JSONPath: a leading "$" (optional),
+// followed by any number of ".key" and "[N]" selectors, e.g.
+// "$.database.password" or "$.users[0].name". It does not support
+// wildcards, slices, or filter expressions - just enough to reach into the
+// nested object or array a JSON secret typically is.
+func parseJSONPath(path string) ([]jsonPathSegment, error) {
+	s := strings.TrimPrefix(path, "$")
+	if s == "" {
+		return nil, nil
+	}
+
+	var segments []jsonPathSegment
+	for len(s) > 0 {
+		switch s[0] {
+		case '.':
+			s = s[1:]
+			end := strings.IndexAny(s, ".[")
+			if end < 0 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, errors.Errorf("invalid jsonpath %q: empty key", path)
+			}
+			segments = append(segments, jsonPathSegment{key: s[:end]})
+			s = s[end:]
+		case '[':
+			end := strings.IndexByte(s, ']')
+			if end < 0 {
+				return nil, errors.Errorf("invalid jsonpath %q: unterminated %q", path, "[")
+			}
+			index, err := strconv.Atoi(s[1:end])
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid jsonpath %q: %q is not an index", path, s[1:end])
+			}
+			segments = append(segments, jsonPathSegment{index: index, isIndex: true})
+			s = s[end+1:]
+		default:
+			return nil, errors.Errorf("invalid jsonpath %q: expected \".\" or \"[\" at %q", path, s)
+		}
+	}
+	return segments, nil
+}
+
+// extractJSONPath parses data as JSON and extracts the value at path (see
+// parseJSONPath for the supported syntax). If the extracted value is a JSON
+// string, its unquoted contents are returned; otherwise the value is
+// re-marshaled to JSON, so a secret storing structured data (e.g. a bundle
+// written by "berglas kv put" or "berglas bundle set") can hand a single
+// field to a caller without it having to pipe the whole secret through jq.
+func extractJSONPath(data []byte, path string) ([]byte, error) {
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return nil, errors.Wrap(err, "failed to parse secret as json")
+	}
+
+	for _, seg := range segments {
+		if seg.isIndex {
+			arr, ok := v.([]interface{})
+			if !ok {
+				return nil, errors.Errorf("jsonpath %q: expected an array at index %d", path, seg.index)
+			}
+			if seg.index < 0 || seg.index >= len(arr) {
+				return nil, errors.Errorf("jsonpath %q: index %d out of range", path, seg.index)
+			}
+			v = arr[seg.index]
+			continue
+		}
+
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return nil, errors.Errorf("jsonpath %q: expected an object at key %q", path, seg.key)
+		}
+		val, ok := obj[seg.key]
+		if !ok {
+			return nil, errors.Errorf("jsonpath %q: no such key %q", path, seg.key)
+		}
+		v = val
+	}
+
+	if s, ok := v.(string); ok {
+		return []byte(s), nil
+	}
+
+	result, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to marshal jsonpath %q result", path)
+	}
+	return result, nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"testing"
+)
+
+func Test_extractJSONPath(t *testing.T) {
+	t.Parallel()
+
+	data := []byte(`{"database":{"password":"s3cr3t","ports":[5432,5433]},"count":3}`)
+
+	cases := []struct {
+		name string
+		path string
+		exp  string
+		err  bool
+	}{
+		{"nested_key", "$.database.password", "s3cr3t", false},
+		{"array_index", "$.database.ports[1]", "5433", false},
+		{"no_dollar_prefix", ".database.password", "s3cr3t", false},
+		{"root", "$", `{"count":3,"database":{"password":"s3cr3t","ports":[5432,5433]}}`, false},
+		{"missing_key", "$.database.username", "", true},
+		{"index_out_of_range", "$.database.ports[5]", "", true},
+		{"index_into_object", "$.database[0]", "", true},
+		{"key_into_array", "$.database.ports.password", "", true},
+		{"invalid_syntax", "$.database..password", "", true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := extractJSONPath(data, tc.path)
+			if (err != nil) != tc.err {
+				t.Fatal(err)
+			}
+			if tc.err {
+				return
+			}
+
+			if act, exp := string(got), tc.exp; act != exp {
+				t.Errorf("expected %q to be %q", act, exp)
+			}
+		})
+	}
+
+	if _, err := extractJSONPath([]byte("not json"), "$.foo"); err == nil {
+		t.Error("expected error for invalid json")
+	}
+}
@@ -0,0 +1,334 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// k8sBinVolumeName and k8sBinVolumeMountPath are the shared, in-memory
+	// volume the berglas binary is copied into, matching the convention used
+	// by the mutating webhook in examples/kubernetes.
+	k8sBinVolumeName      = "berglas-bin"
+	k8sBinVolumeMountPath = "/berglas/bin/"
+
+	// k8sBerglasContainer is the image the berglas binary is copied from.
+	k8sBerglasContainer = "gcr.io/berglas/berglas:latest"
+
+	// k8sInitContainerName is the name given to the init container that
+	// copies the berglas binary into the shared volume.
+	k8sInitContainerName = "copy-berglas-bin"
+
+	// k8sOriginalCommandAnnotation records a container's pre-annotate
+	// command/args (JSON-encoded) so deannotate can restore it.
+	k8sOriginalCommandAnnotation = "berglas.cloud.google.com/original-command"
+)
+
+// k8sAnnotateManifests parses a (possibly multi-document) Kubernetes
+// manifest, wraps the command of every container that references a
+// berglas:// secret so it runs under "berglas exec --local --", and adds the
+// shared init container and volume those wrapped containers need. It
+// returns the rewritten manifest.
+//
+// This only edits the manifest on disk; it does not talk to a cluster. A
+// real MutatingWebhookConfiguration (see examples/kubernetes) does this at
+// admission time for pods that are never expressed as static manifests, but
+// teams that manage workloads as YAML can use this command to adopt or roll
+// back that same rewrite as a reviewable, scriptable diff instead.
+func k8sAnnotateManifests(data []byte) ([]byte, error) {
+	return k8sRewriteManifests(data, k8sAnnotateContainer, k8sEnsureInitContainerAndVolume)
+}
+
+// k8sDeannotateManifests reverses k8sAnnotateManifests: it restores each
+// previously-wrapped container's original command/args and removes the
+// shared init container and volume if nothing else in the document needs
+// them.
+func k8sDeannotateManifests(data []byte) ([]byte, error) {
+	return k8sRewriteManifests(data, k8sDeannotateContainer, k8sRemoveInitContainerAndVolumeIfUnused)
+}
+
+// k8sRewriteManifests decodes each document in data, applies rewriteContainer
+// to every container in every pod template it finds, and - if any container
+// in a pod template changed - applies rewritePodSpec to that pod template's
+// spec before re-encoding the result.
+func k8sRewriteManifests(data []byte, rewriteContainer func(c map[string]interface{}) bool, rewritePodSpec func(podSpec map[string]interface{})) ([]byte, error) {
+	docs, err := k8sSplitDocuments(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var out bytes.Buffer
+	for i, doc := range docs {
+		if i > 0 {
+			out.WriteString("---\n")
+		}
+
+		podSpec, _ := k8sFindPodSpec(doc)
+		if podSpec != nil {
+			containers, _ := podSpec["containers"].([]interface{})
+
+			var mutated bool
+			for _, rc := range containers {
+				c, ok := rc.(map[string]interface{})
+				if !ok {
+					continue
+				}
+				if rewriteContainer(c) {
+					mutated = true
+				}
+			}
+
+			if mutated {
+				rewritePodSpec(podSpec)
+			}
+		}
+
+		enc := yaml.NewEncoder(&out)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return nil, errors.Wrap(err, "failed to encode manifest")
+		}
+		enc.Close()
+	}
+
+	return out.Bytes(), nil
+}
+
+// k8sSplitDocuments decodes every "---"-separated YAML document in data into
+// a generic map, preserving key order is not guaranteed by yaml.v3's
+// map[string]interface{} decoding, but round-tripping is stable enough for
+// manifests that are re-applied rather than diffed byte-for-byte.
+func k8sSplitDocuments(data []byte) ([]map[string]interface{}, error) {
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+
+	var docs []map[string]interface{}
+	for {
+		var doc map[string]interface{}
+		if err := dec.Decode(&doc); err != nil {
+			if err == io.EOF {
+				break
+			}
+			return nil, errors.Wrap(err, "failed to parse manifest")
+		}
+		if doc == nil {
+			continue
+		}
+		docs = append(docs, doc)
+	}
+	return docs, nil
+}
+
+// k8sFindPodSpec returns the pod spec and pod metadata maps for doc,
+// whether doc is a bare Pod or a workload that wraps a pod template
+// (Deployment, StatefulSet, DaemonSet, Job, CronJob's job template, etc).
+// It returns nil, nil if doc does not look like a workload.
+func k8sFindPodSpec(doc map[string]interface{}) (map[string]interface{}, map[string]interface{}) {
+	kind, _ := doc["kind"].(string)
+
+	if kind == "Pod" {
+		spec, _ := doc["spec"].(map[string]interface{})
+		meta, _ := doc["metadata"].(map[string]interface{})
+		return spec, meta
+	}
+
+	spec, _ := doc["spec"].(map[string]interface{})
+	if spec == nil {
+		return nil, nil
+	}
+
+	template, _ := spec["template"].(map[string]interface{})
+	if template == nil {
+		return nil, nil
+	}
+
+	podSpec, _ := template["spec"].(map[string]interface{})
+	podMeta, _ := template["metadata"].(map[string]interface{})
+	return podSpec, podMeta
+}
+
+// k8sAnnotateContainer wraps c's command with "berglas exec --local --" if
+// it has a berglas:// reference in its environment and has not already been
+// wrapped. It returns true if c was changed.
+func k8sAnnotateContainer(c map[string]interface{}) bool {
+	if !k8sHasBerglasReference(c) {
+		return false
+	}
+	if _, ok := c[k8sOriginalCommandAnnotation]; ok {
+		return false
+	}
+
+	command, _ := c["command"].([]interface{})
+	args, _ := c["args"].([]interface{})
+	if len(command) == 0 {
+		return false
+	}
+
+	original := append(append([]interface{}{}, command...), args...)
+	originalJSON, err := json.Marshal(original)
+	if err != nil {
+		return false
+	}
+
+	c[k8sOriginalCommandAnnotation] = string(originalJSON)
+	c["command"] = []interface{}{k8sBinVolumeMountPath + "berglas"}
+	c["args"] = append([]interface{}{"exec", "--local", "--"}, original...)
+
+	volumeMounts, _ := c["volumeMounts"].([]interface{})
+	volumeMounts = append(volumeMounts, map[string]interface{}{
+		"name":      k8sBinVolumeName,
+		"mountPath": k8sBinVolumeMountPath,
+		"readOnly":  true,
+	})
+	c["volumeMounts"] = volumeMounts
+
+	return true
+}
+
+// k8sDeannotateContainer restores c's original command/args if
+// k8sAnnotateContainer previously wrapped it. It returns true if c was
+// changed.
+func k8sDeannotateContainer(c map[string]interface{}) bool {
+	raw, ok := c[k8sOriginalCommandAnnotation].(string)
+	if !ok {
+		return false
+	}
+
+	var original []interface{}
+	if err := json.Unmarshal([]byte(raw), &original); err != nil {
+		return false
+	}
+
+	delete(c, k8sOriginalCommandAnnotation)
+	c["command"] = original
+	delete(c, "args")
+
+	volumeMounts, _ := c["volumeMounts"].([]interface{})
+	kept := volumeMounts[:0]
+	for _, rvm := range volumeMounts {
+		vm, ok := rvm.(map[string]interface{})
+		if ok && vm["name"] == k8sBinVolumeName {
+			continue
+		}
+		kept = append(kept, rvm)
+	}
+	if len(kept) > 0 {
+		c["volumeMounts"] = kept
+	} else {
+		delete(c, "volumeMounts")
+	}
+
+	return true
+}
+
+// k8sHasBerglasReference returns true if c's env includes a berglas://
+// reference.
+func k8sHasBerglasReference(c map[string]interface{}) bool {
+	env, _ := c["env"].([]interface{})
+	for _, re := range env {
+		e, ok := re.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if v, ok := e["value"].(string); ok && berglas.IsReference(v) {
+			return true
+		}
+	}
+	return false
+}
+
+// k8sEnsureInitContainerAndVolume adds the shared berglas-bin init container
+// and volume to podSpec if they are not already present.
+func k8sEnsureInitContainerAndVolume(podSpec map[string]interface{}) {
+	initContainers, _ := podSpec["initContainers"].([]interface{})
+	for _, ric := range initContainers {
+		if ic, ok := ric.(map[string]interface{}); ok && ic["name"] == k8sInitContainerName {
+			return
+		}
+	}
+
+	initContainer := map[string]interface{}{
+		"name":            k8sInitContainerName,
+		"image":           k8sBerglasContainer,
+		"imagePullPolicy": "IfNotPresent",
+		"command":         []interface{}{"sh", "-c", "cp /bin/berglas " + k8sBinVolumeMountPath},
+		"volumeMounts": []interface{}{
+			map[string]interface{}{"name": k8sBinVolumeName, "mountPath": k8sBinVolumeMountPath},
+		},
+	}
+	podSpec["initContainers"] = append([]interface{}{initContainer}, initContainers...)
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+	volumes = append(volumes, map[string]interface{}{
+		"name": k8sBinVolumeName,
+		"emptyDir": map[string]interface{}{
+			"medium": "Memory",
+		},
+	})
+	podSpec["volumes"] = volumes
+}
+
+// k8sRemoveInitContainerAndVolumeIfUnused removes the shared berglas-bin
+// init container and volume from podSpec if no container in it still mounts
+// the shared volume.
+func k8sRemoveInitContainerAndVolumeIfUnused(podSpec map[string]interface{}) {
+	containers, _ := podSpec["containers"].([]interface{})
+	for _, rc := range containers {
+		c, ok := rc.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		volumeMounts, _ := c["volumeMounts"].([]interface{})
+		for _, rvm := range volumeMounts {
+			if vm, ok := rvm.(map[string]interface{}); ok && vm["name"] == k8sBinVolumeName {
+				return
+			}
+		}
+	}
+
+	initContainers, _ := podSpec["initContainers"].([]interface{})
+	kept := initContainers[:0]
+	for _, ric := range initContainers {
+		if ic, ok := ric.(map[string]interface{}); ok && ic["name"] == k8sInitContainerName {
+			continue
+		}
+		kept = append(kept, ric)
+	}
+	if len(kept) > 0 {
+		podSpec["initContainers"] = kept
+	} else {
+		delete(podSpec, "initContainers")
+	}
+
+	volumes, _ := podSpec["volumes"].([]interface{})
+	keptVolumes := volumes[:0]
+	for _, rv := range volumes {
+		if v, ok := rv.(map[string]interface{}); ok && v["name"] == k8sBinVolumeName {
+			continue
+		}
+		keptVolumes = append(keptVolumes, rv)
+	}
+	if len(keptVolumes) > 0 {
+		podSpec["volumes"] = keptVolumes
+	} else {
+		delete(podSpec, "volumes")
+	}
+}
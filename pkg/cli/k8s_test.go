@@ -0,0 +1,160 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+const testDeploymentManifest = `
+apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: envserver
+spec:
+  template:
+    metadata:
+      labels:
+        app: envserver
+    spec:
+      containers:
+      - name: envserver
+        image: sethvargo/envserver
+        command: ["/bin/envserver"]
+        env:
+        - name: API_KEY
+          value: berglas://berglas-test-secrets/api-key
+`
+
+func TestK8sAnnotateManifests(t *testing.T) {
+	t.Parallel()
+
+	rewritten, err := k8sAnnotateManifests([]byte(testDeploymentManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(rewritten, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	podSpec, _ := k8sFindPodSpec(doc)
+	if podSpec == nil {
+		t.Fatal("expected to find a pod spec")
+	}
+
+	containers := podSpec["containers"].([]interface{})
+	c := containers[0].(map[string]interface{})
+
+	command := c["command"].([]interface{})
+	if act, exp := command[0].(string), k8sBinVolumeMountPath+"berglas"; act != exp {
+		t.Errorf("expected command to be %q, got %q", exp, act)
+	}
+
+	args := c["args"].([]interface{})
+	if act, exp := args[len(args)-1].(string), "/bin/envserver"; act != exp {
+		t.Errorf("expected original command to be preserved in args, got %v", args)
+	}
+
+	if _, ok := c[k8sOriginalCommandAnnotation]; !ok {
+		t.Error("expected original command annotation to be set")
+	}
+
+	initContainers, _ := podSpec["initContainers"].([]interface{})
+	if len(initContainers) != 1 {
+		t.Fatalf("expected exactly one init container, got %d", len(initContainers))
+	}
+
+	// Re-running annotate must be a no-op: it must not double-wrap an
+	// already-wrapped container.
+	rewrittenAgain, err := k8sAnnotateManifests(rewritten)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(rewrittenAgain) != string(rewritten) {
+		t.Error("expected annotating an already-annotated manifest to be a no-op")
+	}
+}
+
+func TestK8sDeannotateManifests(t *testing.T) {
+	t.Parallel()
+
+	annotated, err := k8sAnnotateManifests([]byte(testDeploymentManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored, err := k8sDeannotateManifests(annotated)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(restored, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	podSpec, _ := k8sFindPodSpec(doc)
+	if podSpec == nil {
+		t.Fatal("expected to find a pod spec")
+	}
+
+	if _, ok := podSpec["initContainers"]; ok {
+		t.Error("expected the shared init container to be removed")
+	}
+	if _, ok := podSpec["volumes"]; ok {
+		t.Error("expected the shared volume to be removed")
+	}
+
+	containers := podSpec["containers"].([]interface{})
+	c := containers[0].(map[string]interface{})
+
+	if _, ok := c[k8sOriginalCommandAnnotation]; ok {
+		t.Error("expected original command annotation to be removed")
+	}
+
+	command := c["command"].([]interface{})
+	if act, exp := command[0].(string), "/bin/envserver"; act != exp {
+		t.Errorf("expected command to be restored to %q, got %q", exp, act)
+	}
+	if _, ok := c["args"]; ok {
+		t.Error("expected args to be removed after restoring the original command")
+	}
+}
+
+func TestK8sAnnotateManifests_noReferences(t *testing.T) {
+	t.Parallel()
+
+	manifest := strings.Replace(testDeploymentManifest, "berglas://berglas-test-secrets/api-key", "plain-value", 1)
+
+	rewritten, err := k8sAnnotateManifests([]byte(manifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal(rewritten, &doc); err != nil {
+		t.Fatal(err)
+	}
+
+	podSpec, _ := k8sFindPodSpec(doc)
+	if _, ok := podSpec["initContainers"]; ok {
+		t.Error("expected no init container to be added when no container references a secret")
+	}
+}
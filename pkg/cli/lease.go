@@ -0,0 +1,152 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/server"
+	"github.com/pkg/errors"
+)
+
+// defaultLeaseTTL is used by "berglas server"'s lease endpoints when the
+// caller's request does not include a "ttl" query parameter.
+const defaultLeaseTTL = 15 * time.Minute
+
+// leaseResponse is the JSON body returned by "berglas server"'s lease
+// endpoints: POST /v1/lease/<bucket>/<object>, POST/GET/DELETE
+// /v1/leases/<id>.
+type leaseResponse struct {
+	ID        string    `json:"id"`
+	Bucket    string    `json:"bucket"`
+	Object    string    `json:"object"`
+	Value     []byte    `json:"value"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Revoked   bool      `json:"revoked"`
+}
+
+// parseLeaseTTL reads the "ttl" query parameter (a Go duration string, e.g.
+// "15m") from r, defaulting to defaultLeaseTTL if it is absent.
+func parseLeaseTTL(r *http.Request) (time.Duration, error) {
+	s := r.URL.Query().Get("ttl")
+	if s == "" {
+		return defaultLeaseTTL, nil
+	}
+	ttl, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.Wrap(err, "invalid ttl")
+	}
+	return ttl, nil
+}
+
+// writeLeaseJSON writes lease to w as a leaseResponse. The secret's
+// plaintext value is base64-encoded by encoding/json's []byte handling.
+func writeLeaseJSON(w http.ResponseWriter, lease *server.Lease) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(leaseResponse{
+		ID:        lease.ID,
+		Bucket:    lease.Bucket,
+		Object:    lease.Object,
+		Value:     lease.Plaintext,
+		ExpiresAt: lease.ExpiresAt,
+		Revoked:   lease.Revoked(),
+	})
+}
+
+// leaseClient does HTTP calls against a running "berglas server" process's
+// lease endpoints, optionally presenting a bearer token.
+type leaseClient struct {
+	httpClient *http.Client
+	addr       string
+	token      string
+}
+
+func (lc *leaseClient) do(ctx context.Context, method, path string, query url.Values) (*leaseResponse, error) {
+	u := lc.addr + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	if lc.token != "" {
+		req.Header.Set("Authorization", "Bearer "+lc.token)
+	}
+
+	resp, err := lc.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, errors.Errorf("server returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	if len(body) == 0 {
+		return &leaseResponse{}, nil
+	}
+
+	var lease leaseResponse
+	if err := json.Unmarshal(body, &lease); err != nil {
+		return nil, errors.Wrap(err, "failed to parse server response")
+	}
+	return &lease, nil
+}
+
+// acquire calls POST /v1/lease/<bucket>/<object>.
+func (lc *leaseClient) acquire(ctx context.Context, bucket, object string, ttl time.Duration) (*leaseResponse, error) {
+	return lc.do(ctx, http.MethodPost, "/v1/lease/"+bucket+"/"+object,
+		url.Values{"ttl": {ttl.String()}})
+}
+
+// renew calls POST /v1/leases/<id>.
+func (lc *leaseClient) renew(ctx context.Context, id string, ttl time.Duration) (*leaseResponse, error) {
+	return lc.do(ctx, http.MethodPost, "/v1/leases/"+id,
+		url.Values{"ttl": {ttl.String()}})
+}
+
+// revoke calls DELETE /v1/leases/<id>.
+func (lc *leaseClient) revoke(ctx context.Context, id string) error {
+	_, err := lc.do(ctx, http.MethodDelete, "/v1/leases/"+id, nil)
+	return err
+}
+
+// wait calls GET /v1/leases/<id>, which the server holds open until the
+// lease is revoked or expired, or the request's own context is canceled.
+func (lc *leaseClient) wait(ctx context.Context, id string) (*leaseResponse, error) {
+	return lc.do(ctx, http.MethodGet, "/v1/leases/"+id, nil)
+}
+
+// leaseIDArg validates that args contains exactly one non-empty lease ID and
+// returns it.
+func leaseIDArg(args []string) (string, error) {
+	if len(args) != 1 || args[0] == "" {
+		return "", errors.New("missing lease ID")
+	}
+	return args[0], nil
+}
@@ -0,0 +1,51 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// credentialsSupported reports whether this platform can run a child as a
+// different uid/gid, for "exec --user"/"--group".
+const credentialsSupported = true
+
+// applyCredential configures cmd to run as uid/gid, and any supplementary
+// groups, instead of berglas's own.
+func applyCredential(cmd *exec.Cmd, uid, gid uint32, groups []uint32) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Credential = &syscall.Credential{
+		Uid:    uid,
+		Gid:    gid,
+		Groups: groups,
+	}
+}
+
+// umaskSupported reports whether this platform has a process umask, for
+// "exec --umask".
+const umaskSupported = true
+
+// applyUmask sets the process umask and returns the previous value. It must
+// be called immediately before spawning the child, and undone immediately
+// after, since the umask is a process-wide attribute inherited at fork time
+// rather than something exec.Cmd can scope to just the child.
+func applyUmask(mask int) int {
+	return syscall.Umask(mask)
+}
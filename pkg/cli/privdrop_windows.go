@@ -0,0 +1,33 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package cli
+
+import "os/exec"
+
+// credentialsSupported reports whether this platform can run a child as a
+// different uid/gid, for "exec --user"/"--group". Windows has no POSIX
+// process credentials, so it's rejected as a misuse error before
+// applyCredential would ever be called.
+const credentialsSupported = false
+
+func applyCredential(cmd *exec.Cmd, uid, gid uint32, groups []uint32) {}
+
+// umaskSupported reports whether this platform has a process umask, for
+// "exec --umask". Windows has no such concept.
+const umaskSupported = false
+
+func applyUmask(mask int) int { return 0 }
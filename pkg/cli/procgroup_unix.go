@@ -0,0 +1,30 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"io"
+	"os/exec"
+)
+
+// prepareChildProcessGroup is a no-op on POSIX platforms; process groups and
+// signal forwarding already work without Windows's job-object/console
+// machinery.
+func prepareChildProcessGroup(cmd *exec.Cmd) {}
+
+// confineToJobObject is a no-op on POSIX platforms; see procgroup_windows.go.
+func confineToJobObject(pid int) (io.Closer, error) { return nil, nil }
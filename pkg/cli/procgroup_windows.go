@@ -0,0 +1,88 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package cli
+
+import (
+	"io"
+	"os/exec"
+	"syscall"
+	"unsafe"
+
+	"github.com/pkg/errors"
+	"golang.org/x/sys/windows"
+)
+
+// prepareChildProcessGroup configures cmd to start in its own process group,
+// so a CTRL_BREAK_EVENT sent to that group (see stopGracefully, via
+// os.Process.Signal) reaches the child without also hitting berglas's own
+// console session.
+func prepareChildProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= syscall.CREATE_NEW_PROCESS_GROUP
+}
+
+// confineToJobObject puts pid in a new Windows job object with
+// JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE, so the entire child tree (including
+// any grandchildren the child itself spawns) is terminated the moment the
+// returned handle is closed - including implicitly by the OS if berglas
+// itself dies without running its cleanup, which plain process forwarding
+// can't guarantee. Close the returned handle once the child is no longer
+// needed.
+func confineToJobObject(pid int) (io.Closer, error) {
+	job, err := windows.CreateJobObject(nil, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create job object")
+	}
+
+	info := windows.JOBOBJECT_EXTENDED_LIMIT_INFORMATION{
+		BasicLimitInformation: windows.JOBOBJECT_BASIC_LIMIT_INFORMATION{
+			LimitFlags: windows.JOB_OBJECT_LIMIT_KILL_ON_JOB_CLOSE,
+		},
+	}
+	if _, err := windows.SetInformationJobObject(
+		job,
+		windows.JobObjectExtendedLimitInformation,
+		uintptr(unsafe.Pointer(&info)),
+		uint32(unsafe.Sizeof(info)),
+	); err != nil {
+		windows.CloseHandle(job)
+		return nil, errors.Wrap(err, "failed to configure job object")
+	}
+
+	process, err := windows.OpenProcess(windows.PROCESS_SET_QUOTA|windows.PROCESS_TERMINATE, false, uint32(pid))
+	if err != nil {
+		windows.CloseHandle(job)
+		return nil, errors.Wrap(err, "failed to open child process")
+	}
+	defer windows.CloseHandle(process)
+
+	if err := windows.AssignProcessToJobObject(job, process); err != nil {
+		windows.CloseHandle(job)
+		return nil, errors.Wrap(err, "failed to assign child to job object")
+	}
+
+	return jobHandle(job), nil
+}
+
+// jobHandle adapts a windows.Handle to io.Closer.
+type jobHandle windows.Handle
+
+func (h jobHandle) Close() error {
+	return windows.CloseHandle(windows.Handle(h))
+}
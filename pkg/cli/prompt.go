@@ -0,0 +1,86 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/term"
+)
+
+// promptSecret reads a secret value from the terminal with echo disabled,
+// the way "ssh-keygen" reads a passphrase, so the value never appears in
+// shell history or in "ps" output the way a literal DATA argument does. If
+// confirm is true, the value is read a second time and must match, so a
+// typo is caught immediately instead of only when the secret is later read
+// back and doesn't work.
+func (c *CLI) promptSecret(prompt string, confirm bool) ([]byte, error) {
+	f, ok := c.Stdin.(*os.File)
+	if !ok || !term.IsTerminal(int(f.Fd())) {
+		return nil, errors.New("--prompt requires stdin to be an interactive terminal")
+	}
+
+	value, err := c.promptSecretOnce(f, prompt)
+	if err != nil {
+		return nil, err
+	}
+
+	if confirm {
+		again, err := c.promptSecretOnce(f, "Confirm: ")
+		if err != nil {
+			return nil, err
+		}
+		if !bytes.Equal(value, again) {
+			return nil, errors.New("secret and confirmation do not match")
+		}
+	}
+
+	return value, nil
+}
+
+// promptSecretOnce prints prompt to Stderr and reads a single hidden line
+// from f.
+func (c *CLI) promptSecretOnce(f *os.File, prompt string) ([]byte, error) {
+	fmt.Fprint(c.Stderr, prompt)
+	value, err := term.ReadPassword(int(f.Fd()))
+	fmt.Fprintln(c.Stderr)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read secret from terminal")
+	}
+	return value, nil
+}
+
+// createReadPlaintext reads the plaintext for "create"'s SECRET [DATA]
+// arguments: from an interactive, confirmed prompt if prompt is true (in
+// which case DATA must be omitted), otherwise from the required DATA
+// argument via readData.
+func (c *CLI) createReadPlaintext(args []string, prompt bool) ([]byte, error) {
+	if prompt {
+		if len(args) != 1 {
+			return nil, errors.New("DATA and --prompt are mutually exclusive")
+		}
+		return c.promptSecret(fmt.Sprintf("Enter secret value for %s: ", args[0]), true)
+	}
+
+	if len(args) != 2 {
+		return nil, errors.New("accepts 2 arg(s), received " + strconv.Itoa(len(args)))
+	}
+	return c.readData(strings.TrimSpace(args[1]))
+}
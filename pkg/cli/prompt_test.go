@@ -0,0 +1,63 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"testing"
+)
+
+func Test_promptSecret_notATerminal(t *testing.T) {
+	t.Parallel()
+
+	var stderr bytes.Buffer
+	c := New(WithStderr(&stderr))
+
+	if _, err := c.promptSecret("Enter secret: ", true); err == nil {
+		t.Error("expected an error when stdin is not a terminal")
+	}
+}
+
+func Test_createReadPlaintext(t *testing.T) {
+	t.Parallel()
+
+	t.Run("data", func(t *testing.T) {
+		t.Parallel()
+
+		r, err := new(CLI).createReadPlaintext([]string{"my-secrets/foo", "bar"}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if act, exp := r, []byte("bar"); !bytes.Equal(act, exp) {
+			t.Errorf("expected %q to be %q", act, exp)
+		}
+	})
+
+	t.Run("missing data", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := new(CLI).createReadPlaintext([]string{"my-secrets/foo"}, false); err == nil {
+			t.Error("expected an error for a missing DATA argument")
+		}
+	})
+
+	t.Run("prompt with data", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := new(CLI).createReadPlaintext([]string{"my-secrets/foo", "bar"}, true); err == nil {
+			t.Error("expected an error when DATA and --prompt are both given")
+		}
+	})
+}
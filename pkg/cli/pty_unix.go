@@ -0,0 +1,59 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+
+	"github.com/creack/pty"
+)
+
+// ttySupported reports whether this platform can allocate a pseudo-terminal
+// for the child, for "exec --tty".
+const ttySupported = true
+
+// startWithTTY starts cmd attached to a new pseudo-terminal instead of
+// piping its stdin/stdout/stderr directly, returning the PTY's controlling
+// end for the caller to copy to and from.
+func startWithTTY(cmd *exec.Cmd) (*os.File, error) {
+	return pty.Start(cmd)
+}
+
+// watchWindowSize keeps ptmx's window size matched to stdin's for as long as
+// stdin is a terminal, so a resized "exec --tty" window is reflected in the
+// child (e.g. a shell's $COLUMNS/$LINES). It sets the size once immediately,
+// then again on every SIGWINCH, until doneCh is closed.
+func watchWindowSize(stdin, ptmx *os.File, doneCh <-chan struct{}) {
+	_ = pty.InheritSize(stdin, ptmx)
+
+	winCh := make(chan os.Signal, 1)
+	signal.Notify(winCh, syscall.SIGWINCH)
+	go func() {
+		for {
+			select {
+			case <-winCh:
+				_ = pty.InheritSize(stdin, ptmx)
+			case <-doneCh:
+				signal.Stop(winCh)
+				return
+			}
+		}
+	}()
+}
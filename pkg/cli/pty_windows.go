@@ -0,0 +1,31 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package cli
+
+import (
+	"os"
+	"os/exec"
+)
+
+// ttySupported reports whether this platform can allocate a pseudo-terminal
+// for the child, for "exec --tty". Windows is rejected as a misuse error
+// before startWithTTY would ever be called.
+const ttySupported = false
+
+func startWithTTY(cmd *exec.Cmd) (*os.File, error) { return nil, nil }
+
+func watchWindowSize(stdin, ptmx *os.File, doneCh <-chan struct{}) {}
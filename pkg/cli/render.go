@@ -0,0 +1,75 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"text/template"
+)
+
+// renderFuncMap builds the function map available to "berglas render"
+// templates: "secret", which resolves a berglas reference via resolve, and a
+// small set of sprig-style string helpers commonly needed in config
+// templates, so most files don't also need a separate templating tool.
+func renderFuncMap(resolve func(ref string) (string, error)) template.FuncMap {
+	return template.FuncMap{
+		"secret": resolve,
+
+		"default": func(def, val string) string {
+			if val == "" {
+				return def
+			}
+			return val
+		},
+		"upper":      strings.ToUpper,
+		"lower":      strings.ToLower,
+		"trim":       strings.TrimSpace,
+		"trimPrefix": func(prefix, s string) string { return strings.TrimPrefix(s, prefix) },
+		"trimSuffix": func(suffix, s string) string { return strings.TrimSuffix(s, suffix) },
+		"replace":    func(old, new, s string) string { return strings.ReplaceAll(s, old, new) },
+		"contains":   func(substr, s string) bool { return strings.Contains(s, substr) },
+		"quote":      func(s string) string { return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"` },
+		"indent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"nindent": func(spaces int, s string) string {
+			pad := strings.Repeat(" ", spaces)
+			return "\n" + pad + strings.ReplaceAll(s, "\n", "\n"+pad)
+		},
+		"b64enc": func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) },
+		"b64dec": func(s string) (string, error) {
+			data, err := base64.StdEncoding.DecodeString(s)
+			return string(data), err
+		},
+	}
+}
+
+// renderTemplate parses tmplData as a Go template named name and executes it
+// with renderFuncMap's functions, returning the rendered output.
+func renderTemplate(name string, tmplData []byte, resolve func(ref string) (string, error)) ([]byte, error) {
+	tmpl, err := template.New(name).Funcs(renderFuncMap(resolve)).Parse(string(tmplData))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, nil); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"errors"
+	"testing"
+)
+
+func Test_renderTemplate(t *testing.T) {
+	t.Parallel()
+
+	resolve := func(ref string) (string, error) {
+		if ref == "berglas://bucket/db-password" {
+			return "hunter2", nil
+		}
+		return "", errors.New("no such secret")
+	}
+
+	tmpl := `password = {{ secret "berglas://bucket/db-password" | quote }}
+name = {{ default "fallback" "" }}
+`
+	want := `password = "hunter2"
+name = fallback
+`
+	got, err := renderTemplate("t", []byte(tmpl), resolve)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != want {
+		t.Errorf("expected %q, got %q", want, string(got))
+	}
+}
+
+func Test_renderTemplate_resolveError(t *testing.T) {
+	t.Parallel()
+
+	resolve := func(ref string) (string, error) {
+		return "", errors.New("boom")
+	}
+
+	if _, err := renderTemplate("t", []byte(`{{ secret "berglas://bucket/x" }}`), resolve); err == nil {
+		t.Error("expected error")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/bigquery"
+	"cloud.google.com/go/storage"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/pkg/errors"
+)
+
+// reportRow is a single secret's inventory, IAM, and rotation-status
+// snapshot. This schema is exported as-is to both destinations supported by
+// "berglas report export", so a dashboard built against one works unchanged
+// against the other.
+type reportRow struct {
+	Bucket       string    `json:"bucket" yaml:"bucket" bigquery:"bucket"`
+	Secret       string    `json:"secret" yaml:"secret" bigquery:"secret"`
+	Generation   int64     `json:"generation" yaml:"generation" bigquery:"generation"`
+	KMSKey       string    `json:"kms_key" yaml:"kms_key" bigquery:"kms_key"`
+	UpdatedAt    time.Time `json:"updated_at" yaml:"updated_at" bigquery:"updated_at"`
+	RetainUntil  time.Time `json:"retain_until,omitempty" yaml:"retain_until,omitempty" bigquery:"retain_until"`
+	LastAccessed time.Time `json:"last_accessed,omitempty" yaml:"last_accessed,omitempty" bigquery:"last_accessed"`
+	IAMMembers   []string  `json:"iam_members,omitempty" yaml:"iam_members,omitempty" bigquery:"iam_members"`
+	SnapshotAt   time.Time `json:"snapshot_at" yaml:"snapshot_at" bigquery:"snapshot_at"`
+}
+
+// collectReportRows lists the live secrets in each of buckets and snapshots
+// each one's inventory and IAM state as of snapshotAt.
+func collectReportRows(ctx context.Context, client *berglas.Client, buckets []string, snapshotAt time.Time) ([]*reportRow, error) {
+	var rows []*reportRow
+
+	for _, bucket := range buckets {
+		list, err := client.List(ctx, &berglas.ListRequest{Bucket: bucket})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list bucket %s", bucket)
+		}
+
+		for _, s := range list.Secrets {
+			members, err := client.IAMMembers(ctx, bucket, s.Name)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to read IAM members for %s/%s", bucket, s.Name)
+			}
+
+			rows = append(rows, &reportRow{
+				Bucket:       bucket,
+				Secret:       s.Name,
+				Generation:   s.Generation,
+				KMSKey:       s.KMSKey,
+				UpdatedAt:    s.UpdatedAt,
+				RetainUntil:  s.RetainUntil,
+				LastAccessed: s.LastAccessed,
+				IAMMembers:   members,
+				SnapshotAt:   snapshotAt,
+			})
+		}
+	}
+
+	return rows, nil
+}
+
+// exportReportRows writes rows to to, which must be either
+// "bigquery://project.dataset.table" (streamed in with the BigQuery
+// Inserter) or "gs://bucket/object" (written as a single newline-delimited
+// JSON object, one line per row).
+func (c *CLI) exportReportRows(ctx context.Context, impersonate, to string, rows []*reportRow) error {
+	opts, err := c.clientOptions(ctx, impersonate, "", "", "")
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case strings.HasPrefix(to, "bigquery://"):
+		project, dataset, table, err := parseBigQueryTable(strings.TrimPrefix(to, "bigquery://"))
+		if err != nil {
+			return err
+		}
+
+		bqClient, err := bigquery.NewClient(ctx, project, opts...)
+		if err != nil {
+			return errors.Wrap(err, "failed to create bigquery client")
+		}
+		defer bqClient.Close()
+
+		if err := bqClient.DatasetInProject(project, dataset).Table(table).Inserter().Put(ctx, rows); err != nil {
+			return errors.Wrap(err, "failed to insert rows into bigquery")
+		}
+		return nil
+
+	case strings.HasPrefix(to, "gs://"):
+		bucket, object, err := parseRef(to)
+		if err != nil {
+			return err
+		}
+
+		storageClient, err := storage.NewClient(ctx, opts...)
+		if err != nil {
+			return errors.Wrap(err, "failed to create storage client")
+		}
+		defer storageClient.Close()
+
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for _, row := range rows {
+			if err := enc.Encode(row); err != nil {
+				return errors.Wrap(err, "failed to encode row")
+			}
+		}
+
+		w := storageClient.Bucket(bucket).Object(object).NewWriter(ctx)
+		w.ContentType = "application/x-ndjson"
+		if _, err := w.Write(buf.Bytes()); err != nil {
+			return errors.Wrap(err, "failed to write report")
+		}
+		return w.Close()
+
+	default:
+		return errors.Errorf(`unknown export destination %q - must start with "bigquery://" or "gs://"`, to)
+	}
+}
+
+// parseBigQueryTable splits a "project.dataset.table" reference into its
+// three parts.
+func parseBigQueryTable(s string) (project, dataset, table string, err error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", errors.Errorf(`invalid bigquery table %q - expected "project.dataset.table"`, s)
+	}
+	return parts[0], parts[1], parts[2], nil
+}
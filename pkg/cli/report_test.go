@@ -0,0 +1,53 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import "testing"
+
+func Test_parseBigQueryTable(t *testing.T) {
+	t.Parallel()
+
+	cases := []struct {
+		name    string
+		table   string
+		project string
+		dataset string
+		tbl     string
+		exp     bool
+	}{
+		{"valid", "my-project.my_dataset.my_table", "my-project", "my_dataset", "my_table", true},
+		{"missing parts", "my-project.my_dataset", "", "", "", false},
+		{"too many parts", "a.b.c.d", "", "", "", false},
+		{"empty part", "my-project..my_table", "", "", "", false},
+		{"empty", "", "", "", "", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			project, dataset, tbl, err := parseBigQueryTable(tc.table)
+			if (err == nil) != tc.exp {
+				t.Fatalf("parseBigQueryTable(%q) err = %v, want ok = %t", tc.table, err, tc.exp)
+			}
+			if err == nil && (project != tc.project || dataset != tc.dataset || tbl != tc.tbl) {
+				t.Errorf("parseBigQueryTable(%q) = (%q, %q, %q), want (%q, %q, %q)",
+					tc.table, project, dataset, tbl, tc.project, tc.dataset, tc.tbl)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// runConfig is the structure of "berglas run"'s declarative secrets mapping
+// file (default "berglas.yaml"): environment variables and file
+// destinations mapped to references, plus the command to run if one isn't
+// given after "--". Keeping this versioned next to the app avoids encoding
+// long lists of references directly into a deployment's environment.
+type runConfig struct {
+	Command []string          `yaml:"command"`
+	Env     map[string]string `yaml:"env"`
+	Files   map[string]string `yaml:"files"`
+}
+
+// loadRunConfig reads and parses "berglas run"'s config file at path.
+func loadRunConfig(path string) (*runConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read config")
+	}
+
+	var cfg runConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse config %s", path)
+	}
+	return &cfg, nil
+}
+
+// renderEnvMapFile renders env into "exec --map"'s ENV_NAME=berglas://...
+// file format, one pair per line, sorted by name for a deterministic,
+// diff-friendly temporary file.
+func renderEnvMapFile(env map[string]string) []byte {
+	names := make([]string, 0, len(env))
+	for name := range env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range names {
+		fmt.Fprintf(&buf, "%s=%s\n", name, env[name])
+	}
+	return buf.Bytes()
+}
+
+// secretFilePairs renders files into "exec --secret-file"'s PATH=REF flag
+// values, sorted for deterministic ordering.
+func secretFilePairs(files map[string]string) []string {
+	if len(files) == 0 {
+		return nil
+	}
+
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	pairs := make([]string, 0, len(paths))
+	for _, path := range paths {
+		pairs = append(pairs, path+"="+files[path])
+	}
+	return pairs
+}
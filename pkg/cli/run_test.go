@@ -0,0 +1,104 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func Test_loadRunConfig(t *testing.T) {
+	t.Parallel()
+
+	f, err := ioutil.TempFile("", "berglas-run-test-*.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(f.Name())
+
+	const config = `
+env:
+  DATABASE_PASSWORD: berglas://bucket/db-password
+files:
+  /etc/secrets/tls.key: berglas://bucket/tls-key
+command: ["myapp", "--flag"]
+`
+	if _, err := f.WriteString(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadRunConfig(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want, got := []string{"myapp", "--flag"}, cfg.Command; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected command %v, got %v", want, got)
+	}
+	if want, got := "berglas://bucket/db-password", cfg.Env["DATABASE_PASSWORD"]; got != want {
+		t.Errorf("expected env %q, got %q", want, got)
+	}
+	if want, got := "berglas://bucket/tls-key", cfg.Files["/etc/secrets/tls.key"]; got != want {
+		t.Errorf("expected file %q, got %q", want, got)
+	}
+}
+
+func Test_loadRunConfig_missing(t *testing.T) {
+	t.Parallel()
+
+	if _, err := loadRunConfig("/nonexistent/berglas.yaml"); err == nil {
+		t.Error("expected error")
+	}
+}
+
+func Test_renderEnvMapFile(t *testing.T) {
+	t.Parallel()
+
+	env := map[string]string{
+		"DB_PASSWORD": "berglas://bucket/db-password",
+		"API_KEY":     "berglas://bucket/api-key",
+	}
+
+	want := "API_KEY=berglas://bucket/api-key\nDB_PASSWORD=berglas://bucket/db-password\n"
+	if got := string(renderEnvMapFile(env)); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+func Test_secretFilePairs(t *testing.T) {
+	t.Parallel()
+
+	files := map[string]string{
+		"/etc/secrets/tls.key": "berglas://bucket/tls-key",
+		"/etc/secrets/tls.crt": "berglas://bucket/tls-cert",
+	}
+
+	want := []string{
+		"/etc/secrets/tls.crt=berglas://bucket/tls-cert",
+		"/etc/secrets/tls.key=berglas://bucket/tls-key",
+	}
+	if got := secretFilePairs(files); !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+
+	if got := secretFilePairs(nil); got != nil {
+		t.Errorf("expected nil, got %v", got)
+	}
+}
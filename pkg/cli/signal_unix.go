@@ -0,0 +1,51 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"os"
+	"syscall"
+)
+
+// signalsByName maps the signal names accepted by "exec --on-change" (with
+// their "SIG" prefix already stripped, see parseSignal) to the os.Signal to
+// send, for platforms with a full POSIX signal set.
+var signalsByName = map[string]os.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"TERM": syscall.SIGTERM,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"KILL": syscall.SIGKILL,
+}
+
+// terminateSignal is the signal stopGracefully sends a child to ask it to
+// shut down before escalating to a hard kill.
+var terminateSignal os.Signal = syscall.SIGTERM
+
+// defaultForwardSignalNames is what "exec -forward-signals" restricts raw
+// signal forwarding to when the flag isn't given - a safe subset that
+// excludes things like SIGCHLD and SIGURG, which confuse children that
+// don't expect them.
+var defaultForwardSignalNames = []string{"HUP", "INT", "QUIT", "TERM", "USR1", "USR2"}
+
+// stopTriggerSignals are always watched in addition to whatever
+// "exec -forward-signals" resolves to, and are never forwarded raw: they
+// instead ask the child to stop via "-stop-signal"/"-stop-timeout" (see
+// isStopTriggerSignal).
+var stopTriggerSignals = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
@@ -0,0 +1,205 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cli
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// telemetryFileEnv overrides the path of the local telemetry audit file.
+// telemetryEndpointEnv sets the default --telemetry-endpoint value.
+const (
+	telemetryFileEnv     = "BERGLAS_TELEMETRY_FILE"
+	telemetryEndpointEnv = "BERGLAS_TELEMETRY_ENDPOINT"
+)
+
+// telemetryConfig holds the telemetry flags parsed by newRootCmd. Telemetry
+// is entirely opt-in: Enabled defaults to false and must be set with
+// "--telemetry" or the BERGLAS_TELEMETRY=1 environment variable.
+type telemetryConfig struct {
+	// Enabled turns on telemetry recording for this invocation.
+	Enabled bool
+
+	// Endpoint, if set, receives a copy of each recorded event as a JSON
+	// POST body. Recording to the local audit file always happens when
+	// Enabled is true, regardless of Endpoint.
+	Endpoint string
+}
+
+// telemetryEvent is a single recorded command invocation. It never contains
+// secret names, bucket/object names, or secret values - only the command
+// that ran, when, and a coarse, secret-free error classification.
+type telemetryEvent struct {
+	Time       time.Time `json:"time"`
+	Command    string    `json:"command"`
+	ErrorClass string    `json:"error_class"`
+}
+
+// telemetryErrorClass classifies err into a coarse, secret-free category
+// suitable for recording. It never includes err's message, since wrapped
+// errors may reference bucket or secret names.
+func telemetryErrorClass(err error) string {
+	if err == nil {
+		return "ok"
+	}
+
+	if terr, ok := err.(*exitError); ok {
+		switch terr.code {
+		case MisuseExitCode:
+			return "misuse"
+		case APIExitCode:
+			return "api"
+		}
+	}
+
+	return "error"
+}
+
+// telemetryFilePath returns the path of the local telemetry audit file,
+// honoring BERGLAS_TELEMETRY_FILE. Returns "" if no home directory can be
+// determined and the override is unset.
+func telemetryFilePath() string {
+	if f := os.Getenv(telemetryFileEnv); f != "" {
+		return f
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(home, ".berglas", "telemetry.jsonl")
+}
+
+// recordTelemetry appends ev to the local telemetry audit file and, if cfg
+// has an Endpoint configured, best-effort POSTs it there as well. Telemetry
+// is never recorded unless cfg.Enabled, and failures to record it are never
+// surfaced to the user or treated as a command failure.
+func recordTelemetry(cfg *telemetryConfig, ev telemetryEvent) {
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+
+	data, err := json.Marshal(ev)
+	if err != nil {
+		return
+	}
+
+	if path := telemetryFilePath(); path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err == nil {
+			if f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600); err == nil {
+				f.Write(append(data, '\n'))
+				f.Close()
+			}
+		}
+	}
+
+	if cfg.Endpoint != "" {
+		client := &http.Client{Timeout: 5 * time.Second}
+		resp, err := client.Post(cfg.Endpoint, "application/json", bytes.NewReader(data))
+		if err == nil {
+			resp.Body.Close()
+		}
+	}
+}
+
+// telemetryShowRun implements "berglas telemetry show": it reads the local
+// telemetry audit file and prints a summary of command usage counts and
+// error class counts, so teams can see which workflows are used most
+// without any command ever reporting secret names or values.
+func (c *CLI) telemetryShowRun() error {
+	path := telemetryFilePath()
+	if path == "" {
+		fmt.Fprintln(c.Stdout, "No telemetry recorded.")
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			fmt.Fprintln(c.Stdout, "No telemetry recorded.")
+			return nil
+		}
+		return errors.Wrap(err, "failed to read telemetry file")
+	}
+
+	commandCounts := make(map[string]int64)
+	errorCounts := make(map[string]int64)
+
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+
+		var ev telemetryEvent
+		if err := json.Unmarshal(line, &ev); err != nil {
+			continue
+		}
+
+		commandCounts[ev.Command]++
+		if ev.ErrorClass != "" && ev.ErrorClass != "ok" {
+			errorCounts[ev.ErrorClass]++
+		}
+	}
+
+	if len(commandCounts) == 0 {
+		fmt.Fprintln(c.Stdout, "No telemetry recorded.")
+		return nil
+	}
+
+	commands := make([]string, 0, len(commandCounts))
+	for cmd := range commandCounts {
+		commands = append(commands, cmd)
+	}
+	sort.Strings(commands)
+
+	tw := new(tabwriter.Writer)
+	tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+	fmt.Fprintf(tw, "COMMAND\tRUNS\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "%s\t%d\n", cmd, commandCounts[cmd])
+	}
+	tw.Flush()
+
+	if len(errorCounts) > 0 {
+		errClasses := make([]string, 0, len(errorCounts))
+		for ec := range errorCounts {
+			errClasses = append(errClasses, ec)
+		}
+		sort.Strings(errClasses)
+
+		fmt.Fprintln(c.Stdout)
+		tw := new(tabwriter.Writer)
+		tw.Init(c.Stdout, 0, 4, 4, ' ', 0)
+		fmt.Fprintf(tw, "ERROR CLASS\tCOUNT\n")
+		for _, ec := range errClasses {
+			fmt.Fprintf(tw, "%s\t%d\n", ec, errorCounts[ec])
+		}
+		tw.Flush()
+	}
+
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// ramTempDirs are checked, in order, for a tmpfs-backed directory to write
+// secret plaintext to, so it's never written to a persistent disk.
+var ramTempDirs = []string{"/dev/shm", "/run/shm"}
+
+// secureTempDir returns a RAM-backed directory to use for secret tempfiles,
+// or "" if none is available on this system.
+func secureTempDir() string {
+	for _, dir := range ramTempDirs {
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
+	}
+	return ""
+}
+
+// checkTempDirSafe returns an error if dir is world-writable without the
+// sticky bit set, meaning any other local user could replace or read a
+// secret tempfile created there before we do.
+func checkTempDirSafe(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return err
+	}
+	mode := info.Mode()
+	if mode&0002 != 0 && mode&os.ModeSticky == 0 {
+		return errors.Errorf("tempdir %s is world-writable without the sticky bit set; "+
+			"pass --insecure-tempdir to use it anyway", dir)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build !windows
+
+package cli
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+func Test_checkTempDirSafe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("world-writable without sticky bit", func(t *testing.T) {
+		t.Parallel()
+
+		dir, err := ioutil.TempDir("", "berglas-tempdir-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := os.Chmod(dir, 0777); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := checkTempDirSafe(dir); err == nil {
+			t.Error("expected an error for a world-writable directory without the sticky bit")
+		}
+	})
+
+	t.Run("world-writable with sticky bit", func(t *testing.T) {
+		t.Parallel()
+
+		dir, err := ioutil.TempDir("", "berglas-tempdir-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := os.Chmod(dir, 0777|os.ModeSticky); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := checkTempDirSafe(dir); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+
+	t.Run("not world-writable", func(t *testing.T) {
+		t.Parallel()
+
+		dir, err := ioutil.TempDir("", "berglas-tempdir-test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer os.RemoveAll(dir)
+
+		if err := os.Chmod(dir, 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := checkTempDirSafe(dir); err != nil {
+			t.Errorf("expected no error, got %s", err)
+		}
+	})
+}
@@ -0,0 +1,31 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// +build windows
+
+package cli
+
+// secureTempDir returns a RAM-backed directory to use for secret tempfiles,
+// or "" if none is available. Windows has no standard tmpfs-equivalent
+// directory, so this always returns "".
+func secureTempDir() string {
+	return ""
+}
+
+// checkTempDirSafe returns an error if dir is unsafe to write a secret
+// tempfile to. Windows ACLs don't map onto the Unix world-writable/sticky-bit
+// model this guards against, so this is always a no-op.
+func checkTempDirSafe(dir string) error {
+	return nil
+}
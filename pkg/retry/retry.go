@@ -16,6 +16,7 @@ package retry
 
 import (
 	"context"
+	"math/rand"
 	"sync"
 	"time"
 )
@@ -69,6 +70,13 @@ func RetryExp(ctx context.Context, base time.Duration, maxAttempts int, f RetryF
 	return Retry(ctx, ExponentialBackoff(base, maxAttempts), f)
 }
 
+// RetryExpJitter is a wrapper around Retry that uses an exponential backoff
+// with full jitter, capped by maxElapsed total wait time in addition to
+// maxAttempts. See ExponentialBackoffWithJitter.
+func RetryExpJitter(ctx context.Context, base time.Duration, maxAttempts int, maxElapsed time.Duration, f RetryFunc) error {
+	return Retry(ctx, ExponentialBackoffWithJitter(base, maxAttempts, maxElapsed), f)
+}
+
 // Backoff is an interface that backs off.
 type Backoff interface {
 	Next() (next time.Duration, stop bool)
@@ -134,3 +142,46 @@ func (b *exponentialBackoff) Next() (time.Duration, bool) {
 
 	return next, false
 }
+
+// ExponentialBackoffWithJitter creates an exponential backoff that doubles
+// on every attempt, applies full jitter (each returned wait is a random
+// duration between zero and the doubled value, to keep many clients hitting
+// the same quota from retrying in lockstep), and stops once either
+// maxAttempts has been reached or, if maxElapsed is non-zero, the total time
+// requested across all waits would exceed maxElapsed.
+func ExponentialBackoffWithJitter(base time.Duration, maxAttempts int, maxElapsed time.Duration) Backoff {
+	return &jitterBackoff{
+		next:        base,
+		maxAttempts: maxAttempts,
+		maxElapsed:  maxElapsed,
+	}
+}
+
+type jitterBackoff struct {
+	sync.Mutex
+	next        time.Duration
+	maxAttempts int
+	maxElapsed  time.Duration
+	attempts    int
+	elapsed     time.Duration
+}
+
+func (b *jitterBackoff) Next() (time.Duration, bool) {
+	b.Lock()
+	defer b.Unlock()
+
+	b.attempts++
+	if b.attempts > b.maxAttempts {
+		return 0, true
+	}
+
+	wait := time.Duration(rand.Int63n(int64(b.next) + 1))
+	b.next *= 2
+
+	if b.maxElapsed > 0 && b.elapsed+wait > b.maxElapsed {
+		return 0, true
+	}
+	b.elapsed += wait
+
+	return wait, false
+}
@@ -0,0 +1,116 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"strings"
+)
+
+// allPaths is the sentinel path prefix that grants a token access to every
+// secret path.
+const allPaths = "*"
+
+// Authorizer authenticates a bearer token and decides whether it is
+// permitted to access the given secret path (in "bucket/object" form).
+type Authorizer interface {
+	Authorize(token, path string) bool
+}
+
+// TokenAuthorizer is an Authorizer backed by a static map of bearer tokens to
+// the secret path prefixes each token may access. A token may be granted
+// access to every path with the prefix "*".
+type TokenAuthorizer map[string][]string
+
+// Authorize implements Authorizer.
+func (a TokenAuthorizer) Authorize(token, path string) bool {
+	if token == "" {
+		return false
+	}
+
+	prefixes, ok := a[token]
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range prefixes {
+		if prefix == allPaths || strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// PathAllowlist is an Authorizer that scopes access by secret path alone,
+// ignoring the caller's token entirely. It exists for sidecars that fetch
+// secrets over plain loopback HTTP and have no bearer token to present, but
+// still need to be restricted to the secrets they are allowed to read. Each
+// entry is a path prefix; a trailing "*" matches any path sharing that
+// prefix, and the bare "*" matches every path.
+type PathAllowlist []string
+
+// Authorize implements Authorizer.
+func (a PathAllowlist) Authorize(_, path string) bool {
+	for _, pattern := range a {
+		if pattern == allPaths {
+			return true
+		}
+
+		prefix := strings.TrimSuffix(pattern, allPaths)
+		if prefix != pattern {
+			if strings.HasPrefix(path, prefix) {
+				return true
+			}
+			continue
+		}
+
+		if pattern == path {
+			return true
+		}
+	}
+	return false
+}
+
+// requireAuth wraps h so that requests must be authorized (via the server's
+// Authorizer) for the path that pathFunc derives from the request. The
+// caller's bearer token, if any, is passed to the Authorizer, which may
+// ignore it entirely (see PathAllowlist). If no Authorizer is configured,
+// all requests are allowed, preserving the server's prior unauthenticated
+// behavior.
+func (s *Server) requireAuth(pathFunc func(*http.Request) string, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authz == nil {
+			h(w, r)
+			return
+		}
+
+		if !s.authz.Authorize(bearerToken(r), pathFunc(r)) {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		h(w, r)
+	}
+}
+
+// bearerToken extracts the token from a "Authorization: Bearer <token>"
+// header, returning an empty string if the header is missing or malformed.
+func bearerToken(r *http.Request) string {
+	h := r.Header.Get("Authorization")
+	const prefix = "Bearer "
+	if !strings.HasPrefix(h, prefix) {
+		return ""
+	}
+	return strings.TrimPrefix(h, prefix)
+}
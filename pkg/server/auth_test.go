@@ -0,0 +1,119 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestTokenAuthorizer_Authorize(t *testing.T) {
+	t.Parallel()
+
+	authz := TokenAuthorizer{
+		"scoped":   []string{"my-secrets/api-key"},
+		"wildcard": []string{allPaths},
+	}
+
+	cases := []struct {
+		name  string
+		token string
+		path  string
+		exp   bool
+	}{
+		{"unknown token", "nope", "my-secrets/api-key", false},
+		{"matching prefix", "scoped", "my-secrets/api-key", true},
+		{"non-matching path", "scoped", "my-secrets/other-key", false},
+		{"wildcard allows anything", "wildcard", "other-bucket/other-key", true},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if act, exp := authz.Authorize(tc.token, tc.path), tc.exp; act != exp {
+				t.Errorf("expected %t to be %t", act, exp)
+			}
+		})
+	}
+}
+
+func TestPathAllowlist_Authorize(t *testing.T) {
+	t.Parallel()
+
+	authz := PathAllowlist{"my-secrets/api-key", "other-secrets/*"}
+
+	cases := []struct {
+		name string
+		path string
+		exp  bool
+	}{
+		{"exact match", "my-secrets/api-key", true},
+		{"no trailing star requires exact match", "my-secrets/api-key-2", false},
+		{"prefix match", "other-secrets/db-password", true},
+		{"non-matching path", "unrelated/secret", false},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			// The token is ignored entirely, including when empty.
+			if act, exp := authz.Authorize("", tc.path), tc.exp; act != exp {
+				t.Errorf("expected %t to be %t", act, exp)
+			}
+		})
+	}
+}
+
+func TestServer_requireAuth(t *testing.T) {
+	t.Parallel()
+
+	s := New(":0")
+	s.SetAuthorizer(TokenAuthorizer{"good": []string{allPaths}})
+
+	h := s.requireAuth(func(r *http.Request) string {
+		return "my-secrets/api-key"
+	}, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+
+	rr := httptest.NewRecorder()
+	h(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+	if act, exp := rr.Code, http.StatusUnauthorized; act != exp {
+		t.Errorf("expected missing token status %d to be %d", act, exp)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer bad")
+	rr = httptest.NewRecorder()
+	h(rr, req)
+	if act, exp := rr.Code, http.StatusUnauthorized; act != exp {
+		t.Errorf("expected bad token status %d to be %d", act, exp)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer good")
+	rr = httptest.NewRecorder()
+	h(rr, req)
+	if act, exp := rr.Code, http.StatusOK; act != exp {
+		t.Errorf("expected good token status %d to be %d", act, exp)
+	}
+}
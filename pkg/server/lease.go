@@ -0,0 +1,247 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/pkg/errors"
+)
+
+// leaseRevalidateInterval bounds how often a Lease's background watcher
+// re-checks that the caller who acquired it can still access the secret, so
+// a revoked grant kills the lease promptly instead of only at its next
+// renewal or expiry.
+const leaseRevalidateInterval = 30 * time.Second
+
+// Lease is a short-lived grant of a secret's decrypted value, issued by a
+// LeaseManager. It brings Vault-style lease semantics (an opaque ID, a TTL,
+// explicit renewal, and proactive revocation) to berglas-backed secrets,
+// without requiring the secret's own generation to change.
+type Lease struct {
+	// ID uniquely identifies the lease. It carries no information about the
+	// secret it was issued for.
+	ID string
+
+	// Bucket and Object identify the secret this lease was issued for.
+	Bucket string
+	Object string
+
+	// Plaintext is the secret's decrypted value as of when the lease was
+	// acquired (or last renewed).
+	Plaintext []byte
+
+	// ExpiresAt is when the lease expires if not renewed first.
+	ExpiresAt time.Time
+
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// Done returns a channel that is closed when the lease is revoked, either
+// explicitly or because a background revalidation found that access has
+// been withdrawn. It is never closed merely because the lease's TTL has
+// elapsed; callers that care about expiry should compare against ExpiresAt.
+func (l *Lease) Done() <-chan struct{} {
+	return l.done
+}
+
+// Revoked reports whether Done has fired.
+func (l *Lease) Revoked() bool {
+	select {
+	case <-l.done:
+		return true
+	default:
+		return false
+	}
+}
+
+func (l *Lease) revoke() {
+	l.closeOnce.Do(func() { close(l.done) })
+}
+
+// LeaseManager issues, renews, and revokes Leases against a berglas client.
+// It is safe for concurrent use.
+type LeaseManager struct {
+	client *berglas.Client
+
+	mu     sync.Mutex
+	leases map[string]*Lease
+	cancel map[string]context.CancelFunc
+}
+
+// NewLeaseManager creates a LeaseManager backed by client.
+func NewLeaseManager(client *berglas.Client) *LeaseManager {
+	return &LeaseManager{
+		client: client,
+		leases: make(map[string]*Lease),
+		cancel: make(map[string]context.CancelFunc),
+	}
+}
+
+// Acquire decrypts bucket/object and issues a new Lease for it with the
+// given ttl. A background goroutine periodically re-accesses the secret for
+// the lifetime of the lease and revokes it early if access starts failing,
+// so a caller whose IAM grant is pulled mid-lease finds out without having
+// to wait for the next renewal attempt.
+func (m *LeaseManager) Acquire(ctx context.Context, bucket, object string, ttl time.Duration) (*Lease, error) {
+	if bucket == "" {
+		return nil, errors.New("missing bucket name")
+	}
+	if object == "" {
+		return nil, errors.New("missing object name")
+	}
+	if ttl <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
+
+	plaintext, err := m.client.Access(ctx, &berglas.AccessRequest{Bucket: bucket, Object: object})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to access secret")
+	}
+
+	id, err := newLeaseID()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate lease id")
+	}
+
+	lease := &Lease{
+		ID:        id,
+		Bucket:    bucket,
+		Object:    object,
+		Plaintext: plaintext,
+		ExpiresAt: time.Now().Add(ttl),
+		done:      make(chan struct{}),
+	}
+
+	watchCtx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	m.leases[id] = lease
+	m.cancel[id] = cancel
+	m.mu.Unlock()
+
+	go m.watch(watchCtx, lease)
+
+	return lease, nil
+}
+
+// Renew re-validates that the secret can still be accessed and, if so,
+// extends the lease's expiry by ttl from now and refreshes its plaintext to
+// the secret's current value. Renewing an already-revoked or expired lease
+// returns an error.
+func (m *LeaseManager) Renew(ctx context.Context, id string, ttl time.Duration) (*Lease, error) {
+	if ttl <= 0 {
+		return nil, errors.New("ttl must be positive")
+	}
+
+	lease, ok := m.Get(id)
+	if !ok {
+		return nil, errors.New("lease not found")
+	}
+
+	plaintext, err := m.client.Access(ctx, &berglas.AccessRequest{Bucket: lease.Bucket, Object: lease.Object})
+	if err != nil {
+		m.Revoke(id)
+		return nil, errors.Wrap(err, "access revalidation failed, lease revoked")
+	}
+
+	m.mu.Lock()
+	lease.Plaintext = plaintext
+	lease.ExpiresAt = time.Now().Add(ttl)
+	m.mu.Unlock()
+
+	return lease, nil
+}
+
+// Get returns the lease with the given ID, if it exists, has not expired,
+// and has not been revoked. An expired lease is removed and reported as
+// not found.
+func (m *LeaseManager) Get(id string) (*Lease, bool) {
+	m.mu.Lock()
+	lease, ok := m.leases[id]
+	m.mu.Unlock()
+
+	if !ok {
+		return nil, false
+	}
+	if lease.Revoked() {
+		return nil, false
+	}
+	if time.Now().After(lease.ExpiresAt) {
+		m.Revoke(id)
+		return nil, false
+	}
+	return lease, true
+}
+
+// Revoke kills the lease with the given ID, closing its Done channel and
+// stopping its background revalidation. It is safe to call more than once;
+// only the first call has any effect. Revoke reports whether the lease
+// existed.
+func (m *LeaseManager) Revoke(id string) bool {
+	m.mu.Lock()
+	lease, ok := m.leases[id]
+	cancel := m.cancel[id]
+	delete(m.leases, id)
+	delete(m.cancel, id)
+	m.mu.Unlock()
+
+	if !ok {
+		return false
+	}
+	if cancel != nil {
+		cancel()
+	}
+	lease.revoke()
+	return true
+}
+
+// watch periodically re-checks that lease's secret is still accessible,
+// revoking the lease as soon as it is not. It exits once the lease is
+// revoked (by this check, by Revoke, or by expiry at the next Get/Renew) or
+// ctx is canceled.
+func (m *LeaseManager) watch(ctx context.Context, lease *Lease) {
+	ticker := time.NewTicker(leaseRevalidateInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-lease.Done():
+			return
+		case <-ticker.C:
+			if _, err := m.client.Access(ctx, &berglas.AccessRequest{Bucket: lease.Bucket, Object: lease.Object}); err != nil {
+				m.Revoke(lease.ID)
+				return
+			}
+		}
+	}
+}
+
+// newLeaseID generates an opaque, random lease identifier.
+func newLeaseID() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
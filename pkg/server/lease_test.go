@@ -0,0 +1,151 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglastest"
+	"github.com/GoogleCloudPlatform/berglas/pkg/server"
+)
+
+func TestLeaseManager_AcquireRenewRevoke(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "api-key",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := server.NewLeaseManager(h.Client)
+
+	lease, err := m.Acquire(ctx, h.Bucket, "api-key", time.Minute)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+	if string(lease.Plaintext) != "s3cr3t" {
+		t.Errorf("Plaintext = %q, want %q", lease.Plaintext, "s3cr3t")
+	}
+	if lease.Revoked() {
+		t.Error("lease should not be revoked immediately after Acquire")
+	}
+
+	if _, ok := m.Get(lease.ID); !ok {
+		t.Error("Get should find a freshly acquired lease")
+	}
+
+	renewed, err := m.Renew(ctx, lease.ID, time.Minute)
+	if err != nil {
+		t.Fatalf("Renew: %v", err)
+	}
+	if !renewed.ExpiresAt.After(lease.ExpiresAt.Add(-time.Minute)) {
+		t.Error("Renew should extend ExpiresAt")
+	}
+
+	if !m.Revoke(lease.ID) {
+		t.Error("Revoke should report the lease existed")
+	}
+	select {
+	case <-lease.Done():
+	default:
+		t.Error("Done() should be closed after Revoke")
+	}
+
+	if _, ok := m.Get(lease.ID); ok {
+		t.Error("Get should not find a revoked lease")
+	}
+	if m.Revoke(lease.ID) {
+		t.Error("Revoke should report false for an already-revoked lease")
+	}
+	if _, err := m.Renew(ctx, lease.ID, time.Minute); err == nil {
+		t.Error("Renew should fail for a revoked lease")
+	}
+}
+
+func TestLeaseManager_AcquireExpired(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	if _, err := h.Client.Create(ctx, &berglas.CreateRequest{
+		Bucket:    h.Bucket,
+		Object:    "api-key",
+		Key:       h.Key,
+		Plaintext: []byte("s3cr3t"),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	m := server.NewLeaseManager(h.Client)
+
+	lease, err := m.Acquire(ctx, h.Bucket, "api-key", time.Nanosecond)
+	if err != nil {
+		t.Fatalf("Acquire: %v", err)
+	}
+
+	time.Sleep(time.Millisecond)
+
+	if _, ok := m.Get(lease.ID); ok {
+		t.Error("Get should not find an expired lease")
+	}
+}
+
+func TestLeaseManager_AcquireValidation(t *testing.T) {
+	t.Parallel()
+
+	h, cleanup := berglastest.New(t)
+	defer cleanup()
+
+	m := server.NewLeaseManager(h.Client)
+	ctx := context.Background()
+
+	cases := []struct {
+		name   string
+		bucket string
+		object string
+		ttl    time.Duration
+	}{
+		{"missing bucket", "", "api-key", time.Minute},
+		{"missing object", h.Bucket, "", time.Minute},
+		{"non-positive ttl", h.Bucket, "api-key", 0},
+	}
+
+	for _, tc := range cases {
+		tc := tc
+
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if _, err := m.Acquire(ctx, tc.bucket, tc.object, tc.ttl); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package server provides a minimal HTTP server with health and readiness
+// endpoints, intended to be embedded in berglas's long-running server and
+// agent modes so they can be wired up to Kubernetes liveness/readiness
+// probes and similar orchestration health checks.
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+)
+
+// Server is a minimal HTTP server exposing health and readiness endpoints,
+// optionally gated by an Authorizer.
+type Server struct {
+	srv   *http.Server
+	ready int32
+	authz Authorizer
+}
+
+// New creates a new health server listening on addr. The server does not
+// start listening until Start is called. The caller is responsible for
+// registering any additional handlers on the mux before calling Start.
+func New(addr string) *Server {
+	mux := http.NewServeMux()
+
+	s := &Server{
+		srv: &http.Server{
+			Addr:    addr,
+			Handler: mux,
+		},
+	}
+
+	// The health, readiness, and metrics endpoints are intentionally never
+	// gated by the Authorizer: orchestrators probing liveness/readiness
+	// typically cannot supply credentials, and Prometheus scrapers are
+	// usually restricted by network policy rather than a bearer token.
+	mux.HandleFunc("/healthz", s.handleHealthz)
+	mux.HandleFunc("/readyz", s.handleReadyz)
+	mux.Handle("/metrics", berglas.PrometheusHandler())
+
+	return s
+}
+
+// SetAuthorizer configures the Authorizer used to gate any authenticated
+// routes registered on the server. A nil Authorizer (the default) leaves
+// those routes unauthenticated.
+func (s *Server) SetAuthorizer(authz Authorizer) {
+	s.authz = authz
+}
+
+// Handle registers an authenticated handler for the given pattern. pathFunc
+// derives the secret path (in "bucket/object" form) that the caller's bearer
+// token must be authorized for from each incoming request.
+func (s *Server) Handle(pattern string, pathFunc func(*http.Request) string, h http.HandlerFunc) {
+	s.srv.Handler.(*http.ServeMux).HandleFunc(pattern, s.requireAuth(pathFunc, h))
+}
+
+// SetReady marks the server as ready or not ready. /readyz reflects this
+// value until it is changed again.
+func (s *Server) SetReady(ready bool) {
+	if ready {
+		atomic.StoreInt32(&s.ready, 1)
+	} else {
+		atomic.StoreInt32(&s.ready, 0)
+	}
+}
+
+// Ready reports whether the server is currently marked ready.
+func (s *Server) Ready() bool {
+	return atomic.LoadInt32(&s.ready) == 1
+}
+
+// Start starts serving HTTP requests on the configured address. It blocks
+// until the context is canceled or the underlying listener fails, and always
+// returns a non-nil error (http.ErrServerClosed on a clean shutdown).
+func (s *Server) Start(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.srv.ListenAndServe()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return s.srv.Close()
+	case err := <-errCh:
+		return err
+	}
+}
+
+// handleHealthz reports whether the process is alive. It always returns 200
+// OK as long as the server is able to respond at all.
+func (s *Server) handleHealthz(w http.ResponseWriter, r *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
+
+// handleReadyz reports whether the server is ready to serve traffic, as set
+// by SetReady. It returns 503 Service Unavailable until the server has been
+// marked ready.
+func (s *Server) handleReadyz(w http.ResponseWriter, r *http.Request) {
+	if !s.Ready() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("not ready"))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte("ok"))
+}
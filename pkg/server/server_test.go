@@ -0,0 +1,55 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServer_handlers(t *testing.T) {
+	t.Parallel()
+
+	s := New(":0")
+
+	rr := httptest.NewRecorder()
+	s.handleHealthz(rr, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	if act, exp := rr.Code, http.StatusOK; act != exp {
+		t.Errorf("expected healthz status %d to be %d", act, exp)
+	}
+
+	rr = httptest.NewRecorder()
+	s.handleReadyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if act, exp := rr.Code, http.StatusServiceUnavailable; act != exp {
+		t.Errorf("expected readyz status %d to be %d before ready", act, exp)
+	}
+
+	s.SetReady(true)
+
+	rr = httptest.NewRecorder()
+	s.handleReadyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if act, exp := rr.Code, http.StatusOK; act != exp {
+		t.Errorf("expected readyz status %d to be %d after ready", act, exp)
+	}
+
+	s.SetReady(false)
+
+	rr = httptest.NewRecorder()
+	s.handleReadyz(rr, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	if act, exp := rr.Code, http.StatusServiceUnavailable; act != exp {
+		t.Errorf("expected readyz status %d to be %d after unready", act, exp)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package zaplogger adapts a go.uber.org/zap logger to berglas.Logger. It is
+// a separate package from berglas so that only callers who want to log with
+// zap pay for the dependency.
+package zaplogger
+
+import (
+	"github.com/GoogleCloudPlatform/berglas/pkg/berglas"
+	"go.uber.org/zap"
+)
+
+// zapLogger adapts a *zap.SugaredLogger to berglas.Logger.
+type zapLogger struct {
+	s *zap.SugaredLogger
+}
+
+// New adapts l to berglas.Logger, so it can be passed to
+// berglas.Client.SetLogger or berglas.WithLogger.
+func New(l *zap.Logger) berglas.Logger {
+	return &zapLogger{s: l.Sugar()}
+}
+
+// WithField implements berglas.Logger.
+func (l *zapLogger) WithField(key string, value interface{}) berglas.Logger {
+	return &zapLogger{s: l.s.With(key, value)}
+}
+
+// WithFields implements berglas.Logger.
+func (l *zapLogger) WithFields(fields map[string]interface{}) berglas.Logger {
+	args := make([]interface{}, 0, len(fields)*2)
+	for k, v := range fields {
+		args = append(args, k, v)
+	}
+	return &zapLogger{s: l.s.With(args...)}
+}
+
+// WithError implements berglas.Logger.
+func (l *zapLogger) WithError(err error) berglas.Logger {
+	return &zapLogger{s: l.s.With("error", err)}
+}
+
+// Debug implements berglas.Logger.
+func (l *zapLogger) Debug(args ...interface{}) { l.s.Debug(args...) }
+
+// Debugf implements berglas.Logger.
+func (l *zapLogger) Debugf(format string, args ...interface{}) { l.s.Debugf(format, args...) }
+
+// Warn implements berglas.Logger.
+func (l *zapLogger) Warn(args ...interface{}) { l.s.Warn(args...) }
+
+// Warnf implements berglas.Logger.
+func (l *zapLogger) Warnf(format string, args ...interface{}) { l.s.Warnf(format, args...) }
+
+// Error implements berglas.Logger.
+func (l *zapLogger) Error(args ...interface{}) { l.s.Error(args...) }
@@ -0,0 +1,57 @@
+// Copyright 2019 The Berglas Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package zaplogger
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func Test_ZapLogger(t *testing.T) {
+	t.Parallel()
+
+	core, logs := observer.New(zapcore.DebugLevel)
+	l := New(zap.New(core))
+
+	l.WithField("bucket", "my-bucket").
+		WithFields(map[string]interface{}{"object": "my-object"}).
+		WithError(errors.New("test error")).
+		Warnf("failed to %s", "frob")
+
+	if got := logs.Len(); got != 1 {
+		t.Fatalf("expected 1 log entry, got %d", got)
+	}
+
+	entry := logs.All()[0]
+	if entry.Message != "failed to frob" {
+		t.Errorf("expected message %q, got %q", "failed to frob", entry.Message)
+	}
+
+	fields := entry.ContextMap()
+	if fields["bucket"] != "my-bucket" {
+		t.Errorf("expected bucket field %q, got %v", "my-bucket", fields["bucket"])
+	}
+	if fields["object"] != "my-object" {
+		t.Errorf("expected object field %q, got %v", "my-object", fields["object"])
+	}
+	if errStr, ok := fields["error"].(string); !ok || !strings.Contains(errStr, "test error") {
+		t.Errorf("expected error field to contain %q, got %v", "test error", fields["error"])
+	}
+}
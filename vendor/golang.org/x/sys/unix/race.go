@@ -2,7 +2,7 @@
 // Use of this source code is governed by a BSD-style
 // license that can be found in the LICENSE file.
 
-// +build darwin,race linux,race freebsd,race
+//go:build (darwin && race) || (linux && race) || (freebsd && race)
 
 package unix
 
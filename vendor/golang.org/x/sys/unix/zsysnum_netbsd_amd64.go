@@ -1,7 +1,7 @@
 // go run mksysnum.go http://cvsweb.netbsd.org/bsdweb.cgi/~checkout~/src/sys/kern/syscalls.master
 // Code generated by the command above; see README.md. DO NOT EDIT.
 
-// +build amd64,netbsd
+//go:build amd64 && netbsd
 
 package unix
 
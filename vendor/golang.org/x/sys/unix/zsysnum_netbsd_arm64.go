@@ -1,7 +1,7 @@
 // go run mksysnum.go http://cvsweb.netbsd.org/bsdweb.cgi/~checkout~/src/sys/kern/syscalls.master
 // Code generated by the command above; DO NOT EDIT.
 
-// +build arm64,netbsd
+//go:build arm64 && netbsd
 
 package unix
 